@@ -84,6 +84,11 @@ type config struct {
 	// TODO: run loadtests using these flags to determine optimal default values.
 	MaxIdleProxyConns        int `split_words:"true" default:"1000"`
 	MaxIdleProxyConnsPerHost int `split_words:"true" default:"100"`
+
+	// These bound the requests buffered across all revisions while they wait for capacity.
+	// Zero means unbounded.
+	MaxBufferedRequests int   `split_words:"true" default:"0"`
+	MaxBufferedBytes    int64 `split_words:"true" default:"0"`
 }
 
 func main() {
@@ -173,7 +178,10 @@ func main() {
 	}
 
 	// Start throttler.
-	throttler := activatornet.NewThrottler(ctx, env.PodIP)
+	throttler := activatornet.NewThrottler(ctx, env.PodIP, activatornet.BufferLimits{
+		MaxRequests: env.MaxBufferedRequests,
+		MaxBytes:    env.MaxBufferedBytes,
+	})
 	go throttler.Run(ctx, transport, networkConfig.EnableMeshPodAddressability, networkConfig.MeshCompatibilityMode)
 
 	oct := tracing.NewOpenCensusTracer(tracing.WithExporterFull(networking.ActivatorServiceName, env.PodIP, logger))
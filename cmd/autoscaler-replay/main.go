@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// autoscaler-replay replays a recorded stat stream through the autoscaler and
+// prints the desired and ready pod counts over time as CSV, e.g.
+//
+//	autoscaler-replay -stats stats.bin -config config-autoscaler.yaml \
+//	  -annotation autoscaling.knative.dev/target=50 -revision default/hello-00001
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"knative.dev/serving/pkg/autoscaler/config"
+	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
+	"knative.dev/serving/pkg/autoscaler/metrics"
+	"knative.dev/serving/pkg/autoscaler/replay"
+)
+
+// annotations collects the repeated key=value annotation flags.
+type annotations map[string]string
+
+func (a annotations) String() string {
+	return fmt.Sprint(map[string]string(a))
+}
+
+func (a annotations) Set(s string) error {
+	k, v, ok := strings.Cut(s, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("annotation %q must have the form key=value", s)
+	}
+	a[k] = v
+	return nil
+}
+
+var (
+	statsPath            = flag.String("stats", "", "The path of the recorded stat stream to replay.")
+	configPath           = flag.String("config", "", "The path of the config-autoscaler ConfigMap to replay with. Defaults are used if unset.")
+	revision             = flag.String("revision", "", "The namespace/name of the revision to replay. The revision of the first stat is used if unset.")
+	containerConcurrency = flag.Int64("container-concurrency", 0, "The container concurrency of the revision.")
+	podStartDelay        = flag.Duration("pod-start-delay", 10*time.Second, "How long it takes for a new pod to become ready.")
+	verbose              = flag.Bool("verbose", false, "Whether to log the autoscaler's debug output to stderr.")
+	revAnnotations       = annotations{}
+)
+
+func main() {
+	flag.Var(revAnnotations, "annotation", "An autoscaling annotation of the revision as key=value. May be repeated.")
+	flag.Parse()
+	if *statsPath == "" {
+		log.Fatal("-stats must be set")
+	}
+
+	opts := replay.Options{
+		Annotations:          revAnnotations,
+		ContainerConcurrency: *containerConcurrency,
+		PodStartDelay:        *podStartDelay,
+	}
+	if *revision != "" {
+		ns, name, ok := strings.Cut(*revision, "/")
+		if !ok {
+			log.Fatalf("-revision %q must have the form namespace/name", *revision)
+		}
+		opts.Revision = types.NamespacedName{Namespace: ns, Name: name}
+	}
+
+	var err error
+	if opts.Config, err = loadConfig(*configPath); err != nil {
+		log.Fatal("Failed to load the autoscaler config: ", err)
+	}
+
+	f, err := os.Open(*statsPath)
+	if err != nil {
+		log.Fatal("Failed to open the stat stream: ", err)
+	}
+	defer f.Close()
+	stats, err := metrics.ReadStatStream(f)
+	if err != nil {
+		log.Fatal("Failed to read the stat stream: ", err)
+	}
+
+	logger := zap.NewNop()
+	if *verbose {
+		if logger, err = zap.NewDevelopment(); err != nil {
+			log.Fatal("Failed to create the logger: ", err)
+		}
+	}
+	samples, err := replay.Replay(logger.Sugar(), opts, stats)
+	if err != nil {
+		log.Fatal("Failed to replay: ", err)
+	}
+
+	if err := writeCSV(os.Stdout, samples); err != nil {
+		log.Fatal("Failed to write the samples: ", err)
+	}
+}
+
+// loadConfig reads the config-autoscaler ConfigMap at path, or returns the
+// default config if path is empty.
+func loadConfig(path string) (*autoscalerconfig.Config, error) {
+	if path == "" {
+		return config.NewConfigFromMap(nil)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cm corev1.ConfigMap
+	if err := yaml.Unmarshal(b, &cm); err != nil {
+		return nil, err
+	}
+	return config.NewConfigFromConfigMap(&cm)
+}
+
+func writeCSV(out io.Writer, samples []replay.Sample) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"time", "desired_pods", "ready_pods", "scaling_metric",
+		"observed_stable_value", "observed_panic_value", "in_panic_mode"})
+	for _, s := range samples {
+		w.Write([]string{
+			s.Time.UTC().Format(time.RFC3339),
+			strconv.Itoa(int(s.DesiredPods)),
+			strconv.Itoa(int(s.ReadyPods)),
+			s.Decision.ScalingMetric,
+			strconv.FormatFloat(s.Decision.ObservedStableValue, 'f', 3, 64),
+			strconv.FormatFloat(s.Decision.ObservedPanicValue, 'f', 3, 64),
+			strconv.FormatBool(s.Decision.InPanicMode),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
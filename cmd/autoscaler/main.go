@@ -23,12 +23,19 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 
+	// Embed the time zone database for the time zones of the scale schedules.
+	_ "time/tzdata"
+
+	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/rest"
@@ -194,6 +201,15 @@ func main() {
 	// Set up a statserver.
 	statsServer := statserver.New(statsServerAddr, statsCh, logger, f.IsBucketOwner)
 	defer f.Cancel()
+	recorder, err := newStatsRecorder()
+	if err != nil {
+		logger.Fatalw("Failed to set up stats recording", zap.Error(err))
+	}
+	if recorder != nil {
+		logger.Info("Recording the received stats")
+		statsServer.SetRecorder(recorder)
+		defer recorder.Close()
+	}
 
 	go func() {
 		for sm := range statsCh {
@@ -205,7 +221,12 @@ func main() {
 		}
 	}()
 
-	profilingServer := profiling.NewServer(profilingHandler)
+	// Serve the scaling decisions of the revisions next to the profiling
+	// endpoints, for debugging.
+	debugMux := http.NewServeMux()
+	debugMux.Handle(scaling.DecisionsPath, multiScaler.DecisionsHandler())
+	debugMux.Handle("/", profilingHandler)
+	profilingServer := profiling.NewServer(debugMux)
 
 	eg, egCtx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
@@ -230,6 +251,45 @@ func main() {
 	}
 }
 
+// statsRecordingEnv configures the opt-in recording of the received stats,
+// e.g. to replay them later.
+type statsRecordingEnv struct {
+	// StatsRecordingPath is the file to record to. Recording is disabled if unset.
+	StatsRecordingPath     string `split_words:"true"`
+	StatsRecordingMaxSize  int64  `split_words:"true" default:"104857600"`
+	StatsRecordingMaxFiles int    `split_words:"true" default:"3"`
+	// StatsRecordingNamespaces and StatsRecordingRevisions are comma separated
+	// lists of namespaces and namespace/name revisions to restrict recording to.
+	StatsRecordingNamespaces []string `split_words:"true"`
+	StatsRecordingRevisions  []string `split_words:"true"`
+}
+
+// newStatsRecorder creates the recorder of the received stats, if enabled.
+func newStatsRecorder() (*statserver.Recorder, error) {
+	var env statsRecordingEnv
+	if err := envconfig.Process("", &env); err != nil {
+		return nil, err
+	}
+	if env.StatsRecordingPath == "" {
+		return nil, nil
+	}
+	revisions := sets.New[types.NamespacedName]()
+	for _, rev := range env.StatsRecordingRevisions {
+		ns, name, ok := strings.Cut(rev, "/")
+		if !ok {
+			return nil, fmt.Errorf("revision %q must have the form namespace/name", rev)
+		}
+		revisions.Insert(types.NamespacedName{Namespace: ns, Name: name})
+	}
+	return statserver.NewRecorder(statserver.RecorderOptions{
+		Path:       env.StatsRecordingPath,
+		MaxSize:    env.StatsRecordingMaxSize,
+		MaxFiles:   env.StatsRecordingMaxFiles,
+		Namespaces: sets.New(env.StatsRecordingNamespaces...),
+		Revisions:  revisions,
+	})
+}
+
 func uniScalerFactoryFunc(podLister corev1listers.PodLister,
 	metricClient asmetrics.MetricClient) scaling.UniScalerFactory {
 	return func(decider *scaling.Decider) (scaling.UniScaler, error) {
@@ -247,8 +307,7 @@ func uniScalerFactoryFunc(podLister corev1listers.PodLister,
 		ctx := smetrics.RevisionContext(decider.Namespace, serviceName, configName, revisionName)
 
 		podAccessor := resources.NewPodAccessor(podLister, decider.Namespace, revisionName)
-		return scaling.New(ctx, decider.Namespace, decider.Name, metricClient,
-			podAccessor, &decider.Spec), nil
+		return scaling.NewAlgorithm(ctx, decider, metricClient, podAccessor)
 	}
 }
 
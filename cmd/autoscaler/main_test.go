@@ -18,6 +18,7 @@ package main
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -111,3 +112,23 @@ func TestUniScalerFactoryFunc(t *testing.T) {
 func testUniScalerFactory() func(decider *scaling.Decider) (scaling.UniScaler, error) {
 	return uniScalerFactoryFunc(kubeInformer.Core().V1().Pods().Lister(), nil)
 }
+
+func TestNewStatsRecorder(t *testing.T) {
+	if r, err := newStatsRecorder(); err != nil || r != nil {
+		t.Errorf("newStatsRecorder() = %v, %v, want disabled recording", r, err)
+	}
+
+	t.Setenv("STATS_RECORDING_PATH", filepath.Join(t.TempDir(), "stats"))
+	t.Setenv("STATS_RECORDING_NAMESPACES", "ns1,ns2")
+	t.Setenv("STATS_RECORDING_REVISIONS", "ns3/rev")
+	r, err := newStatsRecorder()
+	if err != nil || r == nil {
+		t.Fatalf("newStatsRecorder() = %v, %v, want a recorder", r, err)
+	}
+	r.Close()
+
+	t.Setenv("STATS_RECORDING_REVISIONS", "rev")
+	if _, err := newStatsRecorder(); err == nil {
+		t.Error("newStatsRecorder() with an invalid revision = nil, want error")
+	}
+}
@@ -19,6 +19,9 @@ package main
 import (
 	"context"
 
+	// Embed the time zone database for the time zones of the scale schedules.
+	_ "time/tzdata"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
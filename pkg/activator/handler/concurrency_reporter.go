@@ -35,6 +35,7 @@ import (
 	asmetrics "knative.dev/serving/pkg/autoscaler/metrics"
 	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
 	servinglisters "knative.dev/serving/pkg/client/listers/serving/v1"
+	pkghttp "knative.dev/serving/pkg/http"
 	"knative.dev/serving/pkg/metrics"
 )
 
@@ -42,11 +43,14 @@ const reportInterval = time.Second
 
 // revisionStats is a type that wraps information needed to calculate stats per revision.
 //
-// stats is thread-safe in itself and thus needs no extra synchronization.
+// stats and longLived are thread-safe in themselves and thus need no extra
+// synchronization. longLived tracks the long-lived connections among the
+// requests, e.g. WebSockets.
 // firstRequest is only read/mutated in `report` which is guaranteed to be single-threaded
 // as it is driven by the report channel.
 type revisionStats struct {
 	stats        *netstats.RequestStats
+	longLived    *netstats.RequestStats
 	firstRequest float64
 	refs         atomic.Int64
 }
@@ -126,6 +130,7 @@ func (cr *ConcurrencyReporter) getOrCreateStat(event netstats.ReqEvent) (*revisi
 
 	stat = &revisionStats{
 		stats:        netstats.NewRequestStats(event.Time),
+		longLived:    netstats.NewRequestStats(event.Time),
 		firstRequest: 1,
 	}
 	stat.refs.Inc()
@@ -171,6 +176,7 @@ func (cr *ConcurrencyReporter) computeReport(now time.Time) (msgs []asmetrics.St
 	msgs = make([]asmetrics.StatMessage, 0, len(cr.stats))
 	for key, stat := range cr.stats {
 		report := stat.stats.Report(now)
+		longLivedReport := stat.longLived.Report(now)
 
 		firstAdj := stat.firstRequest
 		stat.firstRequest = 0.
@@ -193,6 +199,8 @@ func (cr *ConcurrencyReporter) computeReport(now time.Time) (msgs []asmetrics.St
 				PodName:                   cr.podName,
 				AverageConcurrentRequests: adjustedConcurrency,
 				RequestCount:              adjustedCount,
+
+				AverageLongLivedConcurrentRequests: longLivedReport.AverageConcurrency,
 			},
 		})
 	}
@@ -249,6 +257,16 @@ func (cr *ConcurrencyReporter) Handler(next http.Handler) http.HandlerFunc {
 		defer func() {
 			cr.handleRequestOut(stat, netstats.ReqEvent{Key: revisionKey, Type: netstats.ReqOut, Time: time.Now()})
 		}()
+		isLongLived := false
+		w = pkghttp.TrackLongLived(w, r, func() {
+			isLongLived = true
+			stat.longLived.HandleEvent(netstats.ReqEvent{Key: revisionKey, Type: netstats.ReqIn, Time: time.Now()})
+		})
+		defer func() {
+			if isLongLived {
+				stat.longLived.HandleEvent(netstats.ReqEvent{Key: revisionKey, Type: netstats.ReqOut, Time: time.Now()})
+			}
+		}()
 
 		next.ServeHTTP(w, r)
 	}
@@ -470,6 +470,52 @@ func TestConcurrencyReporterHandler(t *testing.T) {
 	}
 }
 
+func TestConcurrencyReporterHandlerLongLived(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		contentType   string
+		wantLongLived bool
+	}{{
+		name:        "plain request",
+		contentType: "text/plain",
+	}, {
+		name:          "event stream",
+		contentType:   "text/event-stream",
+		wantLongLived: true,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			cr, _, cancel := newTestReporter(t)
+			defer cancel()
+
+			streaming := make(chan struct{})
+			done := make(chan struct{})
+			handler := cr.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", test.contentType)
+				w.WriteHeader(http.StatusOK)
+				close(streaming)
+				<-done
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			go handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(WithRevisionAndID(context.Background(), nil, rev1)))
+			<-cr.statCh // Scale from 0.
+			<-streaming
+
+			// Let the connection be open for a while.
+			time.Sleep(10 * time.Millisecond)
+			msgs := cr.report(time.Now())
+			close(done)
+
+			if len(msgs) != 1 {
+				t.Fatalf("Got %d messages, want: 1", len(msgs))
+			}
+			if got := msgs[0].Stat.AverageLongLivedConcurrentRequests; (got > 0) != test.wantLongLived {
+				t.Errorf("AverageLongLivedConcurrentRequests = %v, want it > 0: %v", got, test.wantLongLived)
+			}
+		})
+	}
+}
+
 func TestConcurrencyReporterRace(t *testing.T) {
 	cr, _, cancel := newTestReporter(t)
 	defer cancel()
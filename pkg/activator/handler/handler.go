@@ -23,27 +23,39 @@ import (
 	"net/http/httputil"
 	"strconv"
 	"strings"
+	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	"go.opencensus.io/plugin/ochttp"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	"k8s.io/apimachinery/pkg/types"
 
 	netheader "knative.dev/networking/pkg/http/header"
 	netproxy "knative.dev/networking/pkg/http/proxy"
 	"knative.dev/pkg/logging/logkey"
+	pkgmetrics "knative.dev/pkg/metrics"
 	pkghandler "knative.dev/pkg/network/handlers"
 	tracingconfig "knative.dev/pkg/tracing/config"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
 	"knative.dev/serving/pkg/activator"
 	activatorconfig "knative.dev/serving/pkg/activator/config"
+	activatornet "knative.dev/serving/pkg/activator/net"
 	apiconfig "knative.dev/serving/pkg/apis/config"
+	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	pkghttp "knative.dev/serving/pkg/http"
+	"knative.dev/serving/pkg/metrics"
 	"knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/queue"
 	"knative.dev/serving/pkg/reconciler/serverlessservice/resources/names"
 )
 
+// bufferFullRetryAfter is the Retry-After header value, in seconds, of the
+// responses to requests beyond the buffering limits.
+const bufferFullRetryAfter = "1"
+
 // Throttler is the interface that Handler calls to Try to proxy the user request.
 type Throttler interface {
 	Try(ctx context.Context, revID types.NamespacedName, fn func(string) error) error
@@ -59,10 +71,13 @@ type activationHandler struct {
 	bufferPool       httputil.BufferPool
 	logger           *zap.SugaredLogger
 	tls              bool
+	// retryBudgets are the retry budgets of the revisions by their ID.
+	retryBudgets *lru.Cache
 }
 
 // New constructs a new http.Handler that deals with revision activation.
 func New(_ context.Context, t Throttler, transport http.RoundTripper, usePassthroughLb bool, logger *zap.SugaredLogger, tlsEnabled bool) http.Handler {
+	retryBudgets, _ := lru.New(retryBudgetsSize)
 	return &activationHandler{
 		transport: transport,
 		tracingTransport: &ochttp.Transport{
@@ -74,6 +89,7 @@ func New(_ context.Context, t Throttler, transport http.RoundTripper, usePassthr
 		bufferPool:       netproxy.NewBufferPool(),
 		logger:           logger,
 		tls:              tlsEnabled,
+		retryBudgets:     retryBudgets,
 	}
 }
 
@@ -81,20 +97,68 @@ func (a *activationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	config := activatorconfig.FromContext(r.Context())
 	tracingEnabled := config.Tracing.Backend != tracingconfig.None
 
-	tryContext, trySpan := r.Context(), (*trace.Span)(nil)
+	revID := RevIDFrom(r.Context())
+	retrier := a.retrier(r, revID)
+	// The LB policy and the buffering limits may depend on the request.
+	tryContext := activatornet.WithRequest(r.Context(), r)
+	for attempt := 1; ; attempt++ {
+		// The response of an attempt that may be retried is held back if it
+		// fails, so this must be known upfront.
+		canRetry := retrier != nil && retrier.allow(attempt)
+		dest, retry := a.try(tryContext, w, r, revID, tracingEnabled, attempt, canRetry)
+		if !retry {
+			if canRetry {
+				retrier.budget.refund()
+			}
+			return
+		}
+		recordRetry(r.Context())
+		// Retry on another pod, with the body from the start.
+		tryContext = activatornet.WithExcludedDest(tryContext, dest)
+		if r.GetBody != nil {
+			r.Body, _ = r.GetBody()
+		}
+	}
+}
+
+// try proxies the request once it gets capacity, and returns the dest it was
+// proxied to and whether it failed and must be retried. Failures are only
+// retried, rather than written to the response, if canRetry.
+func (a *activationHandler) try(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	revID types.NamespacedName, tracingEnabled bool, attempt int, canRetry bool) (dest string, retry bool) {
+	// The outcome of proxying the request drives the outlier detection.
+	tryContext, result := activatornet.WithProxyResult(ctx)
+	trySpan := (*trace.Span)(nil)
 	if tracingEnabled {
-		tryContext, trySpan = trace.StartSpan(r.Context(), "throttler_try")
+		tryContext, trySpan = trace.StartSpan(tryContext, "throttler_try")
 	}
 
-	revID := RevIDFrom(r.Context())
-	if err := a.throttler.Try(tryContext, revID, func(dest string) error {
+	start := time.Now()
+	if err := a.throttler.Try(tryContext, revID, func(d string) error {
 		trySpan.End()
+		recordBuffered(r.Context(), time.Since(start))
+		dest = d
 
 		proxyCtx, proxySpan := r.Context(), (*trace.Span)(nil)
 		if tracingEnabled {
 			proxyCtx, proxySpan = trace.StartSpan(r.Context(), "activator_proxy")
+			proxySpan.AddAttributes(trace.Int64Attribute("activator.proxy.attempt", int64(attempt)))
+		}
+		pw, rw := w, (*retryWriter)(nil)
+		if canRetry {
+			rw = newRetryWriter(w)
+			pw = rw
+		}
+		rec := pkghttp.NewResponseRecorder(pw, http.StatusOK)
+		a.proxyRequest(revID, rec, r.WithContext(proxyCtx), dest, tracingEnabled, a.usePassthroughLb)
+		// The pod isn't to blame for the requests canceled by the client.
+		if r.Context().Err() == nil {
+			result.SetResponseCode(rec.ResponseCode)
+		}
+		if canRetry && rw.failed {
+			retry = true
+			proxySpan.Annotate([]trace.Attribute{trace.StringAttribute("activator.proxy.dest", dest)}, "Retrying")
 		}
-		a.proxyRequest(revID, w, r.WithContext(proxyCtx), dest, tracingEnabled, a.usePassthroughLb)
 		proxySpan.End()
 
 		return nil
@@ -105,12 +169,61 @@ func (a *activationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		a.logger.Errorw("Throttler try error", zap.String(logkey.Key, revID.String()), zap.Error(err))
 
-		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, queue.ErrRequestQueueFull) {
+		grpc := pkghttp.IsGRPC(r)
+		switch {
+		case errors.Is(err, activatornet.ErrBufferFull):
+			code, grpcCode := http.StatusServiceUnavailable, codes.Unavailable
+			if errors.Is(err, activatornet.ErrBufferThrottled) {
+				code, grpcCode = http.StatusTooManyRequests, codes.ResourceExhausted
+			}
+			recordBufferOverflow(r.Context(), code)
+			w.Header().Set("Retry-After", bufferFullRetryAfter)
+			if grpc {
+				pkghttp.GRPCError(w, grpcCode, err.Error())
+			} else {
+				http.Error(w, err.Error(), code)
+			}
+		case grpc && errors.Is(err, context.DeadlineExceeded):
+			pkghttp.GRPCError(w, codes.DeadlineExceeded, err.Error())
+		case grpc && errors.Is(err, queue.ErrRequestQueueFull):
+			pkghttp.GRPCError(w, codes.ResourceExhausted, err.Error())
+		case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, queue.ErrRequestQueueFull):
 			http.Error(w, err.Error(), http.StatusServiceUnavailable)
-		} else {
+		case grpc:
+			pkghttp.GRPCError(w, codes.Internal, err.Error())
+		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 	}
+	return dest, retry
+}
+
+// recordRetry records a retried request.
+func recordRetry(ctx context.Context) {
+	if rev := RevisionFrom(ctx); rev != nil {
+		pkgmetrics.Record(revisionMetricsContext(rev), retryCountM.M(1))
+	}
+}
+
+// recordBuffered records the time a request was buffered for until there was
+// capacity for it.
+func recordBuffered(ctx context.Context, d time.Duration) {
+	if rev := RevisionFrom(ctx); rev != nil {
+		pkgmetrics.Record(revisionMetricsContext(rev), bufferTimeInMsecM.M(float64(d.Milliseconds())))
+	}
+}
+
+// recordBufferOverflow records a request rejected with the given code because
+// the activator buffers too many requests.
+func recordBufferOverflow(ctx context.Context, code int) {
+	if rev := RevisionFrom(ctx); rev != nil {
+		pkgmetrics.Record(metrics.AugmentWithResponse(revisionMetricsContext(rev), code), bufferOverflowCountM.M(1))
+	}
+}
+
+func revisionMetricsContext(rev *v1.Revision) context.Context {
+	return metrics.RevisionContext(rev.Namespace, rev.Labels[serving.ServiceLabelKey],
+		rev.Labels[serving.ConfigurationLabelKey], rev.Name)
 }
 
 func (a *activationHandler) proxyRequest(revID types.NamespacedName, w http.ResponseWriter,
@@ -25,11 +25,13 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
 	netheader "knative.dev/networking/pkg/http/header"
 	pkgnet "knative.dev/pkg/network"
@@ -40,6 +42,7 @@ import (
 	tracetesting "knative.dev/pkg/tracing/testing"
 	"knative.dev/serving/pkg/activator"
 	activatorconfig "knative.dev/serving/pkg/activator/config"
+	activatornet "knative.dev/serving/pkg/activator/net"
 	activatortest "knative.dev/serving/pkg/activator/testing"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -78,6 +81,8 @@ func TestActivationHandler(t *testing.T) {
 		probeCode int
 		probeResp []string
 		throttler Throttler
+		// wantRetryAfter is the Retry-After header of the response, if any.
+		wantRetryAfter string
 	}{{
 		name:      "active endpoint",
 		wantBody:  wantBody,
@@ -99,6 +104,18 @@ func TestActivationHandler(t *testing.T) {
 		wantBody:  "pending request queue full\n",
 		wantCode:  http.StatusServiceUnavailable,
 		throttler: fakeThrottler{err: queue.ErrRequestQueueFull},
+	}, {
+		name:           "buffer full",
+		wantBody:       activatornet.ErrBufferFull.Error() + "\n",
+		wantCode:       http.StatusServiceUnavailable,
+		throttler:      fakeThrottler{err: activatornet.ErrBufferFull},
+		wantRetryAfter: bufferFullRetryAfter,
+	}, {
+		name:           "buffer throttled",
+		wantBody:       activatornet.ErrBufferThrottled.Error() + "\n",
+		wantCode:       http.StatusTooManyRequests,
+		throttler:      fakeThrottler{err: activatornet.ErrBufferThrottled},
+		wantRetryAfter: bufferFullRetryAfter,
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -147,6 +164,64 @@ func TestActivationHandler(t *testing.T) {
 			if string(gotBody) != test.wantBody {
 				t.Errorf("Response body = %q, want: %q", gotBody, test.wantBody)
 			}
+			if got := resp.Header().Get("Retry-After"); got != test.wantRetryAfter {
+				t.Errorf("Retry-After = %q, want: %q", got, test.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestActivationHandlerGRPCErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{{
+		name:     "throttler timeout",
+		err:      context.DeadlineExceeded,
+		wantCode: codes.DeadlineExceeded,
+	}, {
+		name:     "overflow",
+		err:      queue.ErrRequestQueueFull,
+		wantCode: codes.ResourceExhausted,
+	}, {
+		name:     "buffer full",
+		err:      activatornet.ErrBufferFull,
+		wantCode: codes.Unavailable,
+	}, {
+		name:     "buffer throttled",
+		err:      activatornet.ErrBufferThrottled,
+		wantCode: codes.ResourceExhausted,
+	}, {
+		name:     "other error",
+		err:      errors.New("other error"),
+		wantCode: codes.Internal,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel, _ := rtesting.SetupFakeContextWithCancel(t)
+			defer cancel()
+			handler := New(ctx, fakeThrottler{err: test.err}, http.DefaultTransport, false /*usePassthroughLb*/, logging.FromContext(ctx), false /* TLS */)
+
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/grpc.Service/Method", nil)
+			req.Header.Set("Content-Type", "application/grpc")
+
+			configStore := setupConfigStore(t, logging.FromContext(ctx))
+			ctx = configStore.ToContext(ctx)
+			ctx = WithRevisionAndID(ctx, nil, types.NamespacedName{Namespace: testNamespace, Name: testRevName})
+
+			handler.ServeHTTP(resp, req.WithContext(ctx))
+
+			if resp.Code != http.StatusOK {
+				t.Errorf("Unexpected response status. Want %d, got %d", http.StatusOK, resp.Code)
+			}
+			if got, want := resp.Header().Get("Grpc-Status"), strconv.Itoa(int(test.wantCode)); got != want {
+				t.Errorf("Grpc-Status = %q, want: %q", got, want)
+			}
+			if got, want := resp.Header().Get("Grpc-Message"), test.err.Error(); got != want {
+				t.Errorf("Grpc-Message = %q, want: %q", got, want)
+			}
 		})
 	}
 }
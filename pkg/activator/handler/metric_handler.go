@@ -58,6 +58,9 @@ func (h *MetricHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			panic(err)
 		}
 		reporterCtx := metrics.AugmentWithResponse(reporterCtx, rr.ResponseCode)
+		if code, ok := pkghttp.GRPCStatus(rr.Header()); ok {
+			reporterCtx = metrics.AugmentWithGRPCStatus(reporterCtx, code.String())
+		}
 		pkgmetrics.RecordBatch(reporterCtx, responseTimeInMsecM.M(float64(latency.Milliseconds())), requestCountM.M(1))
 	}()
 
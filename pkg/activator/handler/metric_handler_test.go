@@ -24,6 +24,7 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 
 	"go.opencensus.io/resource"
 	"k8s.io/apimachinery/pkg/types"
@@ -120,8 +121,34 @@ func TestRequestMetricHandler(t *testing.T) {
 	}
 }
 
+func TestBufferMetrics(t *testing.T) {
+	defer reset()
+	rev := revision(testNamespace, testRevName)
+	ctx := WithRevisionAndID(context.Background(), rev, types.NamespacedName{Namespace: testNamespace, Name: testRevName})
+	wantResource := &resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metrics.LabelNamespaceName:     rev.Namespace,
+			metrics.LabelServiceName:       rev.Labels[serving.ServiceLabelKey],
+			metrics.LabelConfigurationName: rev.Labels[serving.ConfigurationLabelKey],
+			metrics.LabelRevisionName:      rev.Name,
+		},
+	}
+
+	recordBuffered(ctx, 10*time.Millisecond)
+	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric(bufferTimeInMsecM.Name(), 1, nil).WithResource(wantResource))
+
+	recordBufferOverflow(ctx, http.StatusTooManyRequests)
+	wantTags := map[string]string{
+		metrics.LabelResponseCode:      "429",
+		metrics.LabelResponseCodeClass: "4xx",
+	}
+	metricstest.AssertMetric(t, metricstest.IntMetric(bufferOverflowCountM.Name(), 1, wantTags).WithResource(wantResource))
+}
+
 func reset() {
-	metricstest.Unregister(requestConcurrencyM.Name(), requestCountM.Name(), responseTimeInMsecM.Name())
+	metricstest.Unregister(requestConcurrencyM.Name(), requestCountM.Name(), responseTimeInMsecM.Name(),
+		bufferOverflowCountM.Name(), bufferTimeInMsecM.Name(), retryCountM.Name())
 	register()
 }
 
@@ -38,6 +38,18 @@ var (
 		"request_latencies",
 		"The response time in millisecond",
 		stats.UnitMilliseconds)
+	bufferOverflowCountM = stats.Int64(
+		"request_buffer_overflow_count",
+		"The number of requests rejected by Activator because it buffers too many requests",
+		stats.UnitDimensionless)
+	retryCountM = stats.Int64(
+		"request_retry_count",
+		"The number of requests retried by Activator",
+		stats.UnitDimensionless)
+	bufferTimeInMsecM = stats.Float64(
+		"request_buffer_latencies",
+		"The time requests are buffered by Activator for until there is capacity in millisecond",
+		stats.UnitMilliseconds)
 
 	// NOTE: 0 should not be used as boundary. See
 	// https://github.com/census-ecosystem/opencensus-go-exporter-stackdriver/issues/98
@@ -63,13 +75,29 @@ func register() {
 			Description: "The number of requests that are routed to Activator",
 			Measure:     requestCountM,
 			Aggregation: view.Count(),
-			TagKeys:     []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey},
+			TagKeys:     []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey, metrics.GRPCStatusKey},
 		},
 		&view.View{
 			Description: "The response time in millisecond",
 			Measure:     responseTimeInMsecM,
 			Aggregation: defaultLatencyDistribution,
-			TagKeys:     []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey},
+			TagKeys:     []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey, metrics.GRPCStatusKey},
+		},
+		&view.View{
+			Description: "The number of requests rejected by Activator because it buffers too many requests",
+			Measure:     bufferOverflowCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{metrics.ResponseCodeKey, metrics.ResponseCodeClassKey},
+		},
+		&view.View{
+			Description: "The number of requests retried by Activator",
+			Measure:     retryCountM,
+			Aggregation: view.Count(),
+		},
+		&view.View{
+			Description: "The time requests are buffered by Activator for until there is capacity in millisecond",
+			Measure:     bufferTimeInMsecM,
+			Aggregation: defaultLatencyDistribution,
 		},
 	); err != nil {
 		panic(err)
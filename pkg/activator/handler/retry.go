@@ -0,0 +1,248 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/pkg/websocket"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+const (
+	// retryBodyLimit is the largest request body the activator buffers to
+	// replay it on retries. Larger requests aren't retried.
+	retryBodyLimit = 64 << 10
+
+	// retryBudgetBurst is the number of retries a revision may make before
+	// its requests paid for them, which is also the most its budget saves up.
+	retryBudgetBurst = 10
+
+	// retryBudgetsSize is the number of revisions the retry budgets are kept for.
+	retryBudgetsSize = 4096
+)
+
+// retrier allows a request to be retried within the policy of its revision.
+type retrier struct {
+	attempts int
+	budget   *retryBudget
+}
+
+// allow returns whether the given attempt may be retried if it fails, which
+// spends the budget until refunded.
+func (r *retrier) allow(attempt int) bool {
+	return attempt < r.attempts && r.budget.withdraw()
+}
+
+// retryBudget bounds the retries of a revision to a ratio of its requests.
+type retryBudget struct {
+	mu     sync.Mutex
+	ratio  float64
+	tokens float64
+}
+
+func newRetryBudget(percent int) *retryBudget {
+	return &retryBudget{
+		ratio:  float64(percent) / 100,
+		tokens: retryBudgetBurst,
+	}
+}
+
+// deposit pays for the retries of a request.
+func (b *retryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+b.ratio, retryBudgetBurst)
+}
+
+// withdraw spends the budget for a retry, if there is enough.
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refund returns the budget for a retry that wasn't needed.
+func (b *retryBudget) refund() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.tokens+1, retryBudgetBurst)
+}
+
+// retrier returns the retrier of the request, or nil if the policy of its
+// revision doesn't allow retrying it.
+func (a *activationHandler) retrier(r *http.Request, revID types.NamespacedName) *retrier {
+	rev := RevisionFrom(r.Context())
+	if rev == nil {
+		return nil
+	}
+	attempts, methods, percent := rev.GetActivatorRetryPolicy()
+	if attempts <= 1 || (methods != serving.ActivatorRetryMethodsAll && !isIdempotent(r.Method)) {
+		return nil
+	}
+
+	var budget *retryBudget
+	if b, ok := a.retryBudgets.Get(revID); ok {
+		budget = b.(*retryBudget)
+	} else {
+		budget = newRetryBudget(percent)
+		a.retryBudgets.Add(revID, budget)
+	}
+	budget.deposit()
+
+	if !replayable(r) {
+		return nil
+	}
+	return &retrier{
+		attempts: attempts,
+		budget:   budget,
+	}
+}
+
+// isIdempotent returns whether the method is idempotent, per RFC 9110.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// replayable buffers the body of the request, so that GetBody replays it, and
+// returns true unless the body is larger than retryBodyLimit.
+func replayable(r *http.Request) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+	if r.ContentLength > retryBodyLimit {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, retryBodyLimit+1))
+	if err != nil || len(body) > retryBodyLimit {
+		// Put back what was read for the only attempt.
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+		return false
+	}
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	r.Body, _ = r.GetBody()
+	return true
+}
+
+// retryWriter holds back the 502 and 503 responses of an attempt that is
+// going to be retried, and passes any other response through.
+type retryWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+	// failed is set once the attempt failed with a response held back.
+	failed      bool
+	wroteHeader bool
+}
+
+func newRetryWriter(w http.ResponseWriter) *retryWriter {
+	return &retryWriter{
+		w:      w,
+		header: make(http.Header),
+	}
+}
+
+// Header implements http.ResponseWriter.
+func (rw *retryWriter) Header() http.Header {
+	if rw.wroteHeader && !rw.failed {
+		// For the trailers.
+		return rw.w.Header()
+	}
+	return rw.header
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (rw *retryWriter) WriteHeader(code int) {
+	if rw.wroteHeader {
+		return
+	}
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		// Informational responses precede the final one.
+		dst := rw.w.Header()
+		for k, v := range rw.header {
+			dst[k] = v
+		}
+		rw.w.WriteHeader(code)
+		for k := range rw.header {
+			dst.Del(k)
+		}
+		return
+	}
+	rw.wroteHeader = true
+	if code == http.StatusBadGateway || code == http.StatusServiceUnavailable {
+		rw.failed = true
+		return
+	}
+	dst := rw.w.Header()
+	for k, v := range rw.header {
+		dst[k] = v
+	}
+	rw.w.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (rw *retryWriter) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	if rw.failed {
+		return len(b), nil
+	}
+	return rw.w.Write(b)
+}
+
+// Flush implements http.Flusher.
+func (rw *retryWriter) Flush() {
+	if !rw.wroteHeader || rw.failed {
+		return
+	}
+	if f, ok := rw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, for the upgraded connections, whose
+// responses pass through.
+func (rw *retryWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	rw.wroteHeader = true
+	return websocket.HijackIfPossible(rw.w)
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (rw *retryWriter) Unwrap() http.ResponseWriter {
+	return rw.w
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/types"
+	pkgnet "knative.dev/pkg/network"
+	rtesting "knative.dev/pkg/reconciler/testing"
+
+	"knative.dev/pkg/logging"
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+// sequenceThrottler hands out a different dest to every attempt.
+type sequenceThrottler struct {
+	dests []string
+	tries int
+}
+
+func (st *sequenceThrottler) Try(_ context.Context, _ types.NamespacedName, f func(string) error) error {
+	dest := st.dests[st.tries%len(st.dests)]
+	st.tries++
+	return f(dest)
+}
+
+func TestActivationHandlerRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		annos     map[string]string
+		codes     map[string]int
+		wantCode  int
+		wantTries int
+	}{{
+		name:      "no policy",
+		method:    http.MethodGet,
+		codes:     map[string]int{"10.0.0.1:8012": http.StatusServiceUnavailable},
+		wantCode:  http.StatusServiceUnavailable,
+		wantTries: 1,
+	}, {
+		name:   "retried on another pod",
+		method: http.MethodGet,
+		annos: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "3",
+		},
+		codes:     map[string]int{"10.0.0.1:8012": http.StatusServiceUnavailable},
+		wantCode:  http.StatusOK,
+		wantTries: 2,
+	}, {
+		name:   "attempts exhausted",
+		method: http.MethodGet,
+		annos: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "2",
+		},
+		codes: map[string]int{
+			"10.0.0.1:8012": http.StatusBadGateway,
+			"10.0.0.2:8012": http.StatusServiceUnavailable,
+		},
+		wantCode:  http.StatusServiceUnavailable,
+		wantTries: 2,
+	}, {
+		name:   "non-idempotent not retried",
+		method: http.MethodPost,
+		annos: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "3",
+		},
+		codes:     map[string]int{"10.0.0.1:8012": http.StatusServiceUnavailable},
+		wantCode:  http.StatusServiceUnavailable,
+		wantTries: 1,
+	}, {
+		name:   "non-idempotent retried",
+		method: http.MethodPost,
+		annos: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "3",
+			serving.ActivatorRetryMethodsAnnotationKey:  serving.ActivatorRetryMethodsAll,
+		},
+		codes:     map[string]int{"10.0.0.1:8012": http.StatusServiceUnavailable},
+		wantCode:  http.StatusOK,
+		wantTries: 2,
+	}, {
+		name:   "other errors not retried",
+		method: http.MethodGet,
+		annos: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "3",
+		},
+		codes:     map[string]int{"10.0.0.1:8012": http.StatusInternalServerError},
+		wantCode:  http.StatusInternalServerError,
+		wantTries: 1,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var bodies []string
+			rt := pkgnet.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				code, ok := test.codes[r.URL.Host]
+				if !ok {
+					code = http.StatusOK
+				}
+				return &http.Response{
+					StatusCode: code,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader(http.StatusText(code))),
+				}, nil
+			})
+			throttler := &sequenceThrottler{dests: []string{"10.0.0.1:8012", "10.0.0.2:8012", "10.0.0.3:8012"}}
+
+			ctx, cancel, _ := rtesting.SetupFakeContextWithCancel(t)
+			defer cancel()
+			handler := New(ctx, throttler, rt, false /*usePassthroughLb*/, logging.FromContext(ctx), false /* TLS */)
+
+			rev := revision(testNamespace, testRevName)
+			rev.Annotations = test.annos
+			configStore := setupConfigStore(t, logging.FromContext(ctx))
+			req := httptest.NewRequest(test.method, "http://example.com", strings.NewReader(wantBody))
+			ctx = configStore.ToContext(req.Context())
+			ctx = WithRevisionAndID(ctx, rev, types.NamespacedName{Namespace: testNamespace, Name: testRevName})
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req.WithContext(ctx))
+
+			if resp.Code != test.wantCode {
+				t.Errorf("Response status = %d, want: %d", resp.Code, test.wantCode)
+			}
+			if got, want := resp.Body.String(), http.StatusText(test.wantCode); got != want {
+				t.Errorf("Response body = %q, want: %q", got, want)
+			}
+			if throttler.tries != test.wantTries {
+				t.Errorf("Tries = %d, want: %d", throttler.tries, test.wantTries)
+			}
+			for i, body := range bodies {
+				if body != wantBody {
+					t.Errorf("Body of attempt %d = %q, want: %q", i+1, body, wantBody)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	b := newRetryBudget(50)
+	for i := 0; i < retryBudgetBurst; i++ {
+		if !b.withdraw() {
+			t.Fatalf("withdraw() #%d = false, want: true", i+1)
+		}
+	}
+	if b.withdraw() {
+		t.Fatal("withdraw() = true with an empty budget")
+	}
+
+	// Every request pays for half a retry.
+	b.deposit()
+	if b.withdraw() {
+		t.Error("withdraw() = true after a single deposit")
+	}
+	b.deposit()
+	if !b.withdraw() {
+		t.Error("withdraw() = false after two deposits")
+	}
+
+	b.refund()
+	if !b.withdraw() {
+		t.Error("withdraw() = false after a refund")
+	}
+
+	// The budget doesn't save up more than the burst.
+	for i := 0; i < 10*retryBudgetBurst; i++ {
+		b.refund()
+	}
+	for i := 0; i < retryBudgetBurst; i++ {
+		b.withdraw()
+	}
+	if b.withdraw() {
+		t.Error("withdraw() = true beyond the burst")
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	for method, want := range map[string]bool{
+		http.MethodGet:     true,
+		http.MethodHead:    true,
+		http.MethodPut:     true,
+		http.MethodDelete:  true,
+		http.MethodOptions: true,
+		http.MethodPost:    false,
+		http.MethodPatch:   false,
+		http.MethodConnect: false,
+	} {
+		if got := isIdempotent(method); got != want {
+			t.Errorf("isIdempotent(%s) = %v, want: %v", method, got, want)
+		}
+	}
+}
+
+func TestReplayable(t *testing.T) {
+	small := strings.Repeat("a", 1024)
+	large := strings.Repeat("b", retryBodyLimit+1)
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		want bool
+	}{{
+		name: "no body",
+		req: func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+		},
+		want: true,
+	}, {
+		name: "small body",
+		req: func() *http.Request {
+			return httptest.NewRequest(http.MethodPut, "http://example.com", strings.NewReader(small))
+		},
+		want: true,
+	}, {
+		name: "large body",
+		req: func() *http.Request {
+			return httptest.NewRequest(http.MethodPut, "http://example.com", strings.NewReader(large))
+		},
+	}, {
+		name: "large body of unknown length",
+		req: func() *http.Request {
+			r := httptest.NewRequest(http.MethodPut, "http://example.com", strings.NewReader(large))
+			r.ContentLength = -1
+			return r
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := test.req()
+			var want bytes.Buffer
+			if r.Body != nil && r.Body != http.NoBody {
+				want.WriteString(map[bool]string{true: small, false: large}[test.want])
+			}
+
+			if got := replayable(r); got != test.want {
+				t.Fatalf("replayable() = %v, want: %v", got, test.want)
+			}
+
+			// The body must be intact either way.
+			if r.Body != nil {
+				got, _ := io.ReadAll(r.Body)
+				if !bytes.Equal(got, want.Bytes()) {
+					t.Errorf("Body has %d bytes, want: %d", len(got), want.Len())
+				}
+			}
+			if test.want && r.GetBody != nil {
+				body, _ := r.GetBody()
+				got, _ := io.ReadAll(body)
+				if !bytes.Equal(got, want.Bytes()) {
+					t.Errorf("Replayed body has %d bytes, want: %d", len(got), want.Len())
+				}
+			}
+		})
+	}
+}
+
+func TestRetryWriter(t *testing.T) {
+	for _, code := range []int{http.StatusBadGateway, http.StatusServiceUnavailable} {
+		w := httptest.NewRecorder()
+		rw := newRetryWriter(w)
+		rw.Header().Set("X-Failed", "true")
+		rw.WriteHeader(code)
+		rw.Write([]byte("failed"))
+		rw.Flush()
+
+		if !rw.failed {
+			t.Errorf("failed = false after %d", code)
+		}
+		if w.Code != http.StatusOK || w.Body.Len() != 0 || w.Flushed || w.Header().Get("X-Failed") != "" {
+			t.Errorf("Response of %d was not held back", code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	rw := newRetryWriter(w)
+	rw.Header().Set("X-Passed", "true")
+	rw.WriteHeader(http.StatusAccepted)
+	rw.Write([]byte(wantBody))
+
+	if rw.failed {
+		t.Error("failed = true after 202")
+	}
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Response status = %d, want: %d", w.Code, http.StatusAccepted)
+	}
+	if got := w.Body.String(); got != wantBody {
+		t.Errorf("Response body = %q, want: %q", got, wantBody)
+	}
+	if got := w.Header().Get("X-Passed"); got != "true" {
+		t.Errorf("X-Passed = %q, want: true", got)
+	}
+}
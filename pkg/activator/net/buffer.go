@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package net
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/atomic"
+
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+var (
+	// ErrBufferFull indicates the activator buffers too many requests, or
+	// request bytes, to buffer another request.
+	ErrBufferFull = errors.New("activator request buffer full")
+
+	// ErrBufferThrottled is the ErrBufferFull of revisions that throttle the
+	// requests beyond their buffering limits.
+	ErrBufferThrottled = fmt.Errorf("%w, throttling", ErrBufferFull)
+)
+
+// spillQueueRatio is the ratio of the buffering request limit of a revision to
+// the depth of its spill queue.
+const spillQueueRatio = 10
+
+// BufferLimits bounds the requests the activator buffers while they wait for
+// capacity.
+type BufferLimits struct {
+	// MaxRequests is the most requests buffered, if positive.
+	MaxRequests int
+	// MaxBytes is the most request body bytes buffered, if positive, as
+	// declared by the Content-Length of the requests.
+	MaxBytes int64
+}
+
+// bufferLimiter enforces BufferLimits.
+type bufferLimiter struct {
+	limits   BufferLimits
+	requests atomic.Int64
+	bytes    atomic.Int64
+	// err is returned for the requests beyond the limits.
+	err error
+
+	// spill holds a token for every request waiting for room in the buffer,
+	// if the limiter spills. freed wakes the waiting requests up when room
+	// is freed.
+	spill chan struct{}
+	freed chan struct{}
+}
+
+// newBufferLimiter creates a bufferLimiter, or returns nil if the limits are
+// unbounded.
+func newBufferLimiter(limits BufferLimits) *bufferLimiter {
+	if limits.MaxRequests <= 0 && limits.MaxBytes <= 0 {
+		return nil
+	}
+	return &bufferLimiter{
+		limits: limits,
+		err:    ErrBufferFull,
+	}
+}
+
+// newRevisionBufferLimiter creates the bufferLimiter of a revision with the
+// given overflow behaviour, or returns nil if the limits are unbounded.
+func newRevisionBufferLimiter(limits BufferLimits, overflow string) *bufferLimiter {
+	b := newBufferLimiter(limits)
+	if b == nil {
+		return nil
+	}
+	switch overflow {
+	case serving.ActivatorBufferOverflowThrottle:
+		b.err = ErrBufferThrottled
+	case serving.ActivatorBufferOverflowSpill:
+		if limits.MaxRequests > 0 {
+			depth := max(limits.MaxRequests/spillQueueRatio, 1)
+			b.spill = make(chan struct{}, depth)
+			b.freed = make(chan struct{}, depth)
+		}
+	}
+	return b
+}
+
+// tryAcquire buffers a request with a body of the given size, if the limits
+// allow it.
+func (b *bufferLimiter) tryAcquire(bytes int64) bool {
+	if n := b.requests.Inc(); b.limits.MaxRequests > 0 && n > int64(b.limits.MaxRequests) {
+		b.requests.Dec()
+		return false
+	}
+	if n := b.bytes.Add(bytes); b.limits.MaxBytes > 0 && n > b.limits.MaxBytes {
+		b.bytes.Sub(bytes)
+		b.requests.Dec()
+		return false
+	}
+	return true
+}
+
+// acquire buffers a request with a body of the given size. If the limits don't
+// allow it, the request waits for room in the spill queue, if there is one, or
+// fails.
+func (b *bufferLimiter) acquire(ctx context.Context, bytes int64) error {
+	if b.tryAcquire(bytes) {
+		return nil
+	}
+	if b.spill == nil {
+		return b.err
+	}
+	select {
+	case b.spill <- struct{}{}:
+	default:
+		return b.err
+	}
+	defer func() { <-b.spill }()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-b.freed:
+		}
+		if b.tryAcquire(bytes) {
+			return nil
+		}
+	}
+}
+
+// release unbuffers a request with a body of the given size.
+func (b *bufferLimiter) release(bytes int64) {
+	b.bytes.Sub(bytes)
+	b.requests.Dec()
+	if b.freed != nil {
+		select {
+		case b.freed <- struct{}{}:
+		default:
+			// As many wakeups as waiting requests are pending already.
+		}
+	}
+}
+
+// buffered returns the number of requests and request body bytes buffered.
+func (b *bufferLimiter) buffered() (int64, int64) {
+	return b.requests.Load(), b.bytes.Load()
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package net
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	pkgnet "knative.dev/networking/pkg/apis/networking"
+	. "knative.dev/pkg/logging/testing"
+	"knative.dev/serving/pkg/apis/serving"
+	"knative.dev/serving/pkg/queue"
+)
+
+func TestBufferLimiter(t *testing.T) {
+	if b := newBufferLimiter(BufferLimits{}); b != nil {
+		t.Errorf("newBufferLimiter() = %#v, want nil", b)
+	}
+
+	tests := []struct {
+		name   string
+		limits BufferLimits
+		// sizes are the body sizes of the requests buffered in turn.
+		sizes []int64
+		want  []bool
+	}{{
+		name:   "requests",
+		limits: BufferLimits{MaxRequests: 2},
+		sizes:  []int64{100, 100, 0},
+		want:   []bool{true, true, false},
+	}, {
+		name:   "bytes",
+		limits: BufferLimits{MaxBytes: 150},
+		sizes:  []int64{100, 100, 50, 0},
+		want:   []bool{true, false, true, true},
+	}, {
+		name:   "both",
+		limits: BufferLimits{MaxRequests: 2, MaxBytes: 150},
+		sizes:  []int64{100, 100, 0, 0},
+		want:   []bool{true, false, true, false},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := newBufferLimiter(test.limits)
+			var wantRequests, wantBytes int64
+			for i, size := range test.sizes {
+				if got := b.tryAcquire(size); got != test.want[i] {
+					t.Errorf("tryAcquire(%d) #%d = %v, want %v", size, i, got, test.want[i])
+				}
+				if test.want[i] {
+					wantRequests++
+					wantBytes += size
+				}
+			}
+			if requests, bytes := b.buffered(); requests != wantRequests || bytes != wantBytes {
+				t.Errorf("buffered() = %d, %d, want %d, %d", requests, bytes, wantRequests, wantBytes)
+			}
+		})
+	}
+}
+
+func TestRevisionBufferLimiterOverflow(t *testing.T) {
+	limits := BufferLimits{MaxRequests: 1}
+
+	b := newRevisionBufferLimiter(limits, serving.ActivatorBufferOverflowReject)
+	b.tryAcquire(0)
+	if err := b.acquire(context.Background(), 0); !errors.Is(err, ErrBufferFull) || errors.Is(err, ErrBufferThrottled) {
+		t.Errorf("acquire() = %v, want %v", err, ErrBufferFull)
+	}
+
+	b = newRevisionBufferLimiter(limits, serving.ActivatorBufferOverflowThrottle)
+	b.tryAcquire(0)
+	if err := b.acquire(context.Background(), 0); !errors.Is(err, ErrBufferThrottled) || !errors.Is(err, ErrBufferFull) {
+		t.Errorf("acquire() = %v, want %v", err, ErrBufferThrottled)
+	}
+}
+
+func TestRevisionBufferLimiterSpill(t *testing.T) {
+	// The spill queue holds a tenth of the requests, at least one.
+	b := newRevisionBufferLimiter(BufferLimits{MaxRequests: 1}, serving.ActivatorBufferOverflowSpill)
+	b.tryAcquire(0)
+
+	spilled := make(chan error)
+	go func() {
+		spilled <- b.acquire(context.Background(), 0)
+	}()
+	// Wait for the request to spill.
+	if err := wait.PollUntilContextTimeout(context.Background(), time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		return len(b.spill) == 1, nil
+	}); err != nil {
+		t.Fatal("The request didn't spill:", err)
+	}
+
+	// The spill queue is full.
+	if err := b.acquire(context.Background(), 0); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("acquire() = %v, want %v", err, ErrBufferFull)
+	}
+
+	b.release(0)
+	select {
+	case err := <-spilled:
+		if err != nil {
+			t.Error("Spilled acquire() =", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("The spilled request wasn't buffered")
+	}
+	if requests, _ := b.buffered(); requests != 1 {
+		t.Errorf("buffered() = %d requests, want 1", requests)
+	}
+
+	// Requests stop waiting in the spill queue with their context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.acquire(ctx, 0); !errors.Is(err, context.Canceled) {
+		t.Errorf("acquire() = %v, want %v", err, context.Canceled)
+	}
+	if got := len(b.spill); got != 0 {
+		t.Errorf("Spilled requests = %d, want 0", got)
+	}
+}
+
+func TestRevisionThrottlerBuffer(t *testing.T) {
+	// Without capacity all requests are buffered.
+	rt := newRevisionThrottler(types.NamespacedName{Namespace: testNamespace, Name: testRevision}, 1, /*cc*/
+		pkgnet.ServicePortNameHTTP1, queue.BreakerParams{QueueDepth: 10, MaxConcurrency: 10}, nil, TestLogger(t))
+	rt.buffer = newRevisionBufferLimiter(BufferLimits{MaxRequests: 2}, serving.ActivatorBufferOverflowThrottle)
+	rt.globalBuffer = newBufferLimiter(BufferLimits{MaxBytes: 100})
+
+	withBody := func(ctx context.Context, size int) context.Context {
+		return WithRequest(ctx, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", size))))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	buffered := make(chan error)
+	go func() {
+		buffered <- rt.try(withBody(ctx, 60), func(string) error { return nil })
+	}()
+	if err := wait.PollUntilContextTimeout(context.Background(), time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		requests, _ := rt.buffer.buffered()
+		return requests == 1, nil
+	}); err != nil {
+		t.Fatal("The request wasn't buffered:", err)
+	}
+
+	// The activator buffers too many bytes across revisions.
+	if err := rt.try(withBody(context.Background(), 60), func(string) error { return nil }); !errors.Is(err, ErrBufferFull) || errors.Is(err, ErrBufferThrottled) {
+		t.Errorf("try() = %v, want %v", err, ErrBufferFull)
+	}
+	// Requests failing the global limit don't count towards the revision's.
+	if requests, bytes := rt.buffer.buffered(); requests != 1 || bytes != 60 {
+		t.Errorf("buffered() = %d, %d, want 1, 60", requests, bytes)
+	}
+
+	cancel()
+	if err := <-buffered; !errors.Is(err, context.Canceled) {
+		t.Errorf("try() = %v, want %v", err, context.Canceled)
+	}
+	for _, b := range []*bufferLimiter{rt.buffer, rt.globalBuffer} {
+		if requests, bytes := b.buffered(); requests != 0 || bytes != 0 {
+			t.Errorf("buffered() = %d, %d after the request was cancelled, want 0, 0", requests, bytes)
+		}
+	}
+
+	// Requests that get capacity but no pod are enqueued again and stay buffered.
+	rt.breaker.UpdateConcurrency(1)
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		buffered <- rt.try(withBody(ctx, 60), func(string) error { return nil })
+	}()
+	if err := wait.PollUntilContextTimeout(context.Background(), time.Millisecond, 10*time.Second, true, func(context.Context) (bool, error) {
+		requests, _ := rt.buffer.buffered()
+		return requests == 1, nil
+	}); err != nil {
+		t.Fatal("The request wasn't buffered:", err)
+	}
+	for i := 0; i < 10; i++ {
+		if requests, bytes := rt.buffer.buffered(); requests != 1 || bytes != 60 {
+			t.Fatalf("buffered() = %d, %d while reenqueued, want 1, 60", requests, bytes)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	rt.breaker.UpdateConcurrency(0)
+	cancel()
+	if err := <-buffered; !errors.Is(err, context.Canceled) {
+		t.Errorf("try() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -20,10 +20,22 @@ package net
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
 	"math/rand"
+	"net/http"
+	"slices"
 	"sync"
+	"time"
+
+	"knative.dev/serving/pkg/apis/serving"
 )
 
+// ewmaLatencyWeight is the weight of the latest observation in the moving
+// average of the request latency of a pod.
+const ewmaLatencyWeight = 0.3
+
 // lbPolicy is a functor that selects a target pod from the list, or (noop, nil) if
 // no such target can be currently acquired.
 // Policies will presume that `targets` list is appropriately guarded by the caller,
@@ -31,6 +43,91 @@ import (
 // and pointers therein are immutable.
 type lbPolicy func(ctx context.Context, targets []*podTracker) (func(), *podTracker)
 
+type requestKey struct{}
+
+// WithRequest attaches the request being throttled to the context, for the LB
+// policies that pick the target based on the request and for the buffering
+// limits, which account for its body.
+func WithRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+func requestFrom(ctx context.Context) *http.Request {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(requestKey{}).(*http.Request)
+	return r
+}
+
+type excludedDestsKey struct{}
+
+// WithExcludedDest attaches a dest the request must not be sent to, if there
+// is another one with capacity, to the context, e.g. for retrying a request
+// that failed on it.
+func WithExcludedDest(ctx context.Context, dest string) context.Context {
+	excluded := excludedDestsFrom(ctx)
+	return context.WithValue(ctx, excludedDestsKey{}, append(excluded[:len(excluded):len(excluded)], dest))
+}
+
+func excludedDestsFrom(ctx context.Context) []string {
+	excluded, _ := ctx.Value(excludedDestsKey{}).([]string)
+	return excluded
+}
+
+// withoutDests returns the targets other than the given dests.
+func withoutDests(targets []*podTracker, dests []string) []*podTracker {
+	ret := make([]*podTracker, 0, len(targets))
+	for _, t := range targets {
+		if !slices.Contains(dests, t.dest) {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+// defaultLBPolicy returns the LB policy for revisions that don't select one.
+func defaultLBPolicy(containerConcurrency int) lbPolicy {
+	switch {
+	case containerConcurrency == 0:
+		return randomChoice2Policy
+	case containerConcurrency <= 3:
+		// For very low CC values use first available pod.
+		return firstAvailableLBPolicy
+	default:
+		// Otherwise RR.
+		return newRoundRobinPolicy()
+	}
+}
+
+// newLBPolicy returns the LB policy with the given name. Either the header or
+// the cookie is the request attribute the consistent hash policy hashes on.
+func newLBPolicy(name string, containerConcurrency int, header, cookie string) (lbPolicy, error) {
+	switch name {
+	case serving.LoadBalancingPolicyRandomChoice2:
+		// The policy doesn't reserve capacity on the targets.
+		if containerConcurrency != 0 {
+			return nil, fmt.Errorf("LB policy %q requires unlimited container concurrency", name)
+		}
+		return randomChoice2Policy, nil
+	case serving.LoadBalancingPolicyFirstAvailable:
+		return firstAvailableLBPolicy, nil
+	case serving.LoadBalancingPolicyRoundRobin:
+		return newRoundRobinPolicy(), nil
+	case serving.LoadBalancingPolicyLeastOutstandingRequests:
+		return leastOutstandingRequestsPolicy, nil
+	case serving.LoadBalancingPolicyEWMALatency:
+		return ewmaLatencyPolicy, nil
+	case serving.LoadBalancingPolicyConsistentHash:
+		if header == "" && cookie == "" {
+			return nil, errors.New("consistent hash LB policy requires a hash key")
+		}
+		return newConsistentHashPolicy(header, cookie, defaultLBPolicy(containerConcurrency)), nil
+	default:
+		return nil, fmt.Errorf("unknown LB policy %q", name)
+	}
+}
+
 // randomLBPolicy is a load balancer policy that picks a random target.
 // This approximates the LB policy done by K8s Service (IPTables based).
 //
@@ -116,3 +213,140 @@ func newRoundRobinPolicy() lbPolicy {
 		return noop, nil
 	}
 }
+
+// reserveFrom reserves the target at index i, or, if it has no capacity left,
+// the first of the following ones that has.
+func reserveFrom(ctx context.Context, targets []*podTracker, i int) (func(), *podTracker) {
+	l := len(targets)
+	for j := 0; j < l; j++ {
+		t := targets[(i+j)%l]
+		if cb, ok := t.Reserve(ctx); ok {
+			return cb, t
+		}
+	}
+	return noop, nil
+}
+
+// leastCost picks the target with the lowest cost that has capacity, and
+// counts the request in the weight of the target while it is in flight.
+func leastCost(ctx context.Context, targets []*podTracker, cost func(*podTracker) float64) (func(), *podTracker) {
+	l := len(targets)
+	if l == 0 {
+		return noop, nil
+	}
+	// Start at a random offset, so that ties are broken randomly.
+	off := rand.Intn(l) //nolint:gosec // We don't need cryptographic randomness here.
+	best, bestCost := off, math.Inf(1)
+	for i := 0; i < l; i++ {
+		p := (off + i) % l
+		if c := cost(targets[p]); c < bestCost {
+			best, bestCost = p, c
+		}
+	}
+	cb, t := reserveFrom(ctx, targets, best)
+	if t == nil {
+		return noop, nil
+	}
+	t.increaseWeight()
+	return func() {
+		t.decreaseWeight()
+		cb()
+	}, t
+}
+
+// leastOutstandingRequestsPolicy is a load balancer policy that picks the
+// target with the fewest requests in flight.
+func leastOutstandingRequestsPolicy(ctx context.Context, targets []*podTracker) (func(), *podTracker) {
+	return leastCost(ctx, targets, outstandingRequests)
+}
+
+func outstandingRequests(t *podTracker) float64 {
+	return float64(t.getWeight())
+}
+
+// ewmaLatencyPolicy is a load balancer policy that picks the target with the
+// lowest moving average of the request latency, scaled by the requests in
+// flight, so that the fastest target doesn't get all the requests.
+func ewmaLatencyPolicy(ctx context.Context, targets []*podTracker) (func(), *podTracker) {
+	cb, t := leastCost(ctx, targets, expectedLatency)
+	if t == nil {
+		return noop, nil
+	}
+	start := time.Now()
+	return func() {
+		t.observeLatency(time.Since(start))
+		cb()
+	}, t
+}
+
+func expectedLatency(t *podTracker) float64 {
+	// Targets without observations yet are presumed to be fast, so
+	// that they get their share of requests.
+	return (t.getLatency() + 1) * float64(t.getWeight()+1)
+}
+
+// newConsistentHashPolicy returns a load balancer policy that picks the target
+// by rendezvous hashing the value of the header or the cookie of the request,
+// so that requests with the same value go to the same target, as long as it
+// exists and has capacity. Requests without the value are balanced by the
+// fallback policy.
+func newConsistentHashPolicy(header, cookie string, fallback lbPolicy) lbPolicy {
+	return func(ctx context.Context, targets []*podTracker) (func(), *podTracker) {
+		key := hashKey(requestFrom(ctx), header, cookie)
+		if key == "" || len(targets) == 0 {
+			return fallback(ctx, targets)
+		}
+		return reserveByHash(ctx, targets, key)
+	}
+}
+
+// reserveByHash reserves the target picked by rendezvous hashing the key, or,
+// if it has no capacity left, the first of the following ones that has.
+func reserveByHash(ctx context.Context, targets []*podTracker, key string) (func(), *podTracker) {
+	// Every target scores the key, and the highest score wins. Unlike
+	// with a modulo, only the keys of a removed target move elsewhere
+	// when the targets change.
+	kh := hashString(key)
+	best, bestScore := 0, uint64(0)
+	for i, t := range targets {
+		if s := mix(kh ^ t.hash); s > bestScore {
+			best, bestScore = i, s
+		}
+	}
+	return reserveFrom(ctx, targets, best)
+}
+
+// hashKey returns the value of the header or the cookie of the request.
+func hashKey(r *http.Request, header, cookie string) string {
+	if r == nil {
+		return ""
+	}
+	if header != "" {
+		return r.Header.Get(header)
+	}
+	if c, err := r.Cookie(cookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+// hashString returns the 64 bit FNV-1a hash of s.
+func hashString(s string) uint64 {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
+
+// mix is the finalizer of SplitMix64, which spreads the bits of x over the
+// whole result.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -19,9 +19,16 @@ package net
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 	"time"
 
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/queue"
 )
 
@@ -260,10 +267,224 @@ func TestRoundRobin(t *testing.T) {
 	})
 }
 
+func TestLeastOutstandingRequests(t *testing.T) {
+	t.Run("no trackers", func(t *testing.T) {
+		if _, pt := leastOutstandingRequestsPolicy(context.Background(), nil); pt != nil {
+			t.Fatalf("Tracker = %v, want: nil", pt)
+		}
+	})
+	t.Run("picks the least loaded", func(t *testing.T) {
+		podTrackers := makeTrackers(3, 0)
+		podTrackers[0].weight.Store(2)
+		podTrackers[2].weight.Store(1)
+		cb, pt := leastOutstandingRequestsPolicy(context.Background(), podTrackers)
+		if got, want := pt, podTrackers[1]; got != want {
+			t.Fatalf("Tracker = %v, want: %v", got, want)
+		}
+		if got, want := pt.getWeight(), int32(1); got != want {
+			t.Errorf("pt.weight = %d, want: %d", got, want)
+		}
+		cb()
+		if got, want := pt.getWeight(), int32(0); got != want {
+			t.Errorf("pt.weight = %d, want: %d", got, want)
+		}
+	})
+	t.Run("spreads the requests", func(t *testing.T) {
+		podTrackers := makeTrackers(3, 0)
+		for i := 0; i < 6; i++ {
+			cb, _ := leastOutstandingRequestsPolicy(context.Background(), podTrackers)
+			t.Cleanup(cb)
+		}
+		for _, pt := range podTrackers {
+			if got, want := pt.getWeight(), int32(2); got != want {
+				t.Errorf("%v weight = %d, want: %d", pt, got, want)
+			}
+		}
+	})
+	t.Run("skips the full trackers", func(t *testing.T) {
+		podTrackers := makeTrackers(2, 1)
+		podTrackers[0].weight.Store(1)
+		podTrackers[1].weight.Store(2)
+		// Fill the least loaded one.
+		cb, _ := podTrackers[0].Reserve(context.Background())
+		t.Cleanup(cb)
+		cb, pt := leastOutstandingRequestsPolicy(context.Background(), podTrackers)
+		t.Cleanup(cb)
+		if got, want := pt, podTrackers[1]; got != want {
+			t.Fatalf("Tracker = %v, want: %v", got, want)
+		}
+		if cb, pt := leastOutstandingRequestsPolicy(context.Background(), podTrackers); pt != nil {
+			cb()
+			t.Fatalf("Tracker = %v, want: nil", pt)
+		}
+	})
+}
+
+func TestEWMALatency(t *testing.T) {
+	t.Run("picks the fastest", func(t *testing.T) {
+		podTrackers := makeTrackers(3, 0)
+		podTrackers[0].observeLatency(100 * time.Millisecond)
+		podTrackers[1].observeLatency(10 * time.Millisecond)
+		podTrackers[2].observeLatency(50 * time.Millisecond)
+		cb, pt := ewmaLatencyPolicy(context.Background(), podTrackers)
+		if got, want := pt, podTrackers[1]; got != want {
+			t.Fatalf("Tracker = %v, want: %v", got, want)
+		}
+		cb()
+		if got := pt.getLatency(); got >= float64(10*time.Millisecond) {
+			t.Errorf("latency = %v, want it to decrease", time.Duration(got))
+		}
+	})
+	t.Run("weighs in the outstanding requests", func(t *testing.T) {
+		podTrackers := makeTrackers(2, 0)
+		podTrackers[0].observeLatency(10 * time.Millisecond)
+		podTrackers[0].weight.Store(9)
+		podTrackers[1].observeLatency(50 * time.Millisecond)
+		cb, pt := ewmaLatencyPolicy(context.Background(), podTrackers)
+		t.Cleanup(cb)
+		if got, want := pt, podTrackers[1]; got != want {
+			t.Fatalf("Tracker = %v, want: %v", got, want)
+		}
+	})
+	t.Run("moving average", func(t *testing.T) {
+		pt := newPodTracker("a", nil)
+		pt.observeLatency(100 * time.Millisecond)
+		if got, want := pt.getLatency(), float64(100*time.Millisecond); got != want {
+			t.Errorf("latency = %v, want: %v", time.Duration(got), time.Duration(want))
+		}
+		pt.observeLatency(200 * time.Millisecond)
+		if got, want := pt.getLatency(), float64(130*time.Millisecond); math.Abs(got-want) > 1 {
+			t.Errorf("latency = %v, want: %v", time.Duration(got), time.Duration(want))
+		}
+	})
+}
+
+func TestConsistentHash(t *testing.T) {
+	withHeader := func(v string) context.Context {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-User", v)
+		return WithRequest(context.Background(), r)
+	}
+	withCookie := func(v string) context.Context {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: "session", Value: v})
+		return WithRequest(context.Background(), r)
+	}
+
+	t.Run("same key, same target", func(t *testing.T) {
+		podTrackers := makeTrackers(10, 0)
+		p := newConsistentHashPolicy("X-User", "", firstAvailableLBPolicy)
+		_, want := p(withHeader("alice"), podTrackers)
+		for i := 0; i < 10; i++ {
+			if _, got := p(withHeader("alice"), podTrackers); got != want {
+				t.Fatalf("Tracker = %v, want: %v", got, want)
+			}
+		}
+	})
+	t.Run("keys are spread", func(t *testing.T) {
+		podTrackers := makeTrackers(3, 0)
+		p := newConsistentHashPolicy("", "session", firstAvailableLBPolicy)
+		picked := sets.New[string]()
+		for i := 0; i < 100; i++ {
+			_, pt := p(withCookie(strconv.Itoa(i)), podTrackers)
+			picked.Insert(pt.dest)
+		}
+		if got, want := picked.Len(), 3; got != want {
+			t.Errorf("#targets = %d, want: %d", got, want)
+		}
+	})
+	t.Run("only keys of removed targets move", func(t *testing.T) {
+		podTrackers := makeTrackers(5, 0)
+		p := newConsistentHashPolicy("X-User", "", firstAvailableLBPolicy)
+		for i := 0; i < 100; i++ {
+			ctx := withHeader(strconv.Itoa(i))
+			_, before := p(ctx, podTrackers)
+			_, after := p(ctx, podTrackers[:4])
+			if before != podTrackers[4] && after != before {
+				t.Errorf("Key %d moved from %v to %v", i, before, after)
+			}
+		}
+	})
+	t.Run("falls over when the target is full", func(t *testing.T) {
+		podTrackers := makeTrackers(3, 1)
+		p := newConsistentHashPolicy("X-User", "", firstAvailableLBPolicy)
+		cb, first := p(withHeader("alice"), podTrackers)
+		t.Cleanup(cb)
+		cb, second := p(withHeader("alice"), podTrackers)
+		t.Cleanup(cb)
+		if second == nil || second == first {
+			t.Errorf("Tracker = %v, want another one than %v", second, first)
+		}
+	})
+	t.Run("no key uses the fallback", func(t *testing.T) {
+		podTrackers := makeTrackers(3, 0)
+		p := newConsistentHashPolicy("X-User", "", firstAvailableLBPolicy)
+		for _, ctx := range []context.Context{context.Background(), withHeader(""), withCookie("alice")} {
+			if _, got := p(ctx, podTrackers); got != podTrackers[0] {
+				t.Errorf("Tracker = %v, want: %v", got, podTrackers[0])
+			}
+		}
+	})
+}
+
+func TestNewLBPolicy(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		cc      int
+		header  string
+		wantErr bool
+	}{{
+		name: serving.LoadBalancingPolicyRandomChoice2,
+	}, {
+		name:    serving.LoadBalancingPolicyRandomChoice2,
+		cc:      10,
+		wantErr: true,
+	}, {
+		name: serving.LoadBalancingPolicyFirstAvailable,
+		cc:   10,
+	}, {
+		name: serving.LoadBalancingPolicyRoundRobin,
+		cc:   10,
+	}, {
+		name: serving.LoadBalancingPolicyLeastOutstandingRequests,
+	}, {
+		name: serving.LoadBalancingPolicyEWMALatency,
+	}, {
+		name:   serving.LoadBalancingPolicyConsistentHash,
+		header: "X-User",
+	}, {
+		name:    serving.LoadBalancingPolicyConsistentHash,
+		wantErr: true,
+	}, {
+		name:    "random",
+		wantErr: true,
+	}} {
+		t.Run(fmt.Sprintf("%s-cc-%d", test.name, test.cc), func(t *testing.T) {
+			p, err := newLBPolicy(test.name, test.cc, test.header, "")
+			if (err != nil) != test.wantErr {
+				t.Fatalf("newLBPolicy() = %v, wantErr: %v", err, test.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			cb, pt := p(context.Background(), makeTrackers(3, test.cc))
+			cb()
+			if pt == nil {
+				t.Error("Policy picked no tracker")
+			}
+		})
+	}
+}
+
 func BenchmarkPolicy(b *testing.B) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-User", "alice")
+	hashCtx := WithRequest(context.Background(), r)
+
 	for _, test := range []struct {
 		name   string
 		policy lbPolicy
+		ctx    context.Context
 	}{{
 		name:   "random",
 		policy: randomLBPolicy,
@@ -276,12 +497,22 @@ func BenchmarkPolicy(b *testing.B) {
 	}, {
 		name:   "round-robin",
 		policy: newRoundRobinPolicy(),
+	}, {
+		name:   "least-outstanding-requests",
+		policy: leastOutstandingRequestsPolicy,
+	}, {
+		name:   "ewma-latency",
+		policy: ewmaLatencyPolicy,
+	}, {
+		name:   "consistent-hash",
+		policy: newConsistentHashPolicy("X-User", "", randomChoice2Policy),
+		ctx:    hashCtx,
 	}} {
 		for _, n := range []int{1, 2, 3, 10, 100} {
 			b.Run(fmt.Sprintf("%s-%d-trackers-sequential", test.name, n), func(b *testing.B) {
 				targets := makeTrackers(n, 0)
 				for i := 0; i < b.N; i++ {
-					cb, _ := test.policy(nil, targets)
+					cb, _ := test.policy(test.ctx, targets)
 					cb()
 				}
 			})
@@ -290,7 +521,7 @@ func BenchmarkPolicy(b *testing.B) {
 				targets := makeTrackers(n, 0)
 				b.RunParallel(func(pb *testing.PB) {
 					for pb.Next() {
-						cb, _ := test.policy(nil, targets)
+						cb, _ := test.policy(test.ctx, targets)
 						cb()
 					}
 				})
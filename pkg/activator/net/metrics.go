@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package net
+
+import (
+	pkgmetrics "knative.dev/pkg/metrics"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+var (
+	podEjectionCountM = stats.Int64(
+		"pod_ejection_count",
+		"The number of times Activator ejected a pod of the revision as an outlier",
+		stats.UnitDimensionless)
+	ejectedPodsM = stats.Int64(
+		"ejected_pods",
+		"The number of pods of the revision ejected by Activator as outliers",
+		stats.UnitDimensionless)
+)
+
+func init() {
+	register()
+}
+
+func register() {
+	if err := pkgmetrics.RegisterResourceView(
+		&view.View{
+			Description: "The number of times Activator ejected a pod of the revision as an outlier",
+			Measure:     podEjectionCountM,
+			Aggregation: view.Count(),
+		},
+		&view.View{
+			Description: "The number of pods of the revision ejected by Activator as outliers",
+			Measure:     ejectedPodsM,
+			Aggregation: view.LastValue(),
+		},
+	); err != nil {
+		panic(err)
+	}
+}
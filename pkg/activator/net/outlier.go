@@ -0,0 +1,229 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package net
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	pkgmetrics "knative.dev/pkg/metrics"
+)
+
+// maxEjectionTimeFactor bounds the time a pod is ejected for, as a multiple of
+// the ejection time, however many times in a row it's ejected.
+const maxEjectionTimeFactor = 10
+
+type proxyResultKey struct{}
+
+// ProxyResult is the outcome of proxying a request to a pod, which the outlier
+// detection of the pods is based on.
+type ProxyResult struct {
+	code int
+}
+
+// SetResponseCode reports the code of the response of the pod, or
+// http.StatusBadGateway if the pod couldn't be reached.
+func (r *ProxyResult) SetResponseCode(code int) {
+	r.code = code
+}
+
+// WithProxyResult attaches a ProxyResult to the context, for the function
+// passed to Try to report the outcome of proxying the request.
+func WithProxyResult(ctx context.Context) (context.Context, *ProxyResult) {
+	r := &ProxyResult{}
+	return context.WithValue(ctx, proxyResultKey{}, r), r
+}
+
+func proxyResultFrom(ctx context.Context) *ProxyResult {
+	r, _ := ctx.Value(proxyResultKey{}).(*ProxyResult)
+	return r
+}
+
+// OutlierDetection configures the passive health checking of the pods of a
+// revision, which ejects the pods that fail the requests proxied to them.
+type OutlierDetection struct {
+	// ConsecutiveErrors is the number of connection errors or 5xx responses
+	// in a row after which a pod is ejected.
+	ConsecutiveErrors int
+	// EjectionTime is the time a pod is ejected for the first time. Every
+	// ejection in a row lasts as long again, up to maxEjectionTimeFactor times.
+	EjectionTime time.Duration
+	// MaxEjectionPercent is the percentage of the pods that may be ejected at
+	// once.
+	MaxEjectionPercent int
+}
+
+// outlierDetector ejects the pods of a revisionThrottler, per OutlierDetection.
+type outlierDetector struct {
+	OutlierDetection
+
+	// mu guards the outlier state of the trackers, ejected, reinstating and
+	// stopped.
+	mu sync.Mutex
+	// ejected is the number of ejected pods.
+	ejected int
+	// reinstating holds the timers reinstating the ejected pods.
+	reinstating map[*podTracker]*time.Timer
+	// stopped is set once the revisionThrottler is deleted, after which no
+	// pods are reinstated anymore.
+	stopped bool
+
+	// metricsCtx tags the metrics of the revision.
+	metricsCtx context.Context
+}
+
+// newOutlierDetector creates an outlierDetector, or returns nil if the
+// outlier detection is off.
+func newOutlierDetector(od OutlierDetection, metricsCtx context.Context) *outlierDetector {
+	if od.ConsecutiveErrors <= 0 {
+		return nil
+	}
+	return &outlierDetector{
+		OutlierDetection: od,
+		reinstating:      make(map[*podTracker]*time.Timer),
+		metricsCtx:       metricsCtx,
+	}
+}
+
+// stop stops reinstating the ejected pods.
+func (od *outlierDetector) stop() {
+	od.mu.Lock()
+	defer od.mu.Unlock()
+	od.stopped = true
+	for tracker, timer := range od.reinstating {
+		timer.Stop()
+		delete(od.reinstating, tracker)
+	}
+}
+
+// outlierState is the outlier detection state of a pod, guarded by the
+// outlierDetector.
+type outlierState struct {
+	consecutiveErrors int
+	// ejections is the number of times in a row the pod was ejected.
+	ejections int
+	// reinstated is the time the pod was last reinstated at.
+	reinstated time.Time
+}
+
+// observeResult folds the outcome of a request proxied to the tracker into
+// the outlier detection, and ejects the tracker if it's an outlier.
+func (rt *revisionThrottler) observeResult(tracker *podTracker, code int) {
+	od := rt.outliers
+	if od == nil || code == 0 {
+		return
+	}
+
+	if code < http.StatusInternalServerError {
+		od.mu.Lock()
+		defer od.mu.Unlock()
+		tracker.outlier.consecutiveErrors = 0
+		// The pod has been healthy for long enough to forget its ejections.
+		if tracker.outlier.ejections > 0 && !tracker.ejected.Load() &&
+			time.Since(tracker.outlier.reinstated) > od.EjectionTime {
+			tracker.outlier.ejections = 0
+		}
+		return
+	}
+
+	ejectionTime, ok := func() (time.Duration, bool) {
+		od.mu.Lock()
+		defer od.mu.Unlock()
+		tracker.outlier.consecutiveErrors++
+		if tracker.outlier.consecutiveErrors < od.ConsecutiveErrors || tracker.ejected.Load() {
+			return 0, false
+		}
+		// Nothing is ejected when the cluster IP is used, as no pods are assigned.
+		if total := rt.numAssignedTrackers(); (od.ejected+1)*100 > total*od.MaxEjectionPercent {
+			rt.logger.Debugf("Not ejecting pod %s, %d of %d pods are ejected already", tracker.dest, od.ejected, total)
+			return 0, false
+		}
+		od.ejected++
+		tracker.ejected.Store(true)
+		tracker.outlier.consecutiveErrors = 0
+		tracker.outlier.ejections++
+		return min(time.Duration(tracker.outlier.ejections), maxEjectionTimeFactor) * od.EjectionTime, true
+	}()
+	if !ok {
+		return
+	}
+
+	rt.logger.Infow("Ejecting outlier pod", zap.String("dest", tracker.dest),
+		zap.Int("consecutiveErrors", od.ConsecutiveErrors), zap.Duration("ejectionTime", ejectionTime))
+	pkgmetrics.Record(od.metricsCtx, podEjectionCountM.M(1))
+	rt.reassignTrackers()
+
+	od.mu.Lock()
+	defer od.mu.Unlock()
+	if !od.stopped {
+		od.reinstating[tracker] = time.AfterFunc(ejectionTime, func() { rt.reinstate(tracker) })
+	}
+}
+
+// reinstate returns an ejected tracker to the assigned trackers.
+func (rt *revisionThrottler) reinstate(tracker *podTracker) {
+	od := rt.outliers
+	func() {
+		od.mu.Lock()
+		defer od.mu.Unlock()
+		delete(od.reinstating, tracker)
+		od.ejected--
+		tracker.ejected.Store(false)
+		tracker.outlier.reinstated = time.Now()
+	}()
+
+	rt.logger.Infow("Reinstating ejected pod", zap.String("dest", tracker.dest))
+	rt.reassignTrackers()
+}
+
+// reassignTrackers recomputes the assigned trackers after an ejection.
+func (rt *revisionThrottler) reassignTrackers() {
+	rt.updateMux.Lock()
+	defer rt.updateMux.Unlock()
+	rt.updateCapacity(rt.backendCount)
+	pkgmetrics.Record(rt.outliers.metricsCtx, ejectedPodsM.M(int64(rt.numEjected())))
+}
+
+// numAssignedTrackers returns the number of trackers assigned to this
+// activator, including the ejected ones.
+func (rt *revisionThrottler) numAssignedTrackers() int {
+	rt.mux.RLock()
+	defer rt.mux.RUnlock()
+	return rt.numAssigned
+}
+
+// numEjected returns the number of ejected pods.
+func (rt *revisionThrottler) numEjected() int {
+	rt.outliers.mu.Lock()
+	defer rt.outliers.mu.Unlock()
+	return rt.outliers.ejected
+}
+
+// withoutEjected returns the trackers that aren't ejected.
+func withoutEjected(trackers []*podTracker) []*podTracker {
+	ret := make([]*podTracker, 0, len(trackers))
+	for _, t := range trackers {
+		if !t.ejected.Load() {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
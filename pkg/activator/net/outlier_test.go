@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package net
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	pkgnet "knative.dev/networking/pkg/apis/networking"
+	. "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
+	"knative.dev/serving/pkg/metrics"
+)
+
+func TestOutlierDetection(t *testing.T) {
+	defer resetMetrics()
+	revID := types.NamespacedName{Namespace: testNamespace, Name: testRevision}
+	rt := newRevisionThrottler(revID, 1 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, firstAvailableLBPolicy, TestLogger(t))
+	rt.outliers = newOutlierDetector(OutlierDetection{
+		ConsecutiveErrors:  2,
+		EjectionTime:       100 * time.Millisecond,
+		MaxEjectionPercent: 50,
+	}, metrics.RevisionContext(testNamespace, "", "", testRevision))
+	rt.handleUpdate(revisionDestsUpdate{
+		Rev:   revID,
+		Dests: sets.New("ip0", "ip1", "ip2", "ip3"),
+	})
+
+	// The first available pod gets every request, until it's ejected.
+	try := func(code int) string {
+		t.Helper()
+		ctx, result := WithProxyResult(context.Background())
+		var dest string
+		if err := rt.try(ctx, func(d string) error {
+			dest = d
+			result.SetResponseCode(code)
+			return nil
+		}); err != nil {
+			t.Fatal("try() =", err)
+		}
+		return dest
+	}
+	assertAssigned := func(want sets.Set[string]) {
+		t.Helper()
+		rt.mux.RLock()
+		defer rt.mux.RUnlock()
+		if got := trackerDestSet(rt.assignedTrackers); !got.Equal(want) {
+			t.Errorf("Assigned trackers = %v, want: %v", sets.List(got), sets.List(want))
+		}
+	}
+
+	// Errors that aren't consecutive don't eject the pod.
+	try(http.StatusServiceUnavailable)
+	try(http.StatusOK)
+	try(http.StatusBadGateway)
+	assertAssigned(sets.New("ip0", "ip1", "ip2", "ip3"))
+
+	if got := try(http.StatusInternalServerError); got != "ip0" {
+		t.Errorf("Dest = %s, want: ip0", got)
+	}
+	assertAssigned(sets.New("ip1", "ip2", "ip3"))
+	if got, want := rt.breaker.Capacity(), 3; got != want {
+		t.Errorf("Capacity = %d, want: %d", got, want)
+	}
+
+	try(http.StatusServiceUnavailable)
+	if got := try(http.StatusServiceUnavailable); got != "ip1" {
+		t.Errorf("Dest = %s, want: ip1", got)
+	}
+	assertAssigned(sets.New("ip2", "ip3"))
+	metricstest.AssertMetric(t, metricstest.IntMetric(podEjectionCountM.Name(), 2, nil))
+
+	// No more than half of the pods are ejected.
+	try(http.StatusServiceUnavailable)
+	try(http.StatusServiceUnavailable)
+	assertAssigned(sets.New("ip2", "ip3"))
+
+	waitReinstated := func() {
+		t.Helper()
+		if err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+			if rt.numEjected() > 0 {
+				return false, nil
+			}
+			rt.mux.RLock()
+			defer rt.mux.RUnlock()
+			return len(rt.assignedTrackers) == 4, nil
+		}); err != nil {
+			t.Fatal("The ejected pods were not reinstated:", err)
+		}
+	}
+	waitReinstated()
+	assertAssigned(sets.New("ip0", "ip1", "ip2", "ip3"))
+	if got, want := rt.breaker.Capacity(), 4; got != want {
+		t.Errorf("Capacity = %d, want: %d", got, want)
+	}
+
+	// The pod is ejected for longer when it's ejected again.
+	try(http.StatusServiceUnavailable)
+	try(http.StatusServiceUnavailable)
+	if got, want := rt.podTrackers[0].outlier.ejections, 2; got != want {
+		t.Errorf("Ejections = %d, want: %d", got, want)
+	}
+	waitReinstated()
+
+	// Pods aren't reinstated anymore once the revision throttler is deleted.
+	try(http.StatusServiceUnavailable)
+	try(http.StatusServiceUnavailable)
+	rt.outliers.stop()
+	time.Sleep(4 * 100 * time.Millisecond)
+	if got, want := rt.numEjected(), 1; got != want {
+		t.Errorf("Ejected = %d, want: %d", got, want)
+	}
+}
+
+func TestOutlierDetectionOff(t *testing.T) {
+	revID := types.NamespacedName{Namespace: testNamespace, Name: testRevision}
+	rt := newRevisionThrottler(revID, 1 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, firstAvailableLBPolicy, TestLogger(t))
+	rt.outliers = newOutlierDetector(OutlierDetection{}, context.Background())
+	rt.handleUpdate(revisionDestsUpdate{
+		Rev:   revID,
+		Dests: sets.New("ip0", "ip1"),
+	})
+
+	for i := 0; i < 10; i++ {
+		ctx, result := WithProxyResult(context.Background())
+		rt.try(ctx, func(string) error {
+			result.SetResponseCode(http.StatusServiceUnavailable)
+			return nil
+		})
+	}
+	if got, want := trackerDestSet(rt.assignedTrackers), sets.New("ip0", "ip1"); !got.Equal(want) {
+		t.Errorf("Assigned trackers = %v, want: %v", sets.List(got), sets.List(want))
+	}
+}
+
+func resetMetrics() {
+	metricstest.Unregister(podEjectionCountM.Name(), ejectedPodsM.Name())
+	register()
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"sort"
 	"sync"
+	"time"
 
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
@@ -43,6 +44,7 @@ import (
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
 	servinglisters "knative.dev/serving/pkg/client/listers/serving/v1"
+	"knative.dev/serving/pkg/metrics"
 	"knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/queue"
 )
@@ -65,6 +67,7 @@ func newPodTracker(dest string, b breaker) *podTracker {
 	tracker := &podTracker{
 		dest: dest,
 		b:    b,
+		hash: hashString(dest),
 	}
 	tracker.decreaseWeight = func() { tracker.weight.Add(-1) }
 
@@ -79,6 +82,17 @@ type podTracker struct {
 	weight atomic.Int32
 	// decreaseWeight is an allocation optimization for the randomChoice2 policy.
 	decreaseWeight func()
+
+	// hash is the hash of dest, used by the consistent hash LB policy.
+	hash uint64
+	// latency is the exponentially weighted moving average of the request
+	// latency in nanoseconds, used by the EWMA latency LB policy.
+	latency atomic.Float64
+
+	// ejected is set while the outlier detection ejects the pod, and outlier
+	// is its outlier detection state.
+	ejected atomic.Bool
+	outlier outlierState
 }
 
 func (p *podTracker) increaseWeight() {
@@ -89,6 +103,25 @@ func (p *podTracker) getWeight() int32 {
 	return p.weight.Load()
 }
 
+// observeLatency folds the latency of a request into the moving average.
+func (p *podTracker) observeLatency(d time.Duration) {
+	for {
+		old := p.latency.Load()
+		// Start with the first observation, rather than decaying from zero.
+		new := float64(d)
+		if old != 0 {
+			new = old + ewmaLatencyWeight*(new-old)
+		}
+		if p.latency.CompareAndSwap(old, new) {
+			return
+		}
+	}
+}
+
+func (p *podTracker) getLatency() float64 {
+	return p.latency.Load()
+}
+
 func (p *podTracker) String() string {
 	if p == nil {
 		return "<nil>"
@@ -150,12 +183,23 @@ type revisionThrottler struct {
 	// This is a breaker for the revision as a whole.
 	breaker breaker
 
+	// buffer bounds the requests of the revision waiting for capacity, and
+	// globalBuffer the ones of all revisions, if set.
+	buffer       *bufferLimiter
+	globalBuffer *bufferLimiter
+
+	// outliers ejects the pods that fail the requests, if set.
+	outliers *outlierDetector
+
 	// This will be non-empty when we're able to use pod addressing.
 	podTrackers []*podTracker
 
 	// Effective trackers that are assigned to this Activator.
-	// This is a subset of podTrackers.
+	// This is a subset of podTrackers, without the ejected ones.
 	assignedTrackers []*podTracker
+	// numAssigned is the number of trackers assigned to this Activator,
+	// including the ejected ones.
+	numAssigned int
 
 	// If we don't have a healthy clusterIPTracker this is set to nil, otherwise
 	// it is the l4dest for this revision's private clusterIP.
@@ -165,30 +209,28 @@ type revisionThrottler struct {
 	// request path. This is: trackers, clusterIPDest.
 	mux sync.RWMutex
 
+	// updateMux serializes the updates of the throttler state, which come
+	// from the Throttler's update loop and from the outlier detection.
+	updateMux sync.Mutex
+
 	logger *zap.SugaredLogger
 }
 
+// newRevisionThrottler creates a revisionThrottler using the given LB policy,
+// or, if nil, the default one for the container concurrency.
 func newRevisionThrottler(revID types.NamespacedName,
 	containerConcurrency int, proto string,
-	breakerParams queue.BreakerParams,
+	breakerParams queue.BreakerParams, lbp lbPolicy,
 	logger *zap.SugaredLogger) *revisionThrottler {
 	logger = logger.With(zap.String(logkey.Key, revID.String()))
-	var (
-		revBreaker breaker
-		lbp        lbPolicy
-	)
-	switch {
-	case containerConcurrency == 0:
+	var revBreaker breaker
+	if containerConcurrency == 0 {
 		revBreaker = newInfiniteBreaker(logger)
-		lbp = randomChoice2Policy
-	case containerConcurrency <= 3:
-		// For very low CC values use first available pod.
-		revBreaker = queue.NewBreaker(breakerParams)
-		lbp = firstAvailableLBPolicy
-	default:
-		// Otherwise RR.
+	} else {
 		revBreaker = queue.NewBreaker(breakerParams)
-		lbp = newRoundRobinPolicy()
+	}
+	if lbp == nil {
+		lbp = defaultLBPolicy(containerConcurrency)
 	}
 	return &revisionThrottler{
 		revID:                revID,
@@ -212,10 +254,58 @@ func (rt *revisionThrottler) acquireDest(ctx context.Context) (func(), *podTrack
 	if rt.clusterIPTracker != nil {
 		return noop, rt.clusterIPTracker
 	}
+	if excluded := excludedDestsFrom(ctx); len(excluded) > 0 {
+		if cb, tracker := rt.lbPolicy(ctx, withoutDests(rt.assignedTrackers, excluded)); tracker != nil {
+			return cb, tracker
+		}
+	}
 	return rt.lbPolicy(ctx, rt.assignedTrackers)
 }
 
+// acquireBuffer accounts the request for the buffering limits of the revision
+// and the activator, until the returned function is first called.
+func (rt *revisionThrottler) acquireBuffer(ctx context.Context) (func(), error) {
+	if rt.buffer == nil && rt.globalBuffer == nil {
+		return noop, nil
+	}
+	var bytes int64
+	if r := requestFrom(ctx); r != nil && r.ContentLength > 0 {
+		bytes = r.ContentLength
+	}
+	if rt.buffer != nil {
+		if err := rt.buffer.acquire(ctx, bytes); err != nil {
+			return nil, err
+		}
+	}
+	if rt.globalBuffer != nil && !rt.globalBuffer.tryAcquire(bytes) {
+		if rt.buffer != nil {
+			rt.buffer.release(bytes)
+		}
+		return nil, ErrBufferFull
+	}
+	released := false
+	return func() {
+		if released {
+			return
+		}
+		released = true
+		if rt.buffer != nil {
+			rt.buffer.release(bytes)
+		}
+		if rt.globalBuffer != nil {
+			rt.globalBuffer.release(bytes)
+		}
+	}, nil
+}
+
 func (rt *revisionThrottler) try(ctx context.Context, function func(string) error) error {
+	// The request is buffered until it gets capacity.
+	unbuffer, err := rt.acquireBuffer(ctx)
+	if err != nil {
+		return err
+	}
+	defer unbuffer()
+
 	var ret error
 
 	// Retrying infinitely as long as we receive no dest. Outer semaphore and inner
@@ -228,13 +318,18 @@ func (rt *revisionThrottler) try(ctx context.Context, function func(string) erro
 			cb, tracker := rt.acquireDest(ctx)
 			if tracker == nil {
 				// This can happen if individual requests raced each other or if pod
-				// capacity was decreased after passing the outer semaphore.
+				// capacity was decreased after passing the outer semaphore. The
+				// request stays buffered until it is enqueued again.
 				reenqueue = true
 				return
 			}
 			defer cb()
+			unbuffer()
 			// We already reserved a guaranteed spot. So just execute the passed functor.
 			ret = function(tracker.dest)
+			if result := proxyResultFrom(ctx); result != nil {
+				rt.observeResult(tracker, result.code)
+			}
 		}); err != nil {
 			return err
 		}
@@ -285,21 +380,24 @@ func (rt *revisionThrottler) resetTrackers() {
 
 // updateCapacity updates the capacity of the throttler and recomputes
 // the assigned trackers to the Activator instance.
-// Currently updateCapacity is ensured to be invoked from a single go routine
+// Currently updateCapacity is ensured to be invoked under updateMux
 // and this does not synchronize
 func (rt *revisionThrottler) updateCapacity(backendCount int) {
 	// We have to make assignments on each updateCapacity, since if number
 	// of activators changes, then we need to rebalance the assignedTrackers.
 	ac, ai := int(rt.numActivators.Load()), int(rt.activatorIndex.Load())
-	numTrackers := func() int {
+	numTrackers, allEjected := func() (int, bool) {
 		// We do not have to process the `podTrackers` under lock, since
-		// updateCapacity is guaranteed to be executed by a single goroutine.
+		// updateCapacity is guaranteed to be executed under updateMux.
 		// But `assignedTrackers` is being read by the serving thread, so the
 		// actual assignment has to be done under lock.
 
 		// We're using cluster IP.
 		if rt.clusterIPTracker != nil {
-			return 0
+			rt.mux.Lock()
+			defer rt.mux.Unlock()
+			rt.numAssigned = 0
+			return 0, false
 		}
 
 		// Sort, so we get more or less stable results.
@@ -311,15 +409,24 @@ func (rt *revisionThrottler) updateCapacity(backendCount int) {
 			rt.resetTrackers()
 			assigned = assignSlice(rt.podTrackers, ai, ac, rt.containerConcurrency)
 		}
+		numAssigned := len(assigned)
+		if rt.outliers != nil {
+			assigned = withoutEjected(assigned)
+		}
 		rt.logger.Debugf("Trackers %d/%d: assignment: %v", ai, ac, assigned)
 		// The actual write out of the assigned trackers has to be under lock.
 		rt.mux.Lock()
 		defer rt.mux.Unlock()
 		rt.assignedTrackers = assigned
-		return len(assigned)
+		rt.numAssigned = numAssigned
+		return len(assigned), numAssigned > 0 && len(assigned) == 0
 	}()
 
 	capacity := rt.calculateCapacity(backendCount, numTrackers, ac)
+	if allEjected {
+		// Wait for the ejected trackers to be reinstated.
+		capacity = 0
+	}
 	rt.logger.Infof("Set capacity to %d (backends: %d, index: %d/%d)",
 		capacity, backendCount, ai, ac)
 
@@ -406,6 +513,8 @@ func assignSlice(trackers []*podTracker, selfIndex, numActivators, cc int) []*po
 // This function will never be called in parallel but `try` can be called in parallel to this so we need
 // to lock on updating concurrency / trackers
 func (rt *revisionThrottler) handleUpdate(update revisionDestsUpdate) {
+	rt.updateMux.Lock()
+	defer rt.updateMux.Unlock()
 	rt.logger.Debugw("Handling update",
 		zap.String("ClusterIP", update.ClusterIPDest), zap.Object("dests", logging.StringSet(update.Dests)))
 
@@ -455,10 +564,13 @@ type Throttler struct {
 	ipAddress               string // The IP address of this activator.
 	logger                  *zap.SugaredLogger
 	epsUpdateCh             chan *corev1.Endpoints
+	// buffer bounds the requests of all revisions waiting for capacity, if set.
+	buffer *bufferLimiter
 }
 
-// NewThrottler creates a new Throttler
-func NewThrottler(ctx context.Context, ipAddr string) *Throttler {
+// NewThrottler creates a new Throttler, which buffers requests up to the given
+// limits across all revisions.
+func NewThrottler(ctx context.Context, ipAddr string, bufferLimits BufferLimits) *Throttler {
 	revisionInformer := revisioninformer.Get(ctx)
 	t := &Throttler{
 		revisionThrottlers: make(map[types.NamespacedName]*revisionThrottler),
@@ -466,6 +578,7 @@ func NewThrottler(ctx context.Context, ipAddr string) *Throttler {
 		ipAddress:          ipAddr,
 		logger:             logging.FromContext(ctx),
 		epsUpdateCh:        make(chan *corev1.Endpoints),
+		buffer:             newBufferLimiter(bufferLimits),
 	}
 
 	// Watch revisions to create throttler with backlog immediately and delete
@@ -541,18 +654,57 @@ func (t *Throttler) getOrCreateRevisionThrottler(revID types.NamespacedName) (*r
 		if err != nil {
 			return nil, err
 		}
+		cc := int(rev.Spec.GetContainerConcurrency())
 		revThrottler = newRevisionThrottler(
 			revID,
-			int(rev.Spec.GetContainerConcurrency()),
+			cc,
 			pkgnet.ServicePortName(rev.GetProtocol()),
 			queue.BreakerParams{QueueDepth: breakerQueueDepth, MaxConcurrency: revisionMaxConcurrency},
+			t.revisionLBPolicy(rev, cc),
 			t.logger,
 		)
+		requests, bytes, overflow := rev.GetActivatorBufferLimits()
+		revThrottler.buffer = newRevisionBufferLimiter(BufferLimits{MaxRequests: requests, MaxBytes: bytes}, overflow)
+		revThrottler.globalBuffer = t.buffer
+		consecutiveErrors, ejectionTime, maxEjectionPercent := rev.GetActivatorOutlierDetection()
+		revThrottler.outliers = newOutlierDetector(OutlierDetection{
+			ConsecutiveErrors:  consecutiveErrors,
+			EjectionTime:       ejectionTime,
+			MaxEjectionPercent: maxEjectionPercent,
+		}, metrics.RevisionContext(rev.Namespace, rev.Labels[serving.ServiceLabelKey],
+			rev.Labels[serving.ConfigurationLabelKey], rev.Name))
 		t.revisionThrottlers[revID] = revThrottler
 	}
 	return revThrottler, nil
 }
 
+// revisionLBPolicy returns the LB policy selected by the annotations of the
+// revision, or nil for the default one. The sessions of a revision with
+// session affinity stick to its pods, and the policy balances the requests
+// without a session.
+func (t *Throttler) revisionLBPolicy(rev *v1.Revision, containerConcurrency int) lbPolicy {
+	var lbp lbPolicy
+	if name := rev.GetLoadBalancingPolicy(); name != "" {
+		var header, cookie string
+		if name == serving.LoadBalancingPolicyConsistentHash {
+			header, cookie = rev.GetLoadBalancingHashKey()
+		}
+		var err error
+		lbp, err = newLBPolicy(name, containerConcurrency, header, cookie)
+		if err != nil {
+			t.logger.Warnw("Falling back to the default LB policy", zap.Error(err),
+				zap.String(logkey.Key, types.NamespacedName{Namespace: rev.Namespace, Name: rev.Name}.String()))
+		}
+	}
+	if header, cookie := rev.GetSessionAffinityKey(); header != "" || cookie != "" {
+		if lbp == nil {
+			lbp = defaultLBPolicy(containerConcurrency)
+		}
+		lbp = newConsistentHashPolicy(header, cookie, lbp)
+	}
+	return lbp
+}
+
 // revisionUpdated is used to ensure we have a backlog set up for a revision as soon as it is created
 // rather than erroring with revision not found until a networking probe succeeds
 func (t *Throttler) revisionUpdated(obj interface{}) {
@@ -582,6 +734,9 @@ func (t *Throttler) revisionDeleted(obj interface{}) {
 
 	t.revisionThrottlersMutex.Lock()
 	defer t.revisionThrottlersMutex.Unlock()
+	if rt, ok := t.revisionThrottlers[revID]; ok && rt.outliers != nil {
+		rt.outliers.stop()
+	}
 	delete(t.revisionThrottlers, revID)
 }
 
@@ -620,6 +775,8 @@ func (t *Throttler) handlePubEpsUpdate(eps *corev1.Endpoints) {
 
 func (rt *revisionThrottler) handlePubEpsUpdate(eps *corev1.Endpoints, selfIP string) {
 	// NB: this is guaranteed to be executed on a single thread.
+	rt.updateMux.Lock()
+	defer rt.updateMux.Unlock()
 	epSet := healthyAddresses(eps, rt.protocol)
 	if !epSet.Has(selfIP) {
 		// No need to do anything, this activator is not in path.
@@ -19,6 +19,8 @@ package net
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"sync"
 	"testing"
@@ -59,7 +61,7 @@ type tryResult struct {
 }
 
 func newTestThrottler(ctx context.Context) *Throttler {
-	return NewThrottler(ctx, "10.10.10.10")
+	return NewThrottler(ctx, "10.10.10.10", BufferLimits{})
 }
 
 func TestThrottlerUpdateCapacity(t *testing.T) {
@@ -508,7 +510,7 @@ func TestThrottlerSuccesses(t *testing.T) {
 
 			updateCh := make(chan revisionDestsUpdate)
 
-			throttler := NewThrottler(ctx, "130.0.0.2")
+			throttler := NewThrottler(ctx, "130.0.0.2", BufferLimits{})
 			var grp errgroup.Group
 			grp.Go(func() error { throttler.run(updateCh); return nil })
 			// Ensure the throttler stopped before we leave the test, so that
@@ -617,7 +619,7 @@ func TestPodAssignmentFinite(t *testing.T) {
 	defer cancel()
 
 	throttler := newTestThrottler(ctx)
-	rt := newRevisionThrottler(revName, 42 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, logger)
+	rt := newRevisionThrottler(revName, 42 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, nil, logger)
 	rt.numActivators.Store(4)
 	rt.activatorIndex.Store(0)
 	throttler.revisionThrottlers[revName] = rt
@@ -669,7 +671,7 @@ func TestPodAssignmentInfinite(t *testing.T) {
 	defer cancel()
 
 	throttler := newTestThrottler(ctx)
-	rt := newRevisionThrottler(revName, 0 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, logger)
+	rt := newRevisionThrottler(revName, 0 /*cc*/, pkgnet.ServicePortNameHTTP1, testBreakerParams, nil, logger)
 	throttler.revisionThrottlers[revName] = rt
 
 	update := revisionDestsUpdate{
@@ -728,7 +730,7 @@ func TestActivatorsIndexUpdate(t *testing.T) {
 
 	updateCh := make(chan revisionDestsUpdate)
 
-	throttler := NewThrottler(ctx, "130.0.0.2")
+	throttler := NewThrottler(ctx, "130.0.0.2", BufferLimits{})
 	var grp errgroup.Group
 	grp.Go(func() error { throttler.run(updateCh); return nil })
 	// Ensure the throttler stopped before we leave the test, so that
@@ -823,7 +825,7 @@ func TestMultipleActivators(t *testing.T) {
 
 	updateCh := make(chan revisionDestsUpdate)
 
-	throttler := NewThrottler(ctx, "130.0.0.2")
+	throttler := NewThrottler(ctx, "130.0.0.2", BufferLimits{})
 	var grp errgroup.Group
 	grp.Go(func() error { throttler.run(updateCh); return nil })
 	// Ensure the throttler stopped before we leave the test, so that
@@ -900,7 +902,7 @@ func TestMultipleActivators(t *testing.T) {
 func TestInfiniteBreakerCreation(t *testing.T) {
 	// This test verifies that we use infiniteBreaker when CC==0.
 	tttl := newRevisionThrottler(types.NamespacedName{Namespace: "a", Name: "b"}, 0, /*cc*/
-		pkgnet.ServicePortNameHTTP1, queue.BreakerParams{}, TestLogger(t))
+		pkgnet.ServicePortNameHTTP1, queue.BreakerParams{}, nil, TestLogger(t))
 	if _, ok := tttl.breaker.(*infiniteBreaker); !ok {
 		t.Errorf("The type of revisionBreaker = %T, want %T", tttl, (*infiniteBreaker)(nil))
 	}
@@ -1237,3 +1239,116 @@ func TestAssignSlice(t *testing.T) {
 		}
 	})
 }
+
+func TestRevisionLBPolicy(t *testing.T) {
+	throttler := &Throttler{logger: TestLogger(t)}
+	for _, test := range []struct {
+		name        string
+		annotations map[string]string
+		cc          int
+		wantPolicy  bool
+	}{{
+		name: "default",
+	}, {
+		name: "selected",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyLeastOutstandingRequests,
+		},
+		wantPolicy: true,
+	}, {
+		name: "consistent hash",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "cookie:session",
+		},
+		wantPolicy: true,
+	}, {
+		name: "consistent hash without hash key",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyConsistentHash,
+		},
+	}, {
+		name: "session affinity",
+		annotations: map[string]string{
+			serving.SessionAffinityAnnotationKey: "cookie:session",
+		},
+		wantPolicy: true,
+	}, {
+		name: "session affinity with a malformed key",
+		annotations: map[string]string{
+			serving.SessionAffinityAnnotationKey: "query:session",
+		},
+	}, {
+		name: "unsupported for the container concurrency",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyRandomChoice2,
+		},
+		cc: 10,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			rev := revisionCC1(types.NamespacedName{Namespace: testNamespace, Name: testRevision}, pkgnet.ProtocolHTTP1)
+			rev.Annotations = test.annotations
+			if got := throttler.revisionLBPolicy(rev, test.cc); (got != nil) != test.wantPolicy {
+				t.Errorf("revisionLBPolicy() = %v, want a policy: %v", got, test.wantPolicy)
+			}
+		})
+	}
+}
+
+func TestRevisionLBPolicySessionAffinity(t *testing.T) {
+	throttler := &Throttler{logger: TestLogger(t)}
+	rev := revisionCC1(types.NamespacedName{Namespace: testNamespace, Name: testRevision}, pkgnet.ProtocolHTTP1)
+	rev.Annotations = map[string]string{serving.SessionAffinityAnnotationKey: "cookie:session"}
+	p := throttler.revisionLBPolicy(rev, 0)
+
+	withSession := func(v string) context.Context {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if v != "" {
+			r.AddCookie(&http.Cookie{Name: "session", Value: v})
+		}
+		return WithRequest(context.Background(), r)
+	}
+	podTrackers := makeTrackers(3, 0)
+	picked := sets.New[string]()
+	for i := 0; i < 100; i++ {
+		session := strconv.Itoa(i)
+		_, want := p(withSession(session), podTrackers)
+		if _, got := p(withSession(session), podTrackers); got != want {
+			t.Fatalf("Tracker of session %s = %v, want: %v", session, got, want)
+		}
+		picked.Insert(want.dest)
+	}
+	if got, want := picked.Len(), 3; got != want {
+		t.Errorf("#targets = %d, want: %d", got, want)
+	}
+	// The requests without a session are balanced by the default policy.
+	if _, got := p(withSession(""), podTrackers); got == nil {
+		t.Error("No tracker for a request without a session")
+	}
+}
+
+func TestAcquireDestExcluded(t *testing.T) {
+	rt := &revisionThrottler{
+		lbPolicy:         firstAvailableLBPolicy,
+		assignedTrackers: makeTrackers(2, 1),
+	}
+
+	ctx := WithExcludedDest(context.Background(), "0")
+	cb, tracker := rt.acquireDest(ctx)
+	if tracker == nil {
+		t.Fatal("Tracker was nil")
+	} else if got, want := tracker.dest, "1"; got != want {
+		t.Errorf("Tracker = %s, want: %s", got, want)
+	}
+	cb()
+
+	// The excluded dest is still used when it's the only one with capacity.
+	ctx = WithExcludedDest(ctx, "1")
+	cb, tracker = rt.acquireDest(ctx)
+	if tracker == nil {
+		t.Fatal("Tracker was nil")
+	} else if got, want := tracker.dest, "0"; got != want {
+		t.Errorf("Tracker = %s, want: %s", got, want)
+	}
+	cb()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaling
+
+import (
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var (
+	// scalingAlgorithmsMux guards scalingAlgorithms.
+	scalingAlgorithmsMux sync.RWMutex
+	// scalingAlgorithms is the set of scaling algorithm names that are
+	// accepted for the scaling-algorithm annotation.
+	scalingAlgorithms = sets.New(ScalingAlgorithmDefault)
+)
+
+// RegisterScalingAlgorithm makes the scaling algorithm with the given name
+// known to the annotation validation. The autoscaler registers its algorithms
+// here when they are registered with it, but since the validation runs in
+// the webhook, the package registering a custom algorithm has to be linked
+// into the webhook binary as well.
+func RegisterScalingAlgorithm(name string) {
+	scalingAlgorithmsMux.Lock()
+	defer scalingAlgorithmsMux.Unlock()
+	scalingAlgorithms.Insert(name)
+}
+
+// IsScalingAlgorithmRegistered returns true if the scaling algorithm with
+// the given name has been registered.
+func IsScalingAlgorithmRegistered(name string) bool {
+	scalingAlgorithmsMux.RLock()
+	defer scalingAlgorithmsMux.RUnlock()
+	return scalingAlgorithms.Has(name)
+}
+
+// ScalingAlgorithms returns the sorted names of the registered scaling algorithms.
+func ScalingAlgorithms() []string {
+	scalingAlgorithmsMux.RLock()
+	defer scalingAlgorithmsMux.RUnlock()
+	return sets.List(scalingAlgorithms)
+}
+
+// ScalingAlgorithmParameters returns the scaling algorithm parameters found in
+// the given annotations, keyed by the parameter name without the
+// ScalingAlgorithmParameterPrefix, or nil if there are none.
+func ScalingAlgorithmParameters(anns map[string]string) map[string]string {
+	var ret map[string]string
+	for k, v := range anns {
+		if name := strings.TrimPrefix(k, ScalingAlgorithmParameterPrefix); name != k {
+			if ret == nil {
+				ret = make(map[string]string, 1)
+			}
+			ret[name] = v
+		}
+	}
+	return ret
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegisterScalingAlgorithm(t *testing.T) {
+	if IsScalingAlgorithmRegistered("queue-length") {
+		t.Fatal("queue-length is registered before registration")
+	}
+	anns := map[string]string{ScalingAlgorithmAnnotationKey: "queue-length"}
+	if err := ValidateAnnotations(context.Background(), defaultConfig(), anns); err == nil {
+		t.Error("ValidateAnnotations() = nil, want error for unregistered algorithm")
+	}
+
+	RegisterScalingAlgorithm("queue-length")
+	t.Cleanup(func() {
+		scalingAlgorithmsMux.Lock()
+		defer scalingAlgorithmsMux.Unlock()
+		scalingAlgorithms.Delete("queue-length")
+	})
+
+	if !IsScalingAlgorithmRegistered("queue-length") {
+		t.Error("queue-length is not registered after registration")
+	}
+	if got, want := ScalingAlgorithms(), []string{ScalingAlgorithmDefault, "queue-length"}; !cmp.Equal(got, want) {
+		t.Errorf("ScalingAlgorithms = %v, want: %v", got, want)
+	}
+	if err := ValidateAnnotations(context.Background(), defaultConfig(), anns); err != nil {
+		t.Error("ValidateAnnotations() =", err)
+	}
+}
+
+func TestScalingAlgorithmParameters(t *testing.T) {
+	tests := []struct {
+		name string
+		anns map[string]string
+		want map[string]string
+	}{{
+		name: "nil",
+	}, {
+		name: "no parameters",
+		anns: map[string]string{
+			ScalingAlgorithmAnnotationKey: "pid",
+			TargetAnnotationKey:           "10",
+		},
+	}, {
+		name: "parameters",
+		anns: map[string]string{
+			ScalingAlgorithmAnnotationKey:          "pid",
+			ScalingAlgorithmParameterPrefix + "kp": "0.5",
+			ScalingAlgorithmParameterPrefix + "ki": "0.1",
+		},
+		want: map[string]string{
+			"kp": "0.5",
+			"ki": "0.1",
+		},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ScalingAlgorithmParameters(tc.anns); !cmp.Equal(got, tc.want) {
+				t.Errorf("ScalingAlgorithmParameters = %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
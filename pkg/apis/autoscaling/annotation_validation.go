@@ -21,12 +21,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmap"
+	"knative.dev/serving/pkg/apis/autoscaling/schedule"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 )
 
@@ -54,12 +56,18 @@ func getIntGE0(m map[string]string, key kmap.KeyPriority) (int32, *apis.FieldErr
 func ValidateAnnotations(ctx context.Context, config *autoscalerconfig.Config, anns map[string]string) *apis.FieldError {
 	return validateClass(anns).
 		Also(validateMinMaxScale(config, anns)).
+		Also(validateScaleSchedule(config, anns)).
 		Also(validateFloats(anns)).
 		Also(validateWindow(anns)).
 		Also(validateLastPodRetention(anns)).
 		Also(validateScaleDownDelay(anns)).
+		Also(validateScalingPolicies(config, anns)).
 		Also(validateMetric(config, anns)).
+		Also(validateAdditionalMetrics(config, anns)).
 		Also(validateAlgorithm(anns)).
+		Also(validateLongLivedConnections(anns)).
+		Also(validateScalingMode(config, anns)).
+		Also(validateScalingAlgorithm(config, anns)).
 		Also(validateInitialScale(config, anns))
 }
 
@@ -90,6 +98,84 @@ func validateAlgorithm(m map[string]string) *apis.FieldError {
 	return nil
 }
 
+func validateLongLivedConnections(m map[string]string) *apis.FieldError {
+	if k, v, ok := LongLivedConnectionsAnnotation.Get(m); ok {
+		switch v {
+		case LongLivedConnectionsInclude, LongLivedConnectionsExclude:
+			return nil
+		default:
+			return apis.ErrInvalidValue(v, k)
+		}
+	}
+	return nil
+}
+
+func validateScalingMode(c *autoscalerconfig.Config, m map[string]string) (errs *apis.FieldError) {
+	classValue := c.PodAutoscalerClass
+	if _, c, ok := ClassAnnotation.Get(m); ok {
+		classValue = c
+	}
+	// Not a KPA? Don't validate, custom autoscalers might have custom values.
+	if classValue != KPA {
+		return nil
+	}
+	if k, v, ok := ScalingModeAnnotation.Get(m); ok {
+		switch v {
+		case ScalingModeReactive, ScalingModePredictive:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		}
+	}
+
+	horizon := PredictiveHorizonDefault
+	if k, v, ok := PredictiveHorizonAnnotation.Get(m); ok {
+		switch d, err := time.ParseDuration(v); {
+		case err != nil:
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		case d <= 0 || d > WindowMax:
+			errs = errs.Also(apis.ErrOutOfBoundsValue(v, 0*time.Second, WindowMax, k))
+		case d.Truncate(time.Second) != d:
+			errs = errs.Also(apis.ErrGeneric("must be specified with at most second precision", k))
+		default:
+			horizon = d
+		}
+	}
+
+	if k, v, ok := SeasonalPeriodAnnotation.Get(m); ok {
+		switch d, err := time.ParseDuration(v); {
+		case err != nil:
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		case d < 0 || d > SeasonalPeriodMax:
+			errs = errs.Also(apis.ErrOutOfBoundsValue(v, 0*time.Second, SeasonalPeriodMax, k))
+		case d.Truncate(time.Minute) != d:
+			errs = errs.Also(apis.ErrGeneric("must be specified with at most minute precision", k))
+		case d != 0 && d <= horizon:
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("seasonal-period=%v must be longer than predictive-horizon=%v", d, horizon),
+				Paths:   []string{k},
+			})
+		}
+	}
+	return errs
+}
+
+func validateScalingAlgorithm(c *autoscalerconfig.Config, m map[string]string) *apis.FieldError {
+	classValue := c.PodAutoscalerClass
+	if _, c, ok := ClassAnnotation.Get(m); ok {
+		classValue = c
+	}
+	// Not a KPA? Don't validate, custom autoscalers might have custom values.
+	if classValue != KPA {
+		return nil
+	}
+	if k, v, ok := ScalingAlgorithmAnnotation.Get(m); ok && !IsScalingAlgorithmRegistered(v) {
+		err := apis.ErrInvalidValue(v, k)
+		err.Details = "known scaling algorithms: " + strings.Join(ScalingAlgorithms(), ", ")
+		return err
+	}
+	return nil
+}
+
 func validateFloats(m map[string]string) (errs *apis.FieldError) {
 	if k, v, ok := PanicWindowPercentageAnnotation.Get(m); ok {
 		if fv, err := strconv.ParseFloat(v, 64); err != nil {
@@ -215,6 +301,46 @@ func validateMinMaxScale(config *autoscalerconfig.Config, m map[string]string) *
 	return errs
 }
 
+func validateScaleSchedule(c *autoscalerconfig.Config, m map[string]string) *apis.FieldError {
+	k, v, ok := ScaleScheduleAnnotation.Get(m)
+	if !ok {
+		return nil
+	}
+	classValue := c.PodAutoscalerClass
+	if _, c, ok := ClassAnnotation.Get(m); ok {
+		classValue = c
+	}
+	// Not a KPA? Don't validate, custom autoscalers might have custom values.
+	if classValue != KPA {
+		return nil
+	}
+	sched, err := schedule.Parse(v)
+	if err != nil {
+		fe := apis.ErrInvalidValue(v, k)
+		fe.Details = err.Error()
+		return fe
+	}
+	// Invalid bounds are reported by validateMinMaxScale.
+	annMin, _ := getIntGE0(m, MinScaleAnnotation)
+	annMax, _ := getIntGE0(m, MaxScaleAnnotation)
+	var errs *apis.FieldError
+	for _, w := range sched {
+		min, max := annMin, annMax
+		if w.MinScale != nil {
+			min = *w.MinScale
+		}
+		if w.MaxScale != nil {
+			max = *w.MaxScale
+			errs = errs.Also(validateMaxScaleWithinLimit(k, max, c.MaxScaleLimit))
+		}
+		if max != 0 && max < min {
+			errs = errs.Also(apis.ErrGeneric(
+				fmt.Sprintf("max scale %d of window %q is less than its min scale %d", max, w.Name, min), k))
+		}
+	}
+	return errs
+}
+
 func validateMaxScaleWithinLimit(key string, maxScale, maxScaleLimit int32) (errs *apis.FieldError) {
 	if maxScaleLimit == 0 {
 		return nil
@@ -246,6 +372,9 @@ func validateMetric(c *autoscalerconfig.Config, m map[string]string) *apis.Field
 			case Concurrency, RPS:
 				return nil
 			}
+			if _, _, ok := CustomMetricPathAnnotation.Get(m); ok && IsCustomMetric(metric) {
+				return validateCustomMetric(metric, m)
+			}
 		case HPA:
 			switch metric {
 			case "":
@@ -262,6 +391,184 @@ func validateMetric(c *autoscalerconfig.Config, m map[string]string) *apis.Field
 	return nil
 }
 
+// customMetricNameRegexp matches the valid Prometheus metric names.
+var customMetricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// IsCustomMetric returns true if the metric is not one of the metrics known to
+// Knative, i.e. it names an application metric.
+func IsCustomMetric(metric string) bool {
+	switch metric {
+	case "", Concurrency, RPS, CPU, Memory:
+		return false
+	}
+	return true
+}
+
+func validateCustomMetric(metric string, m map[string]string) *apis.FieldError {
+	var errs *apis.FieldError
+	if !customMetricNameRegexp.MatchString(metric) {
+		errs = apis.ErrInvalidValue(metric, MetricAnnotationKey)
+	}
+	if k, v, _ := CustomMetricPathAnnotation.Get(m); !strings.HasPrefix(v, "/") {
+		errs = errs.Also(apis.ErrInvalidValue(v, k))
+	}
+	if k, v, ok := CustomMetricPortAnnotation.Get(m); ok {
+		if port, err := strconv.Atoi(v); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		} else if port < 1 || port > 65535 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(port, 1, 65535, k))
+		}
+	}
+	// There is no sensible default target for an application metric.
+	if _, _, ok := TargetAnnotation.Get(m); !ok {
+		errs = errs.Also(apis.ErrMissingField(TargetAnnotationKey))
+	}
+	return errs
+}
+
+// ParseAdditionalMetrics parses the value of the additional metrics annotation,
+// i.e. a comma separated list of metric=target pairs, into a map of targets
+// keyed by metric.
+func ParseAdditionalMetrics(s string) (map[string]float64, error) {
+	ret := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		metric, target, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a metric=target pair", pair)
+		}
+		if _, ok := ret[metric]; ok {
+			return nil, fmt.Errorf("metric %q is specified more than once", metric)
+		}
+		v, err := strconv.ParseFloat(target, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target for metric %q: %w", metric, err)
+		}
+		ret[metric] = v
+	}
+	return ret, nil
+}
+
+func validateAdditionalMetrics(c *autoscalerconfig.Config, m map[string]string) *apis.FieldError {
+	k, v, ok := AdditionalMetricsAnnotation.Get(m)
+	if !ok {
+		return nil
+	}
+	classValue := c.PodAutoscalerClass
+	if _, c, ok := ClassAnnotation.Get(m); ok {
+		classValue = c
+	}
+	// Not a KPA? Don't validate, custom autoscalers might have custom values.
+	if classValue != KPA {
+		return nil
+	}
+	metrics, err := ParseAdditionalMetrics(v)
+	if err != nil {
+		fe := apis.ErrInvalidValue(v, k)
+		fe.Details = err.Error()
+		return fe
+	}
+	primary := Concurrency
+	if _, metric, ok := MetricAnnotation.Get(m); ok {
+		primary = metric
+	}
+	var errs *apis.FieldError
+	for metric, target := range metrics {
+		switch {
+		case metric == primary:
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("metric %q is already specified by %s", metric, MetricAnnotationKey), k))
+		case metric != Concurrency && metric != RPS:
+			errs = errs.Also(apis.ErrInvalidValue(metric, k))
+		case target < TargetMin:
+			errs = errs.Also(apis.ErrOutOfBoundsValue(target, TargetMin, math.MaxFloat64, k))
+		}
+	}
+	return errs
+}
+
+// ParseScalingPolicies parses the value of the scaling policies config and
+// annotations, i.e. a comma separated list of type=value/period rules, e.g.
+// "pods=2/1m,percent=10/5m". An empty value yields no policies.
+func ParseScalingPolicies(s string) ([]autoscalerconfig.ScalingPolicy, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var ret []autoscalerconfig.ScalingPolicy
+	for _, rule := range strings.Split(s, ",") {
+		typ, limit, ok := strings.Cut(strings.TrimSpace(rule), "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a type=value/period rule", rule)
+		}
+		value, period, ok := strings.Cut(limit, "/")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a type=value/period rule", rule)
+		}
+		p := autoscalerconfig.ScalingPolicy{Type: autoscalerconfig.ScalingPolicyType(typ)}
+		switch p.Type {
+		case autoscalerconfig.PodsScalingPolicy, autoscalerconfig.PercentScalingPolicy:
+		default:
+			return nil, fmt.Errorf("unknown policy type %q, must be one of %s or %s", typ,
+				autoscalerconfig.PodsScalingPolicy, autoscalerconfig.PercentScalingPolicy)
+		}
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || v < 1 {
+			return nil, fmt.Errorf("value of %q must be a positive integer", rule)
+		}
+		p.Value = int32(v)
+		if p.Period, err = time.ParseDuration(period); err != nil {
+			return nil, fmt.Errorf("invalid period of %q: %w", rule, err)
+		}
+		if p.Period <= 0 || p.Period > WindowMax {
+			return nil, fmt.Errorf("period of %q must be in (0s, %v] range", rule, WindowMax)
+		}
+		if p.Period.Round(time.Second) != p.Period {
+			return nil, fmt.Errorf("period of %q must be specified with at most second precision", rule)
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// ParseScalingPolicySelect parses the value of the select policy config and
+// annotations.
+func ParseScalingPolicySelect(s string) (autoscalerconfig.ScalingPolicySelect, error) {
+	switch sp := autoscalerconfig.ScalingPolicySelect(s); sp {
+	case autoscalerconfig.MaxChangePolicySelect, autoscalerconfig.MinChangePolicySelect,
+		autoscalerconfig.DisabledPolicySelect:
+		return sp, nil
+	}
+	return "", fmt.Errorf("unknown select policy %q, must be one of %s, %s or %s", s,
+		autoscalerconfig.MaxChangePolicySelect, autoscalerconfig.MinChangePolicySelect,
+		autoscalerconfig.DisabledPolicySelect)
+}
+
+func validateScalingPolicies(c *autoscalerconfig.Config, m map[string]string) (errs *apis.FieldError) {
+	classValue := c.PodAutoscalerClass
+	if _, c, ok := ClassAnnotation.Get(m); ok {
+		classValue = c
+	}
+	// Not a KPA? Don't validate, custom autoscalers might have custom values.
+	if classValue != KPA {
+		return nil
+	}
+	for _, key := range []kmap.KeyPriority{ScaleUpPoliciesAnnotation, ScaleDownPoliciesAnnotation} {
+		if k, v, ok := key.Get(m); ok {
+			if _, err := ParseScalingPolicies(v); err != nil {
+				fe := apis.ErrInvalidValue(v, k)
+				fe.Details = err.Error()
+				errs = errs.Also(fe)
+			}
+		}
+	}
+	for _, key := range []kmap.KeyPriority{ScaleUpSelectPolicyAnnotation, ScaleDownSelectPolicyAnnotation} {
+		if k, v, ok := key.Get(m); ok {
+			if _, err := ParseScalingPolicySelect(v); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(v, k))
+			}
+		}
+	}
+	return errs
+}
+
 func validateInitialScale(config *autoscalerconfig.Config, m map[string]string) *apis.FieldError {
 	if k, v, ok := InitialScaleAnnotation.Get(m); ok {
 		initScaleInt, err := strconv.Atoi(v)
@@ -20,6 +20,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	_ "time/tzdata"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -165,6 +166,73 @@ func TestValidateAnnotations(t *testing.T) {
 			MetricAggregationAlgorithmKey: "random-selection",
 			ClassAnnotationKey:            "of-keys",
 		},
+	}, {
+		name:        "long-lived connections excluded",
+		annotations: map[string]string{LongLivedConnectionsAnnotationKey: LongLivedConnectionsExclude},
+	}, {
+		name:        "invalid long-lived connections",
+		annotations: map[string]string{LongLivedConnectionsAnnotationKey: "sometimes"},
+		expectErr:   "invalid value: sometimes: " + LongLivedConnectionsAnnotationKey,
+	}, {
+		name: "predictive scaling mode",
+		annotations: map[string]string{
+			ScalingModeAnnotationKey:       ScalingModePredictive,
+			PredictiveHorizonAnnotationKey: "5m",
+			SeasonalPeriodAnnotationKey:    "168h",
+		},
+	}, {
+		name:        "unknown scaling mode on KPA",
+		annotations: map[string]string{ScalingModeAnnotationKey: "clairvoyant"},
+		expectErr:   "invalid value: clairvoyant: " + ScalingModeAnnotationKey,
+	}, {
+		name: "unknown scaling mode on non KPA",
+		annotations: map[string]string{
+			ScalingModeAnnotationKey: "clairvoyant",
+			ClassAnnotationKey:       "of-keys",
+		},
+	}, {
+		name:        "predictive horizon is zero",
+		annotations: map[string]string{PredictiveHorizonAnnotationKey: "0s"},
+		expectErr:   "expected 0s <= 0s <= 1h0m0s: " + PredictiveHorizonAnnotationKey,
+	}, {
+		name:        "predictive horizon is not a duration",
+		annotations: map[string]string{PredictiveHorizonAnnotationKey: "soon"},
+		expectErr:   "invalid value: soon: " + PredictiveHorizonAnnotationKey,
+	}, {
+		name:        "predictive horizon with sub-second precision",
+		annotations: map[string]string{PredictiveHorizonAnnotationKey: "1m30.5s"},
+		expectErr:   "must be specified with at most second precision: " + PredictiveHorizonAnnotationKey,
+	}, {
+		name:        "seasonal period disabled",
+		annotations: map[string]string{SeasonalPeriodAnnotationKey: "0s"},
+	}, {
+		name:        "seasonal period too long",
+		annotations: map[string]string{SeasonalPeriodAnnotationKey: "169h"},
+		expectErr:   "expected 0s <= 169h <= 168h0m0s: " + SeasonalPeriodAnnotationKey,
+	}, {
+		name:        "seasonal period with sub-minute precision",
+		annotations: map[string]string{SeasonalPeriodAnnotationKey: "24h30s"},
+		expectErr:   "must be specified with at most minute precision: " + SeasonalPeriodAnnotationKey,
+	}, {
+		name: "seasonal period shorter than horizon",
+		annotations: map[string]string{
+			PredictiveHorizonAnnotationKey: "30m",
+			SeasonalPeriodAnnotationKey:    "10m",
+		},
+		expectErr: "seasonal-period=10m0s must be longer than predictive-horizon=30m0s: " + SeasonalPeriodAnnotationKey,
+	}, {
+		name:        "default scaling algorithm",
+		annotations: map[string]string{ScalingAlgorithmAnnotationKey: ScalingAlgorithmDefault},
+	}, {
+		name:        "unknown scaling algorithm on KPA",
+		annotations: map[string]string{ScalingAlgorithmAnnotationKey: "pid"},
+		expectErr:   "invalid value: pid: " + ScalingAlgorithmAnnotationKey + "\nknown scaling algorithms: kpa",
+	}, {
+		name: "unknown scaling algorithm on non KPA",
+		annotations: map[string]string{
+			ScalingAlgorithmAnnotationKey: "pid",
+			ClassAnnotationKey:            "of-keys",
+		},
 	}, {
 		name:        "panic window percentage bad",
 		annotations: map[string]string{PanicWindowPercentageAnnotationKey: "-1"},
@@ -340,6 +408,164 @@ func TestValidateAnnotations(t *testing.T) {
 	}, {
 		name:        "valid class KPA with metric Concurrency",
 		annotations: map[string]string{MetricAnnotationKey: Concurrency},
+	}, {
+		name: "valid class KPA with custom metric",
+		annotations: map[string]string{
+			MetricAnnotationKey:           "queue_depth",
+			CustomMetricPathAnnotationKey: "/metrics",
+			CustomMetricPortAnnotationKey: "9000",
+			TargetAnnotationKey:           "10",
+		},
+	}, {
+		name:        "custom metric without path",
+		annotations: map[string]string{MetricAnnotationKey: "queue_depth", TargetAnnotationKey: "10"},
+		expectErr:   "invalid value: queue_depth: " + MetricAnnotationKey,
+	}, {
+		name: "custom metric with invalid name",
+		annotations: map[string]string{
+			MetricAnnotationKey:           "queue-depth",
+			CustomMetricPathAnnotationKey: "/metrics",
+			TargetAnnotationKey:           "10",
+		},
+		expectErr: "invalid value: queue-depth: " + MetricAnnotationKey,
+	}, {
+		name: "custom metric with relative path",
+		annotations: map[string]string{
+			MetricAnnotationKey:           "queue_depth",
+			CustomMetricPathAnnotationKey: "metrics",
+			TargetAnnotationKey:           "10",
+		},
+		expectErr: "invalid value: metrics: " + CustomMetricPathAnnotationKey,
+	}, {
+		name: "custom metric with port out of range",
+		annotations: map[string]string{
+			MetricAnnotationKey:           "queue_depth",
+			CustomMetricPathAnnotationKey: "/metrics",
+			CustomMetricPortAnnotationKey: "0",
+			TargetAnnotationKey:           "10",
+		},
+		expectErr: "expected 1 <= 0 <= 65535: " + CustomMetricPortAnnotationKey,
+	}, {
+		name: "custom metric without target",
+		annotations: map[string]string{
+			MetricAnnotationKey:           "queue_depth",
+			CustomMetricPathAnnotationKey: "/metrics",
+		},
+		expectErr: "missing field(s): " + TargetAnnotationKey,
+	}, {
+		name:        "valid additional metrics",
+		annotations: map[string]string{AdditionalMetricsAnnotationKey: "rps=150"},
+	}, {
+		name: "valid additional metrics for custom metric",
+		annotations: map[string]string{
+			MetricAnnotationKey:            "queue_depth",
+			CustomMetricPathAnnotationKey:  "/metrics",
+			TargetAnnotationKey:            "10",
+			AdditionalMetricsAnnotationKey: "concurrency=10, rps=150",
+		},
+	}, {
+		name:        "additional metrics are not pairs",
+		annotations: map[string]string{AdditionalMetricsAnnotationKey: "rps"},
+		expectErr:   "invalid value: rps: " + AdditionalMetricsAnnotationKey + "\n\"rps\" is not a metric=target pair",
+	}, {
+		name:        "additional metrics with duplicates",
+		annotations: map[string]string{AdditionalMetricsAnnotationKey: "rps=1,rps=2"},
+		expectErr:   "invalid value: rps=1,rps=2: " + AdditionalMetricsAnnotationKey + "\nmetric \"rps\" is specified more than once",
+	}, {
+		name:        "additional metric is the primary metric",
+		annotations: map[string]string{MetricAnnotationKey: RPS, AdditionalMetricsAnnotationKey: "rps=150"},
+		expectErr:   `metric "rps" is already specified by ` + MetricAnnotationKey + ": " + AdditionalMetricsAnnotationKey,
+	}, {
+		name:        "additional metric defaults to the primary metric",
+		annotations: map[string]string{AdditionalMetricsAnnotationKey: "concurrency=10"},
+		expectErr:   `metric "concurrency" is already specified by ` + MetricAnnotationKey + ": " + AdditionalMetricsAnnotationKey,
+	}, {
+		name:        "unsupported additional metric",
+		annotations: map[string]string{AdditionalMetricsAnnotationKey: "cpu=50"},
+		expectErr:   "invalid value: cpu: " + AdditionalMetricsAnnotationKey,
+	}, {
+		name:        "additional metric target too small",
+		annotations: map[string]string{AdditionalMetricsAnnotationKey: "rps=0"},
+		expectErr:   "expected 0.01 <= 0 <= 1.7976931348623157e+308: " + AdditionalMetricsAnnotationKey,
+	}, {
+		name:        "additional metrics for HPA are not validated",
+		annotations: map[string]string{ClassAnnotationKey: HPA, MetricAnnotationKey: CPU, AdditionalMetricsAnnotationKey: "cpu"},
+	}, {
+		name: "valid scaling policies",
+		annotations: map[string]string{
+			ScaleUpPoliciesAnnotationKey:       "percent=100/15s",
+			ScaleDownPoliciesAnnotationKey:     "pods=2/1m, percent=10/5m",
+			ScaleUpSelectPolicyAnnotationKey:   "min",
+			ScaleDownSelectPolicyAnnotationKey: "disabled",
+		},
+	}, {
+		name:        "empty scaling policies",
+		annotations: map[string]string{ScaleDownPoliciesAnnotationKey: ""},
+	}, {
+		name:        "scaling policy is not a rule",
+		annotations: map[string]string{ScaleDownPoliciesAnnotationKey: "pods=2"},
+		expectErr:   "invalid value: pods=2: " + ScaleDownPoliciesAnnotationKey + "\n\"pods=2\" is not a type=value/period rule",
+	}, {
+		name:        "unknown scaling policy type",
+		annotations: map[string]string{ScaleUpPoliciesAnnotationKey: "replicas=2/1m"},
+		expectErr:   "invalid value: replicas=2/1m: " + ScaleUpPoliciesAnnotationKey + "\nunknown policy type \"replicas\", must be one of pods or percent",
+	}, {
+		name:        "scaling policy value not positive",
+		annotations: map[string]string{ScaleDownPoliciesAnnotationKey: "pods=0/1m"},
+		expectErr:   "invalid value: pods=0/1m: " + ScaleDownPoliciesAnnotationKey + "\nvalue of \"pods=0/1m\" must be a positive integer",
+	}, {
+		name:        "scaling policy period too long",
+		annotations: map[string]string{ScaleDownPoliciesAnnotationKey: "pods=1/2h"},
+		expectErr:   "invalid value: pods=1/2h: " + ScaleDownPoliciesAnnotationKey + "\nperiod of \"pods=1/2h\" must be in (0s, 1h0m0s] range",
+	}, {
+		name:        "scaling policy period too precise",
+		annotations: map[string]string{ScaleDownPoliciesAnnotationKey: "pods=1/1500ms"},
+		expectErr:   "invalid value: pods=1/1500ms: " + ScaleDownPoliciesAnnotationKey + "\nperiod of \"pods=1/1500ms\" must be specified with at most second precision",
+	}, {
+		name:        "unknown select policy",
+		annotations: map[string]string{ScaleUpSelectPolicyAnnotationKey: "Max"},
+		expectErr:   "invalid value: Max: " + ScaleUpSelectPolicyAnnotationKey,
+	}, {
+		name:        "scaling policies for HPA are not validated",
+		annotations: map[string]string{ClassAnnotationKey: HPA, MetricAnnotationKey: CPU, ScaleDownPoliciesAnnotationKey: "pods"},
+	}, {
+		name: "valid scale schedule",
+		annotations: map[string]string{
+			MaxScaleAnnotationKey:      "10",
+			ScaleScheduleAnnotationKey: `[{"name": "day", "start": "0 9 * * mon-fri", "duration": "10h", "timeZone": "Europe/Berlin", "minScale": 3}]`,
+		},
+	}, {
+		name:        "invalid scale schedule",
+		annotations: map[string]string{ScaleScheduleAnnotationKey: `[{"name": "day", "start": "0 9 * *", "duration": "10h", "minScale": 3}]`},
+		expectErr: `invalid value: [{"name": "day", "start": "0 9 * *", "duration": "10h", "minScale": 3}]: ` + ScaleScheduleAnnotationKey +
+			"\ninvalid start of window \"day\": cron expression \"0 9 * *\" must have 5 fields, has 4",
+	}, {
+		name: "scale schedule min above max scale",
+		annotations: map[string]string{
+			MaxScaleAnnotationKey:      "2",
+			ScaleScheduleAnnotationKey: `[{"name": "day", "start": "0 9 * * *", "duration": "10h", "minScale": 3}]`,
+		},
+		expectErr: `max scale 2 of window "day" is less than its min scale 3: ` + ScaleScheduleAnnotationKey,
+	}, {
+		name: "scale schedule max above max scale limit",
+		configMutator: func(config *autoscalerconfig.Config) {
+			config.MaxScaleLimit = 10
+		},
+		annotations: map[string]string{
+			ScaleScheduleAnnotationKey: `[{"name": "day", "start": "0 9 * * *", "duration": "10h", "maxScale": 11}]`,
+		},
+		expectErr: "expected 1 <= 11 <= 10: " + ScaleScheduleAnnotationKey,
+	}, {
+		name:        "scale schedule for HPA is not validated",
+		annotations: map[string]string{ClassAnnotationKey: HPA, MetricAnnotationKey: CPU, ScaleScheduleAnnotationKey: "nightly"},
+	}, {
+		name: "known metric with custom metric path",
+		annotations: map[string]string{
+			MetricAnnotationKey:           CPU,
+			CustomMetricPathAnnotationKey: "/metrics",
+			TargetAnnotationKey:           "10",
+		},
+		expectErr: "invalid value: cpu: " + MetricAnnotationKey,
 	}, {
 		name:        "valid class HPA with metric CPU",
 		annotations: map[string]string{ClassAnnotationKey: HPA, MetricAnnotationKey: CPU},
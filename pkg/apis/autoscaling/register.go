@@ -56,9 +56,33 @@ const (
 	// allow-zero-initial-scale of config-autoscaler is true.
 	InitialScaleAnnotationKey = GroupName + "/initial-scale"
 
+	// ScaleScheduleAnnotationKey is the annotation to specify recurring time
+	// windows, in which the min and max scale of a revision differ from the
+	// ones of the min-scale and max-scale annotations. The value is a JSON
+	// list of windows starting at the times of a cron expression. If several
+	// windows are active, the one listed first applies. For example,
+	//   autoscaling.knative.dev/scale-schedule: |
+	//     [{"name": "business-hours", "start": "0 9 * * mon-fri", "duration": "10h",
+	//       "timeZone": "Europe/Berlin", "minScale": 3}]
+	ScaleScheduleAnnotationKey = GroupName + "/scale-schedule"
+
 	// ScaleDownDelayAnnotationKey is the annotation to specify a scale down delay.
 	ScaleDownDelayAnnotationKey = GroupName + "/scale-down-delay"
 
+	// ScaleUpPoliciesAnnotationKey and ScaleDownPoliciesAnnotationKey are the
+	// annotations to specify the policies limiting how fast the KPA may scale
+	// the revision up and down, as a comma separated list of
+	// type=value/period rules, type being pods or percent. For example,
+	//   autoscaling.knative.dev/scale-down-policies: "pods=2/1m,percent=10/5m"
+	ScaleUpPoliciesAnnotationKey   = GroupName + "/scale-up-policies"
+	ScaleDownPoliciesAnnotationKey = GroupName + "/scale-down-policies"
+	// ScaleUpSelectPolicyAnnotationKey and ScaleDownSelectPolicyAnnotationKey
+	// are the annotations to specify which of the scaling policies is applied:
+	// max (the one permitting the largest change, the default), min or
+	// disabled (no scaling in the direction at all).
+	ScaleUpSelectPolicyAnnotationKey   = GroupName + "/scale-up-select-policy"
+	ScaleDownSelectPolicyAnnotationKey = GroupName + "/scale-down-select-policy"
+
 	// MetricAnnotationKey is the annotation to specify what metric the PodAutoscaler
 	// should be scaled on. For example,
 	//   autoscaling.knative.dev/metric: cpu
@@ -72,6 +96,28 @@ const (
 	// RPS is the requests per second reaching the Pod.
 	RPS = "rps"
 
+	// CustomMetricPathAnnotationKey is the annotation to specify the HTTP path
+	// on which the user container serves its metrics in the Prometheus text
+	// format. It allows the KPA to scale on the application metric named by the
+	// metric annotation. For example,
+	//   autoscaling.knative.dev/metric: queue_depth
+	//   autoscaling.knative.dev/custom-metric-path: /metrics
+	//   autoscaling.knative.dev/target: "10"   # target a queue depth of 10 per pod
+	CustomMetricPathAnnotationKey = GroupName + "/custom-metric-path"
+	// CustomMetricPortAnnotationKey is the annotation to specify the port on
+	// which the user container serves its metrics, if it's not the port serving
+	// the requests.
+	CustomMetricPortAnnotationKey = GroupName + "/custom-metric-port"
+
+	// AdditionalMetricsAnnotationKey is the annotation to specify the metrics,
+	// along with their targets, that the KPA should scale on in addition to the
+	// one specified by the metric annotation. The KPA computes the desired scale
+	// for every metric and picks the largest one. For example,
+	//   autoscaling.knative.dev/metric: concurrency
+	//   autoscaling.knative.dev/target: "10"
+	//   autoscaling.knative.dev/additional-metrics: "rps=150"
+	AdditionalMetricsAnnotationKey = GroupName + "/additional-metrics"
+
 	// TargetAnnotationKey is the annotation to specify what metric value the
 	// PodAutoscaler should attempt to maintain. For example,
 	//   autoscaling.knative.dev/metric: cpu
@@ -123,6 +169,22 @@ const (
 	// and return MetricAggregationAlgorithmWeightedExponential
 	MetricAggregationAlgorithmWeightedExponentialAlt = "weightedExponential"
 
+	// LongLivedConnectionsAnnotationKey is the annotation to specify whether
+	// long-lived connections, i.e. upgraded connections like WebSockets and
+	// server-sent events streams, count toward the concurrency the KPA scales
+	// on. Such connections hold on to a pod for as long as they're open, so they
+	// can keep a revision from scaling down. For example,
+	//   autoscaling.knative.dev/long-lived-connections: exclude
+	LongLivedConnectionsAnnotationKey = GroupName + "/long-lived-connections"
+
+	// LongLivedConnectionsInclude counts the long-lived connections toward the
+	// concurrency, like any other request (default).
+	LongLivedConnectionsInclude = "include"
+
+	// LongLivedConnectionsExclude leaves the long-lived connections out of the
+	// concurrency.
+	LongLivedConnectionsExclude = "exclude"
+
 	// WindowAnnotationKey is the annotation to specify the time
 	// interval over which to calculate the average metric.  Larger
 	// values result in more smoothing. For example,
@@ -223,6 +285,65 @@ const (
 	// min-scale value while also preserving the ability to scale to zero.
 	// ActivationScale must be >= 2.
 	ActivationScaleKey = GroupName + "/activation-scale"
+
+	// ScalingModeAnnotationKey is the annotation to select how the KPA
+	// computes the desired scale. For example,
+	//   autoscaling.knative.dev/scaling-mode: predictive
+	// Only the kpa.autoscaling.knative.dev class autoscaler supports
+	// the scaling-mode annotation.
+	// NB: this is an Alpha feature and can be removed or modified
+	//     at any point.
+	ScalingModeAnnotationKey = GroupName + "/scaling-mode"
+	// ScalingModeReactive scales on the observed stable and panic values only (default).
+	ScalingModeReactive = "reactive"
+	// ScalingModePredictive additionally scales on the value of the scaling
+	// metric forecasted from its recent trend and its seasonal history.
+	ScalingModePredictive = "predictive"
+
+	// PredictiveHorizonAnnotationKey is the annotation to specify how far
+	// ahead the predictive scaling mode forecasts the load. For example,
+	//   autoscaling.knative.dev/scaling-mode: predictive
+	//   autoscaling.knative.dev/predictive-horizon: "2m"
+	PredictiveHorizonAnnotationKey = GroupName + "/predictive-horizon"
+	// PredictiveHorizonDefault is the horizon used when the annotation is not set.
+	PredictiveHorizonDefault = 1 * time.Minute
+
+	// SeasonalPeriodAnnotationKey is the annotation to specify the period after
+	// which the load of the revision is expected to repeat itself, e.g. a day.
+	// The predictive scaling mode keeps the history of the scaling metric over
+	// this period and uses the load observed one period ago as a forecast.
+	// The history is kept in the memory of the autoscaler, so it starts over
+	// when the autoscaler restarts or another replica becomes the leader of
+	// the revision, and only the trend forecast applies for a period then.
+	// A value of "0s" disables the seasonal forecast. For example,
+	//   autoscaling.knative.dev/scaling-mode: predictive
+	//   autoscaling.knative.dev/seasonal-period: "24h"
+	SeasonalPeriodAnnotationKey = GroupName + "/seasonal-period"
+	// SeasonalPeriodDefault is the seasonal period used when the annotation is not set.
+	SeasonalPeriodDefault = 24 * time.Hour
+	// SeasonalPeriodMax is the longest permitted seasonal period.
+	SeasonalPeriodMax = 7 * 24 * time.Hour
+
+	// ScalingAlgorithmAnnotationKey is the annotation to select the algorithm
+	// the KPA uses to compute the desired scale of the revision. For example,
+	//   autoscaling.knative.dev/scaling-algorithm: pid
+	// The algorithm has to be registered both with the autoscaler and the webhook.
+	// Only the kpa.autoscaling.knative.dev class autoscaler supports
+	// the scaling-algorithm annotation.
+	// NB: this is an Alpha feature and can be removed or modified
+	//     at any point.
+	ScalingAlgorithmAnnotationKey = GroupName + "/scaling-algorithm"
+	// ScalingAlgorithmDefault is the built-in KPA algorithm, which scales
+	// on the stable and panic windows of the scaling metric.
+	ScalingAlgorithmDefault = "kpa"
+
+	// ScalingAlgorithmParameterPrefix is the prefix of the annotations that
+	// carry the parameters of the selected scaling algorithm. The parameters
+	// are passed verbatim to the algorithm, which is responsible for their
+	// interpretation. For example,
+	//   autoscaling.knative.dev/scaling-algorithm: pid
+	//   algorithm.autoscaling.knative.dev/kp: "0.5"
+	ScalingAlgorithmParameterPrefix = "algorithm." + GroupName + "/"
 )
 
 var (
@@ -241,10 +362,22 @@ var (
 	MetricAnnotation = kmap.KeyPriority{
 		MetricAnnotationKey,
 	}
+	AdditionalMetricsAnnotation = kmap.KeyPriority{
+		AdditionalMetricsAnnotationKey,
+	}
+	CustomMetricPathAnnotation = kmap.KeyPriority{
+		CustomMetricPathAnnotationKey,
+	}
+	CustomMetricPortAnnotation = kmap.KeyPriority{
+		CustomMetricPortAnnotationKey,
+	}
 	MetricAggregationAlgorithmAnnotation = kmap.KeyPriority{
 		MetricAggregationAlgorithmKey,
 		GroupName + "/metricAggregationAlgorithm",
 	}
+	LongLivedConnectionsAnnotation = kmap.KeyPriority{
+		LongLivedConnectionsAnnotationKey,
+	}
 	ActivationScale = kmap.KeyPriority{
 		ActivationScaleKey,
 	}
@@ -260,10 +393,37 @@ var (
 		PanicWindowPercentageAnnotationKey,
 		GroupName + "/panicWindowPercentage",
 	}
+	ScaleScheduleAnnotation = kmap.KeyPriority{
+		ScaleScheduleAnnotationKey,
+	}
 	ScaleDownDelayAnnotation = kmap.KeyPriority{
 		ScaleDownDelayAnnotationKey,
 		GroupName + "/scaleDownDelay",
 	}
+	ScaleUpPoliciesAnnotation = kmap.KeyPriority{
+		ScaleUpPoliciesAnnotationKey,
+	}
+	ScaleDownPoliciesAnnotation = kmap.KeyPriority{
+		ScaleDownPoliciesAnnotationKey,
+	}
+	ScaleUpSelectPolicyAnnotation = kmap.KeyPriority{
+		ScaleUpSelectPolicyAnnotationKey,
+	}
+	ScaleDownSelectPolicyAnnotation = kmap.KeyPriority{
+		ScaleDownSelectPolicyAnnotationKey,
+	}
+	ScalingAlgorithmAnnotation = kmap.KeyPriority{
+		ScalingAlgorithmAnnotationKey,
+	}
+	ScalingModeAnnotation = kmap.KeyPriority{
+		ScalingModeAnnotationKey,
+	}
+	PredictiveHorizonAnnotation = kmap.KeyPriority{
+		PredictiveHorizonAnnotationKey,
+	}
+	SeasonalPeriodAnnotation = kmap.KeyPriority{
+		SeasonalPeriodAnnotationKey,
+	}
 	ScaleToZeroPodRetentionPeriodAnnotation = kmap.KeyPriority{
 		ScaleToZeroPodRetentionPeriodKey,
 		GroupName + "/scaleToZeroPodRetentionPeriod",
@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"* * * foo *",
+	} {
+		if _, err := parseCron(s); err == nil {
+			t.Errorf("parseCron(%q) = nil error, want an error", s)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal("Failed to load location:", err)
+	}
+	date := func(loc *time.Location, y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, loc)
+	}
+
+	tests := []struct {
+		name string
+		cron string
+		loc  *time.Location
+		t    time.Time
+		want time.Time
+	}{{
+		name: "every minute",
+		cron: "* * * * *",
+		loc:  time.UTC,
+		t:    time.Date(2024, 3, 1, 10, 0, 30, 0, time.UTC),
+		want: date(time.UTC, 2024, 3, 1, 10, 1),
+	}, {
+		name: "strictly after",
+		cron: "0 9 * * *",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 9, 0),
+		want: date(time.UTC, 2024, 3, 2, 9, 0),
+	}, {
+		name: "steps",
+		cron: "*/15 * * * *",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 9, 16),
+		want: date(time.UTC, 2024, 3, 1, 9, 30),
+	}, {
+		name: "lists and ranges",
+		cron: "0 8,12-14 * * *",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 12, 30),
+		want: date(time.UTC, 2024, 3, 1, 13, 0),
+	}, {
+		name: "weekdays by name",
+		cron: "0 9 * * mon-fri",
+		loc:  time.UTC,
+		// A Friday.
+		t:    date(time.UTC, 2024, 3, 1, 10, 0),
+		want: date(time.UTC, 2024, 3, 4, 9, 0),
+	}, {
+		name: "sunday as 7",
+		cron: "0 0 * * 7",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 0, 0),
+		want: date(time.UTC, 2024, 3, 3, 0, 0),
+	}, {
+		name: "either day field matches",
+		cron: "0 0 15 * mon",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 0, 0),
+		want: date(time.UTC, 2024, 3, 4, 0, 0),
+	}, {
+		name: "months by name",
+		cron: "0 0 1 jun *",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 0, 0),
+		want: date(time.UTC, 2024, 6, 1, 0, 0),
+	}, {
+		name: "leap day",
+		cron: "0 0 29 2 *",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 0, 0),
+		want: date(time.UTC, 2028, 2, 29, 0, 0),
+	}, {
+		name: "never",
+		cron: "0 0 30 2 *",
+		loc:  time.UTC,
+		t:    date(time.UTC, 2024, 3, 1, 0, 0),
+	}, {
+		name: "time zone",
+		cron: "0 9 * * *",
+		loc:  berlin,
+		t:    date(time.UTC, 2024, 1, 10, 7, 0),
+		want: date(time.UTC, 2024, 1, 10, 8, 0),
+	}, {
+		name: "skipped by daylight saving time",
+		cron: "30 2 * * *",
+		loc:  berlin,
+		t:    date(berlin, 2024, 3, 31, 0, 0),
+		want: date(berlin, 2024, 4, 1, 2, 30),
+	}, {
+		name: "after daylight saving time",
+		cron: "0 9 * * *",
+		loc:  berlin,
+		t:    date(berlin, 2024, 3, 31, 0, 0),
+		want: date(time.UTC, 2024, 3, 31, 7, 0),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := parseCron(test.cron)
+			if err != nil {
+				t.Fatalf("parseCron(%q) = %v", test.cron, err)
+			}
+			if got := c.next(test.t, test.loc); !got.Equal(test.want) {
+				t.Errorf("next(%v) = %v, want: %v", test.t, got, test.want)
+			}
+		})
+	}
+}
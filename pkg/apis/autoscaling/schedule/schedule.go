@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule implements the scale schedules of revisions, i.e.
+// recurring time windows in which the scale bounds of a revision differ.
+//
+// The binaries validating or evaluating schedules, i.e. the webhook and the
+// autoscaler, embed the time zone database with a time/tzdata import, so
+// that the time zones of the windows don't depend on the container image.
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Window is a recurring time window of a Schedule.
+type Window struct {
+	// Name identifies the window.
+	Name string
+	// Duration is how long the window lasts after every start.
+	Duration time.Duration
+	// Location is the time zone the start of the window is evaluated in.
+	Location *time.Location
+	// MinScale and MaxScale are the scale bounds while the window is
+	// active. Nil means the bound is not changed by the window.
+	MinScale *int32
+	MaxScale *int32
+
+	start *cron
+}
+
+// Schedule is a list of windows. If several windows are active at the same
+// time, the one listed first applies.
+type Schedule []Window
+
+// window is the serialized form of a Window.
+type window struct {
+	Name     string `json:"name"`
+	Start    string `json:"start"`
+	Duration string `json:"duration"`
+	TimeZone string `json:"timeZone,omitempty"`
+	MinScale *int32 `json:"minScale,omitempty"`
+	MaxScale *int32 `json:"maxScale,omitempty"`
+}
+
+// Parse parses a schedule from its JSON form, a list of windows, e.g.
+//
+//	[{"name": "business-hours", "start": "0 9 * * mon-fri", "duration": "10h",
+//	  "timeZone": "Europe/Berlin", "minScale": 3}]
+//
+// where start is a standard five field cron expression and the time zone
+// defaults to UTC.
+func Parse(s string) (Schedule, error) {
+	var ws []window
+	if err := json.Unmarshal([]byte(s), &ws); err != nil {
+		return nil, fmt.Errorf("schedule must be a JSON list of windows: %w", err)
+	}
+	ret := make(Schedule, 0, len(ws))
+	names := make(map[string]struct{}, len(ws))
+	for _, w := range ws {
+		if w.Name == "" {
+			return nil, errors.New("window name must be set")
+		}
+		if _, ok := names[w.Name]; ok {
+			return nil, fmt.Errorf("window %q is specified more than once", w.Name)
+		}
+		names[w.Name] = struct{}{}
+
+		win := Window{Name: w.Name, MinScale: w.MinScale, MaxScale: w.MaxScale}
+		var err error
+		if win.start, err = parseCron(w.Start); err != nil {
+			return nil, fmt.Errorf("invalid start of window %q: %w", w.Name, err)
+		}
+		if win.Duration, err = time.ParseDuration(w.Duration); err != nil {
+			return nil, fmt.Errorf("invalid duration of window %q: %w", w.Name, err)
+		}
+		if win.Duration <= 0 {
+			return nil, fmt.Errorf("duration of window %q must be positive, was: %v", w.Name, win.Duration)
+		}
+		if win.Duration.Round(time.Second) != win.Duration {
+			return nil, fmt.Errorf("duration of window %q must be specified with at most second precision", w.Name)
+		}
+		if win.Location, err = time.LoadLocation(w.TimeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone of window %q: %w", w.Name, err)
+		}
+		if w.MinScale == nil && w.MaxScale == nil {
+			return nil, fmt.Errorf("window %q must set minScale or maxScale", w.Name)
+		}
+		if (w.MinScale != nil && *w.MinScale < 0) || (w.MaxScale != nil && *w.MaxScale < 0) {
+			return nil, fmt.Errorf("scale bounds of window %q must not be negative", w.Name)
+		}
+		if w.MinScale != nil && w.MaxScale != nil && *w.MaxScale != 0 && *w.MaxScale < *w.MinScale {
+			return nil, fmt.Errorf("maxScale=%d of window %q is less than minScale=%d", *w.MaxScale, w.Name, *w.MinScale)
+		}
+		ret = append(ret, win)
+	}
+	return ret, nil
+}
+
+// At returns the window active at now, if any, along with the end of its
+// current occurrence, and the next time after now at which the active
+// window may change. Both times are zero if there is none.
+func (s Schedule) At(now time.Time) (active *Window, until, next time.Time) {
+	for i := range s {
+		w := &s[i]
+		// The earliest occurrence still in progress, if any.
+		if start := w.start.next(now.Add(-w.Duration), w.Location); !start.IsZero() && !start.After(now) {
+			end := start.Add(w.Duration)
+			if active == nil {
+				active, until = w, end
+			}
+			next = earliest(next, end)
+		}
+		next = earliest(next, w.start.next(now, w.Location))
+	}
+	return active, until, next
+}
+
+// earliest returns the earlier of the times, ignoring zero times.
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
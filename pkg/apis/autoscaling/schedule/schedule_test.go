@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	_ "time/tzdata"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{{
+		name: "empty",
+		s:    "[]",
+	}, {
+		name: "valid",
+		s: `[{"name": "business-hours", "start": "0 9 * * mon-fri", "duration": "10h", "timeZone": "Europe/Berlin", "minScale": 3},
+		     {"name": "night", "start": "0 22 * * *", "duration": "8h", "maxScale": 2}]`,
+	}, {
+		name:    "not JSON",
+		s:       "0 9 * * *",
+		wantErr: true,
+	}, {
+		name:    "no name",
+		s:       `[{"start": "0 9 * * *", "duration": "1h", "minScale": 1}]`,
+		wantErr: true,
+	}, {
+		name:    "duplicate name",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "1h", "minScale": 1}, {"name": "a", "start": "0 9 * * *", "duration": "1h", "minScale": 1}]`,
+		wantErr: true,
+	}, {
+		name:    "invalid start",
+		s:       `[{"name": "a", "start": "0 9 * *", "duration": "1h", "minScale": 1}]`,
+		wantErr: true,
+	}, {
+		name:    "invalid duration",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "1 hour", "minScale": 1}]`,
+		wantErr: true,
+	}, {
+		name:    "non-positive duration",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "0s", "minScale": 1}]`,
+		wantErr: true,
+	}, {
+		name:    "sub-second duration",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "1500ms", "minScale": 1}]`,
+		wantErr: true,
+	}, {
+		name:    "invalid time zone",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "1h", "timeZone": "Mars/Olympus", "minScale": 1}]`,
+		wantErr: true,
+	}, {
+		name:    "no bounds",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "1h"}]`,
+		wantErr: true,
+	}, {
+		name:    "negative bound",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "1h", "minScale": -1}]`,
+		wantErr: true,
+	}, {
+		name:    "max less than min",
+		s:       `[{"name": "a", "start": "0 9 * * *", "duration": "1h", "minScale": 3, "maxScale": 2}]`,
+		wantErr: true,
+	}, {
+		name: "unbounded max",
+		s:    `[{"name": "a", "start": "0 9 * * *", "duration": "1h", "minScale": 3, "maxScale": 0}]`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.s)
+			if (err != nil) != test.wantErr {
+				t.Errorf("Parse() = %v, wantErr: %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleAt(t *testing.T) {
+	s, err := Parse(`[
+		{"name": "business-hours", "start": "0 9 * * mon-fri", "duration": "10h", "timeZone": "Europe/Berlin", "minScale": 3},
+		{"name": "lunch", "start": "0 11 * * *", "duration": "2h", "timeZone": "Europe/Berlin", "minScale": 5, "maxScale": 10}]`)
+	if err != nil {
+		t.Fatal("Parse() =", err)
+	}
+	berlin := s[0].Location
+	date := func(d, h, min int) time.Time {
+		// March 2024 starts on a Friday.
+		return time.Date(2024, 3, d, h, min, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		want      string
+		wantUntil time.Time
+		wantNext  time.Time
+	}{{
+		name:     "before any window",
+		now:      date(1, 8, 0),
+		wantNext: date(1, 9, 0),
+	}, {
+		name:      "at the start of a window",
+		now:       date(1, 9, 0),
+		want:      "business-hours",
+		wantUntil: date(1, 19, 0),
+		wantNext:  date(1, 11, 0),
+	}, {
+		name:      "first listed window wins",
+		now:       date(1, 12, 0),
+		want:      "business-hours",
+		wantUntil: date(1, 19, 0),
+		wantNext:  date(1, 13, 0),
+	}, {
+		name:      "only the later window on weekends",
+		now:       date(2, 12, 0),
+		want:      "lunch",
+		wantUntil: date(2, 13, 0),
+		wantNext:  date(2, 13, 0),
+	}, {
+		name:     "at the end of a window",
+		now:      date(1, 19, 0),
+		wantNext: date(2, 11, 0),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, until, next := s.At(test.now)
+			var got string
+			if w != nil {
+				got = w.Name
+			}
+			if got != test.want {
+				t.Errorf("At() window = %q, want: %q", got, test.want)
+			}
+			if !until.Equal(test.wantUntil) {
+				t.Errorf("At() until = %v, want: %v", until, test.wantUntil)
+			}
+			if !next.Equal(test.wantNext) {
+				t.Errorf("At() next = %v, want: %v", next, test.wantNext)
+			}
+		})
+	}
+
+	if w, until, next := Schedule(nil).At(date(1, 0, 0)); w != nil || !until.IsZero() || !next.IsZero() {
+		t.Errorf("At() = %v, %v, %v for an empty schedule, want nothing", w, until, next)
+	}
+}
@@ -86,3 +86,10 @@ func (m *Metric) AggregationAlgorithm() string {
 	}
 	return ""
 }
+
+// ExcludesLongLivedConnections returns whether the long-lived connections are
+// left out of the concurrency of the revision.
+func (m *Metric) ExcludesLongLivedConnections() bool {
+	_, s, _ := autoscaling.LongLivedConnectionsAnnotation.Get(m.Annotations)
+	return s == autoscaling.LongLivedConnectionsExclude
+}
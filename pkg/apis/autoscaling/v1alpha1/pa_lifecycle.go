@@ -27,6 +27,7 @@ import (
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmap"
 	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/apis/autoscaling/schedule"
 	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 )
@@ -65,6 +66,17 @@ func (pa *PodAutoscaler) Metric() string {
 	return defaultMetric(pa.Class())
 }
 
+// AdditionalMetrics returns the targets of the metrics to scale on in addition
+// to Metric, keyed by metric, or nil if there are none or the annotation is invalid.
+func (pa *PodAutoscaler) AdditionalMetrics() map[string]float64 {
+	if _, s, ok := autoscaling.AdditionalMetricsAnnotation.Get(pa.Annotations); ok {
+		if m, err := autoscaling.ParseAdditionalMetrics(s); err == nil {
+			return m
+		}
+	}
+	return nil
+}
+
 func (pa *PodAutoscaler) annotationInt32(k kmap.KeyPriority) (int32, bool) {
 	if _, s, ok := k.Get(pa.Annotations); ok {
 		i, err := strconv.ParseInt(s, 10, 32)
@@ -83,25 +95,48 @@ func (pa *PodAutoscaler) annotationFloat64(k kmap.KeyPriority) (float64, bool) {
 
 // ScaleBounds returns scale bounds annotations values as a tuple:
 // `(min, max int32)`. The value of 0 for any of min or max means the bound is
-// not set.
-// Note: min will be ignored if the PA is not reachable
+// not set. The bounds of the active window of the scale schedule, as reported
+// in the status, take precedence over the annotations.
+// Note: min will be ignored if the PA is not reachable, unlike the warm scale
+// of the spec, which raises min if larger.
 func (pa *PodAutoscaler) ScaleBounds(asConfig *autoscalerconfig.Config) (int32, int32) {
+	w := pa.Status.ActiveScaleWindow
 	var min int32
 	if pa.Spec.Reachability != ReachabilityUnreachable {
 		min = asConfig.MinScale
 		if paMin, ok := pa.annotationInt32(autoscaling.MinScaleAnnotation); ok {
 			min = paMin
 		}
+		if w != nil && w.MinScale != nil {
+			min = *w.MinScale
+		}
+	}
+	if pa.Spec.WarmScale > min {
+		min = pa.Spec.WarmScale
 	}
 
 	max := asConfig.MaxScale
 	if paMax, ok := pa.annotationInt32(autoscaling.MaxScaleAnnotation); ok {
 		max = paMax
 	}
+	if w != nil && w.MaxScale != nil {
+		max = *w.MaxScale
+	}
 
 	return min, max
 }
 
+// ScaleSchedule returns the scale schedule annotation value, or nil if not
+// present or invalid.
+func (pa *PodAutoscaler) ScaleSchedule() schedule.Schedule {
+	if _, s, ok := autoscaling.ScaleScheduleAnnotation.Get(pa.Annotations); ok {
+		if sched, err := schedule.Parse(s); err == nil {
+			return sched
+		}
+	}
+	return nil
+}
+
 // ActivationScale returns the min-non-zero-replicas annotation value or falise
 // if not present or invalid.
 func (pa *PodAutoscaler) ActivationScale() (int32, bool) {
@@ -155,6 +190,71 @@ func (pa *PodAutoscaler) ScaleDownDelay() (time.Duration, bool) {
 	return pa.annotationDuration(autoscaling.ScaleDownDelayAnnotation)
 }
 
+// ScaleUpRules returns the scale up policies of the annotations, falling back
+// to the ones of the autoscaler config for the values not present or invalid.
+func (pa *PodAutoscaler) ScaleUpRules(asConfig *autoscalerconfig.Config) autoscalerconfig.ScalingRules {
+	return pa.scalingRules(asConfig.ScaleUpRules,
+		autoscaling.ScaleUpPoliciesAnnotation, autoscaling.ScaleUpSelectPolicyAnnotation)
+}
+
+// ScaleDownRules returns the scale down policies of the annotations, falling
+// back to the ones of the autoscaler config for the values not present or invalid.
+func (pa *PodAutoscaler) ScaleDownRules(asConfig *autoscalerconfig.Config) autoscalerconfig.ScalingRules {
+	return pa.scalingRules(asConfig.ScaleDownRules,
+		autoscaling.ScaleDownPoliciesAnnotation, autoscaling.ScaleDownSelectPolicyAnnotation)
+}
+
+func (pa *PodAutoscaler) scalingRules(rules autoscalerconfig.ScalingRules, policiesKey, selectKey kmap.KeyPriority) autoscalerconfig.ScalingRules {
+	rules = *rules.DeepCopy()
+	if _, s, ok := policiesKey.Get(pa.Annotations); ok {
+		if p, err := autoscaling.ParseScalingPolicies(s); err == nil {
+			rules.Policies = p
+		}
+	}
+	if _, s, ok := selectKey.Get(pa.Annotations); ok {
+		if sp, err := autoscaling.ParseScalingPolicySelect(s); err == nil {
+			rules.SelectPolicy = sp
+		}
+	}
+	return rules
+}
+
+// ScalingAlgorithm returns the scaling algorithm annotation value, or the
+// default algorithm if not present.
+func (pa *PodAutoscaler) ScalingAlgorithm() string {
+	if _, a, ok := autoscaling.ScalingAlgorithmAnnotation.Get(pa.Annotations); ok {
+		return a
+	}
+	return autoscaling.ScalingAlgorithmDefault
+}
+
+// ScalingAlgorithmParameters returns the parameters of the scaling algorithm
+// set through annotations, or nil if there are none.
+func (pa *PodAutoscaler) ScalingAlgorithmParameters() map[string]string {
+	return autoscaling.ScalingAlgorithmParameters(pa.Annotations)
+}
+
+// ScalingMode returns the scaling mode annotation value, or the reactive
+// mode if not present.
+func (pa *PodAutoscaler) ScalingMode() string {
+	if _, m, ok := autoscaling.ScalingModeAnnotation.Get(pa.Annotations); ok {
+		return m
+	}
+	return autoscaling.ScalingModeReactive
+}
+
+// PredictiveHorizon returns the predictive horizon annotation value, or false if not present.
+func (pa *PodAutoscaler) PredictiveHorizon() (time.Duration, bool) {
+	// The value is validated in the webhook.
+	return pa.annotationDuration(autoscaling.PredictiveHorizonAnnotation)
+}
+
+// SeasonalPeriod returns the seasonal period annotation value, or false if not present.
+func (pa *PodAutoscaler) SeasonalPeriod() (time.Duration, bool) {
+	// The value is validated in the webhook.
+	return pa.annotationDuration(autoscaling.SeasonalPeriodAnnotation)
+}
+
 // PanicWindowPercentage returns the panic window annotation value, or false if not present.
 func (pa *PodAutoscaler) PanicWindowPercentage() (percentage float64, ok bool) {
 	// The value is validated in the webhook.
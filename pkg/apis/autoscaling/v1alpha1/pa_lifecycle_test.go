@@ -20,6 +20,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -540,6 +542,8 @@ func TestScaleBounds(t *testing.T) {
 		max          string
 		config       autoscalerconfig.Config
 		reachability ReachabilityType
+		window       *ScaleWindow
+		warmScale    int32
 		wantMin      int32
 		wantMax      int32
 	}{{
@@ -604,11 +608,56 @@ func TestScaleBounds(t *testing.T) {
 		},
 		reachability: ReachabilityUnreachable,
 		wantMin:      0,
+	}, {
+		name:    "active scale window",
+		min:     "1",
+		max:     "100",
+		window:  &ScaleWindow{Name: "day", MinScale: ptr.Int32(5), MaxScale: ptr.Int32(50)},
+		wantMin: 5,
+		wantMax: 50,
+	}, {
+		name:    "active scale window, only min",
+		min:     "1",
+		max:     "100",
+		window:  &ScaleWindow{Name: "day", MinScale: ptr.Int32(5)},
+		wantMin: 5,
+		wantMax: 100,
+	}, {
+		name:         "active scale window, unreachable",
+		min:          "1",
+		max:          "100",
+		reachability: ReachabilityUnreachable,
+		window:       &ScaleWindow{Name: "day", MinScale: ptr.Int32(5), MaxScale: ptr.Int32(50)},
+		wantMin:      0,
+		wantMax:      50,
+	}, {
+		name:      "warm scale",
+		min:       "1",
+		max:       "100",
+		warmScale: 4,
+		wantMin:   4,
+		wantMax:   100,
+	}, {
+		name:      "warm scale below min",
+		min:       "5",
+		max:       "100",
+		warmScale: 4,
+		wantMin:   5,
+		wantMax:   100,
+	}, {
+		name:         "warm scale, unreachable",
+		min:          "1",
+		max:          "100",
+		reachability: ReachabilityUnreachable,
+		warmScale:    4,
+		wantMin:      4,
+		wantMax:      100,
 	}}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			pa := pa(map[string]string{})
+			pa.Status.ActiveScaleWindow = tc.window
 			if tc.min != "" {
 				pa.Annotations[autoscaling.MinScaleAnnotationKey] = tc.min
 			}
@@ -616,6 +665,7 @@ func TestScaleBounds(t *testing.T) {
 				pa.Annotations[autoscaling.MaxScaleAnnotationKey] = tc.max
 			}
 			pa.Spec.Reachability = tc.reachability
+			pa.Spec.WarmScale = tc.warmScale
 
 			min, max := pa.ScaleBounds(&tc.config)
 
@@ -629,6 +679,39 @@ func TestScaleBounds(t *testing.T) {
 	}
 }
 
+func TestScaleSchedule(t *testing.T) {
+	cases := []struct {
+		name      string
+		schedule  string
+		wantNames []string
+	}{{
+		name: "not present",
+	}, {
+		name:      "present",
+		schedule:  `[{"name": "day", "start": "0 9 * * *", "duration": "10h", "minScale": 3}, {"name": "night", "start": "0 19 * * *", "duration": "14h", "maxScale": 1}]`,
+		wantNames: []string{"day", "night"},
+	}, {
+		name:     "invalid",
+		schedule: `[{"name": "day", "start": "0 9 * * *", "duration": "10h"}]`,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pa := pa(map[string]string{})
+			if tc.schedule != "" {
+				pa.Annotations[autoscaling.ScaleScheduleAnnotationKey] = tc.schedule
+			}
+			var got []string
+			for _, w := range pa.ScaleSchedule() {
+				got = append(got, w.Name)
+			}
+			if !cmp.Equal(got, tc.wantNames) {
+				t.Errorf("ScaleSchedule() windows = %v, want: %v", got, tc.wantNames)
+			}
+		})
+	}
+}
+
 func TestMarkResourceNotOwned(t *testing.T) {
 	pa := pa(map[string]string{})
 	pa.Status.MarkResourceNotOwned("doesn't", "matter")
@@ -782,6 +865,195 @@ func TestScaleDownDelayAnnotation(t *testing.T) {
 	}
 }
 
+func TestAdditionalMetrics(t *testing.T) {
+	cases := []struct {
+		name string
+		pa   *PodAutoscaler
+		want map[string]float64
+	}{{
+		name: "not present",
+		pa:   pa(map[string]string{}),
+	}, {
+		name: "present",
+		pa: pa(map[string]string{
+			autoscaling.AdditionalMetricsAnnotationKey: "rps=150, concurrency=10.5",
+		}),
+		want: map[string]float64{autoscaling.RPS: 150, autoscaling.Concurrency: 10.5},
+	}, {
+		name: "invalid",
+		pa: pa(map[string]string{
+			autoscaling.AdditionalMetricsAnnotationKey: "rps=fast",
+		}),
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.pa.AdditionalMetrics(); !cmp.Equal(got, tc.want) {
+				t.Errorf("AdditionalMetrics = %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScalingRules(t *testing.T) {
+	config := &autoscalerconfig.Config{
+		ScaleUpRules: autoscalerconfig.ScalingRules{
+			SelectPolicy: autoscalerconfig.MaxChangePolicySelect,
+		},
+		ScaleDownRules: autoscalerconfig.ScalingRules{
+			SelectPolicy: autoscalerconfig.MaxChangePolicySelect,
+			Policies: []autoscalerconfig.ScalingPolicy{{
+				Type:   autoscalerconfig.PodsScalingPolicy,
+				Value:  2,
+				Period: time.Minute,
+			}},
+		},
+	}
+	cases := []struct {
+		name     string
+		pa       *PodAutoscaler
+		wantUp   autoscalerconfig.ScalingRules
+		wantDown autoscalerconfig.ScalingRules
+	}{{
+		name:     "not present",
+		pa:       pa(map[string]string{}),
+		wantUp:   config.ScaleUpRules,
+		wantDown: config.ScaleDownRules,
+	}, {
+		name: "present",
+		pa: pa(map[string]string{
+			autoscaling.ScaleUpPoliciesAnnotationKey:       "percent=100/15s,pods=4/15s",
+			autoscaling.ScaleDownSelectPolicyAnnotationKey: "disabled",
+		}),
+		wantUp: autoscalerconfig.ScalingRules{
+			SelectPolicy: autoscalerconfig.MaxChangePolicySelect,
+			Policies: []autoscalerconfig.ScalingPolicy{{
+				Type:   autoscalerconfig.PercentScalingPolicy,
+				Value:  100,
+				Period: 15 * time.Second,
+			}, {
+				Type:   autoscalerconfig.PodsScalingPolicy,
+				Value:  4,
+				Period: 15 * time.Second,
+			}},
+		},
+		wantDown: autoscalerconfig.ScalingRules{
+			SelectPolicy: autoscalerconfig.DisabledPolicySelect,
+			Policies:     config.ScaleDownRules.Policies,
+		},
+	}, {
+		name: "empty policies",
+		pa: pa(map[string]string{
+			autoscaling.ScaleDownPoliciesAnnotationKey: "",
+		}),
+		wantUp: config.ScaleUpRules,
+		wantDown: autoscalerconfig.ScalingRules{
+			SelectPolicy: autoscalerconfig.MaxChangePolicySelect,
+		},
+	}, {
+		name: "invalid",
+		pa: pa(map[string]string{
+			autoscaling.ScaleDownPoliciesAnnotationKey:     "pods=-1/1m",
+			autoscaling.ScaleDownSelectPolicyAnnotationKey: "fastest",
+		}),
+		wantUp:   config.ScaleUpRules,
+		wantDown: config.ScaleDownRules,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.pa.ScaleUpRules(config); !cmp.Equal(got, tc.wantUp) {
+				t.Error("ScaleUpRules (-want,+got):", cmp.Diff(tc.wantUp, got))
+			}
+			if got := tc.pa.ScaleDownRules(config); !cmp.Equal(got, tc.wantDown) {
+				t.Error("ScaleDownRules (-want,+got):", cmp.Diff(tc.wantDown, got))
+			}
+		})
+	}
+}
+
+func TestScalingAlgorithmAnnotations(t *testing.T) {
+	cases := []struct {
+		name           string
+		pa             *PodAutoscaler
+		wantAlgorithm  string
+		wantParameters map[string]string
+	}{{
+		name:          "not present",
+		pa:            pa(map[string]string{}),
+		wantAlgorithm: autoscaling.ScalingAlgorithmDefault,
+	}, {
+		name: "present",
+		pa: pa(map[string]string{
+			autoscaling.ScalingAlgorithmAnnotationKey:          "pid",
+			autoscaling.ScalingAlgorithmParameterPrefix + "kp": "0.5",
+		}),
+		wantAlgorithm:  "pid",
+		wantParameters: map[string]string{"kp": "0.5"},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.pa.ScalingAlgorithm(); got != tc.wantAlgorithm {
+				t.Errorf("ScalingAlgorithm = %q, want: %q", got, tc.wantAlgorithm)
+			}
+			if got := tc.pa.ScalingAlgorithmParameters(); !cmp.Equal(got, tc.wantParameters) {
+				t.Errorf("ScalingAlgorithmParameters = %v, want: %v", got, tc.wantParameters)
+			}
+		})
+	}
+}
+
+func TestScalingModeAnnotations(t *testing.T) {
+	cases := []struct {
+		name        string
+		pa          *PodAutoscaler
+		wantMode    string
+		wantHorizon time.Duration
+		wantPeriod  time.Duration
+		wantOK      bool
+	}{{
+		name:     "not present",
+		pa:       pa(map[string]string{}),
+		wantMode: autoscaling.ScalingModeReactive,
+	}, {
+		name: "present",
+		pa: pa(map[string]string{
+			autoscaling.ScalingModeAnnotationKey:       autoscaling.ScalingModePredictive,
+			autoscaling.PredictiveHorizonAnnotationKey: "90s",
+			autoscaling.SeasonalPeriodAnnotationKey:    "168h",
+		}),
+		wantMode:    autoscaling.ScalingModePredictive,
+		wantHorizon: 90 * time.Second,
+		wantPeriod:  7 * 24 * time.Hour,
+		wantOK:      true,
+	}, {
+		name: "invalid",
+		pa: pa(map[string]string{
+			autoscaling.ScalingModeAnnotationKey:       autoscaling.ScalingModePredictive,
+			autoscaling.PredictiveHorizonAnnotationKey: "soon",
+			autoscaling.SeasonalPeriodAnnotationKey:    "weekly",
+		}),
+		wantMode: autoscaling.ScalingModePredictive,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.pa.ScalingMode(); got != tc.wantMode {
+				t.Errorf("ScalingMode = %q, want: %q", got, tc.wantMode)
+			}
+			gotHorizon, gotOK := tc.pa.PredictiveHorizon()
+			if gotHorizon != tc.wantHorizon || gotOK != tc.wantOK {
+				t.Errorf("PredictiveHorizon = (%v, %v), want: (%v, %v)", gotHorizon, gotOK, tc.wantHorizon, tc.wantOK)
+			}
+			gotPeriod, gotOK := tc.pa.SeasonalPeriod()
+			if gotPeriod != tc.wantPeriod || gotOK != tc.wantOK {
+				t.Errorf("SeasonalPeriod = (%v, %v), want: (%v, %v)", gotPeriod, gotOK, tc.wantPeriod, tc.wantOK)
+			}
+		})
+	}
+}
+
 func TestProgressDelayAnnotation(t *testing.T) {
 	cases := []struct {
 		name      string
@@ -95,6 +95,12 @@ type PodAutoscalerSpec struct {
 
 	// The application-layer protocol. Matches `ProtocolType` inferred from the revision spec.
 	ProtocolType net.ProtocolType `json:"protocolType"`
+
+	// WarmScale is the number of replicas the `ScaleTargetRef` is kept at,
+	// at least, regardless of its traffic, e.g. while it waits for the
+	// promotion of a blue/green rollout. It overrides the min scale if larger.
+	// +optional
+	WarmScale int32 `json:"warmScale,omitempty"`
 }
 
 const (
@@ -127,6 +133,81 @@ type PodAutoscalerStatus struct {
 
 	// ActualScale shows the actual number of replicas for the revision.
 	ActualScale *int32 `json:"actualScale,omitempty"`
+
+	// LastScaleDecision summarizes the latest scaling decision of the autoscaler.
+	// +optional
+	LastScaleDecision *ScaleDecision `json:"lastScaleDecision,omitempty"`
+
+	// ActiveScaleWindow is the window of the scale schedule that is currently
+	// active, whose scale bounds override the ones of the annotations.
+	// +optional
+	ActiveScaleWindow *ScaleWindow `json:"activeScaleWindow,omitempty"`
+
+	// ResponseStats are the stats of the responses of the revision over the
+	// stable window, for the analysis of the rollouts of the routes. They're
+	// only set while a route of the revision analyzes its rollouts.
+	// +optional
+	ResponseStats *ResponseStats `json:"responseStats,omitempty"`
+}
+
+// ResponseStats are the stats of the responses of a revision over the stable
+// window of its PodAutoscaler.
+type ResponseStats struct {
+	// ResponseCount is the number of responses over the window.
+	ResponseCount int64 `json:"responseCount"`
+
+	// ServerErrorCount is the number of the responses that were server
+	// errors, i.e. had a 5xx status code or a gRPC status that indicates a
+	// failure of the server.
+	ServerErrorCount int64 `json:"serverErrorCount"`
+
+	// MeanLatency is the mean latency of the responses.
+	MeanLatency metav1.Duration `json:"meanLatency"`
+}
+
+// ScaleWindow is an active window of the scale schedule of a PodAutoscaler.
+type ScaleWindow struct {
+	// Name is the name of the window in the schedule.
+	Name string `json:"name"`
+
+	// MinScale is the minimum scale while the window is active, if the
+	// window sets it.
+	// +optional
+	MinScale *int32 `json:"minScale,omitempty"`
+
+	// MaxScale is the maximum scale while the window is active, if the
+	// window sets it.
+	// +optional
+	MaxScale *int32 `json:"maxScale,omitempty"`
+
+	// Until is the end of the current occurrence of the window.
+	Until metav1.Time `json:"until"`
+}
+
+// ScaleDecision summarizes a scaling decision of the autoscaler.
+type ScaleDecision struct {
+	// Time is when the decision was made. It is excluded from equality, so
+	// that it doesn't cause status updates on its own.
+	Time apis.VolatileTime `json:"time"`
+
+	// ScalingMetric is the metric that required the most pods.
+	// +optional
+	ScalingMetric string `json:"scalingMetric,omitempty"`
+
+	// ReadyPodCount is the number of ready pods the decision was based on.
+	ReadyPodCount int32 `json:"readyPodCount"`
+
+	// DesiredPodCount is the number of pods the autoscaler decided on.
+	DesiredPodCount int32 `json:"desiredPodCount"`
+
+	// PanicMode is whether the autoscaler was in panic mode.
+	// +optional
+	PanicMode bool `json:"panicMode,omitempty"`
+
+	// Message is a human readable description of what held the desired pod
+	// count back, e.g. a delayed scale down.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
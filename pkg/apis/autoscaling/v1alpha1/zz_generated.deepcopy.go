@@ -212,6 +212,21 @@ func (in *PodAutoscalerStatus) DeepCopyInto(out *PodAutoscalerStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.LastScaleDecision != nil {
+		in, out := &in.LastScaleDecision, &out.LastScaleDecision
+		*out = new(ScaleDecision)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveScaleWindow != nil {
+		in, out := &in.ActiveScaleWindow, &out.ActiveScaleWindow
+		*out = new(ScaleWindow)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseStats != nil {
+		in, out := &in.ResponseStats, &out.ResponseStats
+		*out = new(ResponseStats)
+		**out = **in
+	}
 	return
 }
 
@@ -328,3 +343,64 @@ func (in *PodScalableStatus) DeepCopy() *PodScalableStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResponseStats) DeepCopyInto(out *ResponseStats) {
+	*out = *in
+	out.MeanLatency = in.MeanLatency
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseStats.
+func (in *ResponseStats) DeepCopy() *ResponseStats {
+	if in == nil {
+		return nil
+	}
+	out := new(ResponseStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleDecision) DeepCopyInto(out *ScaleDecision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleDecision.
+func (in *ScaleDecision) DeepCopy() *ScaleDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleWindow) DeepCopyInto(out *ScaleWindow) {
+	*out = *in
+	if in.MinScale != nil {
+		in, out := &in.MinScale, &out.MinScale
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxScale != nil {
+		in, out := &in.MaxScale, &out.MaxScale
+		*out = new(int32)
+		**out = **in
+	}
+	in.Until.DeepCopyInto(&out.Until)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleWindow.
+func (in *ScaleWindow) DeepCopy() *ScaleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -77,6 +77,7 @@ func defaultFeaturesConfig() *Features {
 		PodSpecDNSConfig:                 Disabled,
 		SecurePodDefaults:                Disabled,
 		TagHeaderBasedRouting:            Disabled,
+		RouteRules:                       Disabled,
 		AutoDetectHTTP2:                  Disabled,
 	}
 }
@@ -108,6 +109,7 @@ func NewFeaturesConfigFromMap(data map[string]string) (*Features, error) {
 		asFlag("kubernetes.podspec-dnsconfig", &nc.PodSpecDNSConfig),
 		asFlag("secure-pod-defaults", &nc.SecurePodDefaults),
 		asFlag("tag-header-based-routing", &nc.TagHeaderBasedRouting),
+		asFlag("route-rules", &nc.RouteRules),
 		asFlag("queueproxy.resource-defaults", &nc.QueueProxyResourceDefaults),
 		asFlag("queueproxy.mount-podinfo", &nc.QueueProxyMountPodInfo),
 		asFlag("autodetect-http2", &nc.AutoDetectHTTP2)); err != nil {
@@ -147,6 +149,7 @@ type Features struct {
 	PodSpecDNSConfig                 Flag
 	SecurePodDefaults                Flag
 	TagHeaderBasedRouting            Flag
+	RouteRules                       Flag
 	AutoDetectHTTP2                  Flag
 }
 
@@ -394,6 +394,15 @@ func TestFeaturesConfiguration(t *testing.T) {
 			data: map[string]string{
 				"tag-header-based-routing": "Enabled",
 			},
+		}, {
+			name:    "route-rules Enabled",
+			wantErr: false,
+			wantFeatures: defaultWith(&Features{
+				RouteRules: Enabled,
+			}),
+			data: map[string]string{
+				"route-rules": "Enabled",
+			},
 		}, {
 			name:    "kubernetes.podspec-volumes-emptyDir Disabled",
 			wantErr: false,
@@ -19,6 +19,7 @@ package serving
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -75,6 +76,100 @@ func ValidateRolloutDurationAnnotation(annos map[string]string) (errs *apis.Fiel
 	return errs
 }
 
+// ValidateRolloutStepsAnnotation validates the rollout steps annotation.
+// This annotation can be set on either service or configuration objects.
+func ValidateRolloutStepsAnnotation(annos map[string]string) *apis.FieldError {
+	if k, v, ok := RolloutStepsAnnotation.Get(annos); ok {
+		if _, err := ParseRolloutSteps(v); err != nil {
+			fe := apis.ErrInvalidValue(v, k)
+			fe.Details = err.Error()
+			return fe
+		}
+	}
+	return nil
+}
+
+// ValidateRolloutControlAnnotation validates the annotation controlling the
+// rollouts in progress. It can be set on either service or route objects.
+func ValidateRolloutControlAnnotation(annos map[string]string) *apis.FieldError {
+	if k, v, ok := RolloutControlAnnotation.Get(annos); ok {
+		switch v {
+		case RolloutControlPause, RolloutControlResume, RolloutControlPromote:
+		default:
+			return apis.ErrInvalidValue(v, k)
+		}
+	}
+	return nil
+}
+
+// ValidateRolloutStrategyAnnotations validates the annotations of the
+// strategy of the rollouts. These annotations can be set on either service
+// or route objects.
+func ValidateRolloutStrategyAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	if k, v, ok := RolloutStrategyAnnotation.Get(annos); ok {
+		switch v {
+		case RolloutStrategyGradual, RolloutStrategyBlueGreen:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		}
+	}
+	if k, v, ok := BlueGreenRollbackWindowAnnotation.Get(annos); ok {
+		if d, err := time.ParseDuration(v); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		} else if d < 0 {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("blue-green-rollback-window=%s must be non-negative", v),
+				Paths:   []string{k},
+			})
+		}
+	}
+	return errs
+}
+
+// ValidateRollbackAnnotation validates the annotation rolling a service back
+// to one of its previous revisions. It can be set on service objects only.
+func ValidateRollbackAnnotation(annos map[string]string) *apis.FieldError {
+	if k, v, ok := RollbackAnnotation.Get(annos); ok {
+		if n, err := strconv.Atoi(v); err != nil {
+			return apis.ErrInvalidValue(v, k)
+		} else if n <= 0 {
+			return &apis.FieldError{
+				Message: fmt.Sprintf("rollback=%s must be positive", v),
+				Paths:   []string{k},
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateRolloutAnalysisAnnotations validates the annotations of the analysis
+// of the rollouts. These annotations can be set on either service or route objects.
+func ValidateRolloutAnalysisAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	if k, v, ok := RolloutMinSuccessPercentageAnnotation.Get(annos); ok {
+		if p, err := strconv.ParseFloat(v, 64); err != nil || p <= 0 || p > 100 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(v, "0 (exclusive)", 100, k))
+		}
+	}
+	if k, v, ok := RolloutMaxLatencyAnnotation.Get(annos); ok {
+		if d, err := time.ParseDuration(v); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		} else if d <= 0 {
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("rollout-max-latency=%s must be positive", v),
+				Paths:   []string{k},
+			})
+		}
+	}
+	if k, v, ok := RolloutAnalysisActionAnnotation.Get(annos); ok {
+		switch v {
+		case RolloutAnalysisActionRollback, RolloutAnalysisActionPause:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(v, k))
+		}
+	}
+	return errs
+}
+
 // ValidateHasNoAutoscalingAnnotation validates that the respective entity does not have
 // annotations from the autoscaling group. It's to be used to validate Service and
 // Configuration.
@@ -490,3 +490,148 @@ func TestValidateRolloutDurationAnnotation(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRolloutAnalysisAnnotations(t *testing.T) {
+	tests := []struct {
+		name  string
+		annos map[string]string
+		want  string
+	}{{
+		name: "empty",
+	}, {
+		name: "valid",
+		annos: map[string]string{
+			RolloutMinSuccessPercentageKey: "99.5",
+			RolloutMaxLatencyKey:           "250ms",
+			RolloutAnalysisActionKey:       RolloutAnalysisActionPause,
+		},
+	}, {
+		name: "success percentage not a number",
+		annos: map[string]string{
+			RolloutMinSuccessPercentageKey: "most",
+		},
+		want: "expected 0 (exclusive) <= most <= 100: serving.knative.dev/rollout-min-success-percentage",
+	}, {
+		name: "success percentage too high",
+		annos: map[string]string{
+			RolloutMinSuccessPercentageKey: "100.1",
+		},
+		want: "expected 0 (exclusive) <= 100.1 <= 100: serving.knative.dev/rollout-min-success-percentage",
+	}, {
+		name: "success percentage zero",
+		annos: map[string]string{
+			RolloutMinSuccessPercentageKey: "0",
+		},
+		want: "expected 0 (exclusive) <= 0 <= 100: serving.knative.dev/rollout-min-success-percentage",
+	}, {
+		name: "latency not a duration",
+		annos: map[string]string{
+			RolloutMaxLatencyKey: "fast",
+		},
+		want: "invalid value: fast: serving.knative.dev/rollout-max-latency",
+	}, {
+		name: "latency not positive",
+		annos: map[string]string{
+			RolloutMaxLatencyKey: "0s",
+		},
+		want: "rollout-max-latency=0s must be positive: serving.knative.dev/rollout-max-latency",
+	}, {
+		name: "unknown action",
+		annos: map[string]string{
+			RolloutAnalysisActionKey: "panic",
+		},
+		want: "invalid value: panic: serving.knative.dev/rollout-analysis-action",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateRolloutAnalysisAnnotations(tc.annos)
+			if got, want := err.Error(), tc.want; got != want {
+				t.Errorf("APIErr mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestValidateRolloutStrategyAnnotations(t *testing.T) {
+	cases := []struct {
+		name    string
+		annos   map[string]string
+		wantErr string
+	}{{
+		name: "empty",
+	}, {
+		name:  "gradual",
+		annos: map[string]string{RolloutStrategyKey: RolloutStrategyGradual},
+	}, {
+		name: "blue-green with rollback window",
+		annos: map[string]string{
+			RolloutStrategyKey:         RolloutStrategyBlueGreen,
+			BlueGreenRollbackWindowKey: "10m",
+		},
+	}, {
+		name:    "invalid strategy",
+		annos:   map[string]string{RolloutStrategyKey: "canary"},
+		wantErr: "invalid value: canary: serving.knative.dev/rollout-strategy",
+	}, {
+		name:    "invalid rollback window",
+		annos:   map[string]string{BlueGreenRollbackWindowKey: "a while"},
+		wantErr: "invalid value: a while: serving.knative.dev/blue-green-rollback-window",
+	}, {
+		name:    "negative rollback window",
+		annos:   map[string]string{BlueGreenRollbackWindowKey: "-1m"},
+		wantErr: "blue-green-rollback-window=-1m must be non-negative: serving.knative.dev/blue-green-rollback-window",
+	}}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateRolloutStrategyAnnotations(c.annos)
+			if got, want := err.Error(), c.wantErr; got != want {
+				t.Errorf("APIErr mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestValidateRollbackAnnotation(t *testing.T) {
+	cases := []struct {
+		name    string
+		annos   map[string]string
+		wantErr string
+	}{{
+		name: "empty",
+	}, {
+		name:  "previous revision",
+		annos: map[string]string{RollbackKey: "1"},
+	}, {
+		name:    "not a number",
+		annos:   map[string]string{RollbackKey: "last"},
+		wantErr: "invalid value: last: serving.knative.dev/rollback",
+	}, {
+		name:    "zero",
+		annos:   map[string]string{RollbackKey: "0"},
+		wantErr: "rollback=0 must be positive: serving.knative.dev/rollback",
+	}}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := ValidateRollbackAnnotation(c.annos)
+			if got, want := err.Error(), c.wantErr; got != want {
+				t.Errorf("APIErr mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestValidateRolloutControlAnnotation(t *testing.T) {
+	for _, v := range []string{RolloutControlPause, RolloutControlResume, RolloutControlPromote} {
+		if err := ValidateRolloutControlAnnotation(map[string]string{RolloutControlKey: v}); err != nil {
+			t.Errorf("ValidateRolloutControlAnnotation(%q) = %v", v, err)
+		}
+	}
+	if err := ValidateRolloutControlAnnotation(nil); err != nil {
+		t.Error("ValidateRolloutControlAnnotation(nil) =", err)
+	}
+	err := ValidateRolloutControlAnnotation(map[string]string{RolloutControlKey: "stop"})
+	if got, want := err.Error(), "invalid value: stop: serving.knative.dev/rollout-control"; got != want {
+		t.Errorf("APIErr mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PriorityClass is a class of requests the queue-proxy queues separately.
+type PriorityClass struct {
+	// Name is the value of the priority header selecting the class.
+	Name string
+	// Weight is the share of the freed capacity the class gets under
+	// weighted queueing.
+	Weight int
+	// QueueDepth is the number of requests of the class that may wait for
+	// capacity before further ones are rejected.
+	QueueDepth int
+}
+
+// ParsePriorityClasses parses the priority classes in the form of
+// QueueSidecarPriorityClassesAnnotationKey.
+func ParsePriorityClasses(s string) ([]PriorityClass, error) {
+	var ret []PriorityClass
+	names := make(map[string]struct{})
+	for _, c := range strings.Split(s, ",") {
+		c = strings.TrimSpace(c)
+		name, spec, ok := strings.Cut(c, "=")
+		weight, depth, ok2 := strings.Cut(spec, "/")
+		if !ok || !ok2 || name == "" {
+			return nil, fmt.Errorf("%q is not a name=weight/queue-depth class", c)
+		}
+		if _, ok := names[name]; ok {
+			return nil, fmt.Errorf("class %q is specified more than once", name)
+		}
+		names[name] = struct{}{}
+		pc := PriorityClass{Name: name}
+		var err error
+		if pc.Weight, err = strconv.Atoi(weight); err != nil || pc.Weight < 1 {
+			return nil, fmt.Errorf("weight of class %q must be a positive integer", name)
+		}
+		if pc.QueueDepth, err = strconv.Atoi(depth); err != nil || pc.QueueDepth < 1 {
+			return nil, fmt.Errorf("queue depth of class %q must be a positive integer", name)
+		}
+		ret = append(ret, pc)
+	}
+	return ret, nil
+}
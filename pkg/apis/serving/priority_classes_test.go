@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParsePriorityClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []PriorityClass
+		wantErr string
+	}{{
+		name: "single",
+		s:    "default=1/100",
+		want: []PriorityClass{{Name: "default", Weight: 1, QueueDepth: 100}},
+	}, {
+		name: "multiple",
+		s:    "interactive=4/50, batch=1/500",
+		want: []PriorityClass{
+			{Name: "interactive", Weight: 4, QueueDepth: 50},
+			{Name: "batch", Weight: 1, QueueDepth: 500},
+		},
+	}, {
+		name:    "empty",
+		s:       "",
+		wantErr: `"" is not a name=weight/queue-depth class`,
+	}, {
+		name:    "no queue depth",
+		s:       "interactive=4",
+		wantErr: `"interactive=4" is not a name=weight/queue-depth class`,
+	}, {
+		name:    "no name",
+		s:       "=4/50",
+		wantErr: `"=4/50" is not a name=weight/queue-depth class`,
+	}, {
+		name:    "duplicate",
+		s:       "a=1/1,a=2/2",
+		wantErr: `class "a" is specified more than once`,
+	}, {
+		name:    "invalid weight",
+		s:       "a=0/1",
+		wantErr: `weight of class "a" must be a positive integer`,
+	}, {
+		name:    "invalid queue depth",
+		s:       "a=1/many",
+		wantErr: `queue depth of class "a" must be a positive integer`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParsePriorityClasses(test.s)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("ParsePriorityClasses() = %v, want error: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("ParsePriorityClasses() =", err)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("ParsePriorityClasses() (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
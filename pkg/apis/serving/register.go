@@ -17,6 +17,8 @@ limitations under the License.
 package serving
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/kmap"
 )
@@ -60,6 +62,112 @@ const (
 	// The value can be specified with at most with a second precision.
 	RolloutDurationKey = GroupName + "/rollout-duration"
 
+	// RolloutStepsKey is an annotation attached to a Configuration or a Service
+	// to roll out its new revisions in explicit steps rather than linearly over
+	// the rollout duration. The value is a comma separated list of steps in
+	// the form `<percent>:<hold>`, e.g. `1:10m,5:30m,25:10m`, with increasing
+	// percentages of the traffic of the configuration. After the hold of the
+	// last step the new revision gets all of it.
+	RolloutStepsKey = GroupName + "/rollout-steps"
+
+	// RolloutControlKey is an annotation attached to a Route or a Service to
+	// control its gradual rollouts in progress, either RolloutControlPause,
+	// RolloutControlResume, the default, or RolloutControlPromote.
+	RolloutControlKey = GroupName + "/rollout-control"
+
+	// RolloutControlPause freezes the traffic split of the rollouts in
+	// progress until the rollouts are resumed.
+	RolloutControlPause = "pause"
+
+	// RolloutControlResume carries on the paused rollouts from where they
+	// were paused.
+	RolloutControlResume = "resume"
+
+	// RolloutControlPromote gives all the traffic to the new revisions of the
+	// rollouts in progress right away. As long as it's set, the later
+	// revisions are promoted right away too.
+	RolloutControlPromote = "promote"
+
+	// RolloutMinSuccessPercentageKey is an annotation attached to a Route to
+	// analyze its rollouts: before each step of a rollout the percentage of the
+	// responses of the new revision that were no server errors must be at
+	// least this value, e.g. `99.5`.
+	RolloutMinSuccessPercentageKey = GroupName + "/rollout-min-success-percentage"
+
+	// RolloutMaxLatencyKey is an annotation attached to a Route to analyze its
+	// rollouts: before each step of a rollout the mean latency of the
+	// responses of the new revision must be at most this Golang time.Duration.
+	RolloutMaxLatencyKey = GroupName + "/rollout-max-latency"
+
+	// RolloutAnalysisActionKey is an annotation attached to a Route to choose
+	// what happens to a rollout whose new revision fails the analysis, either
+	// RolloutAnalysisActionRollback, the default, or RolloutAnalysisActionPause.
+	// A rollout whose new revision has no responses to analyze yet is paused
+	// either way, for up to 10 minutes, after which the revision fails the
+	// analysis.
+	RolloutAnalysisActionKey = GroupName + "/rollout-analysis-action"
+
+	// RolloutAnalysisActionRollback moves the traffic of the failing revision
+	// back to the previous revision and ends its rollout.
+	RolloutAnalysisActionRollback = "rollback"
+
+	// RolloutAnalysisActionPause delays the next step of the rollout until the
+	// failing revision passes the analysis again.
+	RolloutAnalysisActionPause = "pause"
+
+	// RolloutStrategyKey is an annotation attached to a Route or a Service to
+	// choose how the traffic moves to the new revisions, either
+	// RolloutStrategyGradual, the default, or RolloutStrategyBlueGreen.
+	RolloutStrategyKey = GroupName + "/rollout-strategy"
+
+	// RolloutStrategyGradual moves the traffic to the new revisions in steps,
+	// per the rollout duration or the rollout steps.
+	RolloutStrategyGradual = "gradual"
+
+	// RolloutStrategyBlueGreen holds the traffic back from the new revisions,
+	// which are kept warm at the replica count of the previous revision and
+	// exposed under a tag of their own, until the rollout is promoted with
+	// the RolloutControlKey annotation. All the traffic moves at once then.
+	RolloutStrategyBlueGreen = "blue-green"
+
+	// BlueGreenRollbackWindowKey is an annotation attached to a Route or a
+	// Service to keep the previous revision of a promoted blue/green rollout
+	// warm, and exposed under a tag of its own, for this Golang time.Duration,
+	// to be able to roll back quickly.
+	BlueGreenRollbackWindowKey = GroupName + "/blue-green-rollback-window"
+
+	// WarmScaleAnnotationKey is the annotation the Route controller attaches
+	// to the revisions of its blue/green rollouts to keep them at this number
+	// of replicas at least, regardless of their traffic. It is the largest
+	// of the scales in WarmScaleRoutesAnnotationKey.
+	WarmScaleAnnotationKey = GroupName + "/warm-scale"
+
+	// WarmScaleRoutesAnnotationKey is the annotation the Route controller
+	// attaches to the revisions next to WarmScaleAnnotationKey, with the
+	// comma separated route=scale entries of the Routes that keep them warm.
+	// Each Route only changes its own entry, so that a revision stays warm
+	// while any Route still needs it.
+	WarmScaleRoutesAnnotationKey = GroupName + "/warm-scale-routes"
+
+	// RollbackKey is an annotation attached to a Service to route all of its
+	// traffic to the Nth previous ready revision of its revision history, as
+	// recorded in its status, instead of to the targets of its spec.traffic.
+	// The revision is resolved when the annotation is set or changed, and
+	// keeps the traffic while newer revisions are recorded. Removing the
+	// annotation routes the traffic per spec.traffic again.
+	RollbackKey = GroupName + "/rollback"
+
+	// SessionAffinityAnnotationKey is an annotation attached to a Route to pin the
+	// sessions of its users to revisions, and to a Revision to pin them to its
+	// pods, keyed by the value of either `header:<name>` or `cookie:<name>`.
+	// The ingress splits the first request of a session across the revisions of
+	// the Route, and the revision serving it returns its name in the
+	// Knative-Serving-Session-Revision response header. The requests that send
+	// the header back go to that revision as long as the Route routes traffic to
+	// it. The activator sends the requests of a session to the same pod of a
+	// Revision with the annotation as long as the pod exists.
+	SessionAffinityAnnotationKey = GroupName + "/session-affinity"
+
 	// RoutingStateLabelKey is the label attached to a Revision indicating
 	// its state in relation to serving a Route.
 	RoutingStateLabelKey = GroupName + "/routingState"
@@ -137,6 +245,38 @@ const (
 	// QueueSidecarEphemeralStorageResourceLimitAnnotationKey is the explicit value of the ephemeral storage limit for queue-proxy's limit resources
 	QueueSidecarEphemeralStorageResourceLimitAnnotationKey = "queue.sidecar." + GroupName + "/ephemeral-storage-resource-limit"
 
+	// QueueSidecarPriorityClassesAnnotationKey is the annotation key for the priority classes
+	// of the queue-proxy, highest priority first, in the form
+	// `<name>=<weight>/<queue-depth>[,...]`, e.g. `interactive=4/50,batch=1/500`. The requests
+	// name their class in the header the operator trusts for it, set in the config-deployment
+	// ConfigMap. Requests without a known class are queued in the last class.
+	QueueSidecarPriorityClassesAnnotationKey = "queue.sidecar." + GroupName + "/priority-classes"
+
+	// QueueSidecarPriorityQueueingAnnotationKey is the annotation key for how the queue-proxy
+	// dequeues the priority classes, either QueueingWeighted or QueueingStrict.
+	QueueSidecarPriorityQueueingAnnotationKey = "queue.sidecar." + GroupName + "/priority-queueing"
+
+	// QueueingWeighted dequeues the priority classes in proportion to their weights.
+	QueueingWeighted = "weighted"
+
+	// QueueingStrict always dequeues the highest priority class with queued requests first.
+	QueueingStrict = "strict"
+
+	// QueueSidecarQueueDeadlineAnnotationKey is the annotation key for the longest duration,
+	// e.g. `5s`, a request waits in the queue of the queue-proxy before it fails with a 503.
+	QueueSidecarQueueDeadlineAnnotationKey = "queue.sidecar." + GroupName + "/queue-deadline"
+
+	// QueueSidecarSheddingTargetAnnotationKey is the annotation key for the acceptable duration
+	// requests wait in the queue of the queue-proxy. Once they waited longer for a whole
+	// QueueSidecarSheddingIntervalAnnotationKey, the queue-proxy sheds the requests it can't
+	// serve within the target, like CoDel.
+	QueueSidecarSheddingTargetAnnotationKey = "queue.sidecar." + GroupName + "/shedding-target"
+
+	// QueueSidecarSheddingIntervalAnnotationKey is the annotation key for the interval the
+	// queue of the queue-proxy must drain within before it sheds requests. It's also the
+	// longest duration a request waits in the queue while shedding is enabled.
+	QueueSidecarSheddingIntervalAnnotationKey = "queue.sidecar." + GroupName + "/shedding-interval"
+
 	// VisibilityClusterLocal is the label value for VisibilityLabelKey
 	// that will result to the Route/KService getting a cluster local
 	// domain suffix.
@@ -144,6 +284,107 @@ const (
 
 	// ProgressDeadlineAnnotationKey is the label key for the per revision progress deadline to set for the deployment
 	ProgressDeadlineAnnotationKey = GroupName + "/progress-deadline"
+
+	// LoadBalancingPolicyAnnotationKey is the annotation key for the policy the activator
+	// uses to pick the pod of a revision a request is sent to. When unset, the policy is
+	// chosen based on the container concurrency of the revision.
+	LoadBalancingPolicyAnnotationKey = GroupName + "/load-balancing-policy"
+
+	// LoadBalancingHashKeyAnnotationKey is the annotation key for the request attribute the
+	// consistent-hash load balancing policy hashes on, either `header:<name>` or `cookie:<name>`.
+	LoadBalancingHashKeyAnnotationKey = GroupName + "/load-balancing-hash-key"
+
+	// LoadBalancingPolicyRandomChoice2 picks the less loaded of two random pods.
+	LoadBalancingPolicyRandomChoice2 = "random-choice-2"
+
+	// LoadBalancingPolicyFirstAvailable picks the first pod with free capacity.
+	LoadBalancingPolicyFirstAvailable = "first-available"
+
+	// LoadBalancingPolicyRoundRobin picks the pods with free capacity in turn.
+	LoadBalancingPolicyRoundRobin = "round-robin"
+
+	// LoadBalancingPolicyLeastOutstandingRequests picks the pod with the fewest
+	// requests in flight.
+	LoadBalancingPolicyLeastOutstandingRequests = "least-outstanding-requests"
+
+	// LoadBalancingPolicyEWMALatency picks the pod with the lowest exponentially weighted
+	// moving average of the response latency, weighted by its requests in flight.
+	LoadBalancingPolicyEWMALatency = "ewma-latency"
+
+	// LoadBalancingPolicyConsistentHash picks the pod by hashing the request attribute
+	// specified by LoadBalancingHashKeyAnnotationKey, so that the requests with the same
+	// value go to the same pod as long as it exists.
+	LoadBalancingPolicyConsistentHash = "consistent-hash"
+
+	// ActivatorMaxBufferedRequestsAnnotationKey is the annotation key for the most requests
+	// of the revision an activator buffers while they wait for capacity.
+	ActivatorMaxBufferedRequestsAnnotationKey = GroupName + "/activator-max-buffered-requests"
+
+	// ActivatorMaxBufferedBytesAnnotationKey is the annotation key for the most request body
+	// bytes of the revision, e.g. `64Mi`, an activator buffers while the requests wait for
+	// capacity. The size of a request body is taken from its Content-Length.
+	ActivatorMaxBufferedBytesAnnotationKey = GroupName + "/activator-max-buffered-bytes"
+
+	// ActivatorBufferOverflowAnnotationKey is the annotation key for what an activator does
+	// with the requests beyond the buffering limits of the revision, one of
+	// ActivatorBufferOverflowReject, ActivatorBufferOverflowThrottle or
+	// ActivatorBufferOverflowSpill.
+	ActivatorBufferOverflowAnnotationKey = GroupName + "/activator-buffer-overflow"
+
+	// ActivatorBufferOverflowReject rejects the requests with a 503. This is the default.
+	ActivatorBufferOverflowReject = "reject"
+
+	// ActivatorBufferOverflowThrottle rejects the requests with a 429.
+	ActivatorBufferOverflowThrottle = "throttle"
+
+	// ActivatorBufferOverflowSpill queues the requests in a spill queue a tenth the size of
+	// ActivatorMaxBufferedRequestsAnnotationKey until there is room in the buffer, and
+	// rejects the requests beyond it with a 503.
+	ActivatorBufferOverflowSpill = "spill"
+
+	// ActivatorRetryAttemptsAnnotationKey is the annotation key for the most attempts, including
+	// the first one, an activator makes to proxy a request of the revision. The attempts after a
+	// connection error or a 502 or 503 response go to another pod, if there is one.
+	ActivatorRetryAttemptsAnnotationKey = GroupName + "/activator-retry-attempts"
+
+	// ActivatorRetryMethodsAnnotationKey is the annotation key for the requests an activator
+	// retries, either ActivatorRetryMethodsIdempotent, the default, or ActivatorRetryMethodsAll.
+	ActivatorRetryMethodsAnnotationKey = GroupName + "/activator-retry-methods"
+
+	// ActivatorRetryBudgetAnnotationKey is the annotation key for the percentage of the requests
+	// of the revision an activator may retry, 20 by default, so that retries can't multiply the
+	// load on a failing revision.
+	ActivatorRetryBudgetAnnotationKey = GroupName + "/activator-retry-budget"
+
+	// ActivatorRetryMethodsIdempotent retries the requests with idempotent methods.
+	ActivatorRetryMethodsIdempotent = "idempotent"
+
+	// ActivatorRetryMethodsAll retries the requests with any method.
+	ActivatorRetryMethodsAll = "all"
+
+	// DefaultActivatorRetryBudget is the default of ActivatorRetryBudgetAnnotationKey.
+	DefaultActivatorRetryBudget = 20
+
+	// ActivatorOutlierConsecutiveErrorsAnnotationKey is the annotation key for the number of
+	// consecutive connection errors or 5xx responses after which an activator stops sending
+	// requests of the revision to a pod for a while. Outlier detection is off unless it's set.
+	ActivatorOutlierConsecutiveErrorsAnnotationKey = GroupName + "/activator-outlier-consecutive-errors"
+
+	// ActivatorOutlierEjectionTimeAnnotationKey is the annotation key for the time a pod is
+	// ejected for the first time, 30s by default. Every ejection in a row lasts as long again.
+	ActivatorOutlierEjectionTimeAnnotationKey = GroupName + "/activator-outlier-ejection-time"
+
+	// ActivatorOutlierMaxEjectionPercentAnnotationKey is the annotation key for the percentage
+	// of the pods of the revision an activator may eject at once, 50 by default.
+	ActivatorOutlierMaxEjectionPercentAnnotationKey = GroupName + "/activator-outlier-max-ejection-percent"
+
+	// DefaultActivatorOutlierEjectionTime is the default of
+	// ActivatorOutlierEjectionTimeAnnotationKey.
+	DefaultActivatorOutlierEjectionTime = 30 * time.Second
+
+	// DefaultActivatorOutlierMaxEjectionPercent is the default of
+	// ActivatorOutlierMaxEjectionPercentAnnotationKey.
+	DefaultActivatorOutlierMaxEjectionPercent = 50
 )
 
 var (
@@ -177,6 +418,36 @@ var (
 		RolloutDurationKey,
 		GroupName + "/rolloutDuration",
 	}
+	RolloutStepsAnnotation = kmap.KeyPriority{
+		RolloutStepsKey,
+	}
+	RolloutControlAnnotation = kmap.KeyPriority{
+		RolloutControlKey,
+	}
+	RolloutMinSuccessPercentageAnnotation = kmap.KeyPriority{
+		RolloutMinSuccessPercentageKey,
+	}
+	RolloutMaxLatencyAnnotation = kmap.KeyPriority{
+		RolloutMaxLatencyKey,
+	}
+	RolloutAnalysisActionAnnotation = kmap.KeyPriority{
+		RolloutAnalysisActionKey,
+	}
+	RolloutStrategyAnnotation = kmap.KeyPriority{
+		RolloutStrategyKey,
+	}
+	BlueGreenRollbackWindowAnnotation = kmap.KeyPriority{
+		BlueGreenRollbackWindowKey,
+	}
+	WarmScaleAnnotation = kmap.KeyPriority{
+		WarmScaleAnnotationKey,
+	}
+	RollbackAnnotation = kmap.KeyPriority{
+		RollbackKey,
+	}
+	SessionAffinityAnnotation = kmap.KeyPriority{
+		SessionAffinityAnnotationKey,
+	}
 	QueueSidecarResourcePercentageAnnotation = kmap.KeyPriority{
 		QueueSidecarResourcePercentageAnnotationKey,
 		"queue.sidecar." + GroupName + "/resourcePercentage",
@@ -202,4 +473,52 @@ var (
 	ProgressDeadlineAnnotation = kmap.KeyPriority{
 		ProgressDeadlineAnnotationKey,
 	}
+	QueueSidecarPriorityClassesAnnotation = kmap.KeyPriority{
+		QueueSidecarPriorityClassesAnnotationKey,
+	}
+	QueueSidecarPriorityQueueingAnnotation = kmap.KeyPriority{
+		QueueSidecarPriorityQueueingAnnotationKey,
+	}
+	QueueSidecarQueueDeadlineAnnotation = kmap.KeyPriority{
+		QueueSidecarQueueDeadlineAnnotationKey,
+	}
+	QueueSidecarSheddingTargetAnnotation = kmap.KeyPriority{
+		QueueSidecarSheddingTargetAnnotationKey,
+	}
+	QueueSidecarSheddingIntervalAnnotation = kmap.KeyPriority{
+		QueueSidecarSheddingIntervalAnnotationKey,
+	}
+	LoadBalancingPolicyAnnotation = kmap.KeyPriority{
+		LoadBalancingPolicyAnnotationKey,
+	}
+	LoadBalancingHashKeyAnnotation = kmap.KeyPriority{
+		LoadBalancingHashKeyAnnotationKey,
+	}
+	ActivatorMaxBufferedRequestsAnnotation = kmap.KeyPriority{
+		ActivatorMaxBufferedRequestsAnnotationKey,
+	}
+	ActivatorMaxBufferedBytesAnnotation = kmap.KeyPriority{
+		ActivatorMaxBufferedBytesAnnotationKey,
+	}
+	ActivatorBufferOverflowAnnotation = kmap.KeyPriority{
+		ActivatorBufferOverflowAnnotationKey,
+	}
+	ActivatorRetryAttemptsAnnotation = kmap.KeyPriority{
+		ActivatorRetryAttemptsAnnotationKey,
+	}
+	ActivatorRetryMethodsAnnotation = kmap.KeyPriority{
+		ActivatorRetryMethodsAnnotationKey,
+	}
+	ActivatorRetryBudgetAnnotation = kmap.KeyPriority{
+		ActivatorRetryBudgetAnnotationKey,
+	}
+	ActivatorOutlierConsecutiveErrorsAnnotation = kmap.KeyPriority{
+		ActivatorOutlierConsecutiveErrorsAnnotationKey,
+	}
+	ActivatorOutlierEjectionTimeAnnotation = kmap.KeyPriority{
+		ActivatorOutlierEjectionTimeAnnotationKey,
+	}
+	ActivatorOutlierMaxEjectionPercentAnnotation = kmap.KeyPriority{
+		ActivatorOutlierMaxEjectionPercentAnnotationKey,
+	}
 )
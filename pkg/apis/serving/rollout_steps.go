@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RolloutStep is a step of the rollout of a new revision.
+type RolloutStep struct {
+	// Percent is the percentage of the traffic of the configuration the new
+	// revision gets in the step.
+	Percent int
+	// Hold is how long the step lasts before the next one.
+	Hold time.Duration
+}
+
+// ParseRolloutSteps parses the rollout steps in the form of
+// RolloutStepsKey.
+func ParseRolloutSteps(s string) ([]RolloutStep, error) {
+	var ret []RolloutStep
+	for _, st := range strings.Split(s, ",") {
+		st = strings.TrimSpace(st)
+		percent, hold, ok := strings.Cut(st, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a percent:hold step", st)
+		}
+		rs := RolloutStep{}
+		var err error
+		if rs.Percent, err = strconv.Atoi(percent); err != nil || rs.Percent < 1 || rs.Percent > 99 {
+			return nil, fmt.Errorf("percent of step %q must be an integer between 1 and 99", st)
+		}
+		if len(ret) > 0 && rs.Percent <= ret[len(ret)-1].Percent {
+			return nil, fmt.Errorf("percent of step %q must be larger than the one of the previous step", st)
+		}
+		if rs.Hold, err = time.ParseDuration(hold); err != nil || rs.Hold <= 0 {
+			return nil, fmt.Errorf("hold of step %q must be a positive duration", st)
+		}
+		ret = append(ret, rs)
+	}
+	return ret, nil
+}
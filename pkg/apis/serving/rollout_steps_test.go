@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serving
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRolloutSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    []RolloutStep
+		wantErr string
+	}{{
+		name: "single",
+		s:    "10:5m",
+		want: []RolloutStep{{Percent: 10, Hold: 5 * time.Minute}},
+	}, {
+		name: "multiple",
+		s:    "1:10m, 5:30m, 25:10m",
+		want: []RolloutStep{
+			{Percent: 1, Hold: 10 * time.Minute},
+			{Percent: 5, Hold: 30 * time.Minute},
+			{Percent: 25, Hold: 10 * time.Minute},
+		},
+	}, {
+		name:    "empty",
+		s:       "",
+		wantErr: `"" is not a percent:hold step`,
+	}, {
+		name:    "no hold",
+		s:       "10",
+		wantErr: `"10" is not a percent:hold step`,
+	}, {
+		name:    "invalid percent",
+		s:       "all:10m",
+		wantErr: `percent of step "all:10m" must be an integer between 1 and 99`,
+	}, {
+		name:    "percent of 100",
+		s:       "100:10m",
+		wantErr: `percent of step "100:10m" must be an integer between 1 and 99`,
+	}, {
+		name:    "decreasing percent",
+		s:       "10:10m,5:10m",
+		wantErr: `percent of step "5:10m" must be larger than the one of the previous step`,
+	}, {
+		name:    "invalid hold",
+		s:       "10:a while",
+		wantErr: `hold of step "10:a while" must be a positive duration`,
+	}, {
+		name:    "zero hold",
+		s:       "10:0s",
+		wantErr: `hold of step "10:0s" must be a positive duration`,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseRolloutSteps(test.s)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("ParseRolloutSteps() = %v, want error: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("ParseRolloutSteps() =", err)
+			}
+			if !cmp.Equal(got, test.want) {
+				t.Error("ParseRolloutSteps() (-want, +got):", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
@@ -255,6 +255,32 @@ func TestConfigurationValidation(t *testing.T) {
 			},
 		},
 		want: apis.ErrInvalidKeyName("autoscaling.knative.dev/foo", "metadata.annotations", `autoscaling annotations must be put under "spec.template.metadata.annotations" to work`),
+	}, {
+		name: "invalid rollout steps",
+		c: &Configuration{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rollout-steps-annotation",
+				Annotations: map[string]string{
+					serving.RolloutStepsKey: "5:10m,1:10m",
+				},
+			},
+			Spec: ConfigurationSpec{
+				Template: RevisionTemplateSpec{
+					Spec: RevisionSpec{
+						PodSpec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Image: "hellworld",
+							}},
+						},
+					},
+				},
+			},
+		},
+		want: &apis.FieldError{
+			Message: "invalid value: 5:10m,1:10m",
+			Paths:   []string{"metadata.annotations." + serving.RolloutStepsKey},
+			Details: `percent of step "1:10m" must be larger than the one of the previous step`,
+		},
 	}}
 
 	// TODO(dangerd): PodSpec validation failures.
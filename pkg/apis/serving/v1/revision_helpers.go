@@ -17,10 +17,15 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"golang.org/x/net/http/httpguts"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	net "knative.dev/networking/pkg/apis/networking"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/serving/pkg/apis/serving"
@@ -139,6 +144,114 @@ func (r *Revision) GetProtocol() net.ProtocolType {
 	return net.ProtocolHTTP1
 }
 
+// GetLoadBalancingPolicy returns the load balancing policy annotation value,
+// or the empty string if not present.
+func (r *Revision) GetLoadBalancingPolicy() string {
+	_, v, _ := serving.LoadBalancingPolicyAnnotation.Get(r.Annotations)
+	return v
+}
+
+// GetLoadBalancingHashKey returns the name of either the header or the cookie
+// the consistent-hash load balancing policy hashes on, or empty strings if the
+// annotation is not present or invalid.
+func (r *Revision) GetLoadBalancingHashKey() (header, cookie string) {
+	_, v, _ := serving.LoadBalancingHashKeyAnnotation.Get(r.Annotations)
+	header, cookie, _ = ParseHashKey(v)
+	return header, cookie
+}
+
+// GetSessionAffinityKey returns the name of either the header or the cookie
+// the sessions of the revision are pinned to its pods by, or empty strings if
+// the annotation is not present or invalid.
+func (r *Revision) GetSessionAffinityKey() (header, cookie string) {
+	_, v, _ := serving.SessionAffinityAnnotation.Get(r.Annotations)
+	header, cookie, _ = ParseHashKey(v)
+	return header, cookie
+}
+
+// ParseHashKey parses the `header:<name>` or `cookie:<name>` form of the
+// request attributes the requests are hashed on, e.g. the load balancing hash
+// key or the session affinity key.
+func ParseHashKey(s string) (header, cookie string, err error) {
+	source, name, ok := strings.Cut(s, ":")
+	if !ok || !httpguts.ValidHeaderFieldName(name) {
+		return "", "", fmt.Errorf("%q is not of the form header:<name> or cookie:<name>", s)
+	}
+	switch source {
+	case "header":
+		return name, "", nil
+	case "cookie":
+		return "", name, nil
+	default:
+		return "", "", fmt.Errorf("unknown source %q, must be one of header or cookie", source)
+	}
+}
+
+// GetActivatorBufferLimits returns the most requests and request body bytes an
+// activator buffers for the revision, 0 meaning unbounded, and what it does
+// with the requests beyond them.
+func (r *Revision) GetActivatorBufferLimits() (requests int, bytes int64, overflow string) {
+	if _, v, ok := serving.ActivatorMaxBufferedRequestsAnnotation.Get(r.Annotations); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			requests = n
+		}
+	}
+	if _, v, ok := serving.ActivatorMaxBufferedBytesAnnotation.Get(r.Annotations); ok {
+		if q, err := resource.ParseQuantity(v); err == nil && q.Sign() > 0 {
+			bytes = q.Value()
+		}
+	}
+	overflow = serving.ActivatorBufferOverflowReject
+	if _, v, ok := serving.ActivatorBufferOverflowAnnotation.Get(r.Annotations); ok {
+		overflow = v
+	}
+	return requests, bytes, overflow
+}
+
+// GetActivatorRetryPolicy returns the most attempts an activator makes to
+// proxy a request of the revision, 1 meaning it doesn't retry, which requests
+// it retries and the percentage of the requests it may retry.
+func (r *Revision) GetActivatorRetryPolicy() (attempts int, methods string, budget int) {
+	attempts, methods, budget = 1, serving.ActivatorRetryMethodsIdempotent, serving.DefaultActivatorRetryBudget
+	if _, v, ok := serving.ActivatorRetryAttemptsAnnotation.Get(r.Annotations); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 1 {
+			attempts = n
+		}
+	}
+	if _, v, ok := serving.ActivatorRetryMethodsAnnotation.Get(r.Annotations); ok {
+		methods = v
+	}
+	if _, v, ok := serving.ActivatorRetryBudgetAnnotation.Get(r.Annotations); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			budget = n
+		}
+	}
+	return attempts, methods, budget
+}
+
+// GetActivatorOutlierDetection returns the number of consecutive errors after
+// which an activator ejects a pod of the revision, 0 meaning it doesn't, the
+// time of the first ejection and the percentage of the pods it may eject.
+func (r *Revision) GetActivatorOutlierDetection() (consecutiveErrors int, ejectionTime time.Duration, maxEjectionPercent int) {
+	ejectionTime, maxEjectionPercent = serving.DefaultActivatorOutlierEjectionTime, serving.DefaultActivatorOutlierMaxEjectionPercent
+	if _, v, ok := serving.ActivatorOutlierConsecutiveErrorsAnnotation.Get(r.Annotations); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			consecutiveErrors = n
+		}
+	}
+	if _, v, ok := serving.ActivatorOutlierEjectionTimeAnnotation.Get(r.Annotations); ok {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			ejectionTime = d
+		}
+	}
+	if _, v, ok := serving.ActivatorOutlierMaxEjectionPercentAnnotation.Get(r.Annotations); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			maxEjectionPercent = n
+		}
+	}
+	return consecutiveErrors, ejectionTime, maxEjectionPercent
+}
+
 // IsActivationRequired returns true if activation is required.
 func (rs *RevisionStatus) IsActivationRequired() bool {
 	c := revisionCondSet.Manage(rs).GetCondition(RevisionConditionActive)
@@ -156,6 +156,196 @@ func TestRevisionGetProtocol(t *testing.T) {
 	}
 }
 
+func TestRevisionGetLoadBalancing(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantPolicy  string
+		wantHeader  string
+		wantCookie  string
+	}{{
+		name: "not present",
+	}, {
+		name: "header",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "header:X-User",
+		},
+		wantPolicy: serving.LoadBalancingPolicyConsistentHash,
+		wantHeader: "X-User",
+	}, {
+		name: "cookie",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "cookie:session",
+		},
+		wantPolicy: serving.LoadBalancingPolicyConsistentHash,
+		wantCookie: "session",
+	}, {
+		name: "invalid hash key",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "query:user",
+		},
+		wantPolicy: serving.LoadBalancingPolicyConsistentHash,
+	}, {
+		name: "policy only",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyEWMALatency,
+		},
+		wantPolicy: serving.LoadBalancingPolicyEWMALatency,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Revision{}
+			r.Annotations = tt.annotations
+			if got, want := r.GetLoadBalancingPolicy(), tt.wantPolicy; got != want {
+				t.Errorf("GetLoadBalancingPolicy() = %q, want: %q", got, want)
+			}
+			header, cookie := r.GetLoadBalancingHashKey()
+			if header != tt.wantHeader || cookie != tt.wantCookie {
+				t.Errorf("GetLoadBalancingHashKey() = %q, %q, want: %q, %q", header, cookie, tt.wantHeader, tt.wantCookie)
+			}
+		})
+	}
+}
+
+func TestRevisionGetActivatorBufferLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantRequests int
+		wantBytes    int64
+		wantOverflow string
+	}{{
+		name:         "not present",
+		wantOverflow: serving.ActivatorBufferOverflowReject,
+	}, {
+		name: "all present",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedRequestsAnnotationKey: "100",
+			serving.ActivatorMaxBufferedBytesAnnotationKey:    "1Mi",
+			serving.ActivatorBufferOverflowAnnotationKey:      serving.ActivatorBufferOverflowSpill,
+		},
+		wantRequests: 100,
+		wantBytes:    1 << 20,
+		wantOverflow: serving.ActivatorBufferOverflowSpill,
+	}, {
+		name: "invalid limits",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedRequestsAnnotationKey: "-1",
+			serving.ActivatorMaxBufferedBytesAnnotationKey:    "lots",
+		},
+		wantOverflow: serving.ActivatorBufferOverflowReject,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Revision{}
+			r.Annotations = tt.annotations
+			requests, bytes, overflow := r.GetActivatorBufferLimits()
+			if requests != tt.wantRequests || bytes != tt.wantBytes || overflow != tt.wantOverflow {
+				t.Errorf("GetActivatorBufferLimits() = %d, %d, %q, want: %d, %d, %q",
+					requests, bytes, overflow, tt.wantRequests, tt.wantBytes, tt.wantOverflow)
+			}
+		})
+	}
+}
+
+func TestRevisionGetActivatorRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		wantAttempts int
+		wantMethods  string
+		wantBudget   int
+	}{{
+		name:         "not present",
+		wantAttempts: 1,
+		wantMethods:  serving.ActivatorRetryMethodsIdempotent,
+		wantBudget:   serving.DefaultActivatorRetryBudget,
+	}, {
+		name: "all present",
+		annotations: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "3",
+			serving.ActivatorRetryMethodsAnnotationKey:  serving.ActivatorRetryMethodsAll,
+			serving.ActivatorRetryBudgetAnnotationKey:   "50",
+		},
+		wantAttempts: 3,
+		wantMethods:  serving.ActivatorRetryMethodsAll,
+		wantBudget:   50,
+	}, {
+		name: "invalid values",
+		annotations: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "many",
+			serving.ActivatorRetryBudgetAnnotationKey:   "200",
+		},
+		wantAttempts: 1,
+		wantMethods:  serving.ActivatorRetryMethodsIdempotent,
+		wantBudget:   serving.DefaultActivatorRetryBudget,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Revision{}
+			r.Annotations = tt.annotations
+			attempts, methods, budget := r.GetActivatorRetryPolicy()
+			if attempts != tt.wantAttempts || methods != tt.wantMethods || budget != tt.wantBudget {
+				t.Errorf("GetActivatorRetryPolicy() = %d, %q, %d, want: %d, %q, %d",
+					attempts, methods, budget, tt.wantAttempts, tt.wantMethods, tt.wantBudget)
+			}
+		})
+	}
+}
+
+func TestRevisionGetActivatorOutlierDetection(t *testing.T) {
+	tests := []struct {
+		name                   string
+		annotations            map[string]string
+		wantConsecutiveErrors  int
+		wantEjectionTime       time.Duration
+		wantMaxEjectionPercent int
+	}{{
+		name:                   "not present",
+		wantEjectionTime:       serving.DefaultActivatorOutlierEjectionTime,
+		wantMaxEjectionPercent: serving.DefaultActivatorOutlierMaxEjectionPercent,
+	}, {
+		name: "all present",
+		annotations: map[string]string{
+			serving.ActivatorOutlierConsecutiveErrorsAnnotationKey:  "5",
+			serving.ActivatorOutlierEjectionTimeAnnotationKey:       "1m",
+			serving.ActivatorOutlierMaxEjectionPercentAnnotationKey: "100",
+		},
+		wantConsecutiveErrors:  5,
+		wantEjectionTime:       time.Minute,
+		wantMaxEjectionPercent: 100,
+	}, {
+		name: "invalid values",
+		annotations: map[string]string{
+			serving.ActivatorOutlierConsecutiveErrorsAnnotationKey:  "-1",
+			serving.ActivatorOutlierEjectionTimeAnnotationKey:       "forever",
+			serving.ActivatorOutlierMaxEjectionPercentAnnotationKey: "0",
+		},
+		wantEjectionTime:       serving.DefaultActivatorOutlierEjectionTime,
+		wantMaxEjectionPercent: serving.DefaultActivatorOutlierMaxEjectionPercent,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Revision{}
+			r.Annotations = tt.annotations
+			consecutiveErrors, ejectionTime, maxEjectionPercent := r.GetActivatorOutlierDetection()
+			if consecutiveErrors != tt.wantConsecutiveErrors || ejectionTime != tt.wantEjectionTime ||
+				maxEjectionPercent != tt.wantMaxEjectionPercent {
+				t.Errorf("GetActivatorOutlierDetection() = %d, %v, %d, want: %d, %v, %d",
+					consecutiveErrors, ejectionTime, maxEjectionPercent,
+					tt.wantConsecutiveErrors, tt.wantEjectionTime, tt.wantMaxEjectionPercent)
+			}
+		})
+	}
+}
+
 func TestGetContainer(t *testing.T) {
 	cases := []struct {
 		name   string
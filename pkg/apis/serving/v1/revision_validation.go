@@ -72,6 +72,13 @@ func (rts *RevisionTemplateSpec) Validate(ctx context.Context) *apis.FieldError
 	errs = errs.Also(validateRevisionName(ctx, rts.Name, rts.GenerateName))
 	errs = errs.Also(validateQueueSidecarResourceAnnotations(rts.Annotations).ViaField("metadata.annotations"))
 	errs = errs.Also(validateProgressDeadlineAnnotation(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateLoadBalancingAnnotations(rts.Annotations, rts.Spec.ContainerConcurrency).ViaField("metadata.annotations"))
+	errs = errs.Also(validateSessionAffinityAnnotation(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validatePriorityAnnotations(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateQueueSheddingAnnotations(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateActivatorBufferAnnotations(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateActivatorRetryAnnotations(rts.Annotations).ViaField("metadata.annotations"))
+	errs = errs.Also(validateActivatorOutlierAnnotations(rts.Annotations).ViaField("metadata.annotations"))
 	return errs
 }
 
@@ -245,3 +252,199 @@ func validateProgressDeadlineAnnotation(annos map[string]string) *apis.FieldErro
 	}
 	return nil
 }
+
+// validateLoadBalancingAnnotations validates the load balancing policy and
+// hash key annotations.
+func validateLoadBalancingAnnotations(annos map[string]string, containerConcurrency *int64) (errs *apis.FieldError) {
+	pk, policy, hasPolicy := serving.LoadBalancingPolicyAnnotation.Get(annos)
+	if hasPolicy {
+		switch policy {
+		case serving.LoadBalancingPolicyRandomChoice2:
+			// The policy doesn't respect the capacity of the pods.
+			if containerConcurrency != nil && *containerConcurrency != 0 {
+				errs = errs.Also(&apis.FieldError{
+					Message: fmt.Sprintf("%s=%s requires containerConcurrency to be 0", pk, policy),
+					Paths:   []string{pk},
+				})
+			}
+		case serving.LoadBalancingPolicyFirstAvailable,
+			serving.LoadBalancingPolicyRoundRobin, serving.LoadBalancingPolicyLeastOutstandingRequests,
+			serving.LoadBalancingPolicyEWMALatency, serving.LoadBalancingPolicyConsistentHash:
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(policy, pk))
+		}
+	}
+	hk, hash, hasHash := serving.LoadBalancingHashKeyAnnotation.Get(annos)
+	switch {
+	case policy == serving.LoadBalancingPolicyConsistentHash && !hasHash:
+		errs = errs.Also(apis.ErrMissingField(serving.LoadBalancingHashKeyAnnotationKey))
+	case hasHash && policy != serving.LoadBalancingPolicyConsistentHash:
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("only allowed with %s=%s", serving.LoadBalancingPolicyAnnotationKey, serving.LoadBalancingPolicyConsistentHash),
+			Paths:   []string{hk},
+		})
+	case hasHash:
+		if _, _, err := ParseHashKey(hash); err != nil {
+			fe := apis.ErrInvalidValue(hash, hk)
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		}
+	}
+	return errs
+}
+
+// validatePriorityAnnotations validates the annotations configuring the
+// priority classes of the queue-proxy.
+func validatePriorityAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	ck, classes, hasClasses := serving.QueueSidecarPriorityClassesAnnotation.Get(annos)
+	qk, queueing, hasQueueing := serving.QueueSidecarPriorityQueueingAnnotation.Get(annos)
+
+	if hasClasses {
+		if _, err := serving.ParsePriorityClasses(classes); err != nil {
+			fe := apis.ErrInvalidValue(classes, ck)
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		}
+	}
+	if hasQueueing && queueing != serving.QueueingWeighted && queueing != serving.QueueingStrict {
+		errs = errs.Also(apis.ErrInvalidValue(queueing, qk))
+	}
+	if hasQueueing && !hasClasses {
+		errs = errs.Also(apis.ErrMissingField(serving.QueueSidecarPriorityClassesAnnotationKey))
+	}
+	return errs
+}
+
+// validateQueueSheddingAnnotations validates the annotations bounding the time
+// requests wait in the queue of the queue-proxy.
+func validateQueueSheddingAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	_, _, err := positiveDurationAnnotation(annos, serving.QueueSidecarQueueDeadlineAnnotation)
+	errs = errs.Also(err)
+	tk, target, err := positiveDurationAnnotation(annos, serving.QueueSidecarSheddingTargetAnnotation)
+	errs = errs.Also(err)
+	ik, interval, err := positiveDurationAnnotation(annos, serving.QueueSidecarSheddingIntervalAnnotation)
+	errs = errs.Also(err)
+	if errs != nil {
+		return errs
+	}
+
+	// Shedding needs both the target and the interval.
+	switch {
+	case target > 0 && interval == 0:
+		errs = apis.ErrMissingField(serving.QueueSidecarSheddingIntervalAnnotationKey)
+	case interval > 0 && target == 0:
+		errs = apis.ErrMissingField(serving.QueueSidecarSheddingTargetAnnotationKey)
+	case target >= interval && target > 0:
+		errs = apis.ErrInvalidValue(annos[ik], ik, "must be longer than "+tk)
+	}
+	return errs
+}
+
+// validateActivatorBufferAnnotations validates the buffering limits of the
+// activator for the revision.
+func validateActivatorBufferAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	rk, requests, hasRequests := serving.ActivatorMaxBufferedRequestsAnnotation.Get(annos)
+	bk, bytes, hasBytes := serving.ActivatorMaxBufferedBytesAnnotation.Get(annos)
+	ovk, overflow, hasOverflow := serving.ActivatorBufferOverflowAnnotation.Get(annos)
+
+	if hasRequests {
+		if n, err := strconv.Atoi(requests); err != nil || n <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(requests, rk, "must be a positive integer"))
+		}
+	}
+	if hasBytes {
+		if q, err := resource.ParseQuantity(bytes); err != nil {
+			fe := apis.ErrInvalidValue(bytes, bk)
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		} else if q.Sign() <= 0 {
+			errs = errs.Also(apis.ErrInvalidValue(bytes, bk, "must be positive"))
+		}
+	}
+	if hasOverflow {
+		switch overflow {
+		case serving.ActivatorBufferOverflowReject, serving.ActivatorBufferOverflowThrottle:
+		case serving.ActivatorBufferOverflowSpill:
+			// The spill queue is sized after the request limit.
+			if !hasRequests {
+				errs = errs.Also(apis.ErrMissingField(serving.ActivatorMaxBufferedRequestsAnnotationKey))
+			}
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(overflow, ovk))
+		}
+	}
+	return errs
+}
+
+// validateActivatorRetryAnnotations validates the retry policy of the activator
+// for the revision.
+func validateActivatorRetryAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	ak, attempts, hasAttempts := serving.ActivatorRetryAttemptsAnnotation.Get(annos)
+	mk, methods, hasMethods := serving.ActivatorRetryMethodsAnnotation.Get(annos)
+	bk, budget, hasBudget := serving.ActivatorRetryBudgetAnnotation.Get(annos)
+
+	if hasAttempts {
+		if n, err := strconv.Atoi(attempts); err != nil || n < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(attempts, ak, "must be a positive integer"))
+		}
+	}
+	if hasMethods && methods != serving.ActivatorRetryMethodsIdempotent && methods != serving.ActivatorRetryMethodsAll {
+		errs = errs.Also(apis.ErrInvalidValue(methods, mk))
+	}
+	if hasBudget {
+		if n, err := strconv.Atoi(budget); err != nil || n < 1 || n > 100 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(budget, 1, 100, bk))
+		}
+	}
+	// The retry methods and budget configure the retries.
+	if (hasMethods || hasBudget) && !hasAttempts {
+		errs = errs.Also(apis.ErrMissingField(serving.ActivatorRetryAttemptsAnnotationKey))
+	}
+	return errs
+}
+
+// validateActivatorOutlierAnnotations validates the outlier detection of the
+// activator for the revision.
+func validateActivatorOutlierAnnotations(annos map[string]string) (errs *apis.FieldError) {
+	ck, consecutive, hasConsecutive := serving.ActivatorOutlierConsecutiveErrorsAnnotation.Get(annos)
+	_, _, hasEjectionTime := serving.ActivatorOutlierEjectionTimeAnnotation.Get(annos)
+	pk, percent, hasPercent := serving.ActivatorOutlierMaxEjectionPercentAnnotation.Get(annos)
+
+	if hasConsecutive {
+		if n, err := strconv.Atoi(consecutive); err != nil || n < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(consecutive, ck, "must be a positive integer"))
+		}
+	}
+	if _, _, err := positiveDurationAnnotation(annos, serving.ActivatorOutlierEjectionTimeAnnotation); err != nil {
+		errs = errs.Also(err)
+	}
+	if hasPercent {
+		if n, err := strconv.Atoi(percent); err != nil || n < 1 || n > 100 {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(percent, 1, 100, pk))
+		}
+	}
+	// The ejection time and percentage configure the ejections.
+	if (hasEjectionTime || hasPercent) && !hasConsecutive {
+		errs = errs.Also(apis.ErrMissingField(serving.ActivatorOutlierConsecutiveErrorsAnnotationKey))
+	}
+	return errs
+}
+
+// positiveDurationAnnotation returns the key and the duration of the annotation,
+// or 0 if it isn't set, and an error if the duration isn't positive.
+func positiveDurationAnnotation(annos map[string]string, key kmap.KeyPriority) (string, time.Duration, *apis.FieldError) {
+	k, v, ok := key.Get(annos)
+	if !ok {
+		return k, 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		fe := apis.ErrInvalidValue(v, k)
+		fe.Details = err.Error()
+		return k, 0, fe
+	}
+	if d <= 0 {
+		return k, 0, apis.ErrInvalidValue(v, k, "must be positive")
+	}
+	return k, d, nil
+}
@@ -1069,6 +1069,28 @@ func TestRevisionTemplateSpecValidation(t *testing.T) {
 			Message: "invalid value: not-a-duration",
 			Paths:   []string{serving.ProgressDeadlineAnnotationKey},
 		}).ViaField("metadata.annotations"),
+	}, {
+		name: "invalid session affinity",
+		ctx:  autoscalerConfigCtx(true, 1),
+		rts: &RevisionTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					serving.SessionAffinityAnnotationKey: "query:session",
+				},
+			},
+			Spec: RevisionSpec{
+				PodSpec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Image: "helloworld",
+					}},
+				},
+			},
+		},
+		want: (&apis.FieldError{
+			Message: "invalid value: query:session",
+			Paths:   []string{serving.SessionAffinityAnnotationKey},
+			Details: `unknown source "query", must be one of header or cookie`,
+		}).ViaField("metadata.annotations"),
 	}, {
 		name: "negative progress-deadline",
 		ctx:  autoscalerConfigCtx(true, 1),
@@ -1187,6 +1209,368 @@ func TestValidateQueueSidecarAnnotation(t *testing.T) {
 	}
 }
 
+func TestValidateLoadBalancingAnnotations(t *testing.T) {
+	cases := []struct {
+		name                 string
+		annotations          map[string]string
+		containerConcurrency *int64
+		expectErr            string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "valid policy",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyLeastOutstandingRequests,
+		},
+		containerConcurrency: ptr.Int64(10),
+	}, {
+		name: "unknown policy",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: "random",
+		},
+		expectErr: "invalid value: random: " + serving.LoadBalancingPolicyAnnotationKey,
+	}, {
+		name: "random choice of 2 with unlimited concurrency",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyRandomChoice2,
+		},
+		containerConcurrency: ptr.Int64(0),
+	}, {
+		name: "random choice of 2 with limited concurrency",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyRandomChoice2,
+		},
+		containerConcurrency: ptr.Int64(10),
+		expectErr: serving.LoadBalancingPolicyAnnotationKey + "=random-choice-2 requires containerConcurrency to be 0: " +
+			serving.LoadBalancingPolicyAnnotationKey,
+	}, {
+		name: "consistent hash on a header",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "header:X-User",
+		},
+	}, {
+		name: "consistent hash on a cookie",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "cookie:session",
+		},
+	}, {
+		name: "consistent hash without a hash key",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey: serving.LoadBalancingPolicyConsistentHash,
+		},
+		expectErr: "missing field(s): " + serving.LoadBalancingHashKeyAnnotationKey,
+	}, {
+		name: "hash key without consistent hash",
+		annotations: map[string]string{
+			serving.LoadBalancingHashKeyAnnotationKey: "header:X-User",
+		},
+		expectErr: "only allowed with " + serving.LoadBalancingPolicyAnnotationKey + "=consistent-hash: " +
+			serving.LoadBalancingHashKeyAnnotationKey,
+	}, {
+		name: "hash key of unknown source",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "query:user",
+		},
+		expectErr: "invalid value: query:user: " + serving.LoadBalancingHashKeyAnnotationKey +
+			"\nunknown source \"query\", must be one of header or cookie",
+	}, {
+		name: "hash key without a name",
+		annotations: map[string]string{
+			serving.LoadBalancingPolicyAnnotationKey:  serving.LoadBalancingPolicyConsistentHash,
+			serving.LoadBalancingHashKeyAnnotationKey: "X-User",
+		},
+		expectErr: "invalid value: X-User: " + serving.LoadBalancingHashKeyAnnotationKey +
+			"\n\"X-User\" is not of the form header:<name> or cookie:<name>",
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateLoadBalancingAnnotations(c.annotations, c.containerConcurrency)
+			if got, want := err.Error(), c.expectErr; got != want {
+				t.Errorf("Got: %q want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestValidatePriorityAnnotations(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expectErr   string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "valid",
+		annotations: map[string]string{
+			serving.QueueSidecarPriorityClassesAnnotationKey:  "interactive=4/50,batch=1/500",
+			serving.QueueSidecarPriorityQueueingAnnotationKey: serving.QueueingStrict,
+		},
+	}, {
+		name: "invalid classes",
+		annotations: map[string]string{
+			serving.QueueSidecarPriorityClassesAnnotationKey: "interactive=4",
+		},
+		expectErr: "invalid value: interactive=4: " + serving.QueueSidecarPriorityClassesAnnotationKey +
+			"\n\"interactive=4\" is not a name=weight/queue-depth class",
+	}, {
+		name: "invalid queueing",
+		annotations: map[string]string{
+			serving.QueueSidecarPriorityClassesAnnotationKey:  "interactive=4/50",
+			serving.QueueSidecarPriorityQueueingAnnotationKey: "fifo",
+		},
+		expectErr: "invalid value: fifo: " + serving.QueueSidecarPriorityQueueingAnnotationKey,
+	}, {
+		name: "queueing without classes",
+		annotations: map[string]string{
+			serving.QueueSidecarPriorityQueueingAnnotationKey: serving.QueueingWeighted,
+		},
+		expectErr: "missing field(s): " + serving.QueueSidecarPriorityClassesAnnotationKey,
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validatePriorityAnnotations(c.annotations)
+			if got, want := err.Error(), c.expectErr; got != want {
+				t.Errorf("Got: %q want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateQueueSheddingAnnotations(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expectErr   string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "deadline",
+		annotations: map[string]string{
+			serving.QueueSidecarQueueDeadlineAnnotationKey: "5s",
+		},
+	}, {
+		name: "shedding",
+		annotations: map[string]string{
+			serving.QueueSidecarSheddingTargetAnnotationKey:   "50ms",
+			serving.QueueSidecarSheddingIntervalAnnotationKey: "1s",
+		},
+	}, {
+		name: "invalid deadline",
+		annotations: map[string]string{
+			serving.QueueSidecarQueueDeadlineAnnotationKey: "soon",
+		},
+		expectErr: "invalid value: soon: " + serving.QueueSidecarQueueDeadlineAnnotationKey + "\n" + `time: invalid duration "soon"`,
+	}, {
+		name: "negative target",
+		annotations: map[string]string{
+			serving.QueueSidecarSheddingTargetAnnotationKey:   "-1s",
+			serving.QueueSidecarSheddingIntervalAnnotationKey: "1s",
+		},
+		expectErr: "invalid value: -1s: " + serving.QueueSidecarSheddingTargetAnnotationKey + "\nmust be positive",
+	}, {
+		name: "target without interval",
+		annotations: map[string]string{
+			serving.QueueSidecarSheddingTargetAnnotationKey: "50ms",
+		},
+		expectErr: "missing field(s): " + serving.QueueSidecarSheddingIntervalAnnotationKey,
+	}, {
+		name: "interval without target",
+		annotations: map[string]string{
+			serving.QueueSidecarSheddingIntervalAnnotationKey: "1s",
+		},
+		expectErr: "missing field(s): " + serving.QueueSidecarSheddingTargetAnnotationKey,
+	}, {
+		name: "interval not longer than target",
+		annotations: map[string]string{
+			serving.QueueSidecarSheddingTargetAnnotationKey:   "1s",
+			serving.QueueSidecarSheddingIntervalAnnotationKey: "1000ms",
+		},
+		expectErr: "invalid value: 1000ms: " + serving.QueueSidecarSheddingIntervalAnnotationKey +
+			"\nmust be longer than " + serving.QueueSidecarSheddingTargetAnnotationKey,
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateQueueSheddingAnnotations(c.annotations)
+			if got, want := err.Error(), c.expectErr; got != want {
+				t.Errorf("Got: %q want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateActivatorBufferAnnotations(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expectErr   string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "all valid",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedRequestsAnnotationKey: "100",
+			serving.ActivatorMaxBufferedBytesAnnotationKey:    "64Mi",
+			serving.ActivatorBufferOverflowAnnotationKey:      serving.ActivatorBufferOverflowSpill,
+		},
+	}, {
+		name: "throttle with bytes only",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedBytesAnnotationKey: "64Mi",
+			serving.ActivatorBufferOverflowAnnotationKey:   serving.ActivatorBufferOverflowThrottle,
+		},
+	}, {
+		name: "invalid requests",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedRequestsAnnotationKey: "0",
+		},
+		expectErr: "invalid value: 0: " + serving.ActivatorMaxBufferedRequestsAnnotationKey + "\nmust be a positive integer",
+	}, {
+		name: "invalid bytes",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedBytesAnnotationKey: "lots",
+		},
+		expectErr: "invalid value: lots: " + serving.ActivatorMaxBufferedBytesAnnotationKey + "\n" + resource.ErrFormatWrong.Error(),
+	}, {
+		name: "negative bytes",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedBytesAnnotationKey: "-1Mi",
+		},
+		expectErr: "invalid value: -1Mi: " + serving.ActivatorMaxBufferedBytesAnnotationKey + "\nmust be positive",
+	}, {
+		name: "unknown overflow",
+		annotations: map[string]string{
+			serving.ActivatorBufferOverflowAnnotationKey: "drop",
+		},
+		expectErr: "invalid value: drop: " + serving.ActivatorBufferOverflowAnnotationKey,
+	}, {
+		name: "spill without requests",
+		annotations: map[string]string{
+			serving.ActivatorMaxBufferedBytesAnnotationKey: "64Mi",
+			serving.ActivatorBufferOverflowAnnotationKey:   serving.ActivatorBufferOverflowSpill,
+		},
+		expectErr: "missing field(s): " + serving.ActivatorMaxBufferedRequestsAnnotationKey,
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateActivatorBufferAnnotations(c.annotations)
+			if got, want := err.Error(), c.expectErr; got != want {
+				t.Errorf("Got: %q want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateActivatorRetryAnnotations(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expectErr   string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "all valid",
+		annotations: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "3",
+			serving.ActivatorRetryMethodsAnnotationKey:  serving.ActivatorRetryMethodsAll,
+			serving.ActivatorRetryBudgetAnnotationKey:   "100",
+		},
+	}, {
+		name: "invalid attempts",
+		annotations: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "0",
+		},
+		expectErr: "invalid value: 0: " + serving.ActivatorRetryAttemptsAnnotationKey + "\nmust be a positive integer",
+	}, {
+		name: "unknown methods",
+		annotations: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "2",
+			serving.ActivatorRetryMethodsAnnotationKey:  "safe",
+		},
+		expectErr: "invalid value: safe: " + serving.ActivatorRetryMethodsAnnotationKey,
+	}, {
+		name: "budget out of bounds",
+		annotations: map[string]string{
+			serving.ActivatorRetryAttemptsAnnotationKey: "2",
+			serving.ActivatorRetryBudgetAnnotationKey:   "0",
+		},
+		expectErr: "expected 1 <= 0 <= 100: " + serving.ActivatorRetryBudgetAnnotationKey,
+	}, {
+		name: "budget without attempts",
+		annotations: map[string]string{
+			serving.ActivatorRetryBudgetAnnotationKey: "10",
+		},
+		expectErr: "missing field(s): " + serving.ActivatorRetryAttemptsAnnotationKey,
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateActivatorRetryAnnotations(c.annotations)
+			if got, want := err.Error(), c.expectErr; got != want {
+				t.Errorf("Got: %q want: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestValidateActivatorOutlierAnnotations(t *testing.T) {
+	cases := []struct {
+		name        string
+		annotations map[string]string
+		expectErr   string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "all valid",
+		annotations: map[string]string{
+			serving.ActivatorOutlierConsecutiveErrorsAnnotationKey:  "5",
+			serving.ActivatorOutlierEjectionTimeAnnotationKey:       "10s",
+			serving.ActivatorOutlierMaxEjectionPercentAnnotationKey: "30",
+		},
+	}, {
+		name: "invalid consecutive errors",
+		annotations: map[string]string{
+			serving.ActivatorOutlierConsecutiveErrorsAnnotationKey: "none",
+		},
+		expectErr: "invalid value: none: " + serving.ActivatorOutlierConsecutiveErrorsAnnotationKey + "\nmust be a positive integer",
+	}, {
+		name: "negative ejection time",
+		annotations: map[string]string{
+			serving.ActivatorOutlierConsecutiveErrorsAnnotationKey: "5",
+			serving.ActivatorOutlierEjectionTimeAnnotationKey:      "-10s",
+		},
+		expectErr: "invalid value: -10s: " + serving.ActivatorOutlierEjectionTimeAnnotationKey + "\nmust be positive",
+	}, {
+		name: "max ejection percent out of bounds",
+		annotations: map[string]string{
+			serving.ActivatorOutlierConsecutiveErrorsAnnotationKey:  "5",
+			serving.ActivatorOutlierMaxEjectionPercentAnnotationKey: "101",
+		},
+		expectErr: "expected 1 <= 101 <= 100: " + serving.ActivatorOutlierMaxEjectionPercentAnnotationKey,
+	}, {
+		name: "ejection time without consecutive errors",
+		annotations: map[string]string{
+			serving.ActivatorOutlierEjectionTimeAnnotationKey: "10s",
+		},
+		expectErr: "missing field(s): " + serving.ActivatorOutlierConsecutiveErrorsAnnotationKey,
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateActivatorOutlierAnnotations(c.annotations)
+			if got, want := err.Error(), c.expectErr; got != want {
+				t.Errorf("Got: %q want: %q", got, want)
+			}
+		})
+	}
+}
+
 func TestValidateTimeoutSecond(t *testing.T) {
 	cases := []struct {
 		name      string
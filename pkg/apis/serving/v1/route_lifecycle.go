@@ -18,6 +18,7 @@ package v1
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -73,6 +74,73 @@ func (r *Route) RolloutDuration() time.Duration {
 	return 0
 }
 
+// RolloutMinSuccessPercentage returns the minimum success percentage of the
+// rollout analysis specified as an annotation.
+// 0 is returned if missing or cannot be parsed.
+func (r *Route) RolloutMinSuccessPercentage() float64 {
+	if _, v, ok := serving.RolloutMinSuccessPercentageAnnotation.Get(r.Annotations); ok {
+		// WH should've declined all the invalid values for this annotation.
+		if p, err := strconv.ParseFloat(v, 64); err == nil {
+			return p
+		}
+	}
+	return 0
+}
+
+// RolloutMaxLatency returns the maximum mean latency of the rollout analysis
+// specified as an annotation.
+// 0 is returned if missing or cannot be parsed.
+func (r *Route) RolloutMaxLatency() time.Duration {
+	if _, v, ok := serving.RolloutMaxLatencyAnnotation.Get(r.Annotations); ok {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return 0
+}
+
+// RolloutAnalysisAction returns the action on a failed rollout analysis
+// specified as an annotation, serving.RolloutAnalysisActionRollback if missing.
+func (r *Route) RolloutAnalysisAction() string {
+	if _, v, ok := serving.RolloutAnalysisActionAnnotation.Get(r.Annotations); ok && v == serving.RolloutAnalysisActionPause {
+		return v
+	}
+	return serving.RolloutAnalysisActionRollback
+}
+
+// RolloutControl returns the control of the rollouts in progress specified
+// as an annotation, serving.RolloutControlResume if missing.
+func (r *Route) RolloutControl() string {
+	if _, v, ok := serving.RolloutControlAnnotation.Get(r.Annotations); ok {
+		switch v {
+		case serving.RolloutControlPause, serving.RolloutControlPromote:
+			return v
+		}
+	}
+	return serving.RolloutControlResume
+}
+
+// RolloutStrategy returns the strategy of the rollouts specified as an
+// annotation, serving.RolloutStrategyGradual if missing.
+func (r *Route) RolloutStrategy() string {
+	if _, v, ok := serving.RolloutStrategyAnnotation.Get(r.Annotations); ok && v == serving.RolloutStrategyBlueGreen {
+		return v
+	}
+	return serving.RolloutStrategyGradual
+}
+
+// BlueGreenRollbackWindow returns the time the previous revision of a
+// promoted blue/green rollout is kept warm, specified as an annotation.
+// 0 is returned if missing or cannot be parsed.
+func (r *Route) BlueGreenRollbackWindow() time.Duration {
+	if _, v, ok := serving.BlueGreenRollbackWindowAnnotation.Get(r.Annotations); ok {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
 // InitializeConditions sets the initial values to the conditions.
 func (rs *RouteStatus) InitializeConditions() {
 	routeCondSet.Manage(rs).InitializeConditions()
@@ -99,6 +167,40 @@ func (rs *RouteStatus) MarkIngressRolloutInProgress() {
 		"RolloutInProgress", "A gradual rollout of the latest revision(s) is in progress.")
 }
 
+// MarkRolloutAnalysisSucceeded marks the RouteConditionRolloutAnalysisSucceeded
+// condition true, when the new revisions of the rollouts meet the SLOs.
+func (rs *RouteStatus) MarkRolloutAnalysisSucceeded() {
+	routeCondSet.Manage(rs).MarkTrue(RouteConditionRolloutAnalysisSucceeded)
+}
+
+// MarkRolloutPaused marks the RouteConditionRolloutAnalysisSucceeded condition
+// false, when the rollout is paused since its new revision fails the analysis.
+func (rs *RouteStatus) MarkRolloutPaused(msg string) {
+	routeCondSet.Manage(rs).MarkFalse(RouteConditionRolloutAnalysisSucceeded,
+		"RolloutPaused", "The rollout is paused: %s.", msg)
+}
+
+// MarkRolloutAnalysisPending marks the RouteConditionRolloutAnalysisSucceeded
+// condition unknown, when the rollout is paused since there is no data to
+// analyze its new revision yet.
+func (rs *RouteStatus) MarkRolloutAnalysisPending(msg string) {
+	routeCondSet.Manage(rs).MarkUnknown(RouteConditionRolloutAnalysisSucceeded,
+		"NoAnalysisData", "The rollout is paused: %s.", msg)
+}
+
+// MarkRolledBack marks the RouteConditionRolloutAnalysisSucceeded condition
+// false, when the rollout is rolled back since its new revision fails the analysis.
+func (rs *RouteStatus) MarkRolledBack(msg string) {
+	routeCondSet.Manage(rs).MarkFalse(RouteConditionRolloutAnalysisSucceeded,
+		"RolledBack", "The rollout is rolled back: %s.", msg)
+}
+
+// ClearRolloutAnalysis removes the RouteConditionRolloutAnalysisSucceeded
+// condition, when the Route has no rollout analysis.
+func (rs *RouteStatus) ClearRolloutAnalysis() {
+	routeCondSet.Manage(rs).ClearCondition(RouteConditionRolloutAnalysisSucceeded)
+}
+
 // MarkIngressNotConfigured changes the IngressReady condition to be unknown to reflect
 // that the Ingress does not yet have a Status
 func (rs *RouteStatus) MarkIngressNotConfigured() {
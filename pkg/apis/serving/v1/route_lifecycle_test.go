@@ -559,3 +559,156 @@ func TestRolloutDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestRolloutAnalysis(t *testing.T) {
+	r := &Route{}
+	if got := r.RolloutMinSuccessPercentage(); got != 0 {
+		t.Errorf("RolloutMinSuccessPercentage = %v, want: 0", got)
+	}
+	if got := r.RolloutMaxLatency(); got != 0 {
+		t.Errorf("RolloutMaxLatency = %v, want: 0", got)
+	}
+	if got, want := r.RolloutAnalysisAction(), serving.RolloutAnalysisActionRollback; got != want {
+		t.Errorf("RolloutAnalysisAction = %q, want: %q", got, want)
+	}
+
+	r.Annotations = map[string]string{
+		serving.RolloutMinSuccessPercentageKey: "99.9",
+		serving.RolloutMaxLatencyKey:           "300ms",
+		serving.RolloutAnalysisActionKey:       serving.RolloutAnalysisActionPause,
+	}
+	if got, want := r.RolloutMinSuccessPercentage(), 99.9; got != want {
+		t.Errorf("RolloutMinSuccessPercentage = %v, want: %v", got, want)
+	}
+	if got, want := r.RolloutMaxLatency(), 300*time.Millisecond; got != want {
+		t.Errorf("RolloutMaxLatency = %v, want: %v", got, want)
+	}
+	if got, want := r.RolloutAnalysisAction(), serving.RolloutAnalysisActionPause; got != want {
+		t.Errorf("RolloutAnalysisAction = %q, want: %q", got, want)
+	}
+}
+
+func TestRolloutStrategy(t *testing.T) {
+	tests := []struct {
+		name  string
+		annos map[string]string
+		want  string
+	}{{
+		name: "missing",
+		want: serving.RolloutStrategyGradual,
+	}, {
+		name:  "blue-green",
+		annos: map[string]string{serving.RolloutStrategyKey: serving.RolloutStrategyBlueGreen},
+		want:  serving.RolloutStrategyBlueGreen,
+	}, {
+		name:  "invalid",
+		annos: map[string]string{serving.RolloutStrategyKey: "canary"},
+		want:  serving.RolloutStrategyGradual,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Route{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annos}}
+			if got := r.RolloutStrategy(); got != tc.want {
+				t.Errorf("RolloutStrategy = %q, want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlueGreenRollbackWindow(t *testing.T) {
+	tests := []struct {
+		name  string
+		annos map[string]string
+		want  time.Duration
+	}{{
+		name: "missing",
+	}, {
+		name:  "valid",
+		annos: map[string]string{serving.BlueGreenRollbackWindowKey: "15m"},
+		want:  15 * time.Minute,
+	}, {
+		name:  "invalid",
+		annos: map[string]string{serving.BlueGreenRollbackWindowKey: "a while"},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Route{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annos}}
+			if got := r.BlueGreenRollbackWindow(); got != tc.want {
+				t.Errorf("BlueGreenRollbackWindow = %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRolloutControl(t *testing.T) {
+	tests := []struct {
+		name  string
+		annos map[string]string
+		want  string
+	}{{
+		name: "missing",
+		want: serving.RolloutControlResume,
+	}, {
+		name:  "pause",
+		annos: map[string]string{serving.RolloutControlKey: serving.RolloutControlPause},
+		want:  serving.RolloutControlPause,
+	}, {
+		name:  "promote",
+		annos: map[string]string{serving.RolloutControlKey: serving.RolloutControlPromote},
+		want:  serving.RolloutControlPromote,
+	}, {
+		name:  "invalid",
+		annos: map[string]string{serving.RolloutControlKey: "halt"},
+		want:  serving.RolloutControlResume,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Route{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annos}}
+			if got := r.RolloutControl(); got != tc.want {
+				t.Errorf("RolloutControl = %q, want: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRolloutAnalysisConditions(t *testing.T) {
+	r := &RouteStatus{}
+	r.InitializeConditions()
+	r.MarkTrafficAssigned()
+	r.MarkCertificateReady("cert")
+	r.PropagateIngressStatus(netv1alpha1.IngressStatus{
+		Status: duckv1.Status{
+			Conditions: duckv1.Conditions{{
+				Type:   netv1alpha1.IngressConditionReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+	})
+
+	r.MarkRolloutAnalysisSucceeded()
+	apistest.CheckConditionSucceeded(r, RouteConditionRolloutAnalysisSucceeded, t)
+
+	r.MarkRolloutPaused(`revision "foo" failed`)
+	apistest.CheckConditionFailed(r, RouteConditionRolloutAnalysisSucceeded, t)
+	if c := r.GetCondition(RouteConditionRolloutAnalysisSucceeded); c.Reason != "RolloutPaused" {
+		t.Errorf("Reason = %q, want: RolloutPaused", c.Reason)
+	}
+
+	r.MarkRolloutAnalysisPending(`revision "foo" has no data`)
+	apistest.CheckConditionOngoing(r, RouteConditionRolloutAnalysisSucceeded, t)
+	if c := r.GetCondition(RouteConditionRolloutAnalysisSucceeded); c.Reason != "NoAnalysisData" {
+		t.Errorf("Reason = %q, want: NoAnalysisData", c.Reason)
+	}
+
+	r.MarkRolledBack(`revision "foo" failed`)
+	if c := r.GetCondition(RouteConditionRolloutAnalysisSucceeded); c.Reason != "RolledBack" {
+		t.Errorf("Reason = %q, want: RolledBack", c.Reason)
+	}
+	// The analysis doesn't affect the readiness of the Route.
+	apistest.CheckConditionSucceeded(r, RouteConditionReady, t)
+
+	r.ClearRolloutAnalysis()
+	if c := r.GetCondition(RouteConditionRolloutAnalysisSucceeded); c != nil {
+		t.Errorf("GetCondition() = %v, want: nil", c)
+	}
+}
@@ -110,12 +110,45 @@ type TrafficTarget struct {
 	URL *apis.URL `json:"url,omitempty"`
 }
 
+// RouteRule routes the requests that match all of its conditions to a
+// revision, or to the revisions of a tagged traffic target, instead of
+// splitting them per the traffic block.
+type RouteRule struct {
+	// Headers are the conditions on the request headers, keyed by header name.
+	// +optional
+	Headers map[string]StringMatch `json:"headers,omitempty"`
+
+	// RevisionName of a specific revision to which to route the matching
+	// requests. This is mutually exclusive with Tag.
+	// +optional
+	RevisionName string `json:"revisionName,omitempty"`
+
+	// Tag of the traffic target to whose revisions to route the matching
+	// requests. This is mutually exclusive with RevisionName.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+}
+
+// StringMatch matches a string exactly.
+type StringMatch struct {
+	// Exact is the value the string must be equal to.
+	// +optional
+	Exact string `json:"exact,omitempty"`
+}
+
 // RouteSpec holds the desired state of the Route (from the client).
 type RouteSpec struct {
 	// Traffic specifies how to distribute traffic over a collection of
 	// revisions and configurations.
 	// +optional
 	Traffic []TrafficTarget `json:"traffic,omitempty"`
+
+	// Rules specify the requests to route to a given revision or tagged
+	// traffic target, e.g. to route a cohort of beta users to a new revision.
+	// The first rule a request matches applies, and the requests matching no
+	// rule are split per Traffic.
+	// +optional
+	Rules []RouteRule `json:"rules,omitempty"`
 }
 
 const (
@@ -135,6 +168,13 @@ const (
 	// RouteConditionCertificateProvisioned is set to False when the
 	// Knative Certificates fail to be provisioned for the Route.
 	RouteConditionCertificateProvisioned apis.ConditionType = "CertificateProvisioned"
+
+	// RouteConditionRolloutAnalysisSucceeded is set to False when the new
+	// revision of a rollout fails the analysis of the rollout, i.e. doesn't
+	// meet the SLOs of the Route, and to Unknown while there is no data to
+	// analyze the new revision yet. It's only set on Routes with rollout
+	// analysis and doesn't affect the readiness of the Route.
+	RouteConditionRolloutAnalysisSucceeded apis.ConditionType = "RolloutAnalysisSucceeded"
 )
 
 // IsRouteCondition returns true if the ConditionType is a route condition type
@@ -144,7 +184,8 @@ func IsRouteCondition(t apis.ConditionType) bool {
 		RouteConditionReady,
 		RouteConditionAllTrafficAssigned,
 		RouteConditionIngressReady,
-		RouteConditionCertificateProvisioned:
+		RouteConditionCertificateProvisioned,
+		RouteConditionRolloutAnalysisSucceeded:
 		return true
 	}
 	return false
@@ -169,6 +210,43 @@ type RouteStatusFields struct {
 	// LatestReadyRevisionName that we last observed.
 	// +optional
 	Traffic []TrafficTarget `json:"traffic,omitempty"`
+
+	// Rollouts holds the state of the gradual rollouts of the latest revisions
+	// that are in progress.
+	// +optional
+	Rollouts []RolloutStatus `json:"rollouts,omitempty"`
+}
+
+// RolloutStatus describes a gradual rollout of the latest revision of a
+// Configuration that is in progress.
+type RolloutStatus struct {
+	// ConfigurationName of the configuration whose latest revision is rolled out.
+	ConfigurationName string `json:"configurationName"`
+
+	// Tag of the traffic target whose revisions are rolled out, if any.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// RevisionName of the revision that is rolled out.
+	RevisionName string `json:"revisionName"`
+
+	// Percent of the traffic of the Route that is routed to the revision.
+	Percent int64 `json:"percent"`
+
+	// Paused is set when the rollout is paused, in which case the traffic
+	// split doesn't change until the rollout is resumed.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// NextStepTime is when more traffic is routed to the revision next.
+	// +optional
+	NextStepTime *metav1.Time `json:"nextStepTime,omitempty"`
+
+	// RemainingSteps is the number of steps before the revision receives
+	// all the traffic of the configuration. It's unset until the first step
+	// of the rollout is known.
+	// +optional
+	RemainingSteps int64 `json:"remainingSteps,omitempty"`
 }
 
 // RouteStatus communicates the observed state of the Route (from the controller).
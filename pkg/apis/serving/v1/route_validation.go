@@ -20,8 +20,11 @@ import (
 	"context"
 	"fmt"
 
+	"golang.org/x/net/http/httpguts"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
@@ -29,7 +32,11 @@ import (
 func (r *Route) Validate(ctx context.Context) *apis.FieldError {
 	errs := serving.ValidateObjectMetadata(ctx, r.GetObjectMeta(), false).Also(
 		r.validateLabels().ViaField("labels"))
-	errs = errs.Also(serving.ValidateRolloutDurationAnnotation(r.GetAnnotations()).ViaField("annotations"))
+	errs = errs.Also(serving.ValidateRolloutDurationAnnotation(r.GetAnnotations()).Also(
+		validateSessionAffinityAnnotation(r.GetAnnotations())).Also(
+		serving.ValidateRolloutAnalysisAnnotations(r.GetAnnotations())).Also(
+		serving.ValidateRolloutControlAnnotation(r.GetAnnotations())).Also(
+		serving.ValidateRolloutStrategyAnnotations(r.GetAnnotations())).ViaField("annotations"))
 	errs = errs.ViaField("metadata")
 	errs = errs.Also(r.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec"))
 
@@ -45,6 +52,19 @@ func (r *Route) Validate(ctx context.Context) *apis.FieldError {
 	return errs
 }
 
+// validateSessionAffinityAnnotation validates the session affinity annotation.
+// This annotation can be set on service, route or revision template objects.
+func validateSessionAffinityAnnotation(annos map[string]string) *apis.FieldError {
+	if k, v, ok := serving.SessionAffinityAnnotation.Get(annos); ok {
+		if _, _, err := ParseHashKey(v); err != nil {
+			fe := apis.ErrInvalidValue(v, k)
+			fe.Details = err.Error()
+			return fe
+		}
+	}
+	return nil
+}
+
 func validateTrafficList(ctx context.Context, traffic []TrafficTarget) *apis.FieldError {
 	var errs *apis.FieldError
 
@@ -93,7 +113,69 @@ func validateTrafficList(ctx context.Context, traffic []TrafficTarget) *apis.Fie
 
 // Validate implements apis.Validatable
 func (rs *RouteSpec) Validate(ctx context.Context) *apis.FieldError {
-	return validateTrafficList(ctx, rs.Traffic).ViaField("traffic")
+	return validateTrafficList(ctx, rs.Traffic).ViaField("traffic").Also(
+		rs.validateRules(ctx))
+}
+
+func (rs *RouteSpec) validateRules(ctx context.Context) *apis.FieldError {
+	if len(rs.Rules) == 0 {
+		return nil
+	}
+	if config.FromContextOrDefaults(ctx).Features.RouteRules != config.Enabled {
+		return apis.ErrDisallowedFields("rules")
+	}
+
+	tags := make(sets.Set[string], len(rs.Traffic))
+	for _, tt := range rs.Traffic {
+		if tt.Tag != "" {
+			tags.Insert(tt.Tag)
+		}
+	}
+	var errs *apis.FieldError
+	for i, rule := range rs.Rules {
+		errs = errs.Also(rule.Validate().ViaFieldIndex("rules", i))
+		if rule.Tag != "" && !tags.Has(rule.Tag) {
+			errs = errs.Also(apis.ErrGeneric(
+				fmt.Sprintf("Tag %q is not defined in traffic", rule.Tag),
+				"tag").ViaFieldIndex("rules", i))
+		}
+	}
+	return errs
+}
+
+// Validate verifies that RouteRule is properly configured.
+func (rr *RouteRule) Validate() *apis.FieldError {
+	var errs *apis.FieldError
+	if len(rr.Headers) == 0 {
+		errs = apis.ErrMissingField("headers")
+	}
+	for name, m := range rr.Headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "headers"))
+		}
+		errs = errs.Also(m.Validate().ViaKey(name).ViaField("headers"))
+	}
+
+	switch {
+	case rr.RevisionName != "" && rr.Tag != "":
+		errs = errs.Also(apis.ErrMultipleOneOf("revisionName", "tag"))
+	case rr.RevisionName != "":
+		if el := validation.IsQualifiedName(rr.RevisionName); len(el) > 0 {
+			errs = errs.Also(apis.ErrInvalidKeyName(
+				rr.RevisionName, "revisionName", el...))
+		}
+	case rr.Tag == "":
+		errs = errs.Also(apis.ErrMissingOneOf("revisionName", "tag"))
+	}
+	return errs
+}
+
+// Validate verifies that StringMatch is properly configured.
+func (sm *StringMatch) Validate() *apis.FieldError {
+	if sm.Exact == "" {
+		return apis.ErrMissingField("exact")
+	}
+	return nil
 }
 
 // Validate verifies that TrafficTarget is properly configured.
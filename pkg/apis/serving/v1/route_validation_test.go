@@ -26,6 +26,7 @@ import (
 	netapi "knative.dev/networking/pkg/apis/networking"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 )
 
@@ -467,6 +468,96 @@ func TestRouteValidation(t *testing.T) {
 	}
 }
 
+func TestRouteRulesValidation(t *testing.T) {
+	traffic := []TrafficTarget{{
+		RevisionName: "foo",
+		Percent:      ptr.Int64(100),
+	}, {
+		Tag:          "beta",
+		RevisionName: "bar",
+	}}
+	tests := []struct {
+		name     string
+		rules    []RouteRule
+		disabled bool
+		want     *apis.FieldError
+	}{{
+		name: "valid",
+		rules: []RouteRule{{
+			Headers: map[string]StringMatch{
+				"X-Cohort": {Exact: "beta"},
+				"X-User":   {Exact: "alice"},
+			},
+			Tag: "beta",
+		}, {
+			Headers:      map[string]StringMatch{"X-Beta": {Exact: "true"}},
+			RevisionName: "bar",
+		}},
+	}, {
+		name:     "feature disabled",
+		disabled: true,
+		rules: []RouteRule{{
+			Headers:      map[string]StringMatch{"X-Cohort": {Exact: "beta"}},
+			RevisionName: "bar",
+		}},
+		want: apis.ErrDisallowedFields("spec.rules"),
+	}, {
+		name:  "no conditions nor target",
+		rules: []RouteRule{{}},
+		want: apis.ErrMissingField("spec.rules[0].headers").Also(
+			apis.ErrMissingOneOf("spec.rules[0].revisionName", "spec.rules[0].tag")),
+	}, {
+		name: "both targets",
+		rules: []RouteRule{{
+			Headers:      map[string]StringMatch{"X-Cohort": {Exact: "beta"}},
+			RevisionName: "bar",
+			Tag:          "beta",
+		}},
+		want: apis.ErrMultipleOneOf("spec.rules[0].revisionName", "spec.rules[0].tag"),
+	}, {
+		name: "undefined tag",
+		rules: []RouteRule{{
+			Headers: map[string]StringMatch{"X-Cohort": {Exact: "beta"}},
+			Tag:     "alpha",
+		}},
+		want: apis.ErrGeneric(`Tag "alpha" is not defined in traffic`, "spec.rules[0].tag"),
+	}, {
+		name: "invalid matches",
+		rules: []RouteRule{{
+			Headers: map[string]StringMatch{
+				"X Cohort": {Exact: "beta"},
+				"X-Beta":   {},
+			},
+			Tag: "beta",
+		}},
+		want: apis.ErrInvalidKeyName("X Cohort", "spec.rules[0].headers").Also(
+			apis.ErrMissingField("spec.rules[0].headers[X-Beta].exact")),
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			features := &config.Features{RouteRules: config.Enabled}
+			if test.disabled {
+				features.RouteRules = config.Disabled
+			}
+			ctx := config.ToContext(context.Background(), &config.Config{Features: features})
+			r := &Route{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: RouteSpec{
+					Traffic: traffic,
+					Rules:   test.rules,
+				},
+			}
+			got := r.Validate(ctx)
+			if !cmp.Equal(test.want.Error(), got.Error()) {
+				t.Errorf("Validate (-want, +got) = %v",
+					cmp.Diff(test.want.Error(), got.Error()))
+			}
+		})
+	}
+}
+
 func TestRouteLabelValidation(t *testing.T) {
 	validRouteSpec := RouteSpec{
 		Traffic: []TrafficTarget{{
@@ -735,6 +826,45 @@ func TestRouteAnnotationUpdate(t *testing.T) {
 			Spec: getRouteSpec("new"),
 		},
 		wantErr: apis.ErrInvalidValue("three hours and seventeen seconds", serving.RolloutDurationKey).ViaField("metadata.annotations"),
+	}, {
+		name: "rollout analysis validation, fail",
+		this: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.RolloutMaxLatencyKey: "soon",
+				},
+			},
+			Spec: getRouteSpec("new"),
+		},
+		wantErr: apis.ErrInvalidValue("soon", serving.RolloutMaxLatencyKey).ViaField("metadata.annotations"),
+	}, {
+		name: "session affinity validation",
+		this: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.SessionAffinityAnnotationKey: "cookie:session",
+				},
+			},
+			Spec: getRouteSpec("new"),
+		},
+	}, {
+		name: "session affinity validation, fail",
+		this: &Route{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "valid",
+				Annotations: map[string]string{
+					serving.SessionAffinityAnnotationKey: "query:session",
+				},
+			},
+			Spec: getRouteSpec("new"),
+		},
+		wantErr: (&apis.FieldError{
+			Message: "invalid value: query:session",
+			Paths:   []string{serving.SessionAffinityAnnotationKey},
+			Details: `unknown source "query", must be one of header or cookie`,
+		}).ViaField("metadata.annotations"),
 	}, {
 		name: "no validation for lastModifier annotation even after update without spec changes as route owned by service",
 		this: &Route{
@@ -18,11 +18,13 @@ package v1
 
 import (
 	"fmt"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"knative.dev/pkg/apis"
+	"knative.dev/serving/pkg/apis/serving"
 )
 
 const (
@@ -52,6 +54,18 @@ func (s *Service) IsReady() bool {
 		ss.GetCondition(ServiceConditionReady).IsTrue()
 }
 
+// Rollback returns how many revisions back in its revision history the
+// Service is rolled back to, specified as an annotation.
+// 0 is returned if missing or cannot be parsed.
+func (s *Service) Rollback() int {
+	if _, v, ok := serving.RollbackAnnotation.Get(s.Annotations); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
 // IsFailed returns true if the resource has observed
 // the latest generation and ready is false.
 func (s *Service) IsFailed() bool {
@@ -145,3 +159,27 @@ func (ss *ServiceStatus) PropagateRouteStatus(rs *RouteStatus) {
 		m.MarkUnknown(ServiceConditionRoutesReady, rc.Reason, rc.Message)
 	}
 }
+
+// RecordRevision appends the entry to the revision history, unless it is
+// already its most recent entry, dropping the oldest entries past
+// MaxRevisionHistory. It returns whether the entry was appended.
+func (ss *ServiceStatus) RecordRevision(entry RevisionHistoryEntry) bool {
+	if l := len(ss.RevisionHistory); l > 0 && ss.RevisionHistory[l-1].RevisionName == entry.RevisionName {
+		return false
+	}
+	ss.RevisionHistory = append(ss.RevisionHistory, entry)
+	if l := len(ss.RevisionHistory); l > MaxRevisionHistory {
+		ss.RevisionHistory = ss.RevisionHistory[l-MaxRevisionHistory:]
+	}
+	return true
+}
+
+// PreviousRevision returns the name of the revision n entries before the
+// most recent one in the revision history, and whether there is such.
+func (ss *ServiceStatus) PreviousRevision(n int) (string, bool) {
+	idx := len(ss.RevisionHistory) - 1 - n
+	if n < 0 || idx < 0 {
+		return "", false
+	}
+	return ss.RevisionHistory[idx].RevisionName, true
+}
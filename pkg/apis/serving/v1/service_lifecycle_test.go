@@ -17,16 +17,19 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	apistest "knative.dev/pkg/apis/testing"
 	"knative.dev/pkg/ptr"
+	"knative.dev/serving/pkg/apis/serving"
 )
 
 func TestServiceDuckTypes(t *testing.T) {
@@ -710,3 +713,82 @@ func TestRouteStatusPropagation(t *testing.T) {
 		t.Error("unexpected ServiceStatus (-want +got):", diff)
 	}
 }
+
+func TestServiceRollback(t *testing.T) {
+	tests := []struct {
+		name  string
+		annos map[string]string
+		want  int
+	}{{
+		name: "missing",
+	}, {
+		name:  "previous revision",
+		annos: map[string]string{serving.RollbackKey: "1"},
+		want:  1,
+	}, {
+		name:  "invalid",
+		annos: map[string]string{serving.RollbackKey: "last"},
+	}, {
+		name:  "not positive",
+		annos: map[string]string{serving.RollbackKey: "-2"},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &Service{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annos}}
+			if got, want := s.Rollback(), tc.want; got != want {
+				t.Errorf("Rollback = %d, want: %d", got, want)
+			}
+		})
+	}
+}
+
+func TestRecordRevision(t *testing.T) {
+	ss := &ServiceStatus{}
+	if !ss.RecordRevision(RevisionHistoryEntry{RevisionName: "rev-1", Creator: "alice"}) {
+		t.Error("RecordRevision(rev-1) = false, want: true")
+	}
+	if ss.RecordRevision(RevisionHistoryEntry{RevisionName: "rev-1", Creator: "bob"}) {
+		t.Error("RecordRevision(rev-1) again = true, want: false")
+	}
+	for i := 2; i <= MaxRevisionHistory+2; i++ {
+		ss.RecordRevision(RevisionHistoryEntry{RevisionName: fmt.Sprint("rev-", i)})
+	}
+	if got, want := len(ss.RevisionHistory), MaxRevisionHistory; got != want {
+		t.Fatalf("len(RevisionHistory) = %d, want: %d", got, want)
+	}
+	if got, want := ss.RevisionHistory[0].RevisionName, "rev-3"; got != want {
+		t.Errorf("Oldest entry = %s, want: %s", got, want)
+	}
+	if got, want := ss.RevisionHistory[MaxRevisionHistory-1].RevisionName, fmt.Sprint("rev-", MaxRevisionHistory+2); got != want {
+		t.Errorf("Most recent entry = %s, want: %s", got, want)
+	}
+}
+
+func TestPreviousRevision(t *testing.T) {
+	ss := &ServiceStatus{
+		RevisionHistory: []RevisionHistoryEntry{{
+			RevisionName: "rev-1",
+		}, {
+			RevisionName: "rev-2",
+		}, {
+			RevisionName: "rev-3",
+		}},
+	}
+	tests := []struct {
+		n      int
+		want   string
+		wantOK bool
+	}{
+		{n: 0, want: "rev-3", wantOK: true},
+		{n: 1, want: "rev-2", wantOK: true},
+		{n: 2, want: "rev-1", wantOK: true},
+		{n: 3},
+		{n: -1},
+	}
+	for _, tc := range tests {
+		got, ok := ss.PreviousRevision(tc.n)
+		if got != tc.want || ok != tc.wantOK {
+			t.Errorf("PreviousRevision(%d) = (%q, %v), want: (%q, %v)", tc.n, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
@@ -124,6 +124,53 @@ type ServiceStatus struct {
 	// In addition to inlining RouteSpec, we also inline the fields
 	// specific to RouteStatus.
 	RouteStatusFields `json:",inline"`
+
+	// RevisionHistory lists the revisions the Service went through as its
+	// latest ready revision, the most recent last, up to MaxRevisionHistory
+	// of them. The revisions listed here are retained by the garbage collector.
+	// +optional
+	RevisionHistory []RevisionHistoryEntry `json:"revisionHistory,omitempty"`
+
+	// Rollback is the revision the Service is rolled back to. It is resolved
+	// from the revision history when the rollback annotation is set or
+	// changed, so that it stays put as new revisions are recorded, and is
+	// cleared when the annotation is removed.
+	// +optional
+	Rollback *RollbackStatus `json:"rollback,omitempty"`
+}
+
+// RollbackStatus pins the revision a Service is rolled back to.
+type RollbackStatus struct {
+	// Revisions is how many revisions back in the revision history the
+	// rollback annotation asked for when the revision was resolved.
+	Revisions int `json:"revisions"`
+
+	// RevisionName is the name of the revision rolled back to.
+	RevisionName string `json:"revisionName"`
+}
+
+// MaxRevisionHistory is the maximum number of entries kept in the revision
+// history of a Service.
+const MaxRevisionHistory = 10
+
+// RevisionHistoryEntry records a revision that became the latest ready
+// revision of a Service.
+type RevisionHistoryEntry struct {
+	// RevisionName is the name of the revision.
+	RevisionName string `json:"revisionName"`
+
+	// TemplateHash is a hash of the spec of the revision, to tell apart the
+	// entries deploying the same template.
+	// +optional
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	// Timestamp is when the revision became ready.
+	// +optional
+	Timestamp metav1.Time `json:"timestamp,omitempty"`
+
+	// Creator is the user who triggered the revision.
+	// +optional
+	Creator string `json:"creator,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
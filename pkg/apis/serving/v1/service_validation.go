@@ -31,7 +31,13 @@ func (s *Service) Validate(ctx context.Context) (errs *apis.FieldError) {
 	// spec validation.
 	if !apis.IsInStatusUpdate(ctx) {
 		errs = errs.Also(serving.ValidateObjectMetadata(ctx, s.GetObjectMeta(), false))
-		errs = errs.Also(serving.ValidateRolloutDurationAnnotation(s.GetAnnotations()).ViaField("annotations"))
+		errs = errs.Also(serving.ValidateRolloutDurationAnnotation(s.GetAnnotations()).Also(
+			validateSessionAffinityAnnotation(s.GetAnnotations())).Also(
+			serving.ValidateRolloutAnalysisAnnotations(s.GetAnnotations())).Also(
+			serving.ValidateRolloutStepsAnnotation(s.GetAnnotations())).Also(
+			serving.ValidateRolloutControlAnnotation(s.GetAnnotations())).Also(
+			serving.ValidateRolloutStrategyAnnotations(s.GetAnnotations())).Also(
+			serving.ValidateRollbackAnnotation(s.GetAnnotations())).ViaField("annotations"))
 		errs = errs.ViaField("metadata")
 
 		ctx = apis.WithinParent(ctx, s.ObjectMeta)
@@ -220,6 +220,160 @@ func TestServiceValidation(t *testing.T) {
 			},
 		},
 		wantErr: apis.ErrInvalidValue("CLXXXIIIs", serving.RolloutDurationKey).ViaField("metadata.annotations"),
+	}, {
+		name: "invalid rollout analysis action",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rollout-analysis-annotation",
+				Annotations: map[string]string{
+					serving.RolloutAnalysisActionKey: "retry",
+				},
+			},
+			Spec: ServiceSpec{
+				ConfigurationSpec: ConfigurationSpec{
+					Template: RevisionTemplateSpec{
+						Spec: RevisionSpec{
+							PodSpec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Image: "hellworld",
+								}},
+							},
+						},
+					},
+				},
+				RouteSpec: RouteSpec{
+					Traffic: []TrafficTarget{{
+						LatestRevision: ptr.Bool(true),
+						Percent:        ptr.Int64(100),
+					}},
+				},
+			},
+		},
+		wantErr: apis.ErrInvalidValue("retry", serving.RolloutAnalysisActionKey).ViaField("metadata.annotations"),
+	}, {
+		name: "invalid rollout control",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rollout-control-annotation",
+				Annotations: map[string]string{
+					serving.RolloutControlKey: "halt",
+				},
+			},
+			Spec: ServiceSpec{
+				ConfigurationSpec: ConfigurationSpec{
+					Template: RevisionTemplateSpec{
+						Spec: RevisionSpec{
+							PodSpec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Image: "hellworld",
+								}},
+							},
+						},
+					},
+				},
+				RouteSpec: RouteSpec{
+					Traffic: []TrafficTarget{{
+						LatestRevision: ptr.Bool(true),
+						Percent:        ptr.Int64(100),
+					}},
+				},
+			},
+		},
+		wantErr: apis.ErrInvalidValue("halt", serving.RolloutControlKey).ViaField("metadata.annotations"),
+	}, {
+		name: "invalid rollout strategy",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rollout-strategy-annotation",
+				Annotations: map[string]string{
+					serving.RolloutStrategyKey: "canary",
+				},
+			},
+			Spec: ServiceSpec{
+				ConfigurationSpec: ConfigurationSpec{
+					Template: RevisionTemplateSpec{
+						Spec: RevisionSpec{
+							PodSpec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Image: "hellworld",
+								}},
+							},
+						},
+					},
+				},
+				RouteSpec: RouteSpec{
+					Traffic: []TrafficTarget{{
+						LatestRevision: ptr.Bool(true),
+						Percent:        ptr.Int64(100),
+					}},
+				},
+			},
+		},
+		wantErr: apis.ErrInvalidValue("canary", serving.RolloutStrategyKey).ViaField("metadata.annotations"),
+	}, {
+		name: "invalid rollback",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rollback-annotation",
+				Annotations: map[string]string{
+					serving.RollbackKey: "previous",
+				},
+			},
+			Spec: ServiceSpec{
+				ConfigurationSpec: ConfigurationSpec{
+					Template: RevisionTemplateSpec{
+						Spec: RevisionSpec{
+							PodSpec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Image: "hellworld",
+								}},
+							},
+						},
+					},
+				},
+				RouteSpec: RouteSpec{
+					Traffic: []TrafficTarget{{
+						LatestRevision: ptr.Bool(true),
+						Percent:        ptr.Int64(100),
+					}},
+				},
+			},
+		},
+		wantErr: apis.ErrInvalidValue("previous", serving.RollbackKey).ViaField("metadata.annotations"),
+	}, {
+		name: "invalid rollout steps",
+		r: &Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "rollout-steps-annotation",
+				Annotations: map[string]string{
+					serving.RolloutStepsKey: "1:soon",
+				},
+			},
+			Spec: ServiceSpec{
+				ConfigurationSpec: ConfigurationSpec{
+					Template: RevisionTemplateSpec{
+						Spec: RevisionSpec{
+							PodSpec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Image: "hellworld",
+								}},
+							},
+						},
+					},
+				},
+				RouteSpec: RouteSpec{
+					Traffic: []TrafficTarget{{
+						LatestRevision: ptr.Bool(true),
+						Percent:        ptr.Int64(100),
+					}},
+				},
+			},
+		},
+		wantErr: &apis.FieldError{
+			Message: "invalid value: 1:soon",
+			Paths:   []string{"metadata.annotations." + serving.RolloutStepsKey},
+			Details: `hold of step "1:soon" must be a positive duration`,
+		},
 	}, {
 		name: "invalid autoscaling.knative.dev annotation",
 		r: &Service{
@@ -230,6 +230,53 @@ func (t *TimedFloat64Buckets) WindowAverage(now time.Time) float64 {
 	}
 }
 
+// BucketValue returns the value recorded in the bucket that contains `tm`.
+// The second return value is false if that bucket is no longer (or not yet)
+// part of the window that ends at the last write.
+func (t *TimedFloat64Buckets) BucketValue(tm time.Time) (float64, bool) {
+	tm = tm.Truncate(t.granularity)
+	t.bucketsMutex.RLock()
+	defer t.bucketsMutex.RUnlock()
+	if t.lastWrite.IsZero() || tm.After(t.lastWrite) || !tm.After(t.lastWrite.Add(-t.window)) ||
+		tm.Before(t.firstWrite) {
+		return 0, false
+	}
+	return t.buckets[t.timeToIndex(tm)%len(t.buckets)], true
+}
+
+// Slope returns the least squares estimate of the rate of change of the
+// bucket values per second, computed over the buckets that hold valid data.
+// If fewer than two buckets are valid, or nothing was recorded for the
+// whole window, the slope is 0.
+func (t *TimedFloat64Buckets) Slope(now time.Time) float64 {
+	now = now.Truncate(t.granularity)
+	t.bucketsMutex.RLock()
+	defer t.bucketsMutex.RUnlock()
+	if t.isEmptyLocked(now) {
+		return 0
+	}
+
+	numB := min(int(t.lastWrite.Sub(t.firstWrite)/t.granularity)+1, len(t.buckets))
+	if numB < 2 {
+		return 0
+	}
+
+	// x is the bucket's age in granularity units, counted from the first
+	// valid bucket, and y is the bucket value.
+	startIdx := t.timeToIndex(t.lastWrite) - numB + 1
+	var sumX, sumY, sumXY, sumXX float64
+	for i := 0; i < numB; i++ {
+		x, y := float64(i), t.buckets[(startIdx+i)%len(t.buckets)]
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	n := float64(numB)
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	return slope / t.granularity.Seconds()
+}
+
 // timeToIndex converts time to an integer that can be used for modulo
 // operations to find the index in the bucket list.
 // bucketMutex needs to be held.
@@ -375,6 +375,103 @@ func TestTimedFloat64BucketsHoles(t *testing.T) {
 	}
 }
 
+func TestTimedFloat64BucketsSlope(t *testing.T) {
+	now := time.Now().Truncate(granularity)
+	tests := []struct {
+		name   string
+		values []float64
+		at     time.Duration
+		want   float64
+	}{{
+		name: "no data",
+		at:   4 * time.Second,
+		want: 0,
+	}, {
+		name:   "single bucket",
+		values: []float64{42},
+		want:   0,
+	}, {
+		name:   "flat",
+		values: []float64{5, 5, 5, 5, 5},
+		at:     4 * time.Second,
+		want:   0,
+	}, {
+		name:   "ramp up",
+		values: []float64{1, 2, 3, 4, 5},
+		at:     4 * time.Second,
+		want:   1,
+	}, {
+		name:   "ramp down",
+		values: []float64{10, 8, 6, 4, 2},
+		at:     4 * time.Second,
+		want:   -2,
+	}, {
+		name:   "only the window counts",
+		values: []float64{100, 100, 100, 1, 2, 3, 4, 5},
+		at:     7 * time.Second,
+		want:   1,
+	}, {
+		name:   "window expired",
+		values: []float64{1, 2, 3, 4, 5},
+		at:     time.Minute,
+		want:   0,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buckets := NewTimedFloat64Buckets(5*time.Second, granularity)
+			for i, v := range tc.values {
+				buckets.Record(now.Add(time.Duration(i)*granularity), v)
+			}
+			if got := buckets.Slope(now.Add(tc.at)); math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("Slope = %v, want: %v", got, tc.want)
+			}
+		})
+	}
+
+	// Slope is reported per second, regardless of granularity.
+	buckets := NewTimedFloat64Buckets(10*time.Second, 2*time.Second)
+	now = now.Truncate(2 * time.Second)
+	for i := 0; i < 5; i++ {
+		buckets.Record(now.Add(time.Duration(i)*2*time.Second), float64(i))
+	}
+	if got, want := buckets.Slope(now.Add(8*time.Second)), 0.5; got != want {
+		t.Errorf("Slope = %v, want: %v", got, want)
+	}
+}
+
+func TestTimedFloat64BucketsBucketValue(t *testing.T) {
+	now := time.Now().Truncate(granularity)
+	buckets := NewTimedFloat64Buckets(5*time.Second, granularity)
+	if _, ok := buckets.BucketValue(now); ok {
+		t.Error("BucketValue returned ok for empty buckets")
+	}
+
+	for i := 0; i < 8; i++ {
+		buckets.Record(now.Add(time.Duration(i)*granularity), float64(i))
+	}
+	buckets.Record(now.Add(6*time.Second+500*time.Millisecond), 10)
+
+	tests := []struct {
+		at     time.Duration
+		want   float64
+		wantOK bool
+	}{
+		{at: 0},
+		{at: 2 * time.Second},
+		{at: 3 * time.Second, want: 3, wantOK: true},
+		{at: 6*time.Second + 900*time.Millisecond, want: 16, wantOK: true},
+		{at: 7 * time.Second, want: 7, wantOK: true},
+		{at: 8 * time.Second},
+	}
+	for _, tc := range tests {
+		got, gotOK := buckets.BucketValue(now.Add(tc.at))
+		if got != tc.want || gotOK != tc.wantOK {
+			t.Errorf("BucketValue(+%v) = (%v, %v), want: (%v, %v)", tc.at, got, gotOK, tc.want, tc.wantOK)
+		}
+	}
+}
+
 func TestWeightedFloat64BucketsResizeWindow(t *testing.T) {
 	startTime := time.Now()
 	buckets := NewWeightedFloat64Buckets(5*time.Second, granularity)
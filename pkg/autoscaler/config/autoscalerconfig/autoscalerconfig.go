@@ -87,5 +87,55 @@ type Config struct {
 	// add an additional delay to the very last pod, if required.
 	ScaleDownDelay time.Duration
 
+	// ScaleUpRules and ScaleDownRules limit how fast the KPA may change the
+	// scale of a revision in the respective direction.
+	ScaleUpRules   ScalingRules
+	ScaleDownRules ScalingRules
+
 	PodAutoscalerClass string
 }
+
+// ScalingPolicyType is the unit in which a ScalingPolicy limits the change
+// of the scale.
+type ScalingPolicyType string
+
+const (
+	// PodsScalingPolicy limits the change to an absolute number of pods.
+	PodsScalingPolicy ScalingPolicyType = "pods"
+	// PercentScalingPolicy limits the change to a percentage of the scale
+	// at the beginning of the period.
+	PercentScalingPolicy ScalingPolicyType = "percent"
+)
+
+// ScalingPolicy limits the change of the scale within a period of time,
+// e.g. at most 2 pods per minute, or at most 10% per 5 minutes.
+type ScalingPolicy struct {
+	// Type is the unit of Value.
+	Type ScalingPolicyType
+	// Value is the maximum change within Period. It must be positive.
+	Value int32
+	// Period is the length of the sliding window the change is measured in.
+	Period time.Duration
+}
+
+// ScalingPolicySelect selects which of the limits computed by the scaling
+// policies is applied.
+type ScalingPolicySelect string
+
+const (
+	// MaxChangePolicySelect applies the policy permitting the largest change.
+	MaxChangePolicySelect ScalingPolicySelect = "max"
+	// MinChangePolicySelect applies the policy permitting the smallest change.
+	MinChangePolicySelect ScalingPolicySelect = "min"
+	// DisabledPolicySelect disables scaling in the direction altogether.
+	DisabledPolicySelect ScalingPolicySelect = "disabled"
+)
+
+// ScalingRules are the scaling policies for one direction of scaling.
+type ScalingRules struct {
+	// SelectPolicy selects the policy to apply when there are several.
+	SelectPolicy ScalingPolicySelect
+	// Policies are the limits of the change of the scale. No policies
+	// mean no limits, unless SelectPolicy is DisabledPolicySelect.
+	Policies []ScalingPolicy
+}
@@ -64,6 +64,12 @@ func defaultConfig() *autoscalerconfig.Config {
 		MinScale:                      0,
 		MaxScale:                      0,
 		MaxScaleLimit:                 0,
+		ScaleUpRules: autoscalerconfig.ScalingRules{
+			SelectPolicy: autoscalerconfig.MaxChangePolicySelect,
+		},
+		ScaleDownRules: autoscalerconfig.ScalingRules{
+			SelectPolicy: autoscalerconfig.MaxChangePolicySelect,
+		},
 	}
 }
 
@@ -96,6 +102,11 @@ func NewConfigFromMap(data map[string]string) (*autoscalerconfig.Config, error)
 		cm.AsDuration("scale-down-delay", &lc.ScaleDownDelay),
 		cm.AsDuration("scale-to-zero-grace-period", &lc.ScaleToZeroGracePeriod),
 		cm.AsDuration("scale-to-zero-pod-retention-period", &lc.ScaleToZeroPodRetentionPeriod),
+
+		asScalingPolicies("scale-up-policies", &lc.ScaleUpRules.Policies),
+		asScalingPolicies("scale-down-policies", &lc.ScaleDownRules.Policies),
+		asScalingPolicySelect("scale-up-select-policy", &lc.ScaleUpRules.SelectPolicy),
+		asScalingPolicySelect("scale-down-select-policy", &lc.ScaleDownRules.SelectPolicy),
 	); err != nil {
 		return nil, fmt.Errorf("failed to parse data: %w", err)
 	}
@@ -111,6 +122,36 @@ func NewConfigFromMap(data map[string]string) (*autoscalerconfig.Config, error)
 	return validate(lc)
 }
 
+// asScalingPolicies parses the value at key as a list of scaling policies
+// into the target, if it exists.
+func asScalingPolicies(key string, target *[]autoscalerconfig.ScalingPolicy) cm.ParseFunc {
+	return func(data map[string]string) error {
+		if raw, ok := data[key]; ok {
+			val, err := autoscaling.ParseScalingPolicies(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q: %w", key, err)
+			}
+			*target = val
+		}
+		return nil
+	}
+}
+
+// asScalingPolicySelect parses the value at key as a select policy into the
+// target, if it exists.
+func asScalingPolicySelect(key string, target *autoscalerconfig.ScalingPolicySelect) cm.ParseFunc {
+	return func(data map[string]string) error {
+		if raw, ok := data[key]; ok {
+			val, err := autoscaling.ParseScalingPolicySelect(raw)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q: %w", key, err)
+			}
+			*target = val
+		}
+		return nil
+	}
+}
+
 func validate(lc *autoscalerconfig.Config) (*autoscalerconfig.Config, error) {
 	if lc.ScaleToZeroGracePeriod <= 0 {
 		return nil, fmt.Errorf("scale-to-zero-grace-period must be positive, was: %v", lc.ScaleToZeroGracePeriod)
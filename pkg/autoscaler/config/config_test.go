@@ -159,6 +159,50 @@ func TestNewConfig(t *testing.T) {
 			c.MaxScale = 2
 			return c
 		}(),
+	}, {
+		name: "with scaling policies",
+		input: map[string]string{
+			"scale-up-policies":        "percent=100/15s,pods=4/15s",
+			"scale-down-policies":      "pods=2/1m",
+			"scale-up-select-policy":   "min",
+			"scale-down-select-policy": "disabled",
+		},
+		want: func() *autoscalerconfig.Config {
+			c := defaultConfig()
+			c.ScaleUpRules = autoscalerconfig.ScalingRules{
+				SelectPolicy: autoscalerconfig.MinChangePolicySelect,
+				Policies: []autoscalerconfig.ScalingPolicy{{
+					Type:   autoscalerconfig.PercentScalingPolicy,
+					Value:  100,
+					Period: 15 * time.Second,
+				}, {
+					Type:   autoscalerconfig.PodsScalingPolicy,
+					Value:  4,
+					Period: 15 * time.Second,
+				}},
+			}
+			c.ScaleDownRules = autoscalerconfig.ScalingRules{
+				SelectPolicy: autoscalerconfig.DisabledPolicySelect,
+				Policies: []autoscalerconfig.ScalingPolicy{{
+					Type:   autoscalerconfig.PodsScalingPolicy,
+					Value:  2,
+					Period: time.Minute,
+				}},
+			}
+			return c
+		}(),
+	}, {
+		name: "malformed scaling policies",
+		input: map[string]string{
+			"scale-down-policies": "pods=2",
+		},
+		wantErr: true,
+	}, {
+		name: "invalid select policy",
+		input: map[string]string{
+			"scale-up-select-policy": "fastest",
+		},
+		wantErr: true,
 	}, {
 		name: "malformed float",
 		input: map[string]string{
@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"errors"
+	"math"
 	"sync"
 	"time"
 
@@ -80,6 +81,25 @@ type MetricClient interface {
 	// StableAndPanicRPS returns both the stable and the panic RPS
 	// for the given replica as of the given time.
 	StableAndPanicRPS(key types.NamespacedName, now time.Time) (float64, float64, error)
+
+	// StableAndPanicCustom returns both the stable and the panic value of the
+	// custom application metric for the given replica as of the given time.
+	StableAndPanicCustom(key types.NamespacedName, now time.Time) (float64, float64, error)
+
+	// StableResponseStats returns the stats of the responses over the stable
+	// window for the given replica as of the given time.
+	StableResponseStats(key types.NamespacedName, now time.Time) (ResponseStats, error)
+}
+
+// ResponseStats are the stats of the responses of a revision over a window.
+type ResponseStats struct {
+	// ResponseRate is the number of responses per second.
+	ResponseRate float64
+	// ServerErrorRate is the number of the responses per second that were
+	// server errors.
+	ServerErrorRate float64
+	// MeanLatency is the mean latency of the responses.
+	MeanLatency time.Duration
 }
 
 // MetricCollector manages collection of metrics for many entities.
@@ -218,6 +238,50 @@ func (c *MetricCollector) StableAndPanicRPS(key types.NamespacedName, now time.T
 		nil
 }
 
+// StableAndPanicCustom returns both the stable and the panic value of the
+// custom application metric.
+// It may truncate metric buckets as a side-effect.
+func (c *MetricCollector) StableAndPanicCustom(key types.NamespacedName, now time.Time) (float64, float64, error) {
+	c.collectionsMutex.RLock()
+	defer c.collectionsMutex.RUnlock()
+
+	collection, exists := c.collections[key]
+	if !exists {
+		return 0, 0, ErrNotCollecting
+	}
+
+	if collection.customBuckets.IsEmpty(now) && collection.currentMetric().Spec.ScrapeTarget != "" {
+		return 0, 0, ErrNoData
+	}
+	return collection.customBuckets.WindowAverage(now),
+		collection.customPanicBuckets.WindowAverage(now),
+		nil
+}
+
+// StableResponseStats returns the stats of the responses over the stable
+// window.
+// It may truncate metric buckets as a side-effect.
+func (c *MetricCollector) StableResponseStats(key types.NamespacedName, now time.Time) (ResponseStats, error) {
+	c.collectionsMutex.RLock()
+	defer c.collectionsMutex.RUnlock()
+
+	collection, exists := c.collections[key]
+	if !exists {
+		return ResponseStats{}, ErrNotCollecting
+	}
+
+	responses := collection.responseBuckets.WindowAverage(now)
+	if responses <= 0 {
+		return ResponseStats{}, ErrNoData
+	}
+	return ResponseStats{
+		ResponseRate:    responses,
+		ServerErrorRate: collection.serverErrorBuckets.WindowAverage(now),
+		MeanLatency: time.Duration(collection.responseLatencyBuckets.WindowAverage(now) /
+			responses * float64(time.Second)),
+	}, nil
+}
+
 type (
 	// windowAverager is the client side abstraction for various bucket types.
 	windowAverager interface {
@@ -239,6 +303,11 @@ type (
 		concurrencyPanicBuckets windowAverager
 		rpsBuckets              windowAverager
 		rpsPanicBuckets         windowAverager
+		customBuckets           windowAverager
+		customPanicBuckets      windowAverager
+		responseBuckets         windowAverager
+		serverErrorBuckets      windowAverager
+		responseLatencyBuckets  windowAverager
 
 		// Fields relevant for metric scraping specifically.
 		scraper StatsScraper
@@ -286,6 +355,16 @@ func newCollection(metric *autoscalingv1alpha1.Metric, scraper StatsScraper, clo
 			metric.Spec.StableWindow, config.BucketSize),
 		rpsPanicBuckets: bucketCtor(
 			metric.Spec.PanicWindow, config.BucketSize),
+		customBuckets: bucketCtor(
+			metric.Spec.StableWindow, config.BucketSize),
+		customPanicBuckets: bucketCtor(
+			metric.Spec.PanicWindow, config.BucketSize),
+		responseBuckets: bucketCtor(
+			metric.Spec.StableWindow, config.BucketSize),
+		serverErrorBuckets: bucketCtor(
+			metric.Spec.StableWindow, config.BucketSize),
+		responseLatencyBuckets: bucketCtor(
+			metric.Spec.StableWindow, config.BucketSize),
 		scraper: scraper,
 
 		stopCh: make(chan struct{}),
@@ -347,6 +426,11 @@ func (c *collection) updateMetric(metric *autoscalingv1alpha1.Metric) {
 	c.concurrencyPanicBuckets.ResizeWindow(metric.Spec.PanicWindow)
 	c.rpsBuckets.ResizeWindow(metric.Spec.StableWindow)
 	c.rpsPanicBuckets.ResizeWindow(metric.Spec.PanicWindow)
+	c.customBuckets.ResizeWindow(metric.Spec.StableWindow)
+	c.customPanicBuckets.ResizeWindow(metric.Spec.PanicWindow)
+	c.responseBuckets.ResizeWindow(metric.Spec.StableWindow)
+	c.serverErrorBuckets.ResizeWindow(metric.Spec.StableWindow)
+	c.responseLatencyBuckets.ResizeWindow(metric.Spec.StableWindow)
 }
 
 // currentMetric safely returns the current metric stored in the collection.
@@ -382,19 +466,38 @@ func (c *collection) record(now time.Time, stat Stat) {
 	// Proxied requests have been counted at the activator. Subtract
 	// them to avoid double counting.
 	concur := stat.AverageConcurrentRequests - stat.AverageProxiedConcurrentRequests
+	if c.currentMetric().ExcludesLongLivedConnections() {
+		concur -= stat.AverageLongLivedConcurrentRequests - stat.AverageProxiedLongLivedConcurrentRequests
+	}
 	c.concurrencyBuckets.Record(now, concur)
 	c.concurrencyPanicBuckets.Record(now, concur)
 	rps := stat.RequestCount - stat.ProxiedRequestCount
 	c.rpsBuckets.Record(now, rps)
 	c.rpsPanicBuckets.Record(now, rps)
+	// The custom metric is NaN when it has no value, e.g. a pod failed to
+	// scrape it, and is left out then, for its buckets to go stale.
+	if !math.IsNaN(stat.CustomMetricValue) {
+		c.customBuckets.Record(now, stat.CustomMetricValue)
+		c.customPanicBuckets.Record(now, stat.CustomMetricValue)
+	}
+	c.responseBuckets.Record(now, stat.ResponseCount)
+	c.serverErrorBuckets.Record(now, stat.ServerErrorCount)
+	c.responseLatencyBuckets.Record(now, stat.ResponseLatencySum)
 }
 
 // add adds the stats from `src` to `dst`.
+// A custom metric without value in `src` leaves `dst` without value as well.
 func (dst *Stat) add(src Stat) {
 	dst.AverageConcurrentRequests += src.AverageConcurrentRequests
 	dst.AverageProxiedConcurrentRequests += src.AverageProxiedConcurrentRequests
 	dst.RequestCount += src.RequestCount
 	dst.ProxiedRequestCount += src.ProxiedRequestCount
+	dst.CustomMetricValue += src.CustomMetricValue
+	dst.AverageLongLivedConcurrentRequests += src.AverageLongLivedConcurrentRequests
+	dst.AverageProxiedLongLivedConcurrentRequests += src.AverageProxiedLongLivedConcurrentRequests
+	dst.ResponseCount += src.ResponseCount
+	dst.ServerErrorCount += src.ServerErrorCount
+	dst.ResponseLatencySum += src.ResponseLatencySum
 }
 
 // average reduces the aggregate stat from `sample` pods to an averaged one over
@@ -413,4 +516,10 @@ func (dst *Stat) average(sample, total float64) {
 	dst.AverageProxiedConcurrentRequests = dst.AverageProxiedConcurrentRequests / sample * total
 	dst.RequestCount = dst.RequestCount / sample * total
 	dst.ProxiedRequestCount = dst.ProxiedRequestCount / sample * total
+	dst.CustomMetricValue = dst.CustomMetricValue / sample * total
+	dst.AverageLongLivedConcurrentRequests = dst.AverageLongLivedConcurrentRequests / sample * total
+	dst.AverageProxiedLongLivedConcurrentRequests = dst.AverageProxiedLongLivedConcurrentRequests / sample * total
+	dst.ResponseCount = dst.ResponseCount / sample * total
+	dst.ServerErrorCount = dst.ServerErrorCount / sample * total
+	dst.ResponseLatencySum = dst.ResponseLatencySum / sample * total
 }
@@ -440,6 +440,7 @@ func TestMetricCollectorRecord(t *testing.T) {
 		AverageProxiedConcurrentRequests: 10, // this should be subtracted from the above.
 		RequestCount:                     want + 20,
 		ProxiedRequestCount:              20, // this should be subtracted from the above.
+		CustomMetricValue:                want,
 	}
 	scraper := &testScraper{
 		s: func() (Stat, error) {
@@ -466,6 +467,9 @@ func TestMetricCollectorRecord(t *testing.T) {
 	if _, _, err := coll.StableAndPanicRPS(metricKey, now); err == nil {
 		t.Error("StableAndPanicRPS() = nil, wanted an error")
 	}
+	if _, _, err := coll.StableAndPanicCustom(metricKey, now); err == nil {
+		t.Error("StableAndPanicCustom() = nil, wanted an error")
+	}
 
 	// Add two stats. The second record operation will remove the first outdated one.
 	// After this the concurrencies are calculated correctly.
@@ -491,6 +495,129 @@ func TestMetricCollectorRecord(t *testing.T) {
 	if math.Abs(stable-wantS) > tolerance || math.Abs(panic-wantP) > tolerance {
 		t.Errorf("StableAndPanicRPS() = %v, %v; want %v, %v", stable, panic, wantS, wantP)
 	}
+	stable, panic, err = coll.StableAndPanicCustom(metricKey, now)
+	if err != nil {
+		t.Fatal("StableAndPanicCustom:", err)
+	}
+	if math.Abs(stable-wantS) > tolerance || math.Abs(panic-wantP) > tolerance {
+		t.Errorf("StableAndPanicCustom() = %v, %v; want %v, %v", stable, panic, wantS, wantP)
+	}
+}
+
+func TestMetricCollectorRecordNoCustomMetric(t *testing.T) {
+	now := time.Now()
+	metricKey := types.NamespacedName{Namespace: defaultNamespace, Name: defaultName}
+	scraper := &testScraper{
+		s: func() (Stat, error) {
+			return emptyStat, nil
+		},
+	}
+	coll := NewMetricCollector(scraperFactory(scraper, nil), TestLogger(t))
+	coll.CreateOrUpdate(&defaultMetric)
+
+	var stat Stat
+	stat.add(Stat{PodName: "testPod", CustomMetricValue: 10})
+	stat.add(Stat{PodName: "testPod2", CustomMetricValue: math.NaN()})
+	stat.average(2, 2)
+	coll.Record(metricKey, now, stat)
+	if _, _, err := coll.StableAndPanicCustom(metricKey, now); !errors.Is(err, ErrNoData) {
+		t.Errorf("StableAndPanicCustom() = %v, want %v", err, ErrNoData)
+	}
+}
+
+func TestMetricCollectorRecordLongLivedConnections(t *testing.T) {
+	now := time.Now()
+	metricKey := types.NamespacedName{Namespace: defaultNamespace, Name: defaultName}
+	stat := Stat{
+		PodName:                                   "testPod",
+		AverageConcurrentRequests:                 10,
+		AverageProxiedConcurrentRequests:          4,
+		AverageLongLivedConcurrentRequests:        5,
+		AverageProxiedLongLivedConcurrentRequests: 2,
+	}
+
+	for _, test := range []struct {
+		name  string
+		value string
+		want  float64
+	}{{
+		name: "default",
+		want: 6,
+	}, {
+		name:  "included",
+		value: autoscaling.LongLivedConnectionsInclude,
+		want:  6,
+	}, {
+		name:  "excluded",
+		value: autoscaling.LongLivedConnectionsExclude,
+		// Both the direct and the proxied connections are taken out.
+		want: 3,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			coll := NewMetricCollector(scraperFactory(&testScraper{
+				s: func() (Stat, error) {
+					return emptyStat, nil
+				},
+			}, nil), TestLogger(t))
+			metric := defaultMetric.DeepCopy()
+			if test.value != "" {
+				metric.Annotations = map[string]string{
+					autoscaling.LongLivedConnectionsAnnotationKey: test.value,
+				}
+			}
+			coll.CreateOrUpdate(metric)
+			defer coll.Delete(defaultNamespace, defaultName)
+
+			coll.Record(metricKey, now, stat)
+			stable, panic, err := coll.StableAndPanicConcurrency(metricKey, now)
+			if err != nil {
+				t.Fatal("StableAndPanicConcurrency:", err)
+			}
+			if stable != test.want || panic != test.want {
+				t.Errorf("StableAndPanicConcurrency() = %v, %v; want %v, %v", stable, panic, test.want, test.want)
+			}
+		})
+	}
+}
+
+func TestMetricCollectorRecordResponses(t *testing.T) {
+	now := time.Now()
+	metricKey := types.NamespacedName{Namespace: defaultNamespace, Name: defaultName}
+	coll := NewMetricCollector(scraperFactory(&testScraper{
+		s: func() (Stat, error) {
+			return emptyStat, nil
+		},
+	}, nil), TestLogger(t))
+	coll.CreateOrUpdate(&defaultMetric)
+	defer coll.Delete(defaultNamespace, defaultName)
+
+	if _, err := coll.StableResponseStats(metricKey, now); !errors.Is(err, ErrNoData) {
+		t.Errorf("StableResponseStats() = %v, want: %v", err, ErrNoData)
+	}
+
+	coll.Record(metricKey, now, Stat{
+		PodName:            "testPod",
+		ResponseCount:      20,
+		ServerErrorCount:   1,
+		ResponseLatencySum: 5,
+	})
+	// The activator reports no responses, which doesn't skew the stats.
+	coll.Record(metricKey, now, Stat{
+		PodName:      "activator",
+		RequestCount: 3,
+	})
+	got, err := coll.StableResponseStats(metricKey, now)
+	if err != nil {
+		t.Fatal("StableResponseStats:", err)
+	}
+	want := ResponseStats{
+		ResponseRate:    20,
+		ServerErrorRate: 1,
+		MeanLatency:     250 * time.Millisecond,
+	}
+	if !cmp.Equal(got, want) {
+		t.Error("StableResponseStats() diff(-want,+got):", cmp.Diff(want, got))
+	}
 }
 
 func TestDoubleWatch(t *testing.T) {
@@ -618,6 +745,11 @@ func TestMetricCollectorAggregate(t *testing.T) {
 		concurrencyPanicBuckets: aggregation.NewTimedFloat64Buckets(m.Spec.PanicWindow, config.BucketSize),
 		rpsBuckets:              aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize),
 		rpsPanicBuckets:         aggregation.NewTimedFloat64Buckets(m.Spec.PanicWindow, config.BucketSize),
+		customBuckets:           aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize),
+		customPanicBuckets:      aggregation.NewTimedFloat64Buckets(m.Spec.PanicWindow, config.BucketSize),
+		responseBuckets:         aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize),
+		serverErrorBuckets:      aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize),
+		responseLatencyBuckets:  aggregation.NewTimedFloat64Buckets(m.Spec.StableWindow, config.BucketSize),
 	}
 	now := time.Now()
 	for i := time.Duration(0); i < 10; i++ {
@@ -625,6 +757,7 @@ func TestMetricCollectorAggregate(t *testing.T) {
 			PodName:                   "testPod",
 			AverageConcurrentRequests: float64(i + 5),
 			RequestCount:              float64(i + 5),
+			CustomMetricValue:         float64(i + 5),
 		}
 		c.record(now.Add(i*time.Second), stat)
 	}
@@ -639,4 +772,10 @@ func TestMetricCollectorAggregate(t *testing.T) {
 	if got, want := c.concurrencyPanicBuckets.WindowAverage(now), 13.5; got != want {
 		t.Errorf("Stable Concurrency = %f, want: %f", got, want)
 	}
+	if got, want := c.customBuckets.WindowAverage(now), 11.5; got != want {
+		t.Errorf("Stable Custom Metric = %f, want: %f", got, want)
+	}
+	if got, want := c.customPanicBuckets.WindowAverage(now), 13.5; got != want {
+		t.Errorf("Panic Custom Metric = %f, want: %f", got, want)
+	}
 }
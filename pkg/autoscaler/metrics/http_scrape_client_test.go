@@ -35,6 +35,9 @@ const (
 	queueAverageProxiedConcurrentRequests = 2.0
 	queueProxiedOperationsPerSecond       = 4
 	processUptime                         = 2937.12
+	customMetricValue                     = 17.5
+	queueAverageLongLivedRequests         = 1.5
+	queueAverageProxiedLongLivedRequests  = 0.5
 	podName                               = "test-revision-1234"
 )
 
@@ -48,6 +51,10 @@ var (
 		RequestCount:                     queueRequestsPerSecond,
 		ProxiedRequestCount:              queueProxiedOperationsPerSecond,
 		ProcessUptime:                    processUptime,
+		CustomMetricValue:                customMetricValue,
+
+		AverageLongLivedConcurrentRequests:        queueAverageLongLivedRequests,
+		AverageProxiedLongLivedConcurrentRequests: queueAverageProxiedLongLivedRequests,
 	}
 )
 
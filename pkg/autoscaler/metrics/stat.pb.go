@@ -57,6 +57,23 @@ type Stat struct {
 	// Time/date that the stat was generated in seconds since
 	// 1970-01-01 00:00:00.000 UTC.
 	Timestamp int64 `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Value of the custom application metric, if one is configured,
+	// as scraped from the user container. NaN if it could not be scraped.
+	CustomMetricValue float64 `protobuf:"fixed64,8,opt,name=custom_metric_value,json=customMetricValue,proto3" json:"custom_metric_value,omitempty"`
+	// Part of AverageConcurrentRequests, for long-lived connections, i.e.
+	// upgraded connections like WebSockets and server-sent events streams.
+	AverageLongLivedConcurrentRequests float64 `protobuf:"fixed64,9,opt,name=average_long_lived_concurrent_requests,json=averageLongLivedConcurrentRequests,proto3" json:"average_long_lived_concurrent_requests,omitempty"`
+	// Part of AverageLongLivedConcurrentRequests, for connections going through
+	// a proxy.
+	AverageProxiedLongLivedConcurrentRequests float64 `protobuf:"fixed64,10,opt,name=average_proxied_long_lived_concurrent_requests,json=averageProxiedLongLivedConcurrentRequests,proto3" json:"average_proxied_long_lived_concurrent_requests,omitempty"`
+	// Number of responses completed since last Stat (approximately responses
+	// per second). Responses of long-lived connections are not counted.
+	ResponseCount float64 `protobuf:"fixed64,11,opt,name=response_count,json=responseCount,proto3" json:"response_count,omitempty"`
+	// Part of ResponseCount, for responses that were server errors, i.e. had
+	// a 5xx status code.
+	ServerErrorCount float64 `protobuf:"fixed64,12,opt,name=server_error_count,json=serverErrorCount,proto3" json:"server_error_count,omitempty"`
+	// Sum of the latencies of the responses in ResponseCount, in seconds.
+	ResponseLatencySum float64 `protobuf:"fixed64,13,opt,name=response_latency_sum,json=responseLatencySum,proto3" json:"response_latency_sum,omitempty"`
 }
 
 func (m *Stat) Reset()         { *m = Stat{} }
@@ -141,6 +158,48 @@ func (m *Stat) GetTimestamp() int64 {
 	return 0
 }
 
+func (m *Stat) GetCustomMetricValue() float64 {
+	if m != nil {
+		return m.CustomMetricValue
+	}
+	return 0
+}
+
+func (m *Stat) GetAverageLongLivedConcurrentRequests() float64 {
+	if m != nil {
+		return m.AverageLongLivedConcurrentRequests
+	}
+	return 0
+}
+
+func (m *Stat) GetAverageProxiedLongLivedConcurrentRequests() float64 {
+	if m != nil {
+		return m.AverageProxiedLongLivedConcurrentRequests
+	}
+	return 0
+}
+
+func (m *Stat) GetResponseCount() float64 {
+	if m != nil {
+		return m.ResponseCount
+	}
+	return 0
+}
+
+func (m *Stat) GetServerErrorCount() float64 {
+	if m != nil {
+		return m.ServerErrorCount
+	}
+	return 0
+}
+
+func (m *Stat) GetResponseLatencySum() float64 {
+	if m != nil {
+		return m.ResponseLatencySum
+	}
+	return 0
+}
+
 // WireStatMessage is a copy of the StatMessage Golang type, exploding the fields of
 // `types.NamespacedName` to make it compatible with protobufs.
 type WireStatMessage struct {
@@ -261,30 +320,38 @@ func init() {
 func init() { proto.RegisterFile("pkg/autoscaler/metrics/stat.proto", fileDescriptor_cf216df9f6fff44c) }
 
 var fileDescriptor_cf216df9f6fff44c = []byte{
-	// 359 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x4f, 0x4b, 0xc3, 0x30,
-	0x18, 0xc6, 0x97, 0xad, 0xee, 0x4f, 0xe6, 0x54, 0x22, 0x42, 0x86, 0x52, 0xba, 0x0d, 0xa1, 0xa7,
-	0x0d, 0xa6, 0x67, 0x0f, 0xee, 0xe2, 0x65, 0x22, 0x15, 0xf1, 0x58, 0x62, 0xf6, 0x3a, 0x8a, 0xb6,
-	0x89, 0x49, 0x2a, 0x7e, 0x0c, 0x3f, 0x96, 0x27, 0xd9, 0xd1, 0xa3, 0x6c, 0x5f, 0x44, 0x1a, 0xb3,
-	0x4d, 0xc7, 0x4e, 0x0d, 0xcf, 0xfb, 0x7b, 0x9e, 0x17, 0x9e, 0xb7, 0xb8, 0x23, 0x9f, 0xa6, 0x03,
-	0x96, 0x1b, 0xa1, 0x39, 0x7b, 0x06, 0x35, 0x48, 0xc1, 0xa8, 0x84, 0xeb, 0x81, 0x36, 0xcc, 0xf4,
-	0xa5, 0x12, 0x46, 0x90, 0x9a, 0xd3, 0xba, 0x9f, 0x65, 0xec, 0xdd, 0x1a, 0x66, 0x48, 0x1b, 0xd7,
-	0xa5, 0x98, 0xc4, 0x19, 0x4b, 0x81, 0xa2, 0x00, 0x85, 0x8d, 0xa8, 0x26, 0xc5, 0xe4, 0x9a, 0xa5,
-	0x40, 0x2e, 0xf0, 0x31, 0x7b, 0x05, 0xc5, 0xa6, 0x10, 0x73, 0x91, 0xf1, 0x5c, 0x29, 0xc8, 0x4c,
-	0xac, 0xe0, 0x25, 0x07, 0x6d, 0x34, 0x2d, 0x07, 0x28, 0x44, 0x51, 0xdb, 0x21, 0xa3, 0x15, 0x11,
-	0x39, 0x80, 0x8c, 0x71, 0x6f, 0xe9, 0x97, 0x4a, 0xbc, 0x25, 0x30, 0xd9, 0x9a, 0x53, 0xb1, 0x39,
-	0x81, 0x43, 0x6f, 0x7e, 0xc9, 0x2d, 0x71, 0x3d, 0xdc, 0x72, 0x9e, 0x98, 0x8b, 0x3c, 0x33, 0xd4,
-	0xb3, 0xc6, 0x5d, 0x27, 0x8e, 0x0a, 0x8d, 0x0c, 0xf1, 0xd1, 0x72, 0xd7, 0x7f, 0x78, 0xc7, 0xc2,
-	0x87, 0x6e, 0x18, 0xfd, 0xf5, 0x9c, 0xe2, 0x3d, 0xa9, 0x04, 0x07, 0xad, 0xe3, 0x5c, 0x9a, 0x24,
-	0x05, 0x5a, 0xb5, 0x70, 0xcb, 0xa9, 0x77, 0x56, 0x24, 0x27, 0xb8, 0x51, 0x7c, 0xb5, 0x61, 0xa9,
-	0xa4, 0xb5, 0x00, 0x85, 0x95, 0x68, 0x2d, 0x74, 0x1f, 0xf1, 0xfe, 0x7d, 0xa2, 0xa0, 0xe8, 0x74,
-	0x0c, 0x5a, 0xb3, 0xa9, 0x35, 0x14, 0xb5, 0x6a, 0xc9, 0xf8, 0xb2, 0xdb, 0xb5, 0x40, 0x08, 0xf6,
-	0x6c, 0xe9, 0x65, 0x3b, 0xb0, 0x6f, 0xd2, 0xc1, 0x5e, 0x71, 0x2c, 0x5b, 0x49, 0x73, 0xd8, 0xea,
-	0xbb, 0x6b, 0xf5, 0x8b, 0xd4, 0xc8, 0x8e, 0xba, 0x57, 0xf8, 0x60, 0x63, 0x8f, 0x26, 0xe7, 0xb8,
-	0x9e, 0xba, 0x37, 0x45, 0x41, 0x25, 0x6c, 0x0e, 0xe9, 0xca, 0xba, 0x01, 0x47, 0x2b, 0xf2, 0x92,
-	0x7e, 0xcc, 0x7d, 0x34, 0x9b, 0xfb, 0xe8, 0x7b, 0xee, 0xa3, 0xf7, 0x85, 0x5f, 0x9a, 0x2d, 0xfc,
-	0xd2, 0xd7, 0xc2, 0x2f, 0x3d, 0x54, 0xed, 0xcf, 0x72, 0xf6, 0x13, 0x00, 0x00, 0xff, 0xff, 0x6d,
-	0xc3, 0xa5, 0x6a, 0x51, 0x02, 0x00, 0x00,
+	// 496 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0x41, 0x6f, 0xd3, 0x30,
+	0x18, 0x86, 0x1b, 0xda, 0xad, 0xed, 0xd7, 0x75, 0x0c, 0x0f, 0x24, 0x4f, 0xa0, 0xa8, 0xeb, 0x34,
+	0x54, 0x24, 0x94, 0xa2, 0xc2, 0x99, 0x03, 0x13, 0x12, 0x87, 0x16, 0xa1, 0x4c, 0xc0, 0xd1, 0x32,
+	0xe9, 0x47, 0x14, 0x91, 0xc4, 0xc6, 0x76, 0x22, 0xf8, 0x17, 0xfc, 0x20, 0x7e, 0x00, 0xc7, 0x1d,
+	0x39, 0xa2, 0xf6, 0x8f, 0xa0, 0x38, 0x4e, 0xc7, 0xaa, 0x02, 0xa7, 0xba, 0xef, 0xfb, 0x7c, 0xaf,
+	0xa5, 0x37, 0x9f, 0xe1, 0x54, 0x7e, 0x8a, 0xa7, 0xbc, 0x30, 0x42, 0x47, 0x3c, 0x45, 0x35, 0xcd,
+	0xd0, 0xa8, 0x24, 0xd2, 0x53, 0x6d, 0xb8, 0x09, 0xa4, 0x12, 0x46, 0x90, 0xae, 0xd3, 0xc6, 0xdf,
+	0xf7, 0xa0, 0x73, 0x69, 0xb8, 0x21, 0x27, 0xd0, 0x93, 0x62, 0xc9, 0x72, 0x9e, 0x21, 0xf5, 0x46,
+	0xde, 0xa4, 0x1f, 0x76, 0xa5, 0x58, 0xbe, 0xe6, 0x19, 0x92, 0xe7, 0x70, 0x9f, 0x97, 0xa8, 0x78,
+	0x8c, 0x2c, 0x12, 0x79, 0x54, 0x28, 0x85, 0xb9, 0x61, 0x0a, 0x3f, 0x17, 0xa8, 0x8d, 0xa6, 0xb7,
+	0x46, 0xde, 0xc4, 0x0b, 0x4f, 0x1c, 0x72, 0xb1, 0x21, 0x42, 0x07, 0x90, 0x05, 0x9c, 0x35, 0xf3,
+	0x52, 0x89, 0x2f, 0x09, 0x2e, 0x77, 0xe6, 0xb4, 0x6d, 0xce, 0xc8, 0xa1, 0x6f, 0x6a, 0x72, 0x47,
+	0xdc, 0x19, 0x0c, 0xdd, 0x0c, 0x8b, 0x44, 0x91, 0x1b, 0xda, 0xb1, 0x83, 0x07, 0x4e, 0xbc, 0xa8,
+	0x34, 0x32, 0x83, 0x7b, 0xcd, 0x5d, 0x37, 0xe1, 0x3d, 0x0b, 0x1f, 0x3b, 0x33, 0xfc, 0x73, 0xe6,
+	0x1c, 0x0e, 0xa5, 0x12, 0x11, 0x6a, 0xcd, 0x0a, 0x69, 0x92, 0x0c, 0xe9, 0xbe, 0x85, 0x87, 0x4e,
+	0x7d, 0x6b, 0x45, 0xf2, 0x00, 0xfa, 0xd5, 0xaf, 0x36, 0x3c, 0x93, 0xb4, 0x3b, 0xf2, 0x26, 0xed,
+	0xf0, 0x5a, 0x20, 0x01, 0x1c, 0x47, 0x85, 0x36, 0x22, 0x63, 0x75, 0xc5, 0xac, 0xe4, 0x69, 0x81,
+	0xb4, 0x67, 0x93, 0xee, 0xd4, 0xd6, 0xc2, 0x3a, 0xef, 0x2a, 0x83, 0x84, 0xf0, 0xb0, 0x29, 0x27,
+	0x15, 0x79, 0xcc, 0xd2, 0xa4, 0xfc, 0x4b, 0x3f, 0x7d, 0x1b, 0x31, 0x76, 0xf4, 0x5c, 0xe4, 0xf1,
+	0x3c, 0x29, 0x77, 0x36, 0xc4, 0x21, 0xd8, 0x2e, 0xfc, 0x3f, 0xd9, 0x60, 0xb3, 0x1f, 0xdd, 0xec,
+	0xfe, 0x5f, 0x57, 0x9c, 0xc3, 0xa1, 0x42, 0x2d, 0x45, 0xae, 0xd1, 0x15, 0x3b, 0xa8, 0xbb, 0x6a,
+	0xd4, 0xba, 0xd2, 0xc7, 0x40, 0x34, 0xaa, 0x12, 0x15, 0x43, 0xa5, 0x84, 0x72, 0xe8, 0x81, 0x45,
+	0x8f, 0x6a, 0xe7, 0x65, 0x65, 0xd4, 0xf4, 0x13, 0xb8, 0xbb, 0x09, 0x4d, 0xb9, 0xc1, 0x3c, 0xfa,
+	0xca, 0x74, 0x91, 0xd1, 0xa1, 0xe5, 0x49, 0xe3, 0xcd, 0x6b, 0xeb, 0xb2, 0xc8, 0xc6, 0x1f, 0xe1,
+	0xf6, 0xfb, 0x44, 0x61, 0xb5, 0xc1, 0x0b, 0xd4, 0x9a, 0xc7, 0xf6, 0xf3, 0x54, 0x4b, 0xac, 0x25,
+	0x8f, 0x9a, 0x4d, 0xbe, 0x16, 0x08, 0x81, 0x4e, 0xf5, 0xc7, 0x2e, 0x6d, 0x3f, 0xb4, 0x67, 0x72,
+	0x0a, 0x9d, 0xea, 0x69, 0xd8, 0x05, 0x1c, 0xcc, 0x86, 0x81, 0x7b, 0x1b, 0x41, 0x95, 0x1a, 0x5a,
+	0x6b, 0xfc, 0x0a, 0x8e, 0xb6, 0xee, 0xd1, 0xe4, 0x19, 0xf4, 0x32, 0x77, 0xa6, 0xde, 0xa8, 0x3d,
+	0x19, 0xcc, 0xe8, 0x66, 0x74, 0x0b, 0x0e, 0x37, 0xe4, 0x0b, 0xfa, 0x63, 0xe5, 0x7b, 0x57, 0x2b,
+	0xdf, 0xfb, 0xb5, 0xf2, 0xbd, 0x6f, 0x6b, 0xbf, 0x75, 0xb5, 0xf6, 0x5b, 0x3f, 0xd7, 0x7e, 0xeb,
+	0xc3, 0xbe, 0x7d, 0x9a, 0x4f, 0x7f, 0x0f, 0x00, 0x10, 0x5a, 0xc6, 0xf7, 0xbf, 0x03, 0x00, 0x00,
 }
 
 func (m *Stat) Marshal() (dAtA []byte, err error) {
@@ -307,6 +374,42 @@ func (m *Stat) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ResponseLatencySum != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ResponseLatencySum))))
+		i--
+		dAtA[i] = 0x69
+	}
+	if m.ServerErrorCount != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ServerErrorCount))))
+		i--
+		dAtA[i] = 0x61
+	}
+	if m.ResponseCount != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.ResponseCount))))
+		i--
+		dAtA[i] = 0x59
+	}
+	if m.AverageProxiedLongLivedConcurrentRequests != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.AverageProxiedLongLivedConcurrentRequests))))
+		i--
+		dAtA[i] = 0x51
+	}
+	if m.AverageLongLivedConcurrentRequests != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.AverageLongLivedConcurrentRequests))))
+		i--
+		dAtA[i] = 0x49
+	}
+	if m.CustomMetricValue != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.CustomMetricValue))))
+		i--
+		dAtA[i] = 0x41
+	}
 	if m.Timestamp != 0 {
 		i = encodeVarintStat(dAtA, i, uint64(m.Timestamp))
 		i--
@@ -477,6 +580,24 @@ func (m *Stat) Size() (n int) {
 	if m.Timestamp != 0 {
 		n += 1 + sovStat(uint64(m.Timestamp))
 	}
+	if m.CustomMetricValue != 0 {
+		n += 9
+	}
+	if m.AverageLongLivedConcurrentRequests != 0 {
+		n += 9
+	}
+	if m.AverageProxiedLongLivedConcurrentRequests != 0 {
+		n += 9
+	}
+	if m.ResponseCount != 0 {
+		n += 9
+	}
+	if m.ServerErrorCount != 0 {
+		n += 9
+	}
+	if m.ResponseLatencySum != 0 {
+		n += 9
+	}
 	return n
 }
 
@@ -657,6 +778,72 @@ func (m *Stat) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 8:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CustomMetricValue", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.CustomMetricValue = float64(math.Float64frombits(v))
+		case 9:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AverageLongLivedConcurrentRequests", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.AverageLongLivedConcurrentRequests = float64(math.Float64frombits(v))
+		case 10:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AverageProxiedLongLivedConcurrentRequests", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.AverageProxiedLongLivedConcurrentRequests = float64(math.Float64frombits(v))
+		case 11:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseCount", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ResponseCount = float64(math.Float64frombits(v))
+		case 12:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ServerErrorCount", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ServerErrorCount = float64(math.Float64frombits(v))
+		case 13:
+			if wireType != 1 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResponseLatencySum", wireType)
+			}
+			var v uint64
+			if (iNdEx + 8) > l {
+				return io.ErrUnexpectedEOF
+			}
+			v = uint64(encoding_binary.LittleEndian.Uint64(dAtA[iNdEx:]))
+			iNdEx += 8
+			m.ResponseLatencySum = float64(math.Float64frombits(v))
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStat(dAtA[iNdEx:])
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxWireStatMessagesSize limits the size of a single record of a stat
+// stream, to protect against reading corrupted streams.
+const maxWireStatMessagesSize = 16 << 20
+
+// WriteWireStatMessages appends the messages to a stat stream. A stat stream
+// is a sequence of records, each of which is the size of the marshaled
+// WireStatMessages encoded as an unsigned varint, followed by the marshaled
+// WireStatMessages itself.
+func WriteWireStatMessages(w io.Writer, wsms *WireStatMessages) error {
+	b, err := wsms.Marshal()
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(b))
+	buf = append(buf[:binary.PutUvarint(buf, uint64(len(b)))], b...)
+	_, err = w.Write(buf)
+	return err
+}
+
+// StatStreamReader reads the records of a stat stream, as written by
+// WriteWireStatMessages.
+type StatStreamReader struct {
+	r *bufio.Reader
+}
+
+// NewStatStreamReader creates a reader of the stat stream read from r.
+func NewStatStreamReader(r io.Reader) *StatStreamReader {
+	return &StatStreamReader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record of the stream. It returns io.EOF at the end
+// of the stream and io.ErrUnexpectedEOF if the last record is truncated.
+func (r *StatStreamReader) Read() (*WireStatMessages, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		// ReadUvarint returns io.EOF only if no bytes were read.
+		return nil, err
+	}
+	if size > maxWireStatMessagesSize {
+		return nil, fmt.Errorf("stat stream record of %d bytes exceeds the maximum of %d bytes", size, maxWireStatMessagesSize)
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	wsms := &WireStatMessages{}
+	if err := wsms.Unmarshal(b); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stat stream record: %w", err)
+	}
+	return wsms, nil
+}
+
+// ReadStatStream reads all the stat messages of the stream. Messages
+// without a stat are skipped, like the stats server does.
+func ReadStatStream(r io.Reader) ([]StatMessage, error) {
+	var ret []StatMessage
+	sr := NewStatStreamReader(r)
+	for {
+		wsms, err := sr.Read()
+		if errors.Is(err, io.EOF) {
+			return ret, nil
+		} else if err != nil {
+			return nil, err
+		}
+		for _, wsm := range wsms.Messages {
+			if wsm.Stat == nil {
+				continue
+			}
+			ret = append(ret, wsm.ToStatMessage())
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStatStream(t *testing.T) {
+	sms := []StatMessage{{
+		Key: types.NamespacedName{Namespace: "test-namespace", Name: "test-name"},
+		Stat: Stat{
+			PodName:                   "pod1",
+			AverageConcurrentRequests: 1.5,
+			RequestCount:              30,
+			Timestamp:                 1700000000,
+		},
+	}, {
+		Key: types.NamespacedName{Namespace: "test-namespace", Name: "test-name"},
+		Stat: Stat{
+			PodName:                   "pod2",
+			AverageConcurrentRequests: 2.5,
+			RequestCount:              50,
+			Timestamp:                 1700000001,
+		},
+	}, {
+		Key: types.NamespacedName{Namespace: "test-namespace2", Name: "test-name2"},
+		Stat: Stat{
+			PodName:           "pod3",
+			CustomMetricValue: 42,
+			Timestamp:         1700000001,
+		},
+	}}
+
+	var buf bytes.Buffer
+	for _, batch := range [][]StatMessage{sms[:2], sms[2:]} {
+		wsms := ToWireStatMessages(batch)
+		if err := WriteWireStatMessages(&buf, &wsms); err != nil {
+			t.Fatal("WriteWireStatMessages() =", err)
+		}
+	}
+	// Messages without stats are skipped.
+	if err := WriteWireStatMessages(&buf, &WireStatMessages{
+		Messages: []*WireStatMessage{{Namespace: "test-namespace", Name: "test-name"}},
+	}); err != nil {
+		t.Fatal("WriteWireStatMessages() =", err)
+	}
+	stream := buf.Bytes()
+
+	got, err := ReadStatStream(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatal("ReadStatStream() =", err)
+	}
+	if !cmp.Equal(got, sms) {
+		t.Error("ReadStatStream() mismatch (-want,+got):", cmp.Diff(sms, got))
+	}
+
+	// A truncated record is an error.
+	if _, err := ReadStatStream(bytes.NewReader(stream[:len(stream)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("ReadStatStream(truncated) = %v, want: %v", err, io.ErrUnexpectedEOF)
+	}
+	// So is an implausibly large one.
+	if _, err := ReadStatStream(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0x0f})); err == nil {
+		t.Error("ReadStatStream(huge record) = nil, want error")
+	}
+	// And an empty stream is just empty.
+	if got, err := ReadStatStream(bytes.NewReader(nil)); err != nil || len(got) != 0 {
+		t.Errorf("ReadStatStream(empty) = %v, %v, want no messages", got, err)
+	}
+}
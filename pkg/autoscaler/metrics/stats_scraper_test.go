@@ -49,18 +49,21 @@ var (
 		AverageProxiedConcurrentRequests: 2.0,
 		RequestCount:                     5,
 		ProxiedRequestCount:              4,
+		CustomMetricValue:                1,
 	}, {
 		PodName:                          "pod-2",
 		AverageConcurrentRequests:        5.0,
 		AverageProxiedConcurrentRequests: 4.0,
 		RequestCount:                     7,
 		ProxiedRequestCount:              6,
+		CustomMetricValue:                2,
 	}, {
 		PodName:                          "pod-3",
 		AverageConcurrentRequests:        3.0,
 		AverageProxiedConcurrentRequests: 2.0,
 		RequestCount:                     5,
 		ProxiedRequestCount:              4,
+		CustomMetricValue:                1,
 	}}
 )
 
@@ -125,6 +128,10 @@ func checkBaseStat(t *testing.T, got Stat) {
 	if got.ProxiedRequestCount != 14 {
 		t.Errorf("stat.ProxiedRequestCount=%v, want %v", got.ProxiedRequestCount, 14)
 	}
+	// ((1 + 2 + 1) / 3.0) * 3 = 4
+	if got.CustomMetricValue != 4 {
+		t.Errorf("stat.CustomMetricValue=%v, want %v", got.CustomMetricValue, 4)
+	}
 }
 
 func TestPodDirectScrapeSuccess(t *testing.T) {
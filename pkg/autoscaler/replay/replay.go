@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
+	"knative.dev/serving/pkg/autoscaler/metrics"
+	"knative.dev/serving/pkg/autoscaler/scaling"
+	smetrics "knative.dev/serving/pkg/metrics"
+	kparesources "knative.dev/serving/pkg/reconciler/autoscaling/kpa/resources"
+	aresources "knative.dev/serving/pkg/reconciler/autoscaling/resources"
+)
+
+// TickInterval is how often the autoscaler is evaluated during a replay,
+// which matches the interval of the autoscaler.
+const TickInterval = 2 * time.Second
+
+// Options configure a replay.
+type Options struct {
+	// Config is the autoscaler configuration to replay with.
+	Config *autoscalerconfig.Config
+	// Annotations are the autoscaling annotations of the revision.
+	Annotations map[string]string
+	// ContainerConcurrency is the container concurrency of the revision,
+	// 0 meaning unlimited.
+	ContainerConcurrency int64
+	// Revision is the revision whose stats are replayed. The stats of other
+	// revisions are ignored. If unset, the revision of the first stat is used.
+	Revision types.NamespacedName
+	// PodStartDelay is how long it takes for a new pod to become ready.
+	PodStartDelay time.Duration
+}
+
+// Sample is a point of the time series produced by a replay.
+type Sample struct {
+	// Time is the simulated time of the sample.
+	Time time.Time
+	// ReadyPods is the number of ready pods at the end of the tick.
+	ReadyPods int32
+	// DesiredPods is the scale requested from the deployment, i.e. the
+	// desired pod count of the autoscaler within the scale bounds.
+	DesiredPods int32
+	// Decision is the scaling decision made during the tick.
+	Decision scaling.ScaleDecision
+}
+
+// Replay feeds the recorded stats through the metric collector and the
+// autoscaler of the revision, configured by opts, and returns the resulting
+// desired and ready pod counts for every tick. The time is simulated, starting
+// with the timestamp of the earliest stat and ending with the latest one.
+//
+// The deployment is simulated as well: new pods become ready after
+// PodStartDelay and a decision to scale to zero is applied after the
+// scale-to-zero grace period.
+func Replay(logger *zap.SugaredLogger, opts Options, stats []metrics.StatMessage) ([]Sample, error) {
+	if opts.Config == nil {
+		return nil, errors.New("autoscaler config must be set")
+	}
+	if err := autoscaling.ValidateAnnotations(context.Background(), opts.Config, opts.Annotations); err != nil {
+		return nil, fmt.Errorf("invalid annotations: %w", err)
+	}
+	stats = revisionStats(opts.Revision, stats)
+	if len(stats) == 0 {
+		return nil, errors.New("no stats to replay")
+	}
+	key := stats[0].Key
+
+	pa := &autoscalingv1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   key.Namespace,
+			Name:        key.Name,
+			Annotations: kmeta.CopyMap(opts.Annotations),
+		},
+		Spec: autoscalingv1alpha1.PodAutoscalerSpec{
+			ContainerConcurrency: opts.ContainerConcurrency,
+			Reachability:         autoscalingv1alpha1.ReachabilityReachable,
+		},
+	}
+
+	// The stats are only ever recorded, so there is nothing to scrape.
+	collector := metrics.NewMetricCollector(
+		func(*autoscalingv1alpha1.Metric, *zap.SugaredLogger) (metrics.StatsScraper, error) {
+			return nil, nil
+		}, logger)
+	if err := collector.CreateOrUpdate(aresources.MakeMetric(pa, "", opts.Config)); err != nil {
+		return nil, err
+	}
+	defer collector.Delete(key.Namespace, key.Name)
+
+	decider := kparesources.MakeDecider(pa, opts.Config)
+	min, max := pa.ScaleBounds(opts.Config)
+	desired := applyBounds(min, max, decider.Spec.InitialScale)
+	pods := &pods{ready: int(desired), startDelay: opts.PodStartDelay}
+	scaler, err := scaling.NewAlgorithm(smetrics.RevisionContext(key.Namespace, "", "", key.Name),
+		decider, collector, pods)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		samples   []Sample
+		next      int
+		zeroSince time.Time
+	)
+	start, end := time.Unix(stats[0].Stat.Timestamp, 0), time.Unix(stats[len(stats)-1].Stat.Timestamp, 0)
+	for now := start; !now.After(end); now = now.Add(TickInterval) {
+		for ; next < len(stats) && stats[next].Stat.Timestamp <= now.Unix(); next++ {
+			collector.Record(key, time.Unix(stats[next].Stat.Timestamp, 0), stats[next].Stat)
+		}
+		pods.advance(now)
+
+		sr := scaler.Scale(logger, now)
+		sr.Decision.Time = metav1.NewTime(now)
+		sr.Decision.ScaleValid = sr.ScaleValid
+		if sr.ScaleValid {
+			want := sr.DesiredPodCount
+			if want == 0 {
+				if zeroSince.IsZero() {
+					zeroSince = now
+				}
+				// Mimic the PodAutoscaler, which only scales to zero after the grace period.
+				switch {
+				case !opts.Config.EnableScaleToZero:
+					want = 1
+				case now.Sub(zeroSince) < opts.Config.ScaleToZeroGracePeriod:
+					want = desired
+				}
+			} else {
+				zeroSince = time.Time{}
+			}
+			desired = applyBounds(min, max, want)
+			pods.scale(now, int(desired))
+			pods.advance(now)
+		}
+
+		samples = append(samples, Sample{
+			Time:        now,
+			ReadyPods:   int32(pods.ready),
+			DesiredPods: desired,
+			Decision:    sr.Decision,
+		})
+	}
+	return samples, nil
+}
+
+// revisionStats returns the stats of the revision, ordered by time. If the
+// revision is unset, the revision of the first stat is used. Stats without a
+// timestamp can't be placed in time and are dropped.
+func revisionStats(rev types.NamespacedName, stats []metrics.StatMessage) []metrics.StatMessage {
+	var ret []metrics.StatMessage
+	for _, sm := range stats {
+		if sm.Stat.Timestamp == 0 {
+			continue
+		}
+		if rev == (types.NamespacedName{}) {
+			rev = sm.Key
+		}
+		if sm.Key == rev {
+			ret = append(ret, sm)
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Stat.Timestamp < ret[j].Stat.Timestamp
+	})
+	return ret
+}
+
+// applyBounds clamps x to the scale bounds, like the PodAutoscaler does.
+func applyBounds(min, max, x int32) int32 {
+	if x < min {
+		return min
+	}
+	if max != 0 && x > max {
+		return max
+	}
+	return x
+}
+
+// pods simulates the pods of the revision's deployment.
+type pods struct {
+	startDelay time.Duration
+	ready      int
+	// starting holds the times the starting pods become ready, in order.
+	starting []time.Time
+}
+
+// ReadyCount implements resources.EndpointsCounter.
+func (p *pods) ReadyCount() (int, error) {
+	return p.ready, nil
+}
+
+// NotReadyCount implements resources.EndpointsCounter.
+func (p *pods) NotReadyCount() (int, error) {
+	return len(p.starting), nil
+}
+
+// advance marks the pods that started by now as ready.
+func (p *pods) advance(now time.Time) {
+	for len(p.starting) > 0 && !p.starting[0].After(now) {
+		p.ready++
+		p.starting = p.starting[1:]
+	}
+}
+
+// scale starts or stops pods to reach the desired count. The most recently
+// started pods are stopped first.
+func (p *pods) scale(now time.Time, desired int) {
+	n := desired - p.ready - len(p.starting)
+	for ; n > 0; n-- {
+		p.starting = append(p.starting, now.Add(p.startDelay))
+	}
+	for ; n < 0 && len(p.starting) > 0; n++ {
+		p.starting = p.starting[:len(p.starting)-1]
+	}
+	p.ready += n
+}
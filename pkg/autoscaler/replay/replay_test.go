@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	asconfig "knative.dev/serving/pkg/autoscaler/config"
+	"knative.dev/serving/pkg/autoscaler/metrics"
+)
+
+var (
+	testRevision  = types.NamespacedName{Namespace: "test-namespace", Name: "test-revision"}
+	otherRevision = types.NamespacedName{Namespace: "test-namespace", Name: "other-revision"}
+)
+
+// load returns a stat per second between from and to, relative to the start,
+// with the given concurrency.
+func load(key types.NamespacedName, start time.Time, from, to time.Duration, concurrency float64) []metrics.StatMessage {
+	var ret []metrics.StatMessage
+	for d := from; d < to; d += time.Second {
+		ret = append(ret, metrics.StatMessage{
+			Key: key,
+			Stat: metrics.Stat{
+				PodName:                   "pod",
+				AverageConcurrentRequests: concurrency,
+				Timestamp:                 start.Add(d).Unix(),
+			},
+		})
+	}
+	return ret
+}
+
+func TestReplay(t *testing.T) {
+	cfg, err := asconfig.NewConfigFromMap(nil)
+	if err != nil {
+		t.Fatal("NewConfigFromMap() =", err)
+	}
+	start := time.Unix(1700000000, 0)
+
+	// The load of 100 lasts for two minutes and then stops.
+	var stats []metrics.StatMessage
+	stats = append(stats, load(testRevision, start, 0, 2*time.Minute, 100)...)
+	stats = append(stats, load(testRevision, start, 2*time.Minute, 5*time.Minute, 0)...)
+	// The stats of other revisions don't matter.
+	stats = append(load(otherRevision, start, 0, time.Minute, 1000), stats...)
+
+	samples, err := Replay(logtesting.TestLogger(t), Options{
+		Config: cfg,
+		Annotations: map[string]string{
+			autoscaling.TargetAnnotationKey:            "10",
+			autoscaling.TargetUtilizationPercentageKey: "100",
+		},
+		Revision:      testRevision,
+		PodStartDelay: 10 * time.Second,
+	}, stats)
+	if err != nil {
+		t.Fatal("Replay() =", err)
+	}
+
+	if got, want := len(samples), int((5*time.Minute-time.Second)/TickInterval)+1; got != want {
+		t.Fatalf("#samples = %d, want: %d", got, want)
+	}
+	if got, want := samples[0].Time, start; !got.Equal(want) {
+		t.Errorf("samples[0].Time = %v, want: %v", got, want)
+	}
+
+	// Find the first tick that scaled to the full load.
+	scaledUp := -1
+	for i, s := range samples {
+		if s.DesiredPods == 10 {
+			scaledUp = i
+			break
+		}
+	}
+	if scaledUp == -1 {
+		t.Fatalf("Replay() never scaled to 10 pods: %+v", samples)
+	}
+	if !samples[scaledUp].Decision.ScaleValid || !samples[scaledUp].Decision.InPanicMode {
+		t.Errorf("Decision = %+v, want a valid panic mode decision", samples[scaledUp].Decision)
+	}
+	// The pods become ready only after the start delay.
+	delayed := scaledUp + int(10*time.Second/TickInterval)
+	if got := samples[delayed-1].ReadyPods; got == 10 {
+		t.Errorf("ReadyPods before the start delay passed = %d, want less than 10", got)
+	}
+	if got, want := samples[delayed].ReadyPods, int32(10); got != want {
+		t.Errorf("ReadyPods after the start delay passed = %d, want: %d", got, want)
+	}
+
+	// Eventually the revision is scaled to zero.
+	last := samples[len(samples)-1]
+	if last.DesiredPods != 0 || last.ReadyPods != 0 {
+		t.Errorf("Last sample = %+v, want scaled to zero", last)
+	}
+}
+
+func TestReplayScaleToZeroDisabled(t *testing.T) {
+	cfg, err := asconfig.NewConfigFromMap(map[string]string{
+		"enable-scale-to-zero": "false",
+	})
+	if err != nil {
+		t.Fatal("NewConfigFromMap() =", err)
+	}
+	start := time.Unix(1700000000, 0)
+
+	samples, err := Replay(logtesting.TestLogger(t), Options{Config: cfg},
+		load(testRevision, start, 0, 3*time.Minute, 0))
+	if err != nil {
+		t.Fatal("Replay() =", err)
+	}
+	for _, s := range samples {
+		if s.DesiredPods != 1 || s.ReadyPods != 1 {
+			t.Fatalf("Sample = %+v, want 1 desired and ready pod", s)
+		}
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	cfg, err := asconfig.NewConfigFromMap(nil)
+	if err != nil {
+		t.Fatal("NewConfigFromMap() =", err)
+	}
+	stats := load(testRevision, time.Unix(1700000000, 0), 0, time.Minute, 1)
+
+	tests := []struct {
+		name  string
+		opts  Options
+		stats []metrics.StatMessage
+	}{{
+		name:  "no config",
+		stats: stats,
+	}, {
+		name: "invalid annotations",
+		opts: Options{
+			Config:      cfg,
+			Annotations: map[string]string{autoscaling.TargetAnnotationKey: "-1"},
+		},
+		stats: stats,
+	}, {
+		name: "no stats",
+		opts: Options{Config: cfg},
+	}, {
+		name: "no stats of the revision",
+		opts: Options{
+			Config:   cfg,
+			Revision: otherRevision,
+		},
+		stats: stats,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Replay(logtesting.TestLogger(t), test.opts, test.stats); err == nil {
+				t.Error("Replay() = nil, want error")
+			}
+		})
+	}
+}
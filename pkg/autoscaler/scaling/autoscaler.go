@@ -30,6 +30,7 @@ import (
 	pkgmetrics "knative.dev/pkg/metrics"
 	"knative.dev/serving/pkg/apis/autoscaling"
 	"knative.dev/serving/pkg/autoscaler/aggregation/max"
+	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/autoscaler/metrics"
 	"knative.dev/serving/pkg/resources"
 
@@ -57,6 +58,13 @@ type autoscaler struct {
 	// window has passed at the reduced concurrency.
 	delayWindow *max.TimeWindow
 
+	// history holds the desired pod counts for the scaling policies.
+	history scaleHistory
+
+	// forecaster predicts the load in the predictive scaling mode.
+	// It is nil in the reactive scaling mode.
+	forecaster *forecaster
+
 	// specMux guards the current DeciderSpec.
 	specMux     sync.RWMutex
 	deciderSpec *DeciderSpec
@@ -153,16 +161,8 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 
 	metricKey := types.NamespacedName{Namespace: a.namespace, Name: a.revision}
 
-	metricName := spec.ScalingMetric
-	var observedStableValue, observedPanicValue float64
-	switch spec.ScalingMetric {
-	case autoscaling.RPS:
-		observedStableValue, observedPanicValue, err = a.metricClient.StableAndPanicRPS(metricKey, now)
-	default:
-		metricName = autoscaling.Concurrency // concurrency is used by default
-		observedStableValue, observedPanicValue, err = a.metricClient.StableAndPanicConcurrency(metricKey, now)
-	}
-
+	metricName := scalingMetricName(spec.ScalingMetric)
+	observedStableValue, observedPanicValue, err := a.observe(metricName, metricKey, now)
 	if err != nil {
 		if errors.Is(err, metrics.ErrNoData) {
 			logger.Debug("No data to scale on yet")
@@ -186,6 +186,51 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 
 	dspc := math.Ceil(observedStableValue / spec.TargetValue)
 	dppc := math.Ceil(observedPanicValue / spec.TargetValue)
+
+	// In the predictive mode provision for the forecasted load as well, so that
+	// the pods are ready by the time the load arrives.
+	forecastValue := 0.
+	if spec.ScalingMode == autoscaling.ScalingModePredictive {
+		a.forecaster = a.forecaster.update(spec)
+		a.forecaster.record(now, observedStableValue)
+		forecastValue = a.forecaster.forecast(now, observedStableValue)
+		dspc = math.Max(dspc, math.Ceil(forecastValue/spec.TargetValue))
+		if debugEnabled {
+			desugared.Debug(
+				fmt.Sprintf("For metric %s forecasted value in %v: %0.3f", metricName, spec.PredictiveHorizon, forecastValue))
+		}
+	} else {
+		a.forecaster = nil
+	}
+
+	// Compute the desired pod counts for the additional metrics as well and
+	// pick the largest ones, as the pods need to keep every metric at its target.
+	stableMetric, panicMetric := metricName, metricName
+	for _, mt := range spec.AdditionalMetrics {
+		name := scalingMetricName(mt.Metric)
+		stable, panic, err := a.observe(name, metricKey, now)
+		if err != nil {
+			if errors.Is(err, metrics.ErrNoData) {
+				logger.Debugf("No data to scale on %s yet", name)
+			} else {
+				logger.Errorw("Failed to obtain metrics for "+name, zap.Error(err))
+			}
+			continue
+		}
+		a.recordObserved(name, stable, panic, mt.TargetValue)
+		if debugEnabled {
+			desugared.Debug(
+				fmt.Sprintf("For metric %s observed values: stable = %0.3f; panic = %0.3f; target = %0.3f",
+					name, stable, panic, mt.TargetValue))
+		}
+		if pc := math.Ceil(stable / mt.TargetValue); pc > dspc {
+			dspc, stableMetric = pc, name
+		}
+		if pc := math.Ceil(panic / mt.TargetValue); pc > dppc {
+			dppc, panicMetric = pc, name
+		}
+	}
+
 	if debugEnabled {
 		desugared.Debug(
 			fmt.Sprintf("For metric %s observed values: stable = %0.3f; panic = %0.3f; target = %0.3f "+
@@ -227,7 +272,11 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 	}
 
 	desiredPodCount := desiredStablePodCount
+	scalingMetric := stableMetric
 	if !a.panicTime.IsZero() {
+		if desiredPanicPodCount > desiredStablePodCount {
+			scalingMetric = panicMetric
+		}
 		// In some edgecases stable window metric might be larger
 		// than panic one. And we should provision for stable as for panic,
 		// so pick the larger of the two.
@@ -253,6 +302,7 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 	// not the same in the case where two Scale()s happen in the same time
 	// interval (because the largest will be picked rather than the most recent
 	// in that case).
+	undelayedPodCount := desiredPodCount
 	if a.delayWindow != nil {
 		a.delayWindow.Record(now, desiredPodCount)
 		delayedPodCount := a.delayWindow.Current()
@@ -266,6 +316,25 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 		}
 	}
 
+	// Limit the change of the pod count according to the scaling policies.
+	// Like above, scale down of unreachable revisions is not limited.
+	unlimitedPodCount := desiredPodCount
+	if a.history.empty() {
+		// The pod count before the first decision is the current one.
+		a.history.record(now, int32(originalReadyPodsCount), 0)
+	}
+	downRules := spec.ScaleDownRules
+	if !spec.Reachable {
+		downRules = autoscalerconfig.ScalingRules{}
+	}
+	desiredPodCount = a.history.limit(now, desiredPodCount, spec.ScaleUpRules, downRules)
+	if desiredPodCount != unlimitedPodCount && debugEnabled {
+		desugared.Debug(
+			fmt.Sprintf("Limiting scale to %d by the scaling policies, scaling to %d",
+				unlimitedPodCount, desiredPodCount))
+	}
+	a.history.record(now, desiredPodCount, longestPeriod(spec.ScaleUpRules, spec.ScaleDownRules))
+
 	// Compute excess burst capacity
 	//
 	// the excess burst capacity is based on panic value, since we don't want to
@@ -288,29 +357,88 @@ func (a *autoscaler) Scale(logger *zap.SugaredLogger, now time.Time) ScaleResult
 			observedPanicValue, spec.TargetBurstCapacity, excessBCF))
 	}
 
-	switch spec.ScalingMetric {
-	case autoscaling.RPS:
-		pkgmetrics.RecordBatch(a.reporterCtx,
-			excessBurstCapacityM.M(excessBCF),
-			desiredPodCountM.M(int64(desiredPodCount)),
-			stableRPSM.M(observedStableValue),
-			panicRPSM.M(observedPanicValue),
-			targetRPSM.M(spec.TargetValue),
-		)
-	default:
-		pkgmetrics.RecordBatch(a.reporterCtx,
-			excessBurstCapacityM.M(excessBCF),
-			desiredPodCountM.M(int64(desiredPodCount)),
-			stableRequestConcurrencyM.M(observedStableValue),
-			panicRequestConcurrencyM.M(observedPanicValue),
-			targetRequestConcurrencyM.M(spec.TargetValue),
-		)
+	pkgmetrics.RecordBatch(a.reporterCtx,
+		excessBurstCapacityM.M(excessBCF),
+		desiredPodCountM.M(int64(desiredPodCount)),
+	)
+	a.recordObserved(metricName, observedStableValue, observedPanicValue, spec.TargetValue)
+	if a.forecaster != nil {
+		pkgmetrics.Record(a.reporterCtx, forecastValueM.M(forecastValue))
 	}
 
+	// The stats of the responses play no part in the decision, they are only
+	// passed on for the analysis of the rollouts, so their absence is fine.
+	responseStats, _ := a.metricClient.StableResponseStats(metricKey, now)
+
 	return ScaleResult{
 		DesiredPodCount:     desiredPodCount,
 		ExcessBurstCapacity: int32(excessBCF),
-		ScaleValid:          true,
+		ForecastValue:       forecastValue,
+		ScalingMetric:       scalingMetric,
+		ResponseStats:       responseStats,
+		Decision: ScaleDecision{
+			ScalingMetric:         scalingMetric,
+			ObservedStableValue:   observedStableValue,
+			ObservedPanicValue:    observedPanicValue,
+			TargetValue:           spec.TargetValue,
+			ReadyPodCount:         int32(originalReadyPodsCount),
+			MaxScaleUp:            int32(maxScaleUp),
+			MaxScaleDown:          int32(maxScaleDown),
+			DesiredStablePodCount: desiredStablePodCount,
+			DesiredPanicPodCount:  desiredPanicPodCount,
+			InPanicMode:           !a.panicTime.IsZero(),
+			UndelayedPodCount:     undelayedPodCount,
+			UnlimitedPodCount:     unlimitedPodCount,
+			ExcessBurstCapacity:   int32(excessBCF),
+			DesiredPodCount:       desiredPodCount,
+		},
+		ScaleValid: true,
+	}
+}
+
+// scalingMetricName returns the name of the metric the autoscaler scales on
+// for the given metric of the DeciderSpec.
+func scalingMetricName(metric string) string {
+	if metric == autoscaling.RPS || autoscaling.IsCustomMetric(metric) {
+		return metric
+	}
+	return autoscaling.Concurrency // concurrency is used by default
+}
+
+// observe returns the stable and the panic values of the given metric.
+func (a *autoscaler) observe(metric string, key types.NamespacedName, now time.Time) (float64, float64, error) {
+	switch {
+	case metric == autoscaling.RPS:
+		return a.metricClient.StableAndPanicRPS(key, now)
+	case autoscaling.IsCustomMetric(metric):
+		return a.metricClient.StableAndPanicCustom(key, now)
+	default:
+		return a.metricClient.StableAndPanicConcurrency(key, now)
+	}
+}
+
+// recordObserved records the observed stable and panic values of the given
+// metric along with its target.
+func (a *autoscaler) recordObserved(metric string, stable, panic, target float64) {
+	switch {
+	case metric == autoscaling.RPS:
+		pkgmetrics.RecordBatch(a.reporterCtx,
+			stableRPSM.M(stable),
+			panicRPSM.M(panic),
+			targetRPSM.M(target),
+		)
+	case autoscaling.IsCustomMetric(metric):
+		pkgmetrics.RecordBatch(a.reporterCtx,
+			stableCustomMetricM.M(stable),
+			panicCustomMetricM.M(panic),
+			targetCustomMetricM.M(target),
+		)
+	default:
+		pkgmetrics.RecordBatch(a.reporterCtx,
+			stableRequestConcurrencyM.M(stable),
+			panicRequestConcurrencyM.M(panic),
+			targetRequestConcurrencyM.M(target),
+		)
 	}
 }
 
@@ -32,6 +32,8 @@ import (
 	servingmetrics "knative.dev/serving/pkg/metrics"
 
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/autoscaler/metrics"
 	"knative.dev/serving/pkg/resources"
 
@@ -128,6 +130,151 @@ func TestAutoscalerScaleDownDelay(t *testing.T) {
 	})
 }
 
+func TestAutoscalerScalingPolicies(t *testing.T) {
+	pc := &fakePodCounter{}
+	metrics := &metricClient{}
+	spec := &DeciderSpec{
+		TargetValue:      10,
+		MaxScaleDownRate: 10,
+		MaxScaleUpRate:   100,
+		PanicThreshold:   100,
+		Reachable:        true,
+		ScaleUpRules: autoscalerconfig.ScalingRules{
+			Policies: []autoscalerconfig.ScalingPolicy{{
+				Type:   autoscalerconfig.PercentScalingPolicy,
+				Value:  100,
+				Period: time.Minute,
+			}},
+		},
+		ScaleDownRules: autoscalerconfig.ScalingRules{
+			Policies: []autoscalerconfig.ScalingPolicy{{
+				Type:   autoscalerconfig.PodsScalingPolicy,
+				Value:  2,
+				Period: time.Minute,
+			}},
+		},
+	}
+	as := New(context.Background(), testNamespace, testRevision, metrics, pc, spec)
+	pc.readyCount = 1
+	now := time.Time{}
+
+	// Need 10 pods, but may only double the pod count per minute.
+	metrics.SetStableAndPanicConcurrency(100, 100)
+	expectScale(t, as, now, ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 2,
+		Decision: ScaleDecision{
+			ScalingMetric:         autoscaling.Concurrency,
+			ObservedStableValue:   100,
+			ObservedPanicValue:    100,
+			TargetValue:           10,
+			ReadyPodCount:         1,
+			MaxScaleUp:            100,
+			DesiredStablePodCount: 10,
+			DesiredPanicPodCount:  10,
+			UndelayedPodCount:     10,
+			UnlimitedPodCount:     10,
+			DesiredPodCount:       2,
+		},
+	})
+	pc.readyCount = 2
+	expectScale(t, as, now.Add(30*time.Second), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 2,
+	})
+	expectScale(t, as, now.Add(time.Minute), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 4,
+	})
+	pc.readyCount = 4
+	expectScale(t, as, now.Add(2*time.Minute), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 8,
+	})
+	pc.readyCount = 8
+	expectScale(t, as, now.Add(3*time.Minute), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 10,
+	})
+	pc.readyCount = 10
+
+	// May remove only 2 pods per minute.
+	metrics.SetStableAndPanicConcurrency(0, 0)
+	expectScale(t, as, now.Add(4*time.Minute), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 8,
+	})
+	pc.readyCount = 8
+	expectScale(t, as, now.Add(4*time.Minute+30*time.Second), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 8,
+	})
+	expectScale(t, as, now.Add(5*time.Minute), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 6,
+	})
+
+	// Scale down of unreachable revisions is not limited.
+	spec.Reachable = false
+	expectScale(t, as, now.Add(5*time.Minute+2*time.Second), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 0,
+	})
+}
+
+func TestAutoscalerDecision(t *testing.T) {
+	pc := &fakePodCounter{readyCount: 1}
+	metrics := &metricClient{}
+	spec := &DeciderSpec{
+		TargetValue:      10,
+		MaxScaleDownRate: 10,
+		MaxScaleUpRate:   10,
+		PanicThreshold:   100,
+		ScaleDownDelay:   5 * time.Minute,
+		Reachable:        true,
+	}
+	as := New(context.Background(), testNamespace, testRevision, metrics, pc, spec)
+
+	now := time.Time{}
+	metrics.SetStableAndPanicConcurrency(40, 50)
+	expectScale(t, as, now.Add(2*time.Second), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 4,
+		ScalingMetric:   autoscaling.Concurrency,
+		Decision: ScaleDecision{
+			ScalingMetric:         autoscaling.Concurrency,
+			ObservedStableValue:   40,
+			ObservedPanicValue:    50,
+			TargetValue:           10,
+			ReadyPodCount:         1,
+			MaxScaleUp:            10,
+			MaxScaleDown:          0,
+			DesiredStablePodCount: 4,
+			DesiredPanicPodCount:  5,
+			UndelayedPodCount:     4,
+			UnlimitedPodCount:     4,
+			DesiredPodCount:       4,
+		},
+	})
+
+	// The scale down is delayed, which the decision records.
+	metrics.SetStableAndPanicConcurrency(0, 0)
+	expectScale(t, as, now.Add(4*time.Second), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 4,
+		ScalingMetric:   autoscaling.Concurrency,
+		Decision: ScaleDecision{
+			ScalingMetric:     autoscaling.Concurrency,
+			TargetValue:       10,
+			ReadyPodCount:     1,
+			MaxScaleUp:        10,
+			UndelayedPodCount: 0,
+			UnlimitedPodCount: 4,
+			DesiredPodCount:   4,
+		},
+	})
+}
+
 func TestAutoscalerScaleDownDelayZero(t *testing.T) {
 	pc := &fakePodCounter{}
 	metrics := &metricClient{}
@@ -165,7 +312,7 @@ func TestAutoscalerNoDataNoAutoscale(t *testing.T) {
 	}
 
 	a := newTestAutoscalerNoPC(10, 100, metrics)
-	expectScale(t, a, time.Now(), ScaleResult{0, 0, false})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 0, ExcessBurstCapacity: 0, ScaleValid: false})
 }
 
 func expectedEBC(totCap, targetBC, recordedConcurrency, numPods float64) int32 {
@@ -190,7 +337,7 @@ func TestAutoscalerMetrics(t *testing.T) {
 	// Non-panic created autoscaler.
 	metricstest.AssertMetric(t, metricstest.IntMetric(panicM.Name(), 0, nil).WithResource(wantResource))
 	ebc := expectedEBC(10, 100, 50, 1)
-	expectScale(t, a, time.Now(), ScaleResult{5, ebc, true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: ebc, ScaleValid: true})
 	spec := a.currentSpec()
 
 	wantMetrics := []metricstest.Metric{
@@ -209,10 +356,10 @@ func TestAutoscalerMetricsWithRPS(t *testing.T) {
 	metrics := &metricClient{PanicRPS: 99.0, StableRPS: 100}
 	a, _ := newTestAutoscalerWithScalingMetric(10, 100, metrics, "rps", false /*startInPanic*/)
 	ebc := expectedEBC(10, 100, 99, 1)
-	expectScale(t, a, time.Now(), ScaleResult{10, ebc, true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: ebc, ScaleValid: true})
 	spec := a.currentSpec()
 
-	expectScale(t, a, time.Now().Add(61*time.Second), ScaleResult{10, ebc, true})
+	expectScale(t, a, time.Now().Add(61*time.Second), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: ebc, ScaleValid: true})
 	wantMetrics := []metricstest.Metric{
 		metricstest.FloatMetric(stableRPSM.Name(), 100, nil).WithResource(wantResource),
 		metricstest.FloatMetric(panicRPSM.Name(), 99, nil).WithResource(wantResource),
@@ -224,23 +371,91 @@ func TestAutoscalerMetricsWithRPS(t *testing.T) {
 	metricstest.AssertMetric(t, wantMetrics...)
 }
 
+func TestAutoscalerMetricsWithCustomMetric(t *testing.T) {
+	defer reset()
+	metrics := &metricClient{StableCustom: 100, PanicCustom: 99}
+	a, _ := newTestAutoscalerWithScalingMetric(10, 100, metrics, "queue_depth", false /*startInPanic*/)
+	ebc := expectedEBC(10, 100, 99, 1)
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: ebc, ScaleValid: true})
+	spec := a.currentSpec()
+
+	wantMetrics := []metricstest.Metric{
+		metricstest.FloatMetric(stableCustomMetricM.Name(), 100, nil).WithResource(wantResource),
+		metricstest.FloatMetric(panicCustomMetricM.Name(), 99, nil).WithResource(wantResource),
+		metricstest.IntMetric(desiredPodCountM.Name(), 10, nil).WithResource(wantResource),
+		metricstest.FloatMetric(targetCustomMetricM.Name(), spec.TargetValue, nil).WithResource(wantResource),
+		metricstest.FloatMetric(excessBurstCapacityM.Name(), float64(ebc), nil).WithResource(wantResource),
+		metricstest.IntMetric(panicM.Name(), 1, nil).WithResource(wantResource),
+	}
+	metricstest.AssertMetric(t, wantMetrics...)
+}
+
 func TestAutoscalerStableModeIncreaseWithConcurrencyDefault(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 50.0, PanicConcurrency: 10}
 	a := newTestAutoscalerNoPC(10, 101, metrics)
-	expectScale(t, a, time.Now(), ScaleResult{5, expectedEBC(10, 101, 10, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: expectedEBC(10, 101, 10, 1), ScaleValid: true})
 
 	metrics.StableConcurrency = 100
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 101, 10, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 101, 10, 1), ScaleValid: true})
+}
+
+func TestAutoscalerResponseStats(t *testing.T) {
+	mc := &metricClient{StableConcurrency: 50.0, PanicConcurrency: 10}
+	a := newTestAutoscalerNoPC(10, 101, mc)
+	ebc := expectedEBC(10, 101, 10, 1)
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: ebc, ScaleValid: true})
+
+	rs := metrics.ResponseStats{ResponseRate: 10, ServerErrorRate: 1, MeanLatency: 25 * time.Millisecond}
+	mc.ResponseStats = &rs
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: ebc, ResponseStats: rs, ScaleValid: true})
 }
 
 func TestAutoscalerStableModeIncreaseWithRPS(t *testing.T) {
 	metrics := &metricClient{StableRPS: 50.0, PanicRPS: 50}
 	a, _ := newTestAutoscalerWithScalingMetric(10, 101, metrics, "rps", false /*startInPanic*/)
-	expectScale(t, a, time.Now(), ScaleResult{5, expectedEBC(10, 101, 50, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: expectedEBC(10, 101, 50, 1), ScaleValid: true})
 
 	metrics.StableRPS = 100
 	metrics.PanicRPS = 99
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 101, 99, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 101, 99, 1), ScaleValid: true})
+}
+
+func TestAutoscalerStableModeIncreaseWithCustomMetric(t *testing.T) {
+	metrics := &metricClient{StableCustom: 50.0, PanicCustom: 50, StableConcurrency: 1000}
+	a, _ := newTestAutoscalerWithScalingMetric(10, 101, metrics, "queue_depth", false /*startInPanic*/)
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: expectedEBC(10, 101, 50, 1), ScaleValid: true})
+
+	metrics.StableCustom = 100
+	metrics.PanicCustom = 99
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 101, 99, 1), ScaleValid: true})
+}
+
+func TestAutoscalerAdditionalMetrics(t *testing.T) {
+	defer reset()
+	metrics := &metricClient{
+		StableConcurrency: 30,
+		PanicConcurrency:  30,
+		StableRPS:         200,
+		PanicRPS:          200,
+	}
+	a, pc := newTestAutoscalerWithScalingMetric(10, 0, metrics, autoscaling.Concurrency, false /*startInPanic*/)
+	pc.readyCount = 5
+	a.deciderSpec.AdditionalMetrics = []MetricTarget{{
+		Metric:      autoscaling.RPS,
+		TargetValue: 50,
+	}}
+
+	// RPS needs more pods than concurrency.
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 4, ScalingMetric: autoscaling.RPS, ScaleValid: true})
+	metricstest.AssertMetric(t,
+		metricstest.FloatMetric(stableRPSM.Name(), 200, nil).WithResource(wantResource),
+		metricstest.FloatMetric(targetRPSM.Name(), 50, nil).WithResource(wantResource),
+		metricstest.FloatMetric(stableRequestConcurrencyM.Name(), 30, nil).WithResource(wantResource),
+	)
+
+	// Now concurrency needs more pods than RPS.
+	metrics.StableRPS, metrics.PanicRPS = 100, 100
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 3, ScalingMetric: autoscaling.Concurrency, ScaleValid: true})
 }
 
 func TestAutoscalerUnpanicAfterSlowIncrease(t *testing.T) {
@@ -251,7 +466,7 @@ func TestAutoscalerUnpanicAfterSlowIncrease(t *testing.T) {
 
 	start := time.Now()
 	tm := start
-	expectScale(t, a, tm, ScaleResult{25, expectedEBC(1, 98, 25, 10), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 25, ExcessBurstCapacity: expectedEBC(1, 98, 25, 10), ScaleValid: true})
 	if a.panicTime != tm {
 		t.Errorf("PanicTime = %v, want: %v", a.panicTime, tm)
 	}
@@ -262,7 +477,7 @@ func TestAutoscalerUnpanicAfterSlowIncrease(t *testing.T) {
 	metrics.SetStableAndPanicConcurrency(30, 41)
 	tm = tm.Add(stableWindow / 2)
 
-	expectScale(t, a, tm, ScaleResult{41, expectedEBC(1, 98, 41, 40), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 41, ExcessBurstCapacity: expectedEBC(1, 98, 41, 40), ScaleValid: true})
 	if a.panicTime != start {
 		t.Error("Panic Time should not have moved")
 	}
@@ -272,7 +487,7 @@ func TestAutoscalerUnpanicAfterSlowIncrease(t *testing.T) {
 	metrics.SetStableAndPanicConcurrency(50, 56)
 	tm = tm.Add(stableWindow/2 + tickInterval)
 
-	expectScale(t, a, tm, ScaleResult{50 /* no longer in panic*/, expectedEBC(1, 98, 56, 55), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 50 /* no longer in panic*/, ExcessBurstCapacity: expectedEBC(1, 98, 56, 55), ScaleValid: true})
 	if !a.panicTime.IsZero() {
 		t.Errorf("PanicTime = %v, want: 0", a.panicTime)
 	}
@@ -286,7 +501,7 @@ func TestAutoscalerExtendPanicWindow(t *testing.T) {
 
 	start := time.Now()
 	tm := start
-	expectScale(t, a, tm, ScaleResult{25, expectedEBC(1, 98, 25, 10), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 25, ExcessBurstCapacity: expectedEBC(1, 98, 25, 10), ScaleValid: true})
 	if a.panicTime != tm {
 		t.Errorf("PanicTime = %v, want: %v", a.panicTime, tm)
 	}
@@ -296,7 +511,7 @@ func TestAutoscalerExtendPanicWindow(t *testing.T) {
 	metrics.SetStableAndPanicConcurrency(30, 80)
 	tm = tm.Add(stableWindow / 2)
 
-	expectScale(t, a, tm, ScaleResult{80, expectedEBC(1, 98, 80, 40), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 80, ExcessBurstCapacity: expectedEBC(1, 98, 80, 40), ScaleValid: true})
 	if a.panicTime != tm {
 		t.Errorf("PanicTime = %v, want: %v", a.panicTime, tm)
 	}
@@ -306,29 +521,29 @@ func TestAutoscalerStableModeDecrease(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 100.0, PanicConcurrency: 100}
 	a, pc := newTestAutoscaler(10, 98, metrics)
 	pc.readyCount = 8
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 98, 100, 8), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 98, 100, 8), ScaleValid: true})
 
 	metrics.SetStableAndPanicConcurrency(50, 50)
-	expectScale(t, a, time.Now(), ScaleResult{5, expectedEBC(10, 98, 50, 8), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: expectedEBC(10, 98, 50, 8), ScaleValid: true})
 }
 
 func TestAutoscalerStableModeNoTrafficScaleToZero(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 1, PanicConcurrency: 0}
 	a := newTestAutoscalerNoPC(10, 75, metrics)
-	expectScale(t, a, time.Now(), ScaleResult{1, expectedEBC(10, 75, 0, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 1, ExcessBurstCapacity: expectedEBC(10, 75, 0, 1), ScaleValid: true})
 
 	metrics.StableConcurrency = 0.0
-	expectScale(t, a, time.Now(), ScaleResult{0, expectedEBC(10, 75, 0, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 0, ExcessBurstCapacity: expectedEBC(10, 75, 0, 1), ScaleValid: true})
 }
 
 func TestAutoscalerActivationScale(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 0, PanicConcurrency: 0}
 	a := newTestAutoscalerNoPC(10, 75, metrics)
 	a.deciderSpec.ActivationScale = int32(2)
-	expectScale(t, a, time.Now(), ScaleResult{0, expectedEBC(10, 75, 0, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 0, ExcessBurstCapacity: expectedEBC(10, 75, 0, 1), ScaleValid: true})
 
 	metrics.StableConcurrency = 1.0
-	expectScale(t, a, time.Now(), ScaleResult{2, expectedEBC(10, 75, 0, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 2, ExcessBurstCapacity: expectedEBC(10, 75, 0, 1), ScaleValid: true})
 }
 
 // QPS is increasing exponentially. Each scaling event bring concurrency
@@ -337,12 +552,12 @@ func TestAutoscalerActivationScale(t *testing.T) {
 func TestAutoscalerPanicModeExponentialTrackAndStabilize(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 6, PanicConcurrency: 6}
 	a, pc := newTestAutoscaler(1, 101, metrics)
-	expectScale(t, a, time.Now(), ScaleResult{6, expectedEBC(1, 101, 6, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 6, ExcessBurstCapacity: expectedEBC(1, 101, 6, 1), ScaleValid: true})
 
 	tm := time.Now()
 	pc.readyCount = 6
 	metrics.SetStableAndPanicConcurrency(36, 36)
-	expectScale(t, a, tm, ScaleResult{36, expectedEBC(1, 101, 36, 6), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 36, ExcessBurstCapacity: expectedEBC(1, 101, 36, 6), ScaleValid: true})
 	if got, want := a.panicTime, tm; got != tm {
 		t.Errorf("PanicTime = %v, want: %v", got, want)
 	}
@@ -350,21 +565,21 @@ func TestAutoscalerPanicModeExponentialTrackAndStabilize(t *testing.T) {
 	pc.readyCount = 36
 	metrics.SetStableAndPanicConcurrency(216, 216)
 	tm = tm.Add(time.Second)
-	expectScale(t, a, tm, ScaleResult{216, expectedEBC(1, 101, 216, 36), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 216, ExcessBurstCapacity: expectedEBC(1, 101, 216, 36), ScaleValid: true})
 	if got, want := a.panicTime, tm; got != tm {
 		t.Errorf("PanicTime = %v, want: %v", got, want)
 	}
 
 	pc.readyCount = 216
 	metrics.SetStableAndPanicConcurrency(1296, 1296)
-	expectScale(t, a, tm, ScaleResult{1296, expectedEBC(1, 101, 1296, 216), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 1296, ExcessBurstCapacity: expectedEBC(1, 101, 1296, 216), ScaleValid: true})
 	if got, want := a.panicTime, tm; got != tm {
 		t.Errorf("PanicTime = %v, want: %v", got, want)
 	}
 
 	pc.readyCount = 1296
 	tm = tm.Add(time.Second)
-	expectScale(t, a, tm, ScaleResult{1296, expectedEBC(1, 101, 1296, 1296), true})
+	expectScale(t, a, tm, ScaleResult{DesiredPodCount: 1296, ExcessBurstCapacity: expectedEBC(1, 101, 1296, 1296), ScaleValid: true})
 }
 
 func TestAutoscalerScale(t *testing.T) {
@@ -478,7 +693,7 @@ func TestAutoscalerScale(t *testing.T) {
 			if test.prepFunc != nil {
 				test.prepFunc(test.as)
 			}
-			expectScale(tt, test.as, time.Now(), ScaleResult{test.wantScale, test.wantEBC, !test.wantInvalid})
+			expectScale(tt, test.as, time.Now(), ScaleResult{DesiredPodCount: test.wantScale, ExcessBurstCapacity: test.wantEBC, ScaleValid: !test.wantInvalid})
 		})
 	}
 }
@@ -486,19 +701,19 @@ func TestAutoscalerScale(t *testing.T) {
 func TestAutoscalerPanicThenUnPanicScaleDown(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 100, PanicConcurrency: 100}
 	a, pc := newTestAutoscaler(10, 93, metrics)
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 93, 100, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 93, 100, 1), ScaleValid: true})
 	pc.readyCount = 10
 
 	panicTime := time.Now()
 	metrics.PanicConcurrency = 1000
-	expectScale(t, a, panicTime, ScaleResult{100, expectedEBC(10, 93, 1000, 10), true})
+	expectScale(t, a, panicTime, ScaleResult{DesiredPodCount: 100, ExcessBurstCapacity: expectedEBC(10, 93, 1000, 10), ScaleValid: true})
 
 	// Traffic dropped off, scale stays as we're still in panic.
 	metrics.SetStableAndPanicConcurrency(1, 1)
-	expectScale(t, a, panicTime.Add(30*time.Second), ScaleResult{100, expectedEBC(10, 93, 1, 10), true})
+	expectScale(t, a, panicTime.Add(30*time.Second), ScaleResult{DesiredPodCount: 100, ExcessBurstCapacity: expectedEBC(10, 93, 1, 10), ScaleValid: true})
 
 	// Scale down after the StableWindow
-	expectScale(t, a, panicTime.Add(61*time.Second), ScaleResult{1, expectedEBC(10, 93, 1, 10), true})
+	expectScale(t, a, panicTime.Add(61*time.Second), ScaleResult{DesiredPodCount: 1, ExcessBurstCapacity: expectedEBC(10, 93, 1, 10), ScaleValid: true})
 }
 
 func TestAutoscalerRateLimitScaleUp(t *testing.T) {
@@ -506,11 +721,11 @@ func TestAutoscalerRateLimitScaleUp(t *testing.T) {
 	a, pc := newTestAutoscaler(10, 61, metrics)
 
 	// Need 100 pods but only scale x10
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 61, 1001, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 61, 1001, 1), ScaleValid: true})
 
 	pc.readyCount = 10
 	// Scale x10 again
-	expectScale(t, a, time.Now(), ScaleResult{100, expectedEBC(10, 61, 1001, 10), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 100, ExcessBurstCapacity: expectedEBC(10, 61, 1001, 10), ScaleValid: true})
 }
 
 func TestAutoscalerRateLimitScaleDown(t *testing.T) {
@@ -519,11 +734,11 @@ func TestAutoscalerRateLimitScaleDown(t *testing.T) {
 
 	// Need 1 pods but can only scale down ten times, to 10.
 	pc.readyCount = 100
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 61, 1, 100), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 61, 1, 100), ScaleValid: true})
 
 	pc.readyCount = 10
 	// Scale ÷10 again.
-	expectScale(t, a, time.Now(), ScaleResult{1, expectedEBC(10, 61, 1, 10), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 1, ExcessBurstCapacity: expectedEBC(10, 61, 1, 10), ScaleValid: true})
 }
 
 func TestCantCountPods(t *testing.T) {
@@ -542,13 +757,13 @@ func TestAutoscalerUseOnePodAsMinimumIfEndpointsNotFound(t *testing.T) {
 	pc.readyCount = 0
 	// 2*10 as the rate limited if we can get the actual pods number.
 	// 1*10 as the rate limited since no read pods are there from K8S API.
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 81, 888, 0), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 81, 888, 0), ScaleValid: true})
 }
 
 func TestAutoscalerUpdateTarget(t *testing.T) {
 	metrics := &metricClient{StableConcurrency: 100, PanicConcurrency: 101}
 	a, pc := newTestAutoscaler(10, 77, metrics)
-	expectScale(t, a, time.Now(), ScaleResult{10, expectedEBC(10, 77, 101, 1), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 10, ExcessBurstCapacity: expectedEBC(10, 77, 101, 1), ScaleValid: true})
 
 	pc.readyCount = 10
 	a.Update(&DeciderSpec{
@@ -561,7 +776,7 @@ func TestAutoscalerUpdateTarget(t *testing.T) {
 		MaxScaleUpRate:      10,
 		StableWindow:        stableWindow,
 	})
-	expectScale(t, a, time.Now(), ScaleResult{100, expectedEBC(1, 71, 101, 10), true})
+	expectScale(t, a, time.Now(), ScaleResult{DesiredPodCount: 100, ExcessBurstCapacity: expectedEBC(1, 71, 101, 10), ScaleValid: true})
 }
 
 // For table tests and tests that don't care about changing scale.
@@ -615,6 +830,14 @@ func approxEquateInt32(field string) cmp.Option {
 func expectScale(t *testing.T, a UniScaler, now time.Time, want ScaleResult) {
 	t.Helper()
 	got := a.Scale(logtesting.TestLogger(t), now)
+	// Only check the metric that drove the decision, if the test expects one.
+	if want.ScalingMetric == "" {
+		got.ScalingMetric = ""
+	}
+	// Likewise for the decision record.
+	if want.Decision == (ScaleDecision{}) {
+		got.Decision = ScaleDecision{}
+	}
 	if !cmp.Equal(got, want, approxEquateInt32("ExcessBurstCapacity")) {
 		t.Error("ScaleResult mismatch(-want,+got):\n", cmp.Diff(want, got))
 	}
@@ -680,7 +903,9 @@ func reset() {
 		panicRequestConcurrencyM.Name(),
 		targetRequestConcurrencyM.Name(),
 		stableRPSM.Name(), panicRPSM.Name(),
-		targetRPSM.Name(), panicM.Name())
+		targetRPSM.Name(), stableCustomMetricM.Name(),
+		panicCustomMetricM.Name(), targetCustomMetricM.Name(),
+		forecastValueM.Name(), panicM.Name())
 	register()
 }
 
@@ -698,6 +923,9 @@ type metricClient struct {
 	PanicConcurrency  float64
 	StableRPS         float64
 	PanicRPS          float64
+	StableCustom      float64
+	PanicCustom       float64
+	ResponseStats     *metrics.ResponseStats
 	ErrF              func(key types.NamespacedName, now time.Time) error
 }
 
@@ -726,6 +954,25 @@ func (mc *metricClient) StableAndPanicRPS(key types.NamespacedName, now time.Tim
 	return mc.StableRPS, mc.PanicRPS, err
 }
 
+// StableAndPanicCustom returns stable/panic custom metric values stored in
+// the object and the result of Errf as the error.
+func (mc *metricClient) StableAndPanicCustom(key types.NamespacedName, now time.Time) (float64, float64, error) {
+	var err error
+	if mc.ErrF != nil {
+		err = mc.ErrF(key, now)
+	}
+	return mc.StableCustom, mc.PanicCustom, err
+}
+
+// StableResponseStats returns the response stats stored in the object, or
+// metrics.ErrNoData if there are none.
+func (mc *metricClient) StableResponseStats(key types.NamespacedName, now time.Time) (metrics.ResponseStats, error) {
+	if mc.ResponseStats == nil {
+		return metrics.ResponseStats{}, metrics.ErrNoData
+	}
+	return *mc.ResponseStats, nil
+}
+
 func BenchmarkAutoscaler(b *testing.B) {
 	metrics := &metricClient{StableConcurrency: 50.0, PanicConcurrency: 10}
 	a := newTestAutoscalerNoPC(10, 101, metrics)
@@ -0,0 +1,148 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxDecisions is the number of scaling decisions kept per Decider,
+// i.e. 5 minutes worth of ticks.
+const maxDecisions = 150
+
+// DecisionsPath is the path prefix under which DecisionsHandler serves the
+// scaling decisions of a revision, as DecisionsPath + "<namespace>/<name>".
+const DecisionsPath = "/debug/scaling/decisions/"
+
+// ScaleDecision records the inputs and the outputs of a single evaluation
+// cycle of the UniScaler.
+// +k8s:deepcopy-gen=true
+type ScaleDecision struct {
+	// Time is when the decision was made.
+	Time metav1.Time `json:"time"`
+	// ScaleValid is false if the autoscaler did not have the information
+	// necessary to make a decision, in which case the rest is unset.
+	ScaleValid bool `json:"scaleValid"`
+	// ScalingMetric is the metric that required the most pods.
+	ScalingMetric string `json:"scalingMetric,omitempty"`
+	// ObservedStableValue and ObservedPanicValue are the values of the primary
+	// scaling metric observed over the stable and the panic windows.
+	ObservedStableValue float64 `json:"observedStableValue"`
+	ObservedPanicValue  float64 `json:"observedPanicValue"`
+	// TargetValue is the target value of the primary scaling metric per pod.
+	TargetValue float64 `json:"targetValue"`
+	// ReadyPodCount is the number of ready pods of the revision.
+	ReadyPodCount int32 `json:"readyPodCount"`
+	// MaxScaleUp and MaxScaleDown are the bounds the desired pod counts are
+	// clamped to, due to the max scale up and down rates.
+	MaxScaleUp   int32 `json:"maxScaleUp"`
+	MaxScaleDown int32 `json:"maxScaleDown"`
+	// DesiredStablePodCount and DesiredPanicPodCount are the pod counts
+	// desired over the stable and the panic windows.
+	DesiredStablePodCount int32 `json:"desiredStablePodCount"`
+	DesiredPanicPodCount  int32 `json:"desiredPanicPodCount"`
+	// InPanicMode is whether the autoscaler is in panic mode.
+	InPanicMode bool `json:"inPanicMode"`
+	// UndelayedPodCount is the desired pod count before the scale down delay
+	// window was applied. It equals UnlimitedPodCount if the delay did not
+	// change the decision.
+	UndelayedPodCount int32 `json:"undelayedPodCount"`
+	// UnlimitedPodCount is the desired pod count before the scaling policies
+	// were applied. It equals DesiredPodCount if the policies did not change
+	// the decision.
+	UnlimitedPodCount int32 `json:"unlimitedPodCount"`
+	// ExcessBurstCapacity is the computed headroom of the revision.
+	ExcessBurstCapacity int32 `json:"excessBurstCapacity"`
+	// DesiredPodCount is the final number of pods suggested for the revision.
+	DesiredPodCount int32 `json:"desiredPodCount"`
+}
+
+// decisionLog is a bounded, thread safe, log of scaling decisions, which
+// drops the oldest decision once full.
+type decisionLog struct {
+	mux       sync.Mutex
+	decisions []ScaleDecision
+	// next is the index the next decision is written to, once the log is full.
+	next int
+}
+
+func newDecisionLog(size int) *decisionLog {
+	return &decisionLog{decisions: make([]ScaleDecision, 0, size)}
+}
+
+// add appends the decision to the log, evicting the oldest one if needed.
+func (l *decisionLog) add(d ScaleDecision) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	if len(l.decisions) < cap(l.decisions) {
+		l.decisions = append(l.decisions, d)
+		return
+	}
+	l.decisions[l.next] = d
+	l.next = (l.next + 1) % len(l.decisions)
+}
+
+// list returns a copy of the logged decisions, oldest first.
+func (l *decisionLog) list() []ScaleDecision {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	ret := make([]ScaleDecision, 0, len(l.decisions))
+	ret = append(ret, l.decisions[l.next:]...)
+	return append(ret, l.decisions[:l.next]...)
+}
+
+// Decisions returns the recent scaling decisions of the Decider, oldest first.
+func (m *MultiScaler) Decisions(namespace, name string) ([]ScaleDecision, error) {
+	m.scalersMutex.RLock()
+	defer m.scalersMutex.RUnlock()
+	scaler, err := m.scaler(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return scaler.decisions.list(), nil
+}
+
+// DecisionsHandler returns the handler serving the recent scaling decisions
+// of the revision under DecisionsPath as JSON.
+func (m *MultiScaler) DecisionsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace, name, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, DecisionsPath), "/")
+		if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+			http.Error(w, "expected path "+DecisionsPath+"<namespace>/<name>", http.StatusBadRequest)
+			return
+		}
+		decisions, err := m.Decisions(namespace, name)
+		if apierrors.IsNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(decisions); err != nil {
+			m.logger.Errorw("Failed to write scaling decisions", zap.Error(err))
+		}
+	})
+}
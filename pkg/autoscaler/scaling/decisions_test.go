@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	. "knative.dev/pkg/logging/testing"
+	"knative.dev/serving/pkg/autoscaler/fake"
+)
+
+func TestDecisionLog(t *testing.T) {
+	l := newDecisionLog(3)
+	if got := l.list(); len(got) != 0 {
+		t.Errorf("list() = %v, want empty", got)
+	}
+
+	counts := func(ds []ScaleDecision) []int32 {
+		ret := make([]int32, 0, len(ds))
+		for _, d := range ds {
+			ret = append(ret, d.DesiredPodCount)
+		}
+		return ret
+	}
+	for i, want := range [][]int32{
+		{1},
+		{1, 2},
+		{1, 2, 3},
+		{2, 3, 4},
+		{3, 4, 5},
+		{4, 5, 6},
+		{5, 6, 7},
+	} {
+		l.add(ScaleDecision{DesiredPodCount: int32(i + 1)})
+		if got := counts(l.list()); !cmp.Equal(got, want) {
+			t.Errorf("list() after %d adds = %v, want: %v", i+1, got, want)
+		}
+	}
+}
+
+func TestMultiScalerDecisions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ms, uniScaler := createMultiScaler(ctx, TestLogger(t))
+	mtp := &fake.ManualTickProvider{
+		Channel: make(chan time.Time, 1),
+	}
+	ms.tickProvider = mtp.NewTicker
+
+	decider := newDecider()
+	if _, err := ms.Decisions(decider.Namespace, decider.Name); !apierrors.IsNotFound(err) {
+		t.Errorf("Decisions() = %v, want not found error", err)
+	}
+
+	errCh := make(chan error)
+	ms.Watch(watchFunc(ctx, ms, decider, 3 /*desired scale*/, errCh))
+	if _, err := ms.Create(ctx, decider); err != nil {
+		t.Fatal("Create() =", err)
+	}
+
+	// An invalid decision is logged, but doesn't change the Decider.
+	mtp.Channel <- time.Now()
+	if err := verifyNoTick(errCh); err != nil {
+		t.Fatal(err)
+	}
+	uniScaler.setScaleResult(3, 1, true)
+	mtp.Channel <- time.Now()
+	if err := verifyTick(errCh); err != nil {
+		t.Fatal(err)
+	}
+
+	decisions, err := ms.Decisions(decider.Namespace, decider.Name)
+	if err != nil {
+		t.Fatal("Decisions() =", err)
+	}
+	if len(decisions) != 2 {
+		t.Fatalf("#Decisions() = %d, want: 2", len(decisions))
+	}
+	if decisions[0].ScaleValid || decisions[0].Time.IsZero() {
+		t.Errorf("Decisions()[0] = %#v, want an invalid decision with time", decisions[0])
+	}
+	if got, want := decisions[1], (ScaleDecision{
+		Time:                decisions[1].Time,
+		ScaleValid:          true,
+		ExcessBurstCapacity: 1,
+		DesiredPodCount:     3,
+	}); !cmp.Equal(got, want) {
+		t.Error("Decisions()[1] mismatch(-want,+got):", cmp.Diff(want, got))
+	}
+
+	// The latest valid decision is part of the status.
+	d, err := ms.Get(ctx, decider.Namespace, decider.Name)
+	if err != nil {
+		t.Fatal("Get() =", err)
+	}
+	if got, want := d.Status.LastDecision, decisions[1]; !cmp.Equal(got, want) {
+		t.Error("Status.LastDecision mismatch(-want,+got):", cmp.Diff(want, got))
+	}
+
+	// And the decisions are served as JSON.
+	handler := ms.DecisionsHandler()
+	for _, tc := range []struct {
+		path string
+		want int
+	}{{
+		path: DecisionsPath + decider.Namespace + "/" + decider.Name,
+		want: http.StatusOK,
+	}, {
+		path: DecisionsPath + decider.Namespace + "/unknown",
+		want: http.StatusNotFound,
+	}, {
+		path: DecisionsPath + decider.Namespace,
+		want: http.StatusBadRequest,
+	}, {
+		path: DecisionsPath + decider.Namespace + "/" + decider.Name + "/extra",
+		want: http.StatusBadRequest,
+	}} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.want {
+			t.Errorf("GET %s = %d, want: %d", tc.path, rec.Code, tc.want)
+			continue
+		}
+		if tc.want != http.StatusOK {
+			continue
+		}
+		var got []ScaleDecision
+		if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+			t.Fatal("Failed to decode decisions:", err)
+		}
+		// The time is serialized with a second precision.
+		ignoreTime := cmpopts.IgnoreFields(ScaleDecision{}, "Time")
+		if !cmp.Equal(got, decisions, ignoreTime) {
+			t.Error("Served decisions mismatch(-want,+got):", cmp.Diff(decisions, got, ignoreTime))
+		}
+	}
+}
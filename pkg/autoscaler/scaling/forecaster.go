@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"math"
+	"time"
+
+	"knative.dev/serving/pkg/autoscaler/aggregation"
+)
+
+// seasonalGranularity is the resolution of the seasonal history kept by
+// the forecaster. A minute keeps a week worth of history at ~10k buckets.
+const seasonalGranularity = time.Minute
+
+// forecaster predicts the value of the scaling metric a horizon ahead.
+// It combines two forecasts and reports the larger one:
+//   - the trend forecast extrapolates the observed stable values over the
+//     stable window linearly to the horizon;
+//   - the seasonal forecast is the value that was observed one seasonal
+//     period before the forecasted point in time.
+//
+// The history is only kept in memory, so it is lost when the autoscaler
+// restarts or loses the leadership of the revision, and only the trend
+// forecast applies until a seasonal period is observed again.
+//
+// forecaster is not thread safe, it is only used from autoscaler.Scale.
+type forecaster struct {
+	horizon time.Duration
+	period  time.Duration
+
+	// trend keeps the observed stable values, one per tick.
+	trend *aggregation.TimedFloat64Buckets
+	// seasonalSum and seasonalCount keep the sum and the number of the
+	// observed stable values per seasonalGranularity over the seasonal period,
+	// so that the average value in every bucket can be computed.
+	seasonalSum   *aggregation.TimedFloat64Buckets
+	seasonalCount *aggregation.TimedFloat64Buckets
+}
+
+// newForecaster creates a forecaster for the given DeciderSpec.
+func newForecaster(spec *DeciderSpec) *forecaster {
+	f := &forecaster{
+		horizon: spec.PredictiveHorizon,
+		period:  spec.SeasonalPeriod,
+		trend:   aggregation.NewTimedFloat64Buckets(trendWindow(spec), tickInterval),
+	}
+	if f.period > 0 {
+		f.seasonalSum = aggregation.NewTimedFloat64Buckets(f.period, seasonalGranularity)
+		f.seasonalCount = aggregation.NewTimedFloat64Buckets(f.period, seasonalGranularity)
+	}
+	return f
+}
+
+// trendWindow returns the window over which the trend is estimated.
+func trendWindow(spec *DeciderSpec) time.Duration {
+	if spec.StableWindow > 0 {
+		return spec.StableWindow
+	}
+	return time.Minute
+}
+
+// update reconfigures the forecaster in place, if possible, preserving the
+// recorded history. It returns the forecaster to use from now on.
+func (f *forecaster) update(spec *DeciderSpec) *forecaster {
+	if f == nil || (f.period == 0) != (spec.SeasonalPeriod == 0) {
+		return newForecaster(spec)
+	}
+	f.horizon = spec.PredictiveHorizon
+	f.trend.ResizeWindow(trendWindow(spec))
+	if f.period != spec.SeasonalPeriod {
+		f.period = spec.SeasonalPeriod
+		f.seasonalSum.ResizeWindow(f.period)
+		f.seasonalCount.ResizeWindow(f.period)
+	}
+	return f
+}
+
+// record adds the observed stable value at the given time to the history.
+func (f *forecaster) record(now time.Time, observed float64) {
+	f.trend.Record(now, observed)
+	if f.period > 0 {
+		f.seasonalSum.Record(now, observed)
+		f.seasonalCount.Record(now, 1)
+	}
+}
+
+// forecast returns the expected value of the scaling metric `horizon` after
+// `now`, given the currently observed stable value.
+func (f *forecaster) forecast(now time.Time, observed float64) float64 {
+	ret := math.Max(0, observed+f.trend.Slope(now)*f.horizon.Seconds())
+	if f.period > 0 {
+		at := now.Add(f.horizon - f.period)
+		sum, ok := f.seasonalSum.BucketValue(at)
+		if cnt, _ := f.seasonalCount.BucketValue(at); ok && cnt > 0 {
+			ret = math.Max(ret, sum/cnt)
+		}
+	}
+	return ret
+}
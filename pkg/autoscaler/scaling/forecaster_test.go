@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/serving/pkg/apis/autoscaling"
+)
+
+func TestForecasterTrend(t *testing.T) {
+	f := newForecaster(&DeciderSpec{
+		StableWindow:      stableWindow,
+		PredictiveHorizon: 10 * time.Second,
+	})
+	now := time.Unix(1700000000, 0)
+
+	// Load grows by 1 every tick, i.e. 0.5/s.
+	var v float64
+	for i := 0; i < 10; i++ {
+		v = float64(i)
+		f.record(now, v)
+		if i < 9 {
+			now = now.Add(tickInterval)
+		}
+	}
+	if got, want := f.forecast(now, v), 9+0.5*10.; got != want {
+		t.Errorf("forecast = %v, want: %v", got, want)
+	}
+
+	// Decreasing load is never forecasted below 0.
+	for i := 0; i < 30; i++ {
+		now = now.Add(tickInterval)
+		v = float64(30 - i)
+		f.record(now, v)
+	}
+	if got := f.forecast(now, 0); got != 0 {
+		t.Errorf("forecast = %v, want: 0", got)
+	}
+}
+
+func TestForecasterSeasonal(t *testing.T) {
+	const period = time.Hour
+	f := newForecaster(&DeciderSpec{
+		StableWindow:      stableWindow,
+		PredictiveHorizon: 5 * time.Minute,
+		SeasonalPeriod:    period,
+	})
+	start := time.Unix(1700000000, 0).Truncate(time.Hour)
+
+	// Traffic spikes to 100 between minute 30 and 40 and is otherwise 10.
+	load := func(tm time.Time) float64 {
+		if m := tm.Sub(start) % period; m >= 30*time.Minute && m < 40*time.Minute {
+			return 100
+		}
+		return 10
+	}
+	for now := start; now.Before(start.Add(period)); now = now.Add(tickInterval) {
+		f.record(now, load(now))
+	}
+
+	// Five minutes before the spike in the next period, we expect it.
+	now := start.Add(period + 25*time.Minute)
+	f.record(now, load(now))
+	if got, want := f.forecast(now, load(now)), 100.; got != want {
+		t.Errorf("forecast before spike = %v, want: %v", got, want)
+	}
+	// And five minutes before it ends, we expect it to go away.
+	now = start.Add(period + 35*time.Minute)
+	f.record(now, load(now))
+	if got, want := f.forecast(now, 10), 10.; got != want {
+		t.Errorf("forecast before spike end = %v, want: %v", got, want)
+	}
+}
+
+func TestForecasterUpdate(t *testing.T) {
+	spec := &DeciderSpec{
+		StableWindow:      stableWindow,
+		PredictiveHorizon: time.Minute,
+	}
+	var f *forecaster
+	f = f.update(spec)
+	if f == nil || f.seasonalSum != nil {
+		t.Fatalf("update(nil) = %#v, want forecaster without seasonal history", f)
+	}
+
+	// Enabling the seasonal forecast requires a new forecaster.
+	spec.SeasonalPeriod = time.Hour
+	if got := f.update(spec); got == f || got.seasonalSum == nil {
+		t.Errorf("update with seasonal period = %#v, want a new forecaster with seasonal history", got)
+	} else {
+		f = got
+	}
+
+	// Changing parameters preserves the forecaster.
+	spec.PredictiveHorizon = 2 * time.Minute
+	spec.SeasonalPeriod = 2 * time.Hour
+	if got := f.update(spec); got != f {
+		t.Error("update with new parameters created a new forecaster")
+	}
+	if f.horizon != 2*time.Minute || f.period != 2*time.Hour {
+		t.Errorf("horizon, period = %v, %v, want: 2m, 2h", f.horizon, f.period)
+	}
+}
+
+func TestAutoscalerPredictiveMode(t *testing.T) {
+	pc := &fakePodCounter{readyCount: 1}
+	metrics := &metricClient{}
+	spec := &DeciderSpec{
+		TargetValue:       10,
+		TotalValue:        10,
+		MaxScaleDownRate:  10,
+		MaxScaleUpRate:    100,
+		PanicThreshold:    100,
+		StableWindow:      stableWindow,
+		ScalingMode:       autoscaling.ScalingModePredictive,
+		PredictiveHorizon: 20 * time.Second,
+	}
+	as := New(context.Background(), testNamespace, testRevision, metrics, pc, spec)
+
+	// Load grows by 5 each tick, i.e. 2.5/s, so in 20s we expect 50 more.
+	now := time.Unix(1700000000, 0)
+	for i := 0; i < 4; i++ {
+		metrics.SetStableAndPanicConcurrency(float64(5*i), float64(5*i))
+		as.Scale(logtesting.TestLogger(t), now)
+		now = now.Add(tickInterval)
+	}
+	metrics.SetStableAndPanicConcurrency(20, 20)
+	expectScale(t, as, now, ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 7, // (20 + 50) / 10
+		ForecastValue:   70,
+	})
+
+	// Switching back to reactive drops the forecast.
+	spec = &DeciderSpec{
+		TargetValue:      10,
+		TotalValue:       10,
+		MaxScaleDownRate: 10,
+		MaxScaleUpRate:   100,
+		PanicThreshold:   100,
+		StableWindow:     stableWindow,
+	}
+	as.Update(spec)
+	expectScale(t, as, now.Add(tickInterval), ScaleResult{
+		ScaleValid:      true,
+		DesiredPodCount: 2,
+	})
+}
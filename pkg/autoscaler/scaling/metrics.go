@@ -56,6 +56,22 @@ var (
 		"target_requests_per_second",
 		"The desired requests-per-second for each pod",
 		stats.UnitDimensionless)
+	stableCustomMetricM = stats.Float64(
+		"stable_custom_metric",
+		"Average value of the custom application metric over the stable window",
+		stats.UnitDimensionless)
+	panicCustomMetricM = stats.Float64(
+		"panic_custom_metric",
+		"Average value of the custom application metric over the panic window",
+		stats.UnitDimensionless)
+	targetCustomMetricM = stats.Float64(
+		"target_custom_metric",
+		"The desired value of the custom application metric for each pod",
+		stats.UnitDimensionless)
+	forecastValueM = stats.Float64(
+		"forecast_value",
+		"The value of the scaling metric forecasted over the predictive horizon",
+		stats.UnitDimensionless)
 	panicM = stats.Int64(
 		"panic_mode",
 		"1 if autoscaler is in panic mode, 0 otherwise",
@@ -116,6 +132,26 @@ func register() {
 			Measure:     targetRPSM,
 			Aggregation: view.LastValue(),
 		},
+		&view.View{
+			Description: "Average value of the custom application metric over the stable window",
+			Measure:     stableCustomMetricM,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Description: "Average value of the custom application metric over the panic window",
+			Measure:     panicCustomMetricM,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Description: "The desired value of the custom application metric for each pod",
+			Measure:     targetCustomMetricM,
+			Aggregation: view.LastValue(),
+		},
+		&view.View{
+			Description: "The value of the scaling metric forecasted over the predictive horizon",
+			Measure:     forecastValueM,
+			Aggregation: view.LastValue(),
+		},
 	); err != nil {
 		panic(err)
 	}
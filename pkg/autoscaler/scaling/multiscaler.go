@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/logging/logkey"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/autoscaler/metrics"
 )
 
@@ -45,6 +46,7 @@ type Decider struct {
 }
 
 // DeciderSpec is the parameters by which the Revision should be scaled.
+// +k8s:deepcopy-gen=true
 type DeciderSpec struct {
 	MaxScaleUpRate   float64
 	MaxScaleDownRate float64
@@ -82,9 +84,40 @@ type DeciderSpec struct {
 	// min-scale value while also preserving the ability to scale to zero.
 	// ActivationScale must be >= 2.
 	ActivationScale int32
+	// ScalingMode selects how the desired scale is computed, i.e. reactive, predictive.
+	ScalingMode string
+	// PredictiveHorizon is how far ahead the predictive scaling mode forecasts
+	// the value of the scaling metric.
+	PredictiveHorizon time.Duration
+	// SeasonalPeriod is the period after which the load is expected to repeat
+	// itself. The predictive scaling mode uses the value observed one period
+	// ago as a forecast. Zero disables the seasonal forecast.
+	SeasonalPeriod time.Duration
+	// Algorithm is the name of the registered scaling algorithm that
+	// computes the desired scale. Empty selects the default algorithm.
+	Algorithm string
+	// AlgorithmParameters are the algorithm specific parameters, which are
+	// interpreted by the selected Algorithm.
+	AlgorithmParameters map[string]string
+	// AdditionalMetrics are the metrics to scale on in addition to ScalingMetric.
+	// The desired pod count is the largest one computed for any of the metrics.
+	AdditionalMetrics []MetricTarget
+	// ScaleUpRules and ScaleDownRules limit how fast the desired pod count
+	// may change, based on the history of the desired pod counts.
+	ScaleUpRules   autoscalerconfig.ScalingRules
+	ScaleDownRules autoscalerconfig.ScalingRules
+}
+
+// MetricTarget is a scaling metric along with its target value per pod.
+type MetricTarget struct {
+	// Metric is the metric used for scaling, i.e. concurrency, rps.
+	Metric string
+	// TargetValue is the value of the metric per pod that we target to maintain.
+	TargetValue float64
 }
 
 // DeciderStatus is the current scale recommendation.
+// +k8s:deepcopy-gen=true
 type DeciderStatus struct {
 	// DesiredScale is the target number of instances that autoscaler
 	// this revision needs.
@@ -96,6 +129,22 @@ type DeciderStatus struct {
 	// If this number is negative: Activator will be threaded in
 	// the request path by the PodAutoscaler controller.
 	ExcessBurstCapacity int32
+
+	// ForecastValue is the value of the scaling metric the predictive scaling
+	// mode expects to observe PredictiveHorizon ahead. It is always 0 in the
+	// reactive scaling mode.
+	ForecastValue float64
+
+	// ScalingMetric is the metric that drove the latest scale recommendation,
+	// i.e. the one that required the most pods.
+	ScalingMetric string
+
+	// LastDecision is the latest valid scaling decision.
+	LastDecision ScaleDecision
+
+	// ResponseStats are the stats of the responses of the revision over the
+	// stable window. They are zero if the revision has no responses.
+	ResponseStats metrics.ResponseStats
 }
 
 // ScaleResult holds the scale result of the UniScaler evaluation cycle.
@@ -105,9 +154,18 @@ type ScaleResult struct {
 	// ExcessBurstCapacity is computed headroom of the revision taking into
 	// the account target burst capacity.
 	ExcessBurstCapacity int32
+	// ScalingMetric is the metric that required the most pods.
+	ScalingMetric string
+	// ResponseStats are the stats of the responses over the stable window.
+	ResponseStats metrics.ResponseStats
+	// Decision records the inputs and the outputs of the evaluation cycle.
+	Decision ScaleDecision
 	// ScaleValid specifies whether this scale result is valid, i.e. whether
 	// Autoscaler had all the necessary information to compute a suggestion.
 	ScaleValid bool
+	// ForecastValue is the forecasted value of the scaling metric, if the
+	// predictive scaling mode is enabled.
+	ForecastValue float64
 }
 
 var invalidSR = ScaleResult{
@@ -133,9 +191,16 @@ type scalerRunner struct {
 	pokeCh chan struct{}
 	logger *zap.SugaredLogger
 
-	// mux guards access to decider.
+	// decisions keeps the recent scaling decisions, for debugging.
+	decisions *decisionLog
+
+	// mux guards access to decider and statsInformed.
 	mux     sync.RWMutex
 	decider *Decider
+
+	// statsInformed is when the watcher was last informed about changed
+	// response stats.
+	statsInformed time.Time
 }
 
 func (sr *scalerRunner) latestScale() int32 {
@@ -169,6 +234,19 @@ func (sr *scalerRunner) updateLatestScale(sRes ScaleResult) bool {
 
 	// Update with the latest calculation anyway.
 	sr.decider.Status.ExcessBurstCapacity = sRes.ExcessBurstCapacity
+	sr.decider.Status.ForecastValue = sRes.ForecastValue
+	sr.decider.Status.ScalingMetric = sRes.ScalingMetric
+	sr.decider.Status.LastDecision = sRes.Decision
+
+	// The response stats change on every tick while there is traffic, so
+	// inform about them at most once per stable window to not flood the KPA.
+	if sRes.ResponseStats != sr.decider.Status.ResponseStats {
+		sr.decider.Status.ResponseStats = sRes.ResponseStats
+		if now := sRes.Decision.Time.Time; now.Sub(sr.statsInformed) >= sr.decider.Spec.StableWindow {
+			sr.statsInformed = now
+			ret = true
+		}
+	}
 	return ret
 }
 
@@ -205,15 +283,24 @@ func NewMultiScaler(
 
 // Get returns the copy of the current Decider.
 func (m *MultiScaler) Get(_ context.Context, namespace, name string) (*Decider, error) {
-	key := types.NamespacedName{Namespace: namespace, Name: name}
 	m.scalersMutex.RLock()
 	defer m.scalersMutex.RUnlock()
+	scaler, err := m.scaler(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return scaler.safeDecider(), nil
+}
+
+// scaler returns the runner of the Decider. The caller must hold scalersMutex.
+func (m *MultiScaler) scaler(namespace, name string) (*scalerRunner, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
 	scaler, exists := m.scalers[key]
 	if !exists {
 		// This GroupResource is a lie, but unfortunately this interface requires one.
 		return nil, errors.NewNotFound(autoscalingv1alpha1.Resource("Deciders"), key.String())
 	}
-	return scaler.safeDecider(), nil
+	return scaler, nil
 }
 
 // Create instantiates the desired Decider.
@@ -224,7 +311,7 @@ func (m *MultiScaler) Create(_ context.Context, decider *Decider) (*Decider, err
 	scaler, exists := m.scalers[key]
 	if !exists {
 		var err error
-		scaler, err = m.createScaler(decider, key)
+		scaler, err = m.createScaler(decider, key, newDecisionLog(maxDecisions))
 		if err != nil {
 			return nil, err
 		}
@@ -239,6 +326,21 @@ func (m *MultiScaler) Update(_ context.Context, decider *Decider) (*Decider, err
 	m.scalersMutex.Lock()
 	defer m.scalersMutex.Unlock()
 	if scaler, exists := m.scalers[key]; exists {
+		if old := scaler.safeDecider(); old.Spec.Algorithm != decider.Spec.Algorithm {
+			// A different algorithm requires a new UniScaler, so replace the runner,
+			// keeping the latest known status until the new one computes its own,
+			// and the decisions logged so far.
+			runner, err := m.createScaler(decider, key, scaler.decisions)
+			if err != nil {
+				return nil, err
+			}
+			runner.mux.Lock()
+			runner.decider.Status = old.Status
+			runner.mux.Unlock()
+			close(scaler.stopCh)
+			m.scalers[key] = runner
+			return decider, nil
+		}
 		scaler.mux.Lock()
 		defer scaler.mux.Unlock()
 		// Make sure we store the copy.
@@ -303,7 +405,7 @@ func (m *MultiScaler) runScalerTicker(runner *scalerRunner, metricKey types.Name
 	}()
 }
 
-func (m *MultiScaler) createScaler(decider *Decider, key types.NamespacedName) (*scalerRunner, error) {
+func (m *MultiScaler) createScaler(decider *Decider, key types.NamespacedName, decisions *decisionLog) (*scalerRunner, error) {
 	d := decider.DeepCopy()
 	scaler, err := m.uniScalerFactory(d)
 	if err != nil {
@@ -311,11 +413,12 @@ func (m *MultiScaler) createScaler(decider *Decider, key types.NamespacedName) (
 	}
 
 	runner := &scalerRunner{
-		scaler:  scaler,
-		stopCh:  make(chan struct{}),
-		decider: d,
-		pokeCh:  make(chan struct{}),
-		logger:  m.logger.With(zap.String(logkey.Key, key.String())),
+		scaler:    scaler,
+		stopCh:    make(chan struct{}),
+		decider:   d,
+		pokeCh:    make(chan struct{}),
+		logger:    m.logger.With(zap.String(logkey.Key, key.String())),
+		decisions: decisions,
 	}
 	d.Status.DesiredScale = -1
 	switch tbc := d.Spec.TargetBurstCapacity; tbc {
@@ -332,7 +435,13 @@ func (m *MultiScaler) createScaler(decider *Decider, key types.NamespacedName) (
 }
 
 func (m *MultiScaler) tickScaler(scaler UniScaler, runner *scalerRunner, metricKey types.NamespacedName) {
-	sr := scaler.Scale(runner.logger, time.Now())
+	now := time.Now()
+	sr := scaler.Scale(runner.logger, now)
+
+	// Keep every decision, including the invalid ones, for debugging.
+	sr.Decision.Time = metav1.NewTime(now)
+	sr.Decision.ScaleValid = sr.ScaleValid
+	runner.decisions.add(sr.Decision)
 
 	if !sr.ScaleValid {
 		return
@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -310,7 +311,7 @@ func TestMultiScalerScaleFromZero(t *testing.T) {
 	metricKey := types.NamespacedName{Namespace: decider.Namespace, Name: decider.Name}
 	if scaler, exists := ms.scalers[metricKey]; !exists {
 		t.Error("Failed to get scaler for metric", metricKey)
-	} else if !scaler.updateLatestScale(ScaleResult{0, 10, true}) {
+	} else if !scaler.updateLatestScale(ScaleResult{DesiredPodCount: 0, ExcessBurstCapacity: 10, ScaleValid: true}) {
 		t.Error("Failed to set scale for metric to 0")
 	}
 
@@ -365,6 +366,64 @@ func TestMultiScalerUpdate(t *testing.T) {
 	ms.Delete(ctx, decider.Namespace, decider.Name)
 }
 
+func TestMultiScalerUpdateAlgorithm(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var created []string
+	ms := NewMultiScaler(ctx.Done(), func(d *Decider) (UniScaler, error) {
+		created = append(created, d.Spec.Algorithm)
+		return &fakeUniScaler{}, nil
+	}, TestLogger(t))
+
+	decider := newDecider()
+	if _, err := ms.Create(ctx, decider); err != nil {
+		t.Fatal("Create() =", err)
+	}
+	before := ms.scalers[types.NamespacedName{Namespace: decider.Namespace, Name: decider.Name}]
+
+	// Same algorithm, the UniScaler is updated in place.
+	decider.Spec.TargetValue = 10
+	if _, err := ms.Update(ctx, decider); err != nil {
+		t.Fatal("Update() =", err)
+	}
+	before.updateLatestScale(ScaleResult{DesiredPodCount: 5, ExcessBurstCapacity: 10, ScaleValid: true})
+	before.decisions.add(ScaleDecision{DesiredPodCount: 5})
+
+	// Different algorithm, the UniScaler is replaced, but the status is kept.
+	decider.Spec.Algorithm = "pid"
+	if _, err := ms.Update(ctx, decider); err != nil {
+		t.Fatal("Update() =", err)
+	}
+	if got, want := created, []string{"", "pid"}; !cmp.Equal(got, want) {
+		t.Errorf("Created UniScalers for algorithms %v, want: %v", got, want)
+	}
+
+	select {
+	case <-before.stopCh:
+	default:
+		t.Error("The previous scaler runner was not stopped")
+	}
+	m, err := ms.Get(ctx, decider.Namespace, decider.Name)
+	if err != nil {
+		t.Fatal("Get() =", err)
+	}
+	if got, want := m.Spec.Algorithm, "pid"; got != want {
+		t.Errorf("Algorithm = %q, want: %q", got, want)
+	}
+	if got, want := m.Status.DesiredScale, int32(5); got != want {
+		t.Errorf("DesiredScale = %d, want: %d", got, want)
+	}
+	decisions, err := ms.Decisions(decider.Namespace, decider.Name)
+	if err != nil {
+		t.Fatal("Decisions() =", err)
+	}
+	if len(decisions) == 0 || decisions[0].DesiredPodCount != 5 {
+		t.Errorf("Decisions() = %v, want the decisions of the previous scaler first", decisions)
+	}
+	ms.Delete(ctx, decider.Namespace, decider.Name)
+}
+
 func createMultiScaler(ctx context.Context, l *zap.SugaredLogger) (*MultiScaler, *fakeUniScaler) {
 	uniscaler := &fakeUniScaler{}
 	ms := NewMultiScaler(ctx.Done(), uniscaler.fakeUniScalerFactory, l)
@@ -387,7 +446,15 @@ func (u *fakeUniScaler) Scale(*zap.SugaredLogger, time.Time) ScaleResult {
 	u.mutex.Lock()
 	defer u.mutex.Unlock()
 	u.scaleCount++
-	return ScaleResult{u.replicas, u.surplus, u.scaled}
+	return ScaleResult{
+		DesiredPodCount:     u.replicas,
+		ExcessBurstCapacity: u.surplus,
+		Decision: ScaleDecision{
+			DesiredPodCount:     u.replicas,
+			ExcessBurstCapacity: u.surplus,
+		},
+		ScaleValid: u.scaled,
+	}
 }
 
 func (u *fakeUniScaler) setScaleResult(replicas, surplus int32, scaled bool) {
@@ -430,3 +497,34 @@ func TestSameSign(t *testing.T) {
 		}
 	}
 }
+
+func TestScalerRunnerResponseStats(t *testing.T) {
+	decider := newDecider()
+	decider.Spec.StableWindow = time.Minute
+	sr := &scalerRunner{decider: decider}
+	now := time.Now()
+
+	result := func(at time.Time, rate float64) ScaleResult {
+		return ScaleResult{
+			ResponseStats: metrics.ResponseStats{ResponseRate: rate},
+			Decision:      ScaleDecision{Time: metav1.NewTime(at)},
+			ScaleValid:    true,
+		}
+	}
+
+	if !sr.updateLatestScale(result(now, 10)) {
+		t.Error("updateLatestScale() = false for the first response stats")
+	}
+	if sr.updateLatestScale(result(now.Add(tickInterval), 20)) {
+		t.Error("updateLatestScale() = true within the stable window")
+	}
+	if got, want := sr.decider.Status.ResponseStats.ResponseRate, 20.; got != want {
+		t.Errorf("ResponseRate = %v, want: %v", got, want)
+	}
+	if sr.updateLatestScale(result(now.Add(2*time.Minute), 20)) {
+		t.Error("updateLatestScale() = true for unchanged response stats")
+	}
+	if !sr.updateLatestScale(result(now.Add(2*time.Minute), 30)) {
+		t.Error("updateLatestScale() = false after the stable window")
+	}
+}
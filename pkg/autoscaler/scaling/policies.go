@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"math"
+	"time"
+
+	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
+)
+
+// scaleRecord is a change of the desired pod count.
+type scaleRecord struct {
+	time time.Time
+	pods int32
+}
+
+// scaleHistory is the history of the desired pod counts of an autoscaler,
+// which the scaling policies are enforced against. Only the changes are
+// recorded, oldest first.
+type scaleHistory struct {
+	records []scaleRecord
+}
+
+func (h *scaleHistory) empty() bool {
+	return len(h.records) == 0
+}
+
+// current returns the most recent pod count.
+func (h *scaleHistory) current() int32 {
+	return h.records[len(h.records)-1].pods
+}
+
+// record records the pod count at time now, dropping the records which are
+// no longer needed to know the pod counts within the last keep.
+func (h *scaleHistory) record(now time.Time, pods int32, keep time.Duration) {
+	if h.empty() || h.current() != pods {
+		h.records = append(h.records, scaleRecord{time: now, pods: pods})
+	}
+	// Keep the last record before the cutoff, since it holds the pod count
+	// at the beginning of the window.
+	cutoff := now.Add(-keep)
+	i := 0
+	for i+1 < len(h.records) && !h.records[i+1].time.After(cutoff) {
+		i++
+	}
+	h.records = h.records[i:]
+}
+
+// extremes returns the lowest and the highest pod count since the given time.
+// The pod count before the first record is presumed to be the first one.
+func (h *scaleHistory) extremes(since time.Time) (lo, hi int32) {
+	lo, hi = math.MaxInt32, 0
+	for i, r := range h.records {
+		// Skip the records superseded before since.
+		if i+1 < len(h.records) && !h.records[i+1].time.After(since) {
+			continue
+		}
+		lo, hi = min(lo, r.pods), max(hi, r.pods)
+	}
+	return lo, hi
+}
+
+// limit returns the desired pod count limited by the scaling policies. The
+// limits are computed like the ones of the HPA behaviors: within each
+// policy's period the pod count may grow by the policy's value over the
+// lowest pod count, and may shrink by it below the highest pod count.
+// The history must not be empty.
+func (h *scaleHistory) limit(now time.Time, desired int32, up, down autoscalerconfig.ScalingRules) int32 {
+	cur := h.current()
+	switch {
+	case desired > cur:
+		return min(desired, h.scaleUpLimit(now, cur, up))
+	case desired < cur:
+		return max(desired, h.scaleDownLimit(now, cur, down))
+	default:
+		return desired
+	}
+}
+
+func (h *scaleHistory) scaleUpLimit(now time.Time, cur int32, rules autoscalerconfig.ScalingRules) int32 {
+	if rules.SelectPolicy == autoscalerconfig.DisabledPolicySelect {
+		return cur
+	}
+	if len(rules.Policies) == 0 {
+		return math.MaxInt32
+	}
+	var ret int32
+	for i, p := range rules.Policies {
+		lo, _ := h.extremes(now.Add(-p.Period))
+		var l int32
+		if p.Type == autoscalerconfig.PercentScalingPolicy {
+			// Use 1 if there were zero pods, so that we can scale from zero.
+			l = int32(math.Ceil(float64(max(lo, 1)) * (1 + float64(p.Value)/100)))
+		} else {
+			l = lo + p.Value
+		}
+		if i == 0 || (rules.SelectPolicy == autoscalerconfig.MinChangePolicySelect) == (l < ret) {
+			ret = l
+		}
+	}
+	// The limit never forces a scale down.
+	return max(ret, cur)
+}
+
+func (h *scaleHistory) scaleDownLimit(now time.Time, cur int32, rules autoscalerconfig.ScalingRules) int32 {
+	if rules.SelectPolicy == autoscalerconfig.DisabledPolicySelect {
+		return cur
+	}
+	if len(rules.Policies) == 0 {
+		return 0
+	}
+	var ret int32
+	for i, p := range rules.Policies {
+		_, hi := h.extremes(now.Add(-p.Period))
+		var l int32
+		if p.Type == autoscalerconfig.PercentScalingPolicy {
+			// Round up, so that we never remove more than the percentage.
+			l = int32(math.Ceil(float64(hi) * (1 - float64(p.Value)/100)))
+		} else {
+			l = hi - p.Value
+		}
+		l = max(l, 0)
+		if i == 0 || (rules.SelectPolicy == autoscalerconfig.MinChangePolicySelect) == (l > ret) {
+			ret = l
+		}
+	}
+	// The limit never forces a scale up.
+	return min(ret, cur)
+}
+
+// longestPeriod returns the longest period of the policies of the rules.
+func longestPeriod(rules ...autoscalerconfig.ScalingRules) time.Duration {
+	var ret time.Duration
+	for _, r := range rules {
+		for _, p := range r.Policies {
+			ret = max(ret, p.Period)
+		}
+	}
+	return ret
+}
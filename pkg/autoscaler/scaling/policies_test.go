@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"testing"
+	"time"
+
+	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
+)
+
+func pods(value int32, period time.Duration) autoscalerconfig.ScalingPolicy {
+	return autoscalerconfig.ScalingPolicy{Type: autoscalerconfig.PodsScalingPolicy, Value: value, Period: period}
+}
+
+func percent(value int32, period time.Duration) autoscalerconfig.ScalingPolicy {
+	return autoscalerconfig.ScalingPolicy{Type: autoscalerconfig.PercentScalingPolicy, Value: value, Period: period}
+}
+
+func rules(sp autoscalerconfig.ScalingPolicySelect, policies ...autoscalerconfig.ScalingPolicy) autoscalerconfig.ScalingRules {
+	return autoscalerconfig.ScalingRules{SelectPolicy: sp, Policies: policies}
+}
+
+func TestScaleHistoryLimit(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+
+	tests := []struct {
+		name    string
+		history []scaleRecord
+		now     time.Duration
+		desired int32
+		up      autoscalerconfig.ScalingRules
+		down    autoscalerconfig.ScalingRules
+		want    int32
+	}{{
+		name:    "no policies",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Minute,
+		desired: 100,
+		want:    100,
+	}, {
+		name:    "no policies, scale down",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Minute,
+		desired: 0,
+		want:    0,
+	}, {
+		name:    "pods up",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 100,
+		up:      rules("", pods(4, time.Minute)),
+		want:    14,
+	}, {
+		name:    "pods up, counts the lowest pod count within the period",
+		history: []scaleRecord{{at(0), 10}, {at(40 * time.Second), 12}},
+		now:     50 * time.Second,
+		desired: 100,
+		up:      rules("", pods(4, time.Minute)),
+		want:    14,
+	}, {
+		name:    "pods up, period passed",
+		history: []scaleRecord{{at(0), 10}, {at(40 * time.Second), 12}},
+		now:     100 * time.Second,
+		desired: 100,
+		up:      rules("", pods(4, time.Minute)),
+		want:    16,
+	}, {
+		name:    "percent up",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 100,
+		up:      rules("", percent(50, time.Minute)),
+		want:    15,
+	}, {
+		name:    "percent up from zero",
+		history: []scaleRecord{{at(0), 0}},
+		now:     time.Second,
+		desired: 100,
+		up:      rules("", percent(100, time.Minute)),
+		want:    2,
+	}, {
+		name:    "up selects the largest change",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 100,
+		up:      rules(autoscalerconfig.MaxChangePolicySelect, pods(4, time.Minute), percent(100, time.Minute)),
+		want:    20,
+	}, {
+		name:    "up selects the smallest change",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 100,
+		up:      rules(autoscalerconfig.MinChangePolicySelect, pods(4, time.Minute), percent(100, time.Minute)),
+		want:    14,
+	}, {
+		name:    "up disabled",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 100,
+		up:      rules(autoscalerconfig.DisabledPolicySelect),
+		want:    10,
+	}, {
+		name:    "up limit doesn't scale down",
+		history: []scaleRecord{{at(0), 2}, {at(30 * time.Second), 10}},
+		now:     40 * time.Second,
+		desired: 100,
+		up:      rules("", pods(4, time.Minute)),
+		want:    10,
+	}, {
+		name:    "up rules don't limit scale down",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 1,
+		up:      rules(autoscalerconfig.DisabledPolicySelect),
+		want:    1,
+	}, {
+		name:    "pods down",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 0,
+		down:    rules("", pods(2, time.Minute)),
+		want:    8,
+	}, {
+		name:    "pods down, counts the highest pod count within the period",
+		history: []scaleRecord{{at(0), 10}, {at(40 * time.Second), 8}},
+		now:     50 * time.Second,
+		desired: 0,
+		down:    rules("", pods(2, time.Minute)),
+		want:    8,
+	}, {
+		name:    "pods down, period passed",
+		history: []scaleRecord{{at(0), 10}, {at(40 * time.Second), 8}},
+		now:     100 * time.Second,
+		desired: 0,
+		down:    rules("", pods(2, time.Minute)),
+		want:    6,
+	}, {
+		name:    "pods down to zero",
+		history: []scaleRecord{{at(0), 1}},
+		now:     time.Second,
+		desired: 0,
+		down:    rules("", pods(2, time.Minute)),
+		want:    0,
+	}, {
+		name:    "percent down rounds up",
+		history: []scaleRecord{{at(0), 15}},
+		now:     time.Second,
+		desired: 0,
+		down:    rules("", percent(10, 5*time.Minute)),
+		want:    14,
+	}, {
+		name:    "percent down keeps the last pod",
+		history: []scaleRecord{{at(0), 1}},
+		now:     time.Second,
+		desired: 0,
+		down:    rules("", percent(50, time.Minute)),
+		want:    1,
+	}, {
+		name:    "down selects the largest change",
+		history: []scaleRecord{{at(0), 100}},
+		now:     time.Second,
+		desired: 0,
+		down:    rules(autoscalerconfig.MaxChangePolicySelect, pods(2, time.Minute), percent(10, 5*time.Minute)),
+		want:    90,
+	}, {
+		name:    "down selects the smallest change",
+		history: []scaleRecord{{at(0), 100}},
+		now:     time.Second,
+		desired: 0,
+		down:    rules(autoscalerconfig.MinChangePolicySelect, pods(2, time.Minute), percent(10, 5*time.Minute)),
+		want:    98,
+	}, {
+		name:    "down disabled",
+		history: []scaleRecord{{at(0), 10}},
+		now:     time.Second,
+		desired: 0,
+		down:    rules(autoscalerconfig.DisabledPolicySelect),
+		want:    10,
+	}, {
+		name:    "down limit doesn't scale up",
+		history: []scaleRecord{{at(0), 10}, {at(30 * time.Second), 2}},
+		now:     40 * time.Second,
+		desired: 0,
+		down:    rules("", pods(2, time.Minute)),
+		want:    2,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := &scaleHistory{records: test.history}
+			if got := h.limit(at(test.now), test.desired, test.up, test.down); got != test.want {
+				t.Errorf("limit() = %d, want: %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScaleHistoryRecord(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	h := &scaleHistory{}
+
+	h.record(start, 1, time.Minute)
+	h.record(start.Add(10*time.Second), 1, time.Minute)
+	h.record(start.Add(20*time.Second), 2, time.Minute)
+	if got, want := len(h.records), 2; got != want {
+		t.Fatalf("#records = %d, want: %d", got, want)
+	}
+
+	// The record holding the pod count at the beginning of the window is kept.
+	h.record(start.Add(70*time.Second), 3, time.Minute)
+	if got, want := len(h.records), 3; got != want {
+		t.Fatalf("#records = %d, want: %d", got, want)
+	}
+	h.record(start.Add(80*time.Second), 3, time.Minute)
+	if got, want := len(h.records), 2; got != want {
+		t.Fatalf("#records = %d, want: %d", got, want)
+	}
+	if lo, hi := h.extremes(start.Add(20 * time.Second)); lo != 2 || hi != 3 {
+		t.Errorf("extremes() = %d, %d, want: 2, 3", lo, hi)
+	}
+	if got, want := h.current(), int32(3); got != want {
+		t.Errorf("current() = %d, want: %d", got, want)
+	}
+}
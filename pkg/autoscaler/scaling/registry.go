@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/autoscaler/metrics"
+	"knative.dev/serving/pkg/resources"
+)
+
+// AlgorithmFactory creates the UniScaler implementing a scaling algorithm
+// for the given Decider. The reporterCtx carries the revision tags for
+// metric reporting. The algorithm specific parameters are available in
+// decider.Spec.AlgorithmParameters.
+type AlgorithmFactory func(reporterCtx context.Context, decider *Decider,
+	metricClient metrics.MetricClient, podCounter resources.EndpointsCounter) (UniScaler, error)
+
+var (
+	// algorithmsMux guards algorithms.
+	algorithmsMux sync.RWMutex
+	// algorithms maps the names of the scaling algorithms to their factories.
+	algorithms = map[string]AlgorithmFactory{
+		autoscaling.ScalingAlgorithmDefault: newDefaultAlgorithm,
+	}
+)
+
+// newDefaultAlgorithm creates the built-in KPA autoscaler.
+func newDefaultAlgorithm(reporterCtx context.Context, decider *Decider,
+	metricClient metrics.MetricClient, podCounter resources.EndpointsCounter) (UniScaler, error) {
+	return New(reporterCtx, decider.Namespace, decider.Name, metricClient, podCounter, &decider.Spec), nil
+}
+
+// RegisterAlgorithm makes the scaling algorithm available under the given
+// name, so that revisions can select it through the scaling-algorithm
+// annotation. It is meant to be called from the init function of the
+// package implementing the algorithm, which must be linked into both the
+// autoscaler and the webhook. RegisterAlgorithm panics if an algorithm is
+// already registered under the name.
+func RegisterAlgorithm(name string, factory AlgorithmFactory) {
+	algorithmsMux.Lock()
+	defer algorithmsMux.Unlock()
+	if _, ok := algorithms[name]; ok {
+		panic(fmt.Sprintf("scaling algorithm %q is already registered", name))
+	}
+	algorithms[name] = factory
+	autoscaling.RegisterScalingAlgorithm(name)
+}
+
+// NewAlgorithm creates the UniScaler for the scaling algorithm selected in
+// the Decider's spec.
+func NewAlgorithm(reporterCtx context.Context, decider *Decider,
+	metricClient metrics.MetricClient, podCounter resources.EndpointsCounter) (UniScaler, error) {
+	name := decider.Spec.Algorithm
+	if name == "" {
+		name = autoscaling.ScalingAlgorithmDefault
+	}
+
+	algorithmsMux.RLock()
+	factory, ok := algorithms[name]
+	algorithmsMux.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown scaling algorithm %q in Decider %s/%s", name, decider.Namespace, decider.Name)
+	}
+	return factory(reporterCtx, decider, metricClient, podCounter)
+}
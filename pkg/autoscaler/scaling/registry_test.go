@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scaling
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"knative.dev/serving/pkg/apis/autoscaling"
+	"knative.dev/serving/pkg/autoscaler/metrics"
+	"knative.dev/serving/pkg/resources"
+)
+
+func TestNewAlgorithmDefault(t *testing.T) {
+	for _, name := range []string{"", autoscaling.ScalingAlgorithmDefault} {
+		d := newDecider()
+		d.Spec.Algorithm = name
+		us, err := NewAlgorithm(context.Background(), d, &staticMetricClient, &fakePodCounter{})
+		if err != nil {
+			t.Fatalf("NewAlgorithm(%q) = %v", name, err)
+		}
+		if _, ok := us.(*autoscaler); !ok {
+			t.Errorf("NewAlgorithm(%q) = %T, want: *autoscaler", name, us)
+		}
+	}
+}
+
+func TestNewAlgorithmUnknown(t *testing.T) {
+	d := newDecider()
+	d.Spec.Algorithm = "magic"
+	if _, err := NewAlgorithm(context.Background(), d, &staticMetricClient, &fakePodCounter{}); err == nil {
+		t.Error("NewAlgorithm() = nil, want error for unknown algorithm")
+	}
+}
+
+func TestRegisterAlgorithm(t *testing.T) {
+	const name = "test-registry-algorithm"
+	var gotParams map[string]string
+	want := &fakeUniScaler{}
+	RegisterAlgorithm(name, func(_ context.Context, d *Decider, _ metrics.MetricClient, _ resources.EndpointsCounter) (UniScaler, error) {
+		gotParams = d.Spec.AlgorithmParameters
+		return want, nil
+	})
+	t.Cleanup(func() {
+		algorithmsMux.Lock()
+		defer algorithmsMux.Unlock()
+		delete(algorithms, name)
+	})
+
+	if !autoscaling.IsScalingAlgorithmRegistered(name) {
+		t.Errorf("Algorithm %q is not known to the annotation validation", name)
+	}
+
+	d := newDecider()
+	d.Spec.Algorithm = name
+	d.Spec.AlgorithmParameters = map[string]string{"kp": "0.5"}
+	got, err := NewAlgorithm(context.Background(), d, &staticMetricClient, &fakePodCounter{})
+	if err != nil {
+		t.Fatal("NewAlgorithm() =", err)
+	}
+	if got != want {
+		t.Errorf("NewAlgorithm() = %v, want: %v", got, want)
+	}
+	if !cmp.Equal(gotParams, d.Spec.AlgorithmParameters) {
+		t.Errorf("AlgorithmParameters = %v, want: %v", gotParams, d.Spec.AlgorithmParameters)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterAlgorithm() with a duplicate name didn't panic")
+		}
+	}()
+	RegisterAlgorithm(name, newDefaultAlgorithm)
+}
@@ -25,8 +25,8 @@ package scaling
 func (in *Decider) DeepCopyInto(out *Decider) {
 	*out = *in
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -39,3 +39,68 @@ func (in *Decider) DeepCopy() *Decider {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeciderSpec) DeepCopyInto(out *DeciderSpec) {
+	*out = *in
+	if in.AlgorithmParameters != nil {
+		in, out := &in.AlgorithmParameters, &out.AlgorithmParameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AdditionalMetrics != nil {
+		in, out := &in.AdditionalMetrics, &out.AdditionalMetrics
+		*out = make([]MetricTarget, len(*in))
+		copy(*out, *in)
+	}
+	in.ScaleUpRules.DeepCopyInto(&out.ScaleUpRules)
+	in.ScaleDownRules.DeepCopyInto(&out.ScaleDownRules)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeciderSpec.
+func (in *DeciderSpec) DeepCopy() *DeciderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DeciderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeciderStatus) DeepCopyInto(out *DeciderStatus) {
+	*out = *in
+	in.LastDecision.DeepCopyInto(&out.LastDecision)
+	out.ResponseStats = in.ResponseStats
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeciderStatus.
+func (in *DeciderStatus) DeepCopy() *DeciderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DeciderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleDecision) DeepCopyInto(out *ScaleDecision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleDecision.
+func (in *ScaleDecision) DeepCopy() *ScaleDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleDecision)
+	in.DeepCopyInto(out)
+	return out
+}
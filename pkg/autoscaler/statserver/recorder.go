@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/serving/pkg/autoscaler/metrics"
+)
+
+// RecorderOptions configure a Recorder.
+type RecorderOptions struct {
+	// Path is the path of the file the stats are recorded to. Rotated files
+	// get the suffixes ".1", ".2", etc, ".1" being the most recent one.
+	Path string
+	// MaxSize is the size in bytes after which the file is rotated.
+	MaxSize int64
+	// MaxFiles is the number of rotated files to keep.
+	MaxFiles int
+	// Namespaces and Revisions restrict recording to the stats of revisions
+	// in one of the namespaces or being one of the revisions. If both are
+	// empty, the stats of all revisions are recorded.
+	Namespaces sets.Set[string]
+	Revisions  sets.Set[types.NamespacedName]
+}
+
+// Recorder writes stat messages to a rotating file as a stat stream, which
+// can be read by metrics.ReadStatStream.
+type Recorder struct {
+	opts RecorderOptions
+
+	// mux guards the fields below.
+	mux  sync.Mutex
+	file *os.File
+	size int64
+	// closed is set by Close. Otherwise a nil file means that opening it
+	// failed, e.g. on rotation, and it is opened again on the next Record.
+	closed bool
+}
+
+// NewRecorder creates a Recorder, appending to the file at opts.Path if it
+// already exists.
+func NewRecorder(opts RecorderOptions) (*Recorder, error) {
+	if opts.Path == "" {
+		return nil, errors.New("recording path must be set")
+	}
+	if opts.MaxSize <= 0 {
+		return nil, fmt.Errorf("max size must be positive, was: %d", opts.MaxSize)
+	}
+	if opts.MaxFiles < 0 {
+		return nil, fmt.Errorf("max files must not be negative, was: %d", opts.MaxFiles)
+	}
+	r := &Recorder{opts: opts}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Record writes the messages which pass the filter to the file. Stats
+// without a timestamp are stamped with now, like the autoscaler does when
+// processing them.
+func (r *Recorder) Record(wsms *metrics.WireStatMessages, now time.Time) error {
+	rec := &metrics.WireStatMessages{}
+	for _, wsm := range wsms.Messages {
+		if wsm.Stat == nil || !r.matches(types.NamespacedName{Namespace: wsm.Namespace, Name: wsm.Name}) {
+			continue
+		}
+		stat := *wsm.Stat
+		if stat.Timestamp == 0 {
+			stat.Timestamp = now.Unix()
+		}
+		rec.Messages = append(rec.Messages, &metrics.WireStatMessage{
+			Namespace: wsm.Namespace,
+			Name:      wsm.Name,
+			Stat:      &stat,
+		})
+	}
+	if len(rec.Messages) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.WriteWireStatMessages(&buf, rec); err != nil {
+		return err
+	}
+
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.closed {
+		return errors.New("recorder is closed")
+	}
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return fmt.Errorf("failed to reopen the recording: %w", err)
+		}
+	}
+	// Never split a record across files, so that each of them can be read on its own.
+	if r.size > 0 && r.size+int64(buf.Len()) > r.opts.MaxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := r.file.Write(buf.Bytes())
+	r.size += int64(n)
+	return err
+}
+
+// Close closes the file.
+func (r *Recorder) Close() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.closed = true
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *Recorder) matches(rev types.NamespacedName) bool {
+	if r.opts.Namespaces.Len() == 0 && r.opts.Revisions.Len() == 0 {
+		return true
+	}
+	return r.opts.Namespaces.Has(rev.Namespace) || r.opts.Revisions.Has(rev)
+}
+
+// open opens the file for appending. The caller must hold mux, if needed.
+func (r *Recorder) open() error {
+	f, err := os.OpenFile(r.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, st.Size()
+	return nil
+}
+
+// rotate shifts the rotated files, dropping the oldest one, and starts a new
+// file. If it fails, the file is left closed, and the rotation is retried on
+// the next Record, once the file is opened again. The caller must hold mux.
+func (r *Recorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+	if r.opts.MaxFiles == 0 {
+		if err := os.Remove(r.opts.Path); err != nil {
+			return err
+		}
+		return r.open()
+	}
+	for i := r.opts.MaxFiles - 1; i > 0; i-- {
+		if err := os.Rename(r.rotatedPath(i), r.rotatedPath(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(r.opts.Path, r.rotatedPath(1)); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *Recorder) rotatedPath(i int) string {
+	return fmt.Sprintf("%s.%d", r.opts.Path, i)
+}
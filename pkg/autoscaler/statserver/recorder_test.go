@@ -0,0 +1,226 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statserver
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/serving/pkg/autoscaler/metrics"
+)
+
+func readStatFile(t *testing.T, path string) []metrics.StatMessage {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal("Failed to open recording:", err)
+	}
+	defer f.Close()
+	sms, err := metrics.ReadStatStream(f)
+	if err != nil {
+		t.Fatal("ReadStatStream() =", err)
+	}
+	return sms
+}
+
+func withTimestamp(sm metrics.StatMessage, ts int64) metrics.StatMessage {
+	sm.Stat.Timestamp = ts
+	return sm
+}
+
+func TestRecorderFilter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	msg3 := metrics.StatMessage{
+		Key:  types.NamespacedName{Namespace: "other-namespace", Name: "test-revision"},
+		Stat: metrics.Stat{PodName: "pod3", Timestamp: 1600000000},
+	}
+	all := []metrics.StatMessage{msg1, msg2, msg3}
+
+	tests := []struct {
+		name       string
+		namespaces sets.Set[string]
+		revisions  sets.Set[types.NamespacedName]
+		want       []metrics.StatMessage
+	}{{
+		name: "no filter",
+		want: []metrics.StatMessage{withTimestamp(msg1, now.Unix()), withTimestamp(msg2, now.Unix()), msg3},
+	}, {
+		name:       "namespace",
+		namespaces: sets.New("other-namespace"),
+		want:       []metrics.StatMessage{msg3},
+	}, {
+		name:      "revision",
+		revisions: sets.New(msg2.Key),
+		want:      []metrics.StatMessage{withTimestamp(msg2, now.Unix())},
+	}, {
+		name:       "namespace or revision",
+		namespaces: sets.New("other-namespace"),
+		revisions:  sets.New(msg1.Key),
+		want:       []metrics.StatMessage{withTimestamp(msg1, now.Unix()), msg3},
+	}, {
+		name:       "nothing matches",
+		namespaces: sets.New("unknown"),
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "stats")
+			r, err := NewRecorder(RecorderOptions{
+				Path:       path,
+				MaxSize:    1 << 20,
+				Namespaces: test.namespaces,
+				Revisions:  test.revisions,
+			})
+			if err != nil {
+				t.Fatal("NewRecorder() =", err)
+			}
+			wsms := metrics.ToWireStatMessages(all)
+			if err := r.Record(&wsms, now); err != nil {
+				t.Fatal("Record() =", err)
+			}
+			if err := r.Close(); err != nil {
+				t.Fatal("Close() =", err)
+			}
+
+			if got := readStatFile(t, path); !cmp.Equal(got, test.want) {
+				t.Error("Recording mismatch (-want,+got):", cmp.Diff(test.want, got))
+			}
+			// The received messages are left alone.
+			if got := wsms.Messages[0].Stat.Timestamp; got != 0 {
+				t.Errorf("Timestamp of the received message = %d, want: 0", got)
+			}
+		})
+	}
+}
+
+func TestRecorderRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats")
+	sm := withTimestamp(msg1, 1700000000)
+	one := metrics.ToWireStatMessages([]metrics.StatMessage{sm})
+	r, err := NewRecorder(RecorderOptions{
+		Path: path,
+		// Fits two records.
+		MaxSize:  int64(2*(one.Size()+1) + 1),
+		MaxFiles: 2,
+	})
+	if err != nil {
+		t.Fatal("NewRecorder() =", err)
+	}
+	for i := 0; i < 7; i++ {
+		if err := r.Record(&one, time.Now()); err != nil {
+			t.Fatal("Record() =", err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+	if err := r.Record(&one, time.Now()); err == nil {
+		t.Error("Record() after Close() = nil, want error")
+	}
+
+	// Every file holds whole records: 7 = 2 + 2 + 2 + 1, the oldest dropped.
+	for p, want := range map[string]int{path: 1, path + ".1": 2, path + ".2": 2} {
+		if got := len(readStatFile(t, p)); got != want {
+			t.Errorf("#records in %s = %d, want: %d", p, got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(%s.3) = %v, want not exist", path, err)
+	}
+
+	// A new recorder appends to the existing file.
+	r, err = NewRecorder(RecorderOptions{Path: path, MaxSize: 1 << 20})
+	if err != nil {
+		t.Fatal("NewRecorder() =", err)
+	}
+	defer r.Close()
+	if err := r.Record(&one, time.Now()); err != nil {
+		t.Fatal("Record() =", err)
+	}
+	if got, want := len(readStatFile(t, path)), 2; got != want {
+		t.Errorf("#records after reopening = %d, want: %d", got, want)
+	}
+}
+
+func TestRecorderRotationFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats")
+	sm := withTimestamp(msg1, 1700000000)
+	one := metrics.ToWireStatMessages([]metrics.StatMessage{sm})
+	r, err := NewRecorder(RecorderOptions{
+		Path: path,
+		// Fits one record.
+		MaxSize:  int64(one.Size() + 2),
+		MaxFiles: 1,
+	})
+	if err != nil {
+		t.Fatal("NewRecorder() =", err)
+	}
+	defer r.Close()
+	if err := r.Record(&one, time.Now()); err != nil {
+		t.Fatal("Record() =", err)
+	}
+
+	// A directory in the way of the rotated file fails the rotations.
+	if err := os.Mkdir(path+".1", 0o755); err != nil {
+		t.Fatal("Mkdir() =", err)
+	}
+	if err := os.WriteFile(filepath.Join(path+".1", "blocker"), nil, 0o644); err != nil {
+		t.Fatal("WriteFile() =", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := r.Record(&one, time.Now()); err == nil {
+			t.Error("Record() = nil, want error")
+		}
+	}
+
+	// The recording recovers once the rotation succeeds.
+	if err := os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal("RemoveAll() =", err)
+	}
+	if err := r.Record(&one, time.Now()); err != nil {
+		t.Fatal("Record() =", err)
+	}
+	for p, want := range map[string]int{path: 1, path + ".1": 1} {
+		if got := len(readStatFile(t, p)); got != want {
+			t.Errorf("#records in %s = %d, want: %d", p, got, want)
+		}
+	}
+}
+
+func TestNewRecorderErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, opts := range map[string]RecorderOptions{
+		"no path":           {MaxSize: 1},
+		"no max size":       {Path: filepath.Join(dir, "stats")},
+		"negative files":    {Path: filepath.Join(dir, "stats"), MaxSize: 1, MaxFiles: -1},
+		"missing directory": {Path: filepath.Join(dir, "missing", "stats"), MaxSize: 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if r, err := NewRecorder(opts); err == nil {
+				r.Close()
+				t.Error("NewRecorder() = nil, want error")
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 	netheader "knative.dev/networking/pkg/http/header"
 	"knative.dev/serving/pkg/autoscaler/bucket"
 	"knative.dev/serving/pkg/autoscaler/metrics"
@@ -48,7 +49,12 @@ type Server struct {
 	statsCh     chan<- metrics.StatMessage
 	openClients sync.WaitGroup
 	isBktOwner  func(bktName string) bool
+	recorder    *Recorder
 	logger      *zap.SugaredLogger
+
+	// recordErrors rate limits the logging of the recording errors,
+	// which likely repeat for every received message.
+	recordErrors rate.Sometimes
 }
 
 // New creates a Server which will receive autoscaler statistics and forward them to statsCh until Shutdown is called.
@@ -61,6 +67,8 @@ func New(statsServerAddr string, statsCh chan<- metrics.StatMessage, logger *zap
 		openClients: sync.WaitGroup{},
 		isBktOwner:  isBktOwner,
 		logger:      logger.Named("stats-websocket-server").With("address", statsServerAddr),
+
+		recordErrors: rate.Sometimes{First: 1, Interval: time.Minute},
 	}
 
 	mux := http.NewServeMux()
@@ -75,6 +83,12 @@ func New(statsServerAddr string, statsCh chan<- metrics.StatMessage, logger *zap
 	return &svr
 }
 
+// SetRecorder makes the Server record the received stats with the Recorder.
+// It must be called before ListenAndServe.
+func (s *Server) SetRecorder(r *Recorder) {
+	s.recorder = r
+}
+
 func (s *Server) onConnStateChange(conn net.Conn, state http.ConnState) {
 	if state == http.StateNew {
 		tcpConn := conn.(*net.TCPConn)
@@ -183,6 +197,14 @@ func (s *Server) Handler(w http.ResponseWriter, r *http.Request) {
 				continue
 			}
 
+			if s.recorder != nil {
+				if err := s.recorder.Record(&wsms, time.Now()); err != nil {
+					s.recordErrors.Do(func() {
+						s.logger.Errorw("Failed to record stats", zap.Error(err))
+					})
+				}
+			}
+
 			for _, wsm := range wsms.Messages {
 				if wsm.Stat == nil {
 					// To allow for future protobuf schema changes.
@@ -22,6 +22,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -34,6 +35,7 @@ import (
 	"knative.dev/serving/pkg/autoscaler/metrics"
 
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 var (
@@ -108,6 +110,37 @@ func TestStatsReceived(t *testing.T) {
 	closeSink(t, statSink)
 }
 
+func TestStatsRecorded(t *testing.T) {
+	statsCh := make(chan metrics.StatMessage)
+	server := newTestServer(statsCh)
+	path := filepath.Join(t.TempDir(), "stats")
+	recorder, err := NewRecorder(RecorderOptions{
+		Path:      path,
+		MaxSize:   1 << 20,
+		Revisions: sets.New(msg2.Key),
+	})
+	if err != nil {
+		t.Fatal("NewRecorder() =", err)
+	}
+	server.SetRecorder(recorder)
+
+	defer server.Shutdown(0)
+	go server.listenAndServe()
+
+	statSink := dialOK(t, server.listenAddr())
+	// The stats are still forwarded, unchanged.
+	assertReceivedProto(t, both, statSink, statsCh)
+	closeSink(t, statSink)
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal("Close() =", err)
+	}
+	got := readStatFile(t, path)
+	if len(got) != 1 || got[0].Key != msg2.Key || got[0].Stat.Timestamp == 0 {
+		t.Errorf("Recording = %v, want msg2 with a timestamp", got)
+	}
+}
+
 func TestServerShutdown(t *testing.T) {
 	statsCh := make(chan metrics.StatMessage)
 	server := newTestServer(statsCh)
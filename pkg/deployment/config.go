@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"time"
 
+	"golang.org/x/net/http/httpguts"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -68,6 +69,10 @@ const (
 	// qpoptions
 	queueSidecarTokenAudiencesKey = "queue-sidecar-token-audiences"
 	queueSidecarRooCAKey          = "queue-sidecar-rootca"
+
+	// queueSidecarPriorityHeaderKey is the config map key for the request
+	// header naming the priority class of the requests in the queue-proxy.
+	queueSidecarPriorityHeaderKey = "queue-sidecar-priority-header"
 )
 
 var (
@@ -144,6 +149,7 @@ func NewConfigFromMap(configMap map[string]string) (*Config, error) {
 
 		cm.AsStringSet(queueSidecarTokenAudiencesKey, &nc.QueueSidecarTokenAudiences),
 		cm.AsString(queueSidecarRooCAKey, &nc.QueueSidecarRootCA),
+		cm.AsString(queueSidecarPriorityHeaderKey, &nc.QueueSidecarPriorityHeader),
 	); err != nil {
 		return nil, err
 	}
@@ -164,6 +170,10 @@ func NewConfigFromMap(configMap map[string]string) (*Config, error) {
 		return nil, fmt.Errorf("digest-resolution-timeout cannot be a non-positive duration, was %v", nc.DigestResolutionTimeout)
 	}
 
+	if h := nc.QueueSidecarPriorityHeader; h != "" && !httpguts.ValidHeaderFieldName(h) {
+		return nil, fmt.Errorf("queue-sidecar-priority-header must be a valid header name, was: %q", h)
+	}
+
 	return nc, nil
 }
 
@@ -214,4 +224,9 @@ type Config struct {
 
 	// QueueSidecarRootCA is a root certificate to be trusted by the queue proxy sidecar  qpoptions.
 	QueueSidecarRootCA string
+
+	// QueueSidecarPriorityHeader is the request header naming the priority
+	// class the queue proxy sidecar queues the request in. The ingress must
+	// strip or overwrite it on the requests of untrusted clients.
+	QueueSidecarPriorityHeader string
 }
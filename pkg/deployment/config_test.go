@@ -161,6 +161,28 @@ func TestControllerConfiguration(t *testing.T) {
 			queueSidecarMemoryLimitKey:             "654m",
 			queueSidecarEphemeralStorageLimitKey:   "321M",
 		},
+	}, {
+		name: "controller configuration with priority header",
+		wantConfig: &Config{
+			RegistriesSkippingTagResolving: sets.New("kind.local", "ko.local", "dev.local"),
+			DigestResolutionTimeout:        digestResolutionTimeoutDefault,
+			QueueSidecarImage:              defaultSidecarImage,
+			QueueSidecarCPURequest:         &QueueSidecarCPURequestDefault,
+			QueueSidecarTokenAudiences:     sets.New(""),
+			ProgressDeadline:               ProgressDeadlineDefault,
+			QueueSidecarPriorityHeader:     "X-Priority",
+		},
+		data: map[string]string{
+			QueueSidecarImageKey:          defaultSidecarImage,
+			queueSidecarPriorityHeaderKey: "X-Priority",
+		},
+	}, {
+		name:    "controller configuration invalid priority header",
+		wantErr: true,
+		data: map[string]string{
+			QueueSidecarImageKey:          defaultSidecarImage,
+			queueSidecarPriorityHeaderKey: "X Priority",
+		},
 	}, {
 		name:    "controller with no side car image",
 		wantErr: true,
@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	grpcContentType   = "application/grpc"
+	grpcTimeoutHeader = "Grpc-Timeout"
+	grpcStatusHeader  = "Grpc-Status"
+	grpcMessageHeader = "Grpc-Message"
+)
+
+// IsGRPC returns whether the request is a gRPC request, per its content type.
+func IsGRPC(r *http.Request) bool {
+	ct := r.Header.Get("Content-Type")
+	if !strings.HasPrefix(ct, grpcContentType) {
+		return false
+	}
+	// E.g. application/grpc+proto, but not application/grpc-web.
+	return len(ct) == len(grpcContentType) || ct[len(grpcContentType)] == '+' || ct[len(grpcContentType)] == ';'
+}
+
+// GRPCTimeout returns the timeout a gRPC client set for the request, if any.
+func GRPCTimeout(r *http.Request) (time.Duration, bool) {
+	v := r.Header.Get(grpcTimeoutHeader)
+	// The value is at most 8 digits and a unit.
+	if len(v) < 2 || len(v) > 9 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch v[len(v)-1] {
+	case 'H':
+		unit = time.Hour
+	case 'M':
+		unit = time.Minute
+	case 'S':
+		unit = time.Second
+	case 'm':
+		unit = time.Millisecond
+	case 'u':
+		unit = time.Microsecond
+	case 'n':
+		unit = time.Nanosecond
+	default:
+		return 0, false
+	}
+	if n > math.MaxInt64/int64(unit) {
+		return math.MaxInt64, true
+	}
+	return time.Duration(n) * unit, true
+}
+
+// GRPCError replies to a gRPC request with a trailers-only response with the
+// given status code and message.
+func GRPCError(w http.ResponseWriter, code codes.Code, msg string) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", grpcContentType)
+	h.Set(grpcStatusHeader, strconv.Itoa(int(code)))
+	if msg != "" {
+		h.Set(grpcMessageHeader, encodeGRPCMessage(msg))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// GRPCStatus returns the status code of a gRPC response from its header, which
+// holds its trailers too once the response is written.
+func GRPCStatus(h http.Header) (codes.Code, bool) {
+	v := h.Get(grpcStatusHeader)
+	if v == "" {
+		// The trailers that weren't announced upfront.
+		if vs := h[http.TrailerPrefix+grpcStatusHeader]; len(vs) > 0 {
+			v = vs[0]
+		}
+	}
+	if v == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(v, 10, 32)
+	if err != nil {
+		return codes.Unknown, true
+	}
+	return codes.Code(n), true
+}
+
+// encodeGRPCMessage percent-encodes the message, as the gRPC protocol requires.
+func encodeGRPCMessage(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c >= ' ' && c <= '~' && c != '%' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
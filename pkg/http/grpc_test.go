@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestIsGRPC(t *testing.T) {
+	for ct, want := range map[string]bool{
+		"":                              false,
+		"application/json":              false,
+		"application/grpc":              true,
+		"application/grpc+proto":        true,
+		"application/grpc;charset=utf8": true,
+		"application/grpc-web":          false,
+		"application/grpc-web+proto":    false,
+	} {
+		r := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+		r.Header.Set("Content-Type", ct)
+		if got := IsGRPC(r); got != want {
+			t.Errorf("IsGRPC(%q) = %v, want: %v", ct, got, want)
+		}
+	}
+}
+
+func TestGRPCTimeout(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{{
+		value: "",
+	}, {
+		value:  "1H",
+		want:   time.Hour,
+		wantOK: true,
+	}, {
+		value:  "30S",
+		want:   30 * time.Second,
+		wantOK: true,
+	}, {
+		value:  "250m",
+		want:   250 * time.Millisecond,
+		wantOK: true,
+	}, {
+		value:  "99999999n",
+		want:   99999999 * time.Nanosecond,
+		wantOK: true,
+	}, {
+		value: "123456789S",
+	}, {
+		value: "10s",
+	}, {
+		value: "S",
+	}}
+
+	for _, test := range tests {
+		r := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
+		r.Header.Set("Grpc-Timeout", test.value)
+		got, ok := GRPCTimeout(r)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("GRPCTimeout(%q) = %v, %v, want: %v, %v", test.value, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestGRPCError(t *testing.T) {
+	w := httptest.NewRecorder()
+	GRPCError(w, codes.DeadlineExceeded, "request timeout: 100% of 1s\n")
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Status = %d, want: %d", w.Code, http.StatusOK)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Body = %q, want it empty", w.Body.String())
+	}
+	for k, want := range map[string]string{
+		"Content-Type": "application/grpc",
+		"Grpc-Status":  "4",
+		"Grpc-Message": "request timeout: 100%25 of 1s%0A",
+	} {
+		if got := w.Header().Get(k); got != want {
+			t.Errorf("%s = %q, want: %q", k, got, want)
+		}
+	}
+
+	if code, ok := GRPCStatus(w.Header()); !ok || code != codes.DeadlineExceeded {
+		t.Errorf("GRPCStatus() = %v, %v, want: %v, true", code, ok, codes.DeadlineExceeded)
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   http.Header
+		wantCode codes.Code
+		wantOK   bool
+	}{{
+		name:   "not gRPC",
+		header: http.Header{"Content-Type": {"text/plain"}},
+	}, {
+		name:     "announced trailer",
+		header:   http.Header{"Grpc-Status": {"14"}},
+		wantCode: codes.Unavailable,
+		wantOK:   true,
+	}, {
+		name:     "unannounced trailer",
+		header:   http.Header{http.TrailerPrefix + "Grpc-Status": {"0"}},
+		wantCode: codes.OK,
+		wantOK:   true,
+	}, {
+		name:     "invalid",
+		header:   http.Header{"Grpc-Status": {"ok"}},
+		wantCode: codes.Unknown,
+		wantOK:   true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, ok := GRPCStatus(test.header)
+			if code != test.wantCode || ok != test.wantOK {
+				t.Errorf("GRPCStatus() = %v, %v, want: %v, %v", code, ok, test.wantCode, test.wantOK)
+			}
+		})
+	}
+}
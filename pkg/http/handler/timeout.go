@@ -25,8 +25,10 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/grpc/codes"
 	"k8s.io/utils/clock"
 	"knative.dev/pkg/websocket"
+	pkghttp "knative.dev/serving/pkg/http"
 )
 
 // TimeoutFunc returns the timeout duration to be used by the timeout handler.
@@ -47,6 +49,8 @@ type timeoutHandler struct {
 // call runs for longer than its time limit, the handler responds with
 // a 504 Gateway Timeout error and the given message in its body.
 // (If msg is empty, a suitable default message will be sent.)
+// gRPC requests get a DEADLINE_EXCEEDED status instead, and time out
+// after the grpc-timeout of the client, if it's sooner.
 // After such a timeout, writes by h to its ResponseWriter will return
 // ErrHandlerTimeout.
 //
@@ -69,6 +73,12 @@ func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer cancel()
 
 	revTimeout, revResponseStartTimeout, revIdleTimeout := h.timeoutFunc(r)
+	grpc := pkghttp.IsGRPC(r)
+	if grpc {
+		if clientTimeout, ok := pkghttp.GRPCTimeout(r); ok && clientTimeout < revTimeout {
+			revTimeout = clientTimeout
+		}
+	}
 
 	timeout := getTimer(h.clock, revTimeout)
 	var timeoutDrained bool
@@ -92,7 +102,7 @@ func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// done is closed when h.handler.ServeHTTP completes and contains
 	// the panic from h.handler.ServeHTTP if h.handler.ServeHTTP panics.
 	done := make(chan interface{})
-	tw := &timeoutWriter{w: w, clock: h.clock}
+	tw := &timeoutWriter{w: w, clock: h.clock, grpc: grpc}
 
 	var responseStartTimeout clock.Timer
 	var responseStartTimeoutDrained bool
@@ -157,6 +167,8 @@ func (h *timeoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 type timeoutWriter struct {
 	w     http.ResponseWriter
 	clock clock.PassiveClock
+	// grpc is whether the request is a gRPC request.
+	grpc bool
 
 	mu            sync.Mutex
 	timedOut      bool
@@ -270,8 +282,12 @@ func (tw *timeoutWriter) tryIdleTimeoutAndWriteError(curTime time.Time, idleTime
 }
 
 func (tw *timeoutWriter) timeoutAndWriteError(msg string) {
-	tw.w.WriteHeader(http.StatusGatewayTimeout)
-	io.WriteString(tw.w, msg)
+	if tw.grpc {
+		pkghttp.GRPCError(tw.w, codes.DeadlineExceeded, msg)
+	} else {
+		tw.w.WriteHeader(http.StatusGatewayTimeout)
+		io.WriteString(tw.w, msg)
+	}
 
 	tw.timedOut = true
 }
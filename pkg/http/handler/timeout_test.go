@@ -85,9 +85,11 @@ type timeoutHandlerTestScenario struct {
 	responseStartTimeout time.Duration
 	idleTimeout          time.Duration
 	handler              func(clock *clocktest.FakeClock, mux *sync.Mutex, writeErrors chan error) http.Handler
+	requestHeader        http.Header
 	timeoutMessage       string
 	wantStatus           int
 	wantBody             string
+	wantHeader           http.Header
 	wantWriteError       bool
 	wantPanic            bool
 }
@@ -99,6 +101,9 @@ func testTimeoutScenario(t *testing.T, scenarios []timeoutHandlerTestScenario) {
 	for _, scenario := range scenarios {
 		t.Run(scenario.name, func(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range scenario.requestHeader {
+				req.Header[k] = v
+			}
 
 			var reqMux sync.Mutex
 			writeErrors := make(chan error, 1)
@@ -131,6 +136,12 @@ func testTimeoutScenario(t *testing.T, scenarios []timeoutHandlerTestScenario) {
 				t.Errorf("Handler returned unexpected body: got %q want %q", rr.Body.String(), scenario.wantBody)
 			}
 
+			for k := range scenario.wantHeader {
+				if got, want := rr.Header().Get(k), scenario.wantHeader.Get(k); got != want {
+					t.Errorf("Handler returned unexpected %s header: got %q want %q", k, got, want)
+				}
+			}
+
 			if scenario.wantWriteError {
 				if err := <-writeErrors; !errors.Is(err, http.ErrHandlerTimeout) {
 					t.Error("Expected a timeout error, got", err)
@@ -395,6 +406,30 @@ func TestTimeoutHandler(t *testing.T) {
 		wantStatus:     http.StatusGatewayTimeout,
 		wantBody:       "request timeout",
 		wantPanic:      false,
+	}, {
+		name:                 "gRPC client timeout",
+		responseStartTimeout: longTimeout,
+		idleTimeout:          longTimeout,
+		timeout:              longTimeout,
+		requestHeader: http.Header{
+			"Content-Type": {"application/grpc"},
+			"Grpc-Timeout": {"100m"},
+		},
+		handler: func(c *clocktest.FakeClock, mux *sync.Mutex, _ chan error) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.Step(shortTimeout)
+				mux.Lock()
+				defer mux.Unlock()
+				w.Write([]byte("hi"))
+			})
+		},
+		timeoutMessage: "request timeout",
+		wantStatus:     http.StatusOK,
+		wantHeader: http.Header{
+			"Content-Type": {"application/grpc"},
+			"Grpc-Status":  {"4"},
+			"Grpc-Message": {"request timeout"},
+		},
 	}}
 
 	testTimeoutScenario(t, scenarios)
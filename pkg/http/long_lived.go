@@ -0,0 +1,113 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bufio"
+	"mime"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http/httpguts"
+
+	"knative.dev/pkg/websocket"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+// IsUpgrade returns whether the request asks to switch the connection to
+// another protocol, e.g. WebSocket.
+func IsUpgrade(r *http.Request) bool {
+	return r.Header.Get("Upgrade") != "" &&
+		httpguts.HeaderValuesContainsToken(r.Header["Connection"], "Upgrade")
+}
+
+// IsEventStream returns whether the response is a server-sent events stream,
+// per its header.
+func IsEventStream(h http.Header) bool {
+	mt, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	return err == nil && mt == eventStreamContentType
+}
+
+// TrackLongLived calls onLongLived if the request turns out to open a
+// long-lived connection: right away if it's an upgrade request, or once the
+// header of a server-sent events stream is written otherwise. It returns the
+// writer to serve the request with.
+func TrackLongLived(w http.ResponseWriter, r *http.Request, onLongLived func()) http.ResponseWriter {
+	if IsUpgrade(r) {
+		onLongLived()
+		return w
+	}
+	return &eventStreamWriter{writer: w, onEventStream: onLongLived}
+}
+
+// eventStreamWriter is an http.ResponseWriter that calls onEventStream when
+// the response header it writes is that of a server-sent events stream.
+type eventStreamWriter struct {
+	writer        http.ResponseWriter
+	onEventStream func()
+	wroteHeader   bool
+}
+
+var (
+	_ http.Flusher  = (*eventStreamWriter)(nil)
+	_ http.Hijacker = (*eventStreamWriter)(nil)
+)
+
+// Unwrap returns the underlying writer.
+func (w *eventStreamWriter) Unwrap() http.ResponseWriter {
+	return w.writer
+}
+
+// Flush flushes the buffer to the client.
+func (w *eventStreamWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.writer.(http.Flusher).Flush()
+}
+
+// Hijack calls Hijack() on the wrapped http.ResponseWriter if it implements
+// http.Hijacker interface. Otherwise returns an error.
+func (w *eventStreamWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return websocket.HijackIfPossible(w.writer)
+}
+
+// Header returns the header map that will be sent by WriteHeader.
+func (w *eventStreamWriter) Header() http.Header {
+	return w.writer.Header()
+}
+
+// Write writes the data to the connection as part of an HTTP reply.
+func (w *eventStreamWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.writer.Write(p)
+}
+
+// WriteHeader sends an HTTP response header with the provided status code.
+func (w *eventStreamWriter) WriteHeader(code int) {
+	// Informational responses precede the actual one.
+	if !w.wroteHeader && code >= http.StatusOK {
+		w.wroteHeader = true
+		if code == http.StatusOK && IsEventStream(w.writer.Header()) {
+			w.onEventStream()
+		}
+	}
+	w.writer.WriteHeader(code)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrackLongLived(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestHeader http.Header
+		contentType   string
+		code          int
+		writeHeader   bool
+		want          bool
+	}{{
+		name:          "websocket",
+		requestHeader: http.Header{"Connection": {"keep-alive, Upgrade"}, "Upgrade": {"websocket"}},
+		want:          true,
+	}, {
+		name:          "upgrade header only",
+		requestHeader: http.Header{"Upgrade": {"websocket"}},
+	}, {
+		name:        "event stream",
+		contentType: "text/event-stream; charset=utf-8",
+		code:        http.StatusOK,
+		writeHeader: true,
+		want:        true,
+	}, {
+		name:        "event stream written implicitly",
+		contentType: "text/event-stream",
+		want:        true,
+	}, {
+		name:        "failed event stream",
+		contentType: "text/event-stream",
+		code:        http.StatusInternalServerError,
+		writeHeader: true,
+	}, {
+		name:        "plain response",
+		contentType: "text/plain",
+		code:        http.StatusOK,
+		writeHeader: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			for k, v := range test.requestHeader {
+				r.Header[k] = v
+			}
+
+			calls := 0
+			w := TrackLongLived(httptest.NewRecorder(), r, func() { calls++ })
+			if test.contentType != "" {
+				w.Header().Set("Content-Type", test.contentType)
+			}
+			if test.writeHeader {
+				w.WriteHeader(test.code)
+			}
+			w.Write([]byte("data: hello\n\n"))
+			w.Write([]byte("data: world\n\n"))
+
+			if got, want := calls, map[bool]int{true: 1}[test.want]; got != want {
+				t.Errorf("onLongLived called %d times, want: %d", got, want)
+			}
+		})
+	}
+}
@@ -61,6 +61,12 @@ const (
 	// LabelResponseTimeout is the label timeout.
 	LabelResponseTimeout = metricskey.LabelResponseTimeout
 
+	// LabelPriorityClass is the label for the priority class the queue-proxy queued the request in.
+	LabelPriorityClass = "priority_class"
+
+	// LabelGRPCStatus is the label for the gRPC status code of the responses to gRPC requests.
+	LabelGRPCStatus = "grpc_status"
+
 	// ValueUnknown is the default value if the field is unknown, e.g. project will be unknown if Knative
 	// is not running on GKE.
 	ValueUnknown = metricskey.ValueUnknown
@@ -77,4 +83,6 @@ var (
 	ResponseCodeKey      = tag.MustNewKey(LabelResponseCode)
 	ResponseCodeClassKey = tag.MustNewKey(LabelResponseCodeClass)
 	RouteTagKey          = tag.MustNewKey(LabelRouteTag)
+	PriorityClassKey     = tag.MustNewKey(LabelPriorityClass)
+	GRPCStatusKey        = tag.MustNewKey(LabelGRPCStatus)
 )
@@ -148,6 +148,26 @@ func AugmentWithResponseAndRouteTag(baseCtx context.Context, responseCode int, r
 	return ctx
 }
 
+// AugmentWithPriorityClass augments the given context with the priority class tag,
+// unless the class is empty.
+func AugmentWithPriorityClass(baseCtx context.Context, class string) context.Context {
+	if class == "" {
+		return baseCtx
+	}
+	ctx, _ := tag.New(baseCtx, tag.Upsert(PriorityClassKey, class))
+	return ctx
+}
+
+// AugmentWithGRPCStatus augments the given context with the gRPC status tag,
+// unless the status is empty.
+func AugmentWithGRPCStatus(baseCtx context.Context, status string) context.Context {
+	if status == "" {
+		return baseCtx
+	}
+	ctx, _ := tag.New(baseCtx, tag.Upsert(GRPCStatusKey, status))
+	return ctx
+}
+
 // responseCodeClass converts response code to a string of response code class.
 // e.g. The response code class is "5xx" for response code 503.
 func responseCodeClass(responseCode int) string {
@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networking
+
+const (
+	// SessionAffinityHeaderName is the header the ingress appends to the
+	// requests of a Route with session affinity. Its value is the session key
+	// of the Route. The revision serving such a request returns its name in
+	// the SessionRevisionHeaderName header of the response.
+	SessionAffinityHeaderName = "Knative-Serving-Session-Affinity"
+
+	// SessionRevisionHeaderName is the header that pins the requests of a
+	// session to a revision of a Route with session affinity. The ingress
+	// routes the requests with it to the revision it names, as long as the
+	// Route routes traffic to that revision, and splits the others.
+	SessionRevisionHeaderName = "Knative-Serving-Session-Revision"
+)
@@ -21,8 +21,12 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"go.uber.org/atomic"
+
+	"knative.dev/serving/pkg/apis/serving"
 )
 
 var (
@@ -39,6 +43,22 @@ type BreakerParams struct {
 	QueueDepth      int
 	MaxConcurrency  int
 	InitialCapacity int
+
+	// Classes are the priority classes requests are queued in, highest
+	// priority first. If set, the queue depths of the classes replace
+	// QueueDepth.
+	Classes []serving.PriorityClass
+	// StrictPriority hands capacity to the queued requests of the highest
+	// priority class first, rather than in proportion to the class weights.
+	StrictPriority bool
+
+	// QueueDeadline is the longest time a request waits for capacity, if set.
+	QueueDeadline time.Duration
+	// SheddingTarget and SheddingInterval, if set, shed the requests that
+	// don't get capacity within the target once requests kept waiting for
+	// longer than the target for a whole interval.
+	SheddingTarget   time.Duration
+	SheddingInterval time.Duration
 }
 
 // Breaker is a component that enforces a concurrency limit on the
@@ -49,6 +69,11 @@ type Breaker struct {
 	inFlight   atomic.Int64
 	totalSlots int64
 	sem        *semaphore
+	// classes replaces sem and the pending "queue" if the breaker has
+	// priority classes.
+	classes *classQueue
+	// queueTimeout bounds the time requests wait for capacity, if set.
+	queueTimeout *queueTimeout
 
 	// release is the callback function returned to callers by Reserve to
 	// allow the reservation made by Reserve to be released.
@@ -58,7 +83,7 @@ type Breaker struct {
 // NewBreaker creates a Breaker with the desired queue depth,
 // concurrency limit and initial capacity.
 func NewBreaker(params BreakerParams) *Breaker {
-	if params.QueueDepth <= 0 {
+	if len(params.Classes) == 0 && params.QueueDepth <= 0 {
 		panic(fmt.Sprintf("Queue depth must be greater than 0. Got %v.", params.QueueDepth))
 	}
 	if params.MaxConcurrency < 0 {
@@ -68,9 +93,24 @@ func NewBreaker(params BreakerParams) *Breaker {
 		panic(fmt.Sprintf("Initial capacity must be between 0 and max concurrency. Got %v.", params.InitialCapacity))
 	}
 
+	if params.SheddingTarget > 0 && params.SheddingInterval <= params.SheddingTarget {
+		panic(fmt.Sprintf("Shedding interval must be longer than the shedding target. Got %v.", params.SheddingInterval))
+	}
+
+	queueTimeout := newQueueTimeout(params.QueueDeadline, params.SheddingTarget, params.SheddingInterval, time.Now())
+	if len(params.Classes) > 0 {
+		b := &Breaker{
+			classes:      newClassQueue(params.Classes, params.StrictPriority, params.InitialCapacity),
+			queueTimeout: queueTimeout,
+		}
+		b.release = b.classes.release
+		return b
+	}
+
 	b := &Breaker{
-		totalSlots: int64(params.QueueDepth + params.MaxConcurrency),
-		sem:        newSemaphore(params.MaxConcurrency, params.InitialCapacity),
+		totalSlots:   int64(params.QueueDepth + params.MaxConcurrency),
+		sem:          newSemaphore(params.MaxConcurrency, params.InitialCapacity),
+		queueTimeout: queueTimeout,
 	}
 
 	// Allocating the closure returned by Reserve here avoids an allocation in Reserve.
@@ -118,6 +158,13 @@ func (b *Breaker) releasePending() {
 // richer semantics in the caller.
 // The caller on success must execute the callback when done with work.
 func (b *Breaker) Reserve(ctx context.Context) (func(), bool) {
+	if b.classes != nil {
+		if !b.classes.tryAcquire() {
+			return nil, false
+		}
+		return b.release, true
+	}
+
 	if !b.tryAcquirePending() {
 		return nil, false
 	}
@@ -134,7 +181,50 @@ func (b *Breaker) Reserve(ctx context.Context) (func(), bool) {
 // and queue parameters. If the concurrency limit and queue capacity are
 // already consumed, Maybe returns immediately without calling thunk. If
 // the thunk was executed, Maybe returns nil, else error.
+// If the breaker has priority classes, the thunk is queued in the class
+// attached to the context by WithPriorityClass. If the thunk waits for
+// capacity for longer than the breaker's queue timeout allows, Maybe
+// returns ErrRequestQueueTimeout.
 func (b *Breaker) Maybe(ctx context.Context, thunk func()) error {
+	if b.queueTimeout == nil {
+		return b.maybe(ctx, thunk)
+	}
+
+	start := time.Now()
+	waitCtx := ctx
+	if timeout := b.queueTimeout.enqueue(start); timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	dequeued := false
+	err := b.maybe(waitCtx, func() {
+		now := time.Now()
+		dequeued = true
+		b.queueTimeout.dequeue(now, now.Sub(start))
+		thunk()
+	})
+	if !dequeued {
+		b.queueTimeout.abandon(time.Now())
+	}
+	if err != nil && ctx.Err() == nil && waitCtx.Err() != nil {
+		return ErrRequestQueueTimeout
+	}
+	return err
+}
+
+// maybe executes thunk once there is capacity, without bounding the time it
+// waits for it other than by the context.
+func (b *Breaker) maybe(ctx context.Context, thunk func()) error {
+	if b.classes != nil {
+		if err := b.classes.acquire(ctx, b.classes.classIndex(PriorityClassFrom(ctx))); err != nil {
+			return err
+		}
+		defer b.classes.release()
+		thunk()
+		return nil
+	}
+
 	if !b.tryAcquirePending() {
 		return ErrRequestQueueFull
 	}
@@ -159,19 +249,44 @@ func (b *Breaker) Maybe(ctx context.Context, thunk func()) error {
 
 // InFlight returns the number of requests currently in flight in this breaker.
 func (b *Breaker) InFlight() int {
+	if b.classes != nil {
+		return b.classes.queued()
+	}
 	return int(b.inFlight.Load())
 }
 
 // UpdateConcurrency updates the maximum number of in-flight requests.
 func (b *Breaker) UpdateConcurrency(size int) {
+	if b.classes != nil {
+		b.classes.updateCapacity(size)
+		return
+	}
 	b.sem.updateCapacity(size)
 }
 
 // Capacity returns the number of allowed in-flight requests on this breaker.
 func (b *Breaker) Capacity() int {
+	if b.classes != nil {
+		return b.classes.getCapacity()
+	}
 	return b.sem.Capacity()
 }
 
+// priorityClass returns the name of the priority class of the breaker that
+// matches the value, case insensitively, or the last class if none matches.
+// It returns the empty string if the breaker has no priority classes.
+func (b *Breaker) priorityClass(value string) string {
+	if b == nil || b.classes == nil {
+		return ""
+	}
+	for _, c := range b.classes.classes {
+		if strings.EqualFold(c.Name, value) {
+			return c.Name
+		}
+	}
+	return b.classes.classes[len(b.classes.classes)-1].Name
+}
+
 // newSemaphore creates a semaphore with the desired initial capacity.
 func newSemaphore(maxCapacity, initialCapacity int) *semaphore {
 	queue := make(chan struct{}, maxCapacity)
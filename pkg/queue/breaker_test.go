@@ -49,6 +49,9 @@ func TestBreakerInvalidConstructor(t *testing.T) {
 	}, {
 		name:    "InitialCapacity out-of-bounds",
 		options: BreakerParams{QueueDepth: 1, MaxConcurrency: 5, InitialCapacity: 6},
+	}, {
+		name:    "SheddingInterval not longer than SheddingTarget",
+		options: BreakerParams{QueueDepth: 1, MaxConcurrency: 1, SheddingTarget: time.Second, SheddingInterval: time.Second},
 	}}
 
 	for _, test := range tests {
@@ -0,0 +1,90 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// maxCustomMetricBodyBytes limits the size of the metrics page read from the
+// user container.
+const maxCustomMetricBodyBytes = 4 << 20
+
+// CustomMetricScraper scrapes the value of an application metric, which the
+// user container serves in the Prometheus text format.
+type CustomMetricScraper struct {
+	url    string
+	name   string
+	client *http.Client
+}
+
+// NewCustomMetricScraper creates a scraper of the metric with the given name
+// served at the given URL.
+func NewCustomMetricScraper(url, name string, client *http.Client) *CustomMetricScraper {
+	return &CustomMetricScraper{
+		url:    url,
+		name:   name,
+		client: client,
+	}
+}
+
+// Scrape returns the current value of the metric. The values of all the series
+// of the metric, e.g. with different labels, are summed up.
+func (s *CustomMetricScraper) Scrape(ctx context.Context) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Accept", string(expfmt.FmtText))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("GET %s returned unexpected status: %d", s.url, resp.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(io.LimitReader(resp.Body, maxCustomMetricBodyBytes))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+	family, ok := families[s.name]
+	if !ok {
+		return 0, fmt.Errorf("metric %q not found", s.name)
+	}
+
+	var ret float64
+	for _, m := range family.GetMetric() {
+		switch family.GetType() {
+		case dto.MetricType_GAUGE:
+			ret += m.GetGauge().GetValue()
+		case dto.MetricType_UNTYPED:
+			ret += m.GetUntyped().GetValue()
+		default:
+			return 0, fmt.Errorf("metric %q has unsupported type %v, want a gauge", s.name, family.GetType())
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testMetricsPage = `# HELP queue_depth The number of queued jobs.
+# TYPE queue_depth gauge
+queue_depth{queue="high"} 3
+queue_depth{queue="low"} 4.5
+# HELP jobs_total The number of processed jobs.
+# TYPE jobs_total counter
+jobs_total 1027
+free_slots 2
+`
+
+func TestCustomMetricScraper(t *testing.T) {
+	tests := []struct {
+		name    string
+		metric  string
+		status  int
+		want    float64
+		wantErr bool
+	}{{
+		name:   "gauge with multiple series",
+		metric: "queue_depth",
+		status: http.StatusOK,
+		want:   7.5,
+	}, {
+		name:   "untyped",
+		metric: "free_slots",
+		status: http.StatusOK,
+		want:   2,
+	}, {
+		name:    "counter",
+		metric:  "jobs_total",
+		status:  http.StatusOK,
+		wantErr: true,
+	}, {
+		name:    "missing metric",
+		metric:  "gpu_free",
+		status:  http.StatusOK,
+		wantErr: true,
+	}, {
+		name:    "bad status",
+		metric:  "queue_depth",
+		status:  http.StatusInternalServerError,
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/metrics" {
+					t.Errorf("Path = %q, want: /metrics", r.URL.Path)
+				}
+				w.WriteHeader(test.status)
+				w.Write([]byte(testMetricsPage))
+			}))
+			t.Cleanup(server.Close)
+
+			s := NewCustomMetricScraper(server.URL+"/metrics", test.metric, server.Client())
+			got, err := s.Scrape(context.Background())
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Scrape() = %v, wantErr: %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("Scrape() = %v, want: %v", got, test.want)
+			}
+		})
+	}
+}
@@ -23,14 +23,22 @@ import (
 	"time"
 
 	"go.opencensus.io/trace"
+	"google.golang.org/grpc/codes"
 	netheader "knative.dev/networking/pkg/http/header"
 	netstats "knative.dev/networking/pkg/http/stats"
 	"knative.dev/serving/pkg/activator"
+	pkghttp "knative.dev/serving/pkg/http"
 )
 
+// queueTimeoutRetryAfter is the Retry-After header value, in seconds, of the
+// responses to requests that waited for too long in the breaker queue.
+const queueTimeoutRetryAfter = "1"
+
 // ProxyHandler sends requests to the `next` handler at a rate controlled by
-// the passed `breaker`, while recording stats to `stats`.
-func ProxyHandler(breaker *Breaker, stats *netstats.RequestStats, tracingEnabled bool, next http.Handler) http.HandlerFunc {
+// the passed `breaker`, while recording stats to `stats`. The long-lived
+// connections, e.g. WebSockets, are recorded to `longLived` too, and the
+// responses of the others to `responses`.
+func ProxyHandler(breaker *Breaker, stats, longLived *netstats.RequestStats, responses *ResponseStats, tracingEnabled bool, next http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if netheader.IsKubeletProbe(r) {
 			next.ServeHTTP(w, r)
@@ -48,10 +56,26 @@ func ProxyHandler(breaker *Breaker, stats *netstats.RequestStats, tracingEnabled
 		if activator.Name == netheader.GetKnativeProxyValue(r) {
 			in, out = netstats.ProxiedIn, netstats.ProxiedOut
 		}
-		stats.HandleEvent(netstats.ReqEvent{Time: time.Now(), Type: in})
+		start := time.Now()
+		stats.HandleEvent(netstats.ReqEvent{Time: start, Type: in})
 		defer func() {
 			stats.HandleEvent(netstats.ReqEvent{Time: time.Now(), Type: out})
 		}()
+		rr := pkghttp.NewResponseRecorder(w, http.StatusOK)
+		isLongLived := false
+		w = pkghttp.TrackLongLived(rr, r, func() {
+			isLongLived = true
+			longLived.HandleEvent(netstats.ReqEvent{Time: time.Now(), Type: in})
+		})
+		defer func() {
+			if isLongLived {
+				longLived.HandleEvent(netstats.ReqEvent{Time: time.Now(), Type: out})
+			} else {
+				// The latencies of the long-lived connections say nothing
+				// about the health of the revision.
+				responses.HandleResponse(rr.ResponseCode, rr.Header(), time.Since(start))
+			}
+		}()
 		netheader.RewriteHostOut(r)
 
 		// Enforce queuing and concurrency limits.
@@ -65,9 +89,26 @@ func ProxyHandler(breaker *Breaker, stats *netstats.RequestStats, tracingEnabled
 				next.ServeHTTP(w, r)
 			}); err != nil {
 				waitSpan.End()
-				if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRequestQueueFull) {
+				grpc := pkghttp.IsGRPC(r)
+				switch {
+				case errors.Is(err, ErrRequestQueueTimeout):
+					// The request didn't get capacity in time, but a retry
+					// may well do.
+					w.Header().Set("Retry-After", queueTimeoutRetryAfter)
+					if grpc {
+						pkghttp.GRPCError(w, codes.Unavailable, err.Error())
+					} else {
+						http.Error(w, err.Error(), http.StatusServiceUnavailable)
+					}
+				case grpc && errors.Is(err, context.DeadlineExceeded):
+					pkghttp.GRPCError(w, codes.DeadlineExceeded, err.Error())
+				case grpc && errors.Is(err, ErrRequestQueueFull):
+					pkghttp.GRPCError(w, codes.ResourceExhausted, err.Error())
+				case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRequestQueueFull):
 					http.Error(w, err.Error(), http.StatusServiceUnavailable)
-				} else {
+				case grpc:
+					pkghttp.GRPCError(w, codes.Internal, err.Error())
+				default:
 					// This line is most likely untestable :-).
 					w.WriteHeader(http.StatusInternalServerError)
 				}
@@ -23,11 +23,13 @@ import (
 	"net/http/httptest"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
 
 	"go.uber.org/atomic"
+	"google.golang.org/grpc/codes"
 	netheader "knative.dev/networking/pkg/http/header"
 	netstats "knative.dev/networking/pkg/http/stats"
 	"knative.dev/serving/pkg/activator"
@@ -48,7 +50,7 @@ func TestHandlerBreakerQueueFull(t *testing.T) {
 		QueueDepth: 1, MaxConcurrency: 1, InitialCapacity: 1,
 	})
 	stats := netstats.NewRequestStats(time.Now())
-	h := ProxyHandler(breaker, stats, false /*tracingEnabled*/, blockHandler)
+	h := ProxyHandler(breaker, stats, netstats.NewRequestStats(time.Now()), NewResponseStats(), false /*tracingEnabled*/, blockHandler)
 
 	req := httptest.NewRequest(http.MethodGet, "http://localhost:8081/time", nil)
 	resps := make(chan *httptest.ResponseRecorder)
@@ -96,7 +98,7 @@ func TestHandlerBreakerTimeout(t *testing.T) {
 		QueueDepth: 1, MaxConcurrency: 1, InitialCapacity: 1,
 	})
 	stats := netstats.NewRequestStats(time.Now())
-	h := ProxyHandler(breaker, stats, false /*tracingEnabled*/, blockHandler)
+	h := ProxyHandler(breaker, stats, netstats.NewRequestStats(time.Now()), NewResponseStats(), false /*tracingEnabled*/, blockHandler)
 
 	go func() {
 		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:8081/time", nil))
@@ -120,6 +122,88 @@ func TestHandlerBreakerTimeout(t *testing.T) {
 	}
 }
 
+func TestHandlerBreakerQueueDeadline(t *testing.T) {
+	// This test sends a request which will take a long time to complete.
+	// Then another one which exceeds the queue deadline of the breaker.
+	// Verifies that the second one fails fast and is asked to retry.
+	seen := make(chan struct{})
+	resp := make(chan struct{})
+	defer close(resp) // Allow all requests to pass through.
+	blockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen <- struct{}{}
+		<-resp
+	})
+	breaker := NewBreaker(BreakerParams{
+		QueueDepth: 1, MaxConcurrency: 1, InitialCapacity: 1, QueueDeadline: 10 * time.Millisecond,
+	})
+	stats := netstats.NewRequestStats(time.Now())
+	h := ProxyHandler(breaker, stats, netstats.NewRequestStats(time.Now()), NewResponseStats(), false /*tracingEnabled*/, blockHandler)
+
+	go func() {
+		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:8081/time", nil))
+	}()
+
+	// Wait until the first request has entered the handler.
+	<-seen
+
+	rec := httptest.NewRecorder()
+	h(rec, httptest.NewRequest(http.MethodGet, "http://localhost:8081/time", nil))
+	if got, want := rec.Code, http.StatusServiceUnavailable; got != want {
+		t.Fatalf("Code = %d, want: %d", got, want)
+	}
+	if got, want := rec.Header().Get("Retry-After"), queueTimeoutRetryAfter; got != want {
+		t.Errorf("Retry-After = %q, want: %q", got, want)
+	}
+	want := ErrRequestQueueTimeout.Error()
+	if got := rec.Body.String(); !strings.Contains(got, want) {
+		t.Fatalf("Body = %q wanted to contain %q", got, want)
+	}
+}
+
+func TestHandlerBreakerGRPC(t *testing.T) {
+	// This test sends a gRPC request which will take a long time to complete.
+	// Then gRPC requests that fail in the breaker queue.
+	// Verifies that they fail with gRPC statuses.
+	seen := make(chan struct{})
+	resp := make(chan struct{})
+	defer close(resp) // Allow all requests to pass through.
+	blockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen <- struct{}{}
+		<-resp
+	})
+	breaker := NewBreaker(BreakerParams{
+		QueueDepth: 1, MaxConcurrency: 1, InitialCapacity: 1,
+	})
+	stats := netstats.NewRequestStats(time.Now())
+	h := ProxyHandler(breaker, stats, netstats.NewRequestStats(time.Now()), NewResponseStats(), false /*tracingEnabled*/, blockHandler)
+
+	grpcRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:8081/grpc.Service/Method", nil)
+		req.Header.Set("Content-Type", "application/grpc")
+		return req
+	}
+	go func() {
+		h(httptest.NewRecorder(), grpcRequest())
+	}()
+
+	// Wait until the first request has entered the handler.
+	<-seen
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	rec := httptest.NewRecorder()
+	h(rec, grpcRequest().WithContext(ctx))
+	if got, want := rec.Code, http.StatusOK; got != want {
+		t.Errorf("Code = %d, want: %d", got, want)
+	}
+	if got, want := rec.Header().Get("Grpc-Status"), strconv.Itoa(int(codes.DeadlineExceeded)); got != want {
+		t.Errorf("Grpc-Status = %q, want: %q", got, want)
+	}
+	if got := rec.Body.String(); got != "" {
+		t.Errorf("Body = %q, want it empty", got)
+	}
+}
+
 func TestHandlerReqEvent(t *testing.T) {
 	params := BreakerParams{QueueDepth: 10, MaxConcurrency: 10, InitialCapacity: 10}
 	breaker := NewBreaker(params)
@@ -154,7 +238,7 @@ func TestHandlerReqEvent(t *testing.T) {
 			proxy := httputil.NewSingleHostReverseProxy(serverURL)
 
 			stats := netstats.NewRequestStats(time.Now())
-			h := ProxyHandler(br, stats, true /*tracingEnabled*/, proxy)
+			h := ProxyHandler(br, stats, netstats.NewRequestStats(time.Now()), NewResponseStats(), true /*tracingEnabled*/, proxy)
 
 			writer := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodPost, "http://example.com", nil)
@@ -172,6 +256,70 @@ func TestHandlerReqEvent(t *testing.T) {
 	}
 }
 
+func TestHandlerLongLived(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestHeader http.Header
+		contentType   string
+		proxied       bool
+		wantLongLived bool
+	}{{
+		name:        "plain request",
+		contentType: "text/plain",
+	}, {
+		name:          "websocket",
+		requestHeader: http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}},
+		wantLongLived: true,
+	}, {
+		name:          "event stream",
+		contentType:   "text/event-stream",
+		wantLongLived: true,
+	}, {
+		name:          "proxied event stream",
+		contentType:   "text/event-stream",
+		proxied:       true,
+		wantLongLived: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start := time.Now()
+			stats, longLived := netstats.NewRequestStats(start), netstats.NewRequestStats(start)
+			responses := NewResponseStats()
+			h := ProxyHandler(nil, stats, longLived, responses, false /*tracingEnabled*/, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.contentType != "" {
+					w.Header().Set("Content-Type", test.contentType)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "http://example.com", nil)
+			for k, v := range test.requestHeader {
+				req.Header[k] = v
+			}
+			if test.proxied {
+				req.Header.Set(netheader.ProxyKey, activator.Name)
+			}
+			h(httptest.NewRecorder(), req)
+
+			now := time.Now()
+			if got := stats.Report(now).RequestCount; got != 1 {
+				t.Errorf("RequestCount = %v, want: 1", got)
+			}
+			report := longLived.Report(now)
+			if got, want := report.RequestCount, map[bool]float64{true: 1}[test.wantLongLived]; got != want {
+				t.Errorf("Long-lived RequestCount = %v, want: %v", got, want)
+			}
+			if got, want := report.ProxiedRequestCount, map[bool]float64{true: 1}[test.wantLongLived && test.proxied]; got != want {
+				t.Errorf("Long-lived ProxiedRequestCount = %v, want: %v", got, want)
+			}
+			if got, want := responses.Report().ResponseCount, map[bool]float64{false: 1}[test.wantLongLived]; got != want {
+				t.Errorf("ResponseCount = %v, want: %v", got, want)
+			}
+		})
+	}
+}
+
 func TestIgnoreProbe(t *testing.T) {
 	// Verifies that probes don't queue.
 	resp := make(chan struct{})
@@ -214,7 +362,7 @@ func TestIgnoreProbe(t *testing.T) {
 	// Ensure no more than 1 request can be queued. So we'll send 3.
 	breaker := NewBreaker(BreakerParams{QueueDepth: 1, MaxConcurrency: 1, InitialCapacity: 1})
 	stats := netstats.NewRequestStats(time.Now())
-	h := ProxyHandler(breaker, stats, false /*tracingEnabled*/, proxy)
+	h := ProxyHandler(breaker, stats, netstats.NewRequestStats(time.Now()), NewResponseStats(), false /*tracingEnabled*/, proxy)
 
 	req := httptest.NewRequest(http.MethodPost, "http://prob.in", nil)
 	req.Header.Set(netheader.KubeletProbeKey, "1") // Mark it a probe.
@@ -262,7 +410,7 @@ func BenchmarkProxyHandler(b *testing.B) {
 	for _, tc := range tests {
 		reportTicker := time.NewTicker(tc.reportPeriod)
 
-		h := ProxyHandler(tc.breaker, stats, true /*tracingEnabled*/, baseHandler)
+		h := ProxyHandler(tc.breaker, stats, netstats.NewRequestStats(time.Now()), NewResponseStats(), true /*tracingEnabled*/, baseHandler)
 		b.Run("sequential-"+tc.label, func(b *testing.B) {
 			resp := httptest.NewRecorder()
 			for j := 0; j < b.N; j++ {
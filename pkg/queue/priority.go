@@ -0,0 +1,227 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+type priorityClassKey struct{}
+
+// WithPriorityClass attaches the name of the priority class of a request to
+// the context, which the Breaker queues the request in.
+func WithPriorityClass(ctx context.Context, class string) context.Context {
+	return context.WithValue(ctx, priorityClassKey{}, class)
+}
+
+// PriorityClassFrom returns the name of the priority class attached to the
+// context, or the empty string if there is none.
+func PriorityClassFrom(ctx context.Context) string {
+	class, _ := ctx.Value(priorityClassKey{}).(string)
+	return class
+}
+
+// PriorityClassHandler attaches the priority class of the breaker named by the
+// header of the request to the request context. The header is the one the
+// operator trusts the ingress to strip or overwrite on untrusted requests.
+func PriorityClassHandler(breaker *Breaker, header string, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		class := breaker.priorityClass(r.Header.Get(header))
+		next.ServeHTTP(w, r.WithContext(WithPriorityClass(r.Context(), class)))
+	}
+}
+
+// classQueue is the state of a Breaker with priority classes. Rather than
+// letting waiting requests race for freed capacity, it hands the capacity to
+// the next request by the priority of the classes, hence it's guarded by a
+// mutex.
+type classQueue struct {
+	mu       sync.Mutex
+	capacity int
+	// inFlight is the number of requests holding capacity.
+	inFlight int
+	// waiting is the number of queued requests across the classes.
+	waiting int
+	classes []priorityClass
+	// index maps the names of the classes to their index.
+	index  map[string]int
+	strict bool
+}
+
+type priorityClass struct {
+	serving.PriorityClass
+	// waiters are the channels of the queued requests, which are
+	// closed when they are handed capacity.
+	waiters list.List
+	// current is the running credit of the class under weighted queueing.
+	current int
+}
+
+func newClassQueue(classes []serving.PriorityClass, strict bool, initialCapacity int) *classQueue {
+	q := &classQueue{
+		capacity: initialCapacity,
+		classes:  make([]priorityClass, len(classes)),
+		index:    make(map[string]int, len(classes)),
+		strict:   strict,
+	}
+	for i, c := range classes {
+		q.classes[i].PriorityClass = c
+		q.index[c.Name] = i
+	}
+	return q
+}
+
+// classIndex returns the index of the class with the name, or the last class
+// if there is none.
+func (q *classQueue) classIndex(name string) int {
+	if i, ok := q.index[name]; ok {
+		return i
+	}
+	return len(q.classes) - 1
+}
+
+// tryAcquire acquires capacity if there is some and no request is queued.
+func (q *classQueue) tryAcquire() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.inFlight < q.capacity && q.waiting == 0 {
+		q.inFlight++
+		return true
+	}
+	return false
+}
+
+// acquire acquires capacity, queueing the request in its class while there
+// is none.
+func (q *classQueue) acquire(ctx context.Context, class int) error {
+	q.mu.Lock()
+	if q.inFlight < q.capacity && q.waiting == 0 {
+		q.inFlight++
+		q.mu.Unlock()
+		return nil
+	}
+	c := &q.classes[class]
+	if c.waiters.Len() >= c.QueueDepth {
+		q.mu.Unlock()
+		return ErrRequestQueueFull
+	}
+	ready := make(chan struct{})
+	e := c.waiters.PushBack(ready)
+	q.waiting++
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	select {
+	case <-ready:
+		// We were handed capacity concurrently, so pass it on.
+		q.mu.Unlock()
+		q.release()
+	default:
+		c.waiters.Remove(e)
+		q.waiting--
+		q.mu.Unlock()
+	}
+	return ctx.Err()
+}
+
+// release releases capacity, handing it to the next queued request.
+func (q *classQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.inFlight == 0 {
+		panic("release and acquire are not paired")
+	}
+	q.inFlight--
+	q.dispatch()
+}
+
+// updateCapacity updates the capacity, handing any added to the queued
+// requests.
+func (q *classQueue) updateCapacity(size int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.capacity = size
+	q.dispatch()
+}
+
+// dispatch hands the free capacity to the queued requests.
+// It must be called with mu held.
+func (q *classQueue) dispatch() {
+	for q.inFlight < q.capacity && q.waiting > 0 {
+		c := q.next()
+		ready := c.waiters.Remove(c.waiters.Front()).(chan struct{})
+		q.waiting--
+		q.inFlight++
+		close(ready)
+	}
+}
+
+// next returns the class to dequeue a request from. There must be at least
+// one queued request.
+func (q *classQueue) next() *priorityClass {
+	if q.strict {
+		for i := range q.classes {
+			if q.classes[i].waiters.Len() > 0 {
+				return &q.classes[i]
+			}
+		}
+	}
+	// Smooth weighted round robin: every class with queued requests earns
+	// its weight, and the one with the most credit pays for the request
+	// with the total weight.
+	var (
+		best  *priorityClass
+		total int
+	)
+	for i := range q.classes {
+		c := &q.classes[i]
+		if c.waiters.Len() == 0 {
+			continue
+		}
+		c.current += c.Weight
+		total += c.Weight
+		if best == nil || c.current > best.current {
+			best = c
+		}
+	}
+	best.current -= total
+	return best
+}
+
+// queued returns the number of requests waiting for capacity and holding it.
+func (q *classQueue) queued() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.inFlight + q.waiting
+}
+
+func (q *classQueue) getCapacity() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.capacity
+}
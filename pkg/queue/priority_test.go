@@ -0,0 +1,205 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"knative.dev/serving/pkg/apis/serving"
+)
+
+var testClasses = []serving.PriorityClass{{
+	Name: "high", Weight: 2, QueueDepth: 3,
+}, {
+	Name: "low", Weight: 1, QueueDepth: 3,
+}}
+
+// queueAll queues a request for each of the classes in order, with the
+// breaker out of capacity, and returns the order the requests execute in
+// once the breaker gets capacity for one request at a time.
+func queueAll(t *testing.T, b *Breaker, classes ...string) []string {
+	t.Helper()
+	var (
+		mu    sync.Mutex
+		order []string
+		wg    sync.WaitGroup
+	)
+	for i, class := range classes {
+		class := class
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := WithPriorityClass(context.Background(), class)
+			if err := b.Maybe(ctx, func() {
+				mu.Lock()
+				defer mu.Unlock()
+				order = append(order, class)
+			}); err != nil {
+				t.Errorf("Maybe() = %v", err)
+			}
+		}()
+		waitInFlight(t, b, i+1)
+	}
+	b.UpdateConcurrency(1)
+	wg.Wait()
+	return order
+}
+
+func waitInFlight(t *testing.T, b *Breaker, want int) {
+	t.Helper()
+	if err := wait.PollUntilContextTimeout(context.Background(), time.Millisecond, semAcquireTimeout, true, func(context.Context) (bool, error) {
+		return b.InFlight() == want, nil
+	}); err != nil {
+		t.Fatalf("InFlight() = %d, want %d", b.InFlight(), want)
+	}
+}
+
+func TestPriorityClassesStrict(t *testing.T) {
+	b := NewBreaker(BreakerParams{MaxConcurrency: 1, Classes: testClasses, StrictPriority: true})
+
+	got := queueAll(t, b, "low", "high", "low", "high")
+	if want := []string{"high", "high", "low", "low"}; !cmp.Equal(got, want) {
+		t.Error("Execution order (-want, +got):", cmp.Diff(want, got))
+	}
+}
+
+func TestPriorityClassesWeighted(t *testing.T) {
+	b := NewBreaker(BreakerParams{MaxConcurrency: 1, Classes: testClasses})
+
+	got := queueAll(t, b, "low", "low", "low", "high", "high", "high")
+	if want := []string{"high", "low", "high", "high", "low", "low"}; !cmp.Equal(got, want) {
+		t.Error("Execution order (-want, +got):", cmp.Diff(want, got))
+	}
+}
+
+func TestPriorityClassesQueueDepth(t *testing.T) {
+	b := NewBreaker(BreakerParams{MaxConcurrency: 1, Classes: []serving.PriorityClass{{
+		Name: "high", Weight: 1, QueueDepth: 1,
+	}, {
+		Name: "low", Weight: 1, QueueDepth: 1,
+	}}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.Maybe(WithPriorityClass(ctx, "high"), func() {})
+	}()
+	waitInFlight(t, b, 1)
+
+	if err := b.Maybe(WithPriorityClass(ctx, "high"), func() {}); !errors.Is(err, ErrRequestQueueFull) {
+		t.Errorf("Maybe(high) = %v, want %v", err, ErrRequestQueueFull)
+	}
+
+	// The other class still has room.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.Maybe(WithPriorityClass(ctx, "low"), func() {})
+	}()
+	waitInFlight(t, b, 2)
+
+	if _, ok := b.Reserve(ctx); ok {
+		t.Error("Reserve() succeeded with requests queued")
+	}
+	cancel()
+	wg.Wait()
+	if got := b.InFlight(); got != 0 {
+		t.Errorf("InFlight() = %d after cancelling the queued requests, want 0", got)
+	}
+}
+
+func TestPriorityClassesReserve(t *testing.T) {
+	b := NewBreaker(BreakerParams{MaxConcurrency: 1, InitialCapacity: 1, Classes: testClasses})
+
+	release, ok := b.Reserve(context.Background())
+	if !ok {
+		t.Fatal("Reserve() failed with capacity")
+	}
+	if _, ok := b.Reserve(context.Background()); ok {
+		t.Error("Reserve() succeeded without capacity")
+	}
+	release()
+	if got, want := b.Capacity(), 1; got != want {
+		t.Errorf("Capacity() = %d, want %d", got, want)
+	}
+	if got := b.InFlight(); got != 0 {
+		t.Errorf("InFlight() = %d, want 0", got)
+	}
+}
+
+func TestPriorityClassHandler(t *testing.T) {
+	b := NewBreaker(BreakerParams{MaxConcurrency: 1, Classes: testClasses})
+
+	tests := []struct {
+		name    string
+		breaker *Breaker
+		value   string
+		want    string
+	}{{
+		name:    "exact",
+		breaker: b,
+		value:   "high",
+		want:    "high",
+	}, {
+		name:    "case insensitive",
+		breaker: b,
+		value:   "HIGH",
+		want:    "high",
+	}, {
+		name:    "unknown class",
+		breaker: b,
+		value:   "urgent",
+		want:    "low",
+	}, {
+		name:    "no header",
+		breaker: b,
+		want:    "low",
+	}, {
+		name:    "no classes",
+		breaker: NewBreaker(BreakerParams{QueueDepth: 1, MaxConcurrency: 1}),
+		value:   "high",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			h := PriorityClassHandler(test.breaker, "X-Priority", http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = PriorityClassFrom(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.value != "" {
+				req.Header.Set("X-Priority", test.value)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			if got != test.want {
+				t.Errorf("PriorityClassFrom() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
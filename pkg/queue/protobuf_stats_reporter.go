@@ -17,6 +17,7 @@ limitations under the License.
 package queue
 
 import (
+	"math"
 	"net/http"
 	"time"
 
@@ -39,6 +40,9 @@ type ProtobufStatsReporter struct {
 	stat      atomic.Value
 	podName   string
 
+	// customMetricValue is the latest value of the custom application metric.
+	customMetricValue atomic.Float64
+
 	// RequestCount and ProxiedRequestCount need to be divided by the reporting period
 	// they were collected over to get a "per-second" value.
 	reportingPeriodSeconds float64
@@ -61,8 +65,9 @@ func NewProtobufStatsReporter(pod string, reportingPeriod time.Duration) *Protob
 	return r
 }
 
-// Report captures request metrics.
-func (r *ProtobufStatsReporter) Report(stats netstats.RequestStatsReport) {
+// Report captures request metrics, along with those of the long-lived
+// connections among the requests, and of the responses.
+func (r *ProtobufStatsReporter) Report(stats, longLived netstats.RequestStatsReport, responses ResponseStatsReport) {
 	r.stat.Store(metrics.Stat{
 		PodName:       r.podName,
 		ProcessUptime: time.Since(r.startTime).Seconds(),
@@ -72,9 +77,31 @@ func (r *ProtobufStatsReporter) Report(stats netstats.RequestStatsReport) {
 		ProxiedRequestCount:              stats.ProxiedRequestCount / r.reportingPeriodSeconds,
 		AverageConcurrentRequests:        stats.AverageConcurrency,
 		AverageProxiedConcurrentRequests: stats.AverageProxiedConcurrency,
+		CustomMetricValue:                r.customMetricValue.Load(),
+
+		AverageLongLivedConcurrentRequests:        longLived.AverageConcurrency,
+		AverageProxiedLongLivedConcurrentRequests: longLived.AverageProxiedConcurrency,
+
+		// So are the response counts.
+		ResponseCount:      responses.ResponseCount / r.reportingPeriodSeconds,
+		ServerErrorCount:   responses.ServerErrorCount / r.reportingPeriodSeconds,
+		ResponseLatencySum: responses.LatencySum.Seconds() / r.reportingPeriodSeconds,
 	})
 }
 
+// ReportCustomMetric captures the latest value of the custom application metric,
+// to be reported along with the request metrics.
+func (r *ProtobufStatsReporter) ReportCustomMetric(v float64) {
+	r.customMetricValue.Store(v)
+}
+
+// ClearCustomMetric drops the value of the custom application metric, e.g. when
+// it could not be scraped, so that no stale value is reported. NaN is reported
+// instead until the next value is captured.
+func (r *ProtobufStatsReporter) ClearCustomMetric() {
+	r.customMetricValue.Store(math.NaN())
+}
+
 // ServeHTTP serves the stats in protobuf format over HTTP.
 func (r *ProtobufStatsReporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
 	data := r.stat.Load().(metrics.Stat)
@@ -18,6 +18,7 @@ package queue
 
 import (
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -107,7 +108,7 @@ func TestProtobufStatsReporterReport(t *testing.T) {
 			reporter := NewProtobufStatsReporter(pod, test.reportingPeriod)
 			// Make the value slightly more interesting, rather than microseconds.
 			reporter.startTime = reporter.startTime.Add(-5 * time.Second)
-			reporter.Report(test.report)
+			reporter.Report(test.report, netstats.RequestStatsReport{}, ResponseStatsReport{})
 			got := scrapeProtobufStat(t, reporter)
 			test.want.PodName = pod
 			if !cmp.Equal(test.want, got, ignoreStatFields) {
@@ -120,6 +121,34 @@ func TestProtobufStatsReporterReport(t *testing.T) {
 	}
 }
 
+func TestProtobufStatsReporterReportCustomMetric(t *testing.T) {
+	reporter := NewProtobufStatsReporter(pod, time.Second)
+	reporter.ReportCustomMetric(42)
+	reporter.Report(netstats.RequestStatsReport{
+		AverageConcurrency: 3,
+		RequestCount:       39,
+	}, netstats.RequestStatsReport{}, ResponseStatsReport{})
+	want := metrics.Stat{
+		PodName:                   pod,
+		AverageConcurrentRequests: 3,
+		RequestCount:              39,
+		CustomMetricValue:         42,
+	}
+	if got := scrapeProtobufStat(t, reporter); !cmp.Equal(want, got, ignoreStatFields) {
+		t.Errorf("Scraped stat mismatch; diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestProtobufStatsReporterClearCustomMetric(t *testing.T) {
+	reporter := NewProtobufStatsReporter(pod, time.Second)
+	reporter.ReportCustomMetric(42)
+	reporter.ClearCustomMetric()
+	reporter.Report(netstats.RequestStatsReport{}, netstats.RequestStatsReport{}, ResponseStatsReport{})
+	if got := scrapeProtobufStat(t, reporter).CustomMetricValue; !math.IsNaN(got) {
+		t.Errorf("CustomMetricValue = %v, want NaN", got)
+	}
+}
+
 func TestInitialProtobufStateValid(t *testing.T) {
 	r := NewProtobufStatsReporter(pod, 1*time.Second)
 	emptyStat := metrics.Stat{
@@ -155,3 +184,51 @@ func scrapeProtobufStat(t *testing.T, r *ProtobufStatsReporter) metrics.Stat {
 
 	return stat
 }
+
+func TestProtobufStatsReporterReportLongLived(t *testing.T) {
+	reporter := NewProtobufStatsReporter(pod, time.Second)
+	reporter.Report(netstats.RequestStatsReport{
+		AverageConcurrency:        5,
+		AverageProxiedConcurrency: 2,
+		RequestCount:              39,
+	}, netstats.RequestStatsReport{
+		AverageConcurrency:        3,
+		AverageProxiedConcurrency: 1,
+		// The connections are counted in the request count already.
+		RequestCount: 3,
+	}, ResponseStatsReport{})
+	want := metrics.Stat{
+		PodName:                                   pod,
+		AverageConcurrentRequests:                 5,
+		AverageProxiedConcurrentRequests:          2,
+		RequestCount:                              39,
+		AverageLongLivedConcurrentRequests:        3,
+		AverageProxiedLongLivedConcurrentRequests: 1,
+	}
+	if got := scrapeProtobufStat(t, reporter); !cmp.Equal(want, got, ignoreStatFields) {
+		t.Errorf("Scraped stat mismatch; diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestProtobufStatsReporterReportResponses(t *testing.T) {
+	reporter := NewProtobufStatsReporter(pod, 2*time.Second)
+	reporter.Report(netstats.RequestStatsReport{
+		AverageConcurrency: 5,
+		RequestCount:       40,
+	}, netstats.RequestStatsReport{}, ResponseStatsReport{
+		ResponseCount:    38,
+		ServerErrorCount: 2,
+		LatencySum:       19 * time.Second,
+	})
+	want := metrics.Stat{
+		PodName:                   pod,
+		AverageConcurrentRequests: 5,
+		RequestCount:              20,
+		ResponseCount:             19,
+		ServerErrorCount:          1,
+		ResponseLatencySum:        9.5,
+	}
+	if got := scrapeProtobufStat(t, reporter); !cmp.Equal(want, got, ignoreStatFields) {
+		t.Errorf("Scraped stat mismatch; diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
@@ -75,7 +75,7 @@ type appRequestMetricsHandler struct {
 // NewRequestMetricsHandler creates an http.Handler that emits request metrics.
 func NewRequestMetricsHandler(next http.Handler,
 	ns, service, config, rev, pod string) (http.Handler, error) {
-	keys := []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey, metrics.RouteTagKey}
+	keys := []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey, metrics.RouteTagKey, metrics.PriorityClassKey, metrics.GRPCStatusKey}
 	if err := pkgmetrics.RegisterResourceView(
 		&view.View{
 			Description: "The number of requests that are routed to queue-proxy",
@@ -118,15 +118,17 @@ func (h *requestMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		err := recover()
 		latency := time.Since(startTime)
 		routeTag := GetRouteTagNameFromRequest(r)
+		statsCtx := metrics.AugmentWithPriorityClass(h.statsCtx, PriorityClassFrom(r.Context()))
 		if err != nil {
-			ctx := metrics.AugmentWithResponseAndRouteTag(h.statsCtx,
+			ctx := metrics.AugmentWithResponseAndRouteTag(statsCtx,
 				http.StatusInternalServerError, routeTag)
 			pkgmetrics.RecordBatch(ctx, requestCountM.M(1),
 				responseTimeInMsecM.M(float64(latency.Milliseconds())))
 			panic(err)
 		}
-		ctx := metrics.AugmentWithResponseAndRouteTag(h.statsCtx,
+		ctx := metrics.AugmentWithResponseAndRouteTag(statsCtx,
 			rr.ResponseCode, routeTag)
+		ctx = augmentWithGRPCStatus(ctx, rr)
 		pkgmetrics.RecordBatch(ctx, requestCountM.M(1),
 			responseTimeInMsecM.M(float64(latency.Milliseconds())))
 	}()
@@ -137,7 +139,7 @@ func (h *requestMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 // NewAppRequestMetricsHandler creates an http.Handler that emits request metrics.
 func NewAppRequestMetricsHandler(next http.Handler, b *Breaker,
 	ns, service, config, rev, pod string) (http.Handler, error) {
-	keys := []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey}
+	keys := []tag.Key{metrics.PodKey, metrics.ContainerKey, metrics.ResponseCodeKey, metrics.ResponseCodeClassKey, metrics.PriorityClassKey, metrics.GRPCStatusKey}
 	if err := pkgmetrics.RegisterResourceView(&view.View{
 		Description: "The number of requests that are routed to user-container",
 		Measure:     appRequestCountM,
@@ -185,20 +187,31 @@ func (h *appRequestMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		// If ServeHTTP panics, recover, record the failure and panic again.
 		err := recover()
 		latency := time.Since(startTime)
+		statsCtx := metrics.AugmentWithPriorityClass(h.statsCtx, PriorityClassFrom(r.Context()))
 		if err != nil {
-			ctx := metrics.AugmentWithResponse(h.statsCtx, http.StatusInternalServerError)
+			ctx := metrics.AugmentWithResponse(statsCtx, http.StatusInternalServerError)
 			pkgmetrics.RecordBatch(ctx, appRequestCountM.M(1),
 				appResponseTimeInMsecM.M(float64(latency.Milliseconds())))
 			panic(err)
 		}
 
-		ctx := metrics.AugmentWithResponse(h.statsCtx, rr.ResponseCode)
+		ctx := metrics.AugmentWithResponse(statsCtx, rr.ResponseCode)
+		ctx = augmentWithGRPCStatus(ctx, rr)
 		pkgmetrics.RecordBatch(ctx, appRequestCountM.M(1),
 			appResponseTimeInMsecM.M(float64(latency.Milliseconds())))
 	}()
 	h.next.ServeHTTP(rr, r)
 }
 
+// augmentWithGRPCStatus augments the given context with the gRPC status of the
+// response, if it's a gRPC response.
+func augmentWithGRPCStatus(ctx context.Context, rr *pkghttp.ResponseRecorder) context.Context {
+	if code, ok := pkghttp.GRPCStatus(rr.Header()); ok {
+		return metrics.AugmentWithGRPCStatus(ctx, code.String())
+	}
+	return ctx
+}
+
 const (
 	defaultTagName   = "DEFAULT"
 	undefinedTagName = "UNDEFINED"
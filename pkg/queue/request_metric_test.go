@@ -25,6 +25,7 @@ import (
 	"go.opencensus.io/resource"
 	netheader "knative.dev/networking/pkg/http/header"
 	"knative.dev/pkg/metrics/metricstest"
+	"knative.dev/serving/pkg/apis/serving"
 	"knative.dev/serving/pkg/metrics"
 
 	_ "knative.dev/pkg/metrics/testing"
@@ -290,6 +291,85 @@ func TestAppRequestMetricsHandler(t *testing.T) {
 	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("app_request_latencies", 1, wantTags).WithResource(wantResource))
 }
 
+func TestAppRequestMetricsHandlerPriorityClass(t *testing.T) {
+	defer reset()
+	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	breaker := NewBreaker(BreakerParams{MaxConcurrency: 10, InitialCapacity: 10, Classes: []serving.PriorityClass{{
+		Name: "high", Weight: 1, QueueDepth: 10,
+	}, {
+		Name: "low", Weight: 1, QueueDepth: 10,
+	}}})
+	handler, err := NewAppRequestMetricsHandler(baseHandler, breaker,
+		"ns", "svc", "cfg", "rev", "pod")
+	if err != nil {
+		t.Fatal("Failed to create handler:", err)
+	}
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, targetURI, bytes.NewBufferString("test"))
+	req.Header.Set("X-Priority", "high")
+	PriorityClassHandler(breaker, "X-Priority", handler).ServeHTTP(resp, req)
+
+	wantTags := map[string]string{
+		metrics.LabelPodName:           "pod",
+		metrics.LabelContainerName:     "queue-proxy",
+		metrics.LabelResponseCode:      "200",
+		metrics.LabelResponseCodeClass: "2xx",
+		metrics.LabelPriorityClass:     "high",
+	}
+	wantResource := &resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metrics.LabelNamespaceName:     "ns",
+			metrics.LabelRevisionName:      "rev",
+			metrics.LabelServiceName:       "svc",
+			metrics.LabelConfigurationName: "cfg",
+		},
+	}
+
+	metricstest.AssertMetric(t, metricstest.IntMetric("app_request_count", 1, wantTags).WithResource(wantResource))
+	metricstest.AssertMetric(t, metricstest.DistributionCountOnlyMetric("app_request_latencies", 1, wantTags).WithResource(wantResource))
+}
+
+func TestRequestMetricsHandlerGRPCStatus(t *testing.T) {
+	defer reset()
+	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.WriteHeader(http.StatusOK)
+		w.Header().Set("Grpc-Status", "14")
+	})
+	handler, err := NewRequestMetricsHandler(baseHandler, "ns", "svc", "cfg", "rev", "pod")
+	if err != nil {
+		t.Fatal("Failed to create handler:", err)
+	}
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, targetURI, bytes.NewBufferString("test"))
+	req.Header.Set("Content-Type", "application/grpc")
+	handler.ServeHTTP(resp, req)
+
+	wantTags := map[string]string{
+		metrics.LabelPodName:           "pod",
+		metrics.LabelContainerName:     "queue-proxy",
+		metrics.LabelResponseCode:      "200",
+		metrics.LabelResponseCodeClass: "2xx",
+		metrics.LabelRouteTag:          disabledTagName,
+		metrics.LabelGRPCStatus:        "Unavailable",
+	}
+	wantResource := &resource.Resource{
+		Type: "knative_revision",
+		Labels: map[string]string{
+			metrics.LabelNamespaceName:     "ns",
+			metrics.LabelRevisionName:      "rev",
+			metrics.LabelServiceName:       "svc",
+			metrics.LabelConfigurationName: "cfg",
+		},
+	}
+
+	metricstest.AssertMetric(t, metricstest.IntMetric("request_count", 1, wantTags).WithResource(wantResource))
+}
+
 func BenchmarkRequestMetricsHandler(b *testing.B) {
 	baseHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
 	handler, _ := NewRequestMetricsHandler(baseHandler, "ns", "svc", "cfg", "rev", "pod")
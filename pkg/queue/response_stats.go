@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	pkghttp "knative.dev/serving/pkg/http"
+)
+
+// ResponseStats counts the responses of the requests, and the server errors
+// among them, and sums their latencies, between two reports.
+type ResponseStats struct {
+	mux sync.Mutex
+
+	responses    float64
+	serverErrors float64
+	latencySum   time.Duration
+}
+
+// ResponseStatsReport holds the responses recorded between two reports.
+type ResponseStatsReport struct {
+	// ResponseCount is the number of responses.
+	ResponseCount float64
+	// ServerErrorCount is the number of the responses that were server errors.
+	ServerErrorCount float64
+	// LatencySum is the sum of the latencies of the responses.
+	LatencySum time.Duration
+}
+
+// NewResponseStats creates a new ResponseStats.
+func NewResponseStats() *ResponseStats {
+	return &ResponseStats{}
+}
+
+// HandleResponse records the response with the given status code and header,
+// which took the given time since the request came in.
+func (s *ResponseStats) HandleResponse(code int, header http.Header, latency time.Duration) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.responses++
+	if isServerError(code, header) {
+		s.serverErrors++
+	}
+	s.latencySum += latency
+}
+
+// Report returns the responses recorded since the previous report.
+func (s *ResponseStats) Report() ResponseStatsReport {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	report := ResponseStatsReport{
+		ResponseCount:    s.responses,
+		ServerErrorCount: s.serverErrors,
+		LatencySum:       s.latencySum,
+	}
+	s.responses, s.serverErrors, s.latencySum = 0, 0, 0
+	return report
+}
+
+// isServerError returns whether the response is a server error, i.e. has a
+// 5xx status code or, for gRPC, a status the server is to blame for.
+func isServerError(code int, header http.Header) bool {
+	if code >= http.StatusInternalServerError {
+		return true
+	}
+	grpcCode, ok := pkghttp.GRPCStatus(header)
+	if !ok {
+		return false
+	}
+	switch grpcCode {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
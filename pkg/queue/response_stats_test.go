@@ -0,0 +1,53 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+)
+
+func TestResponseStats(t *testing.T) {
+	grpcHeader := func(code codes.Code) http.Header {
+		return http.Header{"Grpc-Status": []string{strconv.Itoa(int(code))}}
+	}
+
+	s := NewResponseStats()
+	s.HandleResponse(http.StatusOK, http.Header{}, 100*time.Millisecond)
+	s.HandleResponse(http.StatusNotFound, http.Header{}, 100*time.Millisecond)
+	s.HandleResponse(http.StatusBadGateway, http.Header{}, 200*time.Millisecond)
+	s.HandleResponse(http.StatusOK, grpcHeader(codes.NotFound), 100*time.Millisecond)
+	s.HandleResponse(http.StatusOK, grpcHeader(codes.Unavailable), 500*time.Millisecond)
+
+	want := ResponseStatsReport{
+		ResponseCount:    5,
+		ServerErrorCount: 2,
+		LatencySum:       time.Second,
+	}
+	if got := s.Report(); !cmp.Equal(got, want) {
+		t.Error("Report() diff(-want,+got):", cmp.Diff(want, got))
+	}
+	// The report resets the stats.
+	if got, want := s.Report(), (ResponseStatsReport{}); !cmp.Equal(got, want) {
+		t.Error("Second Report() diff(-want,+got):", cmp.Diff(want, got))
+	}
+}
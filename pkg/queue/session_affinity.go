@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+
+	"knative.dev/serving/pkg/networking"
+)
+
+// SessionRevisionHandler returns the name of the revision in the session
+// revision header of the responses to the requests of the Routes with session
+// affinity, for their sessions to send it back and stick to the revision.
+func SessionRevisionHandler(revision string, next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(networking.SessionAffinityHeaderName) != "" {
+			w.Header().Set(networking.SessionRevisionHeaderName, revision)
+		}
+		next.ServeHTTP(w, r)
+	}
+}
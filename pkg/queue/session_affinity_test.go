@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"knative.dev/serving/pkg/networking"
+)
+
+func TestSessionRevisionHandler(t *testing.T) {
+	h := SessionRevisionHandler("rev-00002", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, test := range []struct {
+		name    string
+		session string
+		want    string
+	}{{
+		name: "no session affinity",
+	}, {
+		name:    "session affinity",
+		session: "cookie:session",
+		want:    "rev-00002",
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.session != "" {
+				req.Header.Set(networking.SessionAffinityHeaderName, test.session)
+			}
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, req)
+			if got := resp.Header().Get(networking.SessionRevisionHeaderName); got != test.want {
+				t.Errorf("Header %s = %q, want: %q", networking.SessionRevisionHeaderName, got, test.want)
+			}
+		})
+	}
+}
@@ -41,7 +41,8 @@ func mainHandler(
 	env config,
 	transport http.RoundTripper,
 	prober func() bool,
-	stats *netstats.RequestStats,
+	stats, longLivedStats *netstats.RequestStats,
+	responseStats *queue.ResponseStats,
 	logger *zap.SugaredLogger,
 ) (http.Handler, *pkghandler.Drainer) {
 	target := net.JoinHostPort("127.0.0.1", env.UserPort)
@@ -71,8 +72,9 @@ func mainHandler(
 	if metricsSupported {
 		composedHandler = requestAppMetricsHandler(logger, composedHandler, breaker, env)
 	}
-	composedHandler = queue.ProxyHandler(breaker, stats, tracingEnabled, composedHandler)
+	composedHandler = queue.ProxyHandler(breaker, stats, longLivedStats, responseStats, tracingEnabled, composedHandler)
 	composedHandler = queue.ForwardedShimHandler(composedHandler)
+	composedHandler = queue.SessionRevisionHandler(env.ServingRevision, composedHandler)
 	composedHandler = handler.NewTimeoutHandler(composedHandler, "request timeout", func(r *http.Request) (time.Duration, time.Duration, time.Duration) {
 		return timeout, responseStartTimeout, idleTimeout
 	})
@@ -83,6 +85,10 @@ func mainHandler(
 	if tracingEnabled {
 		composedHandler = tracing.HTTPSpanMiddleware(composedHandler)
 	}
+	if breaker != nil && env.QueuePriorityHeader != "" {
+		// The priority class must be known to the request metrics handler too.
+		composedHandler = queue.PriorityClassHandler(breaker, env.QueuePriorityHeader, composedHandler)
+	}
 
 	composedHandler = withFullDuplex(composedHandler, env.EnableHTTPFullDuplex, logger)
 
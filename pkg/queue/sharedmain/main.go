@@ -45,6 +45,7 @@ import (
 	"knative.dev/pkg/tracing"
 	tracingconfig "knative.dev/pkg/tracing/config"
 	"knative.dev/pkg/tracing/propagation/tracecontextb3"
+	"knative.dev/serving/pkg/apis/serving"
 	pkghttp "knative.dev/serving/pkg/http"
 	"knative.dev/serving/pkg/logging"
 	"knative.dev/serving/pkg/networking"
@@ -102,6 +103,20 @@ type config struct {
 	ServingRequestMetricsReportingPeriodSeconds int    `split_words:"true"` // optional
 	MetricsCollectorAddress                     string `split_words:"true"` // optional
 
+	// Custom application metric configuration
+	ServingCustomMetricName string `split_words:"true"` // optional
+	ServingCustomMetricURL  string `split_words:"true"` // optional
+
+	// Priority class configuration
+	QueuePriorityHeader   string `split_words:"true"` // optional
+	QueuePriorityClasses  string `split_words:"true"` // optional
+	QueuePriorityQueueing string `split_words:"true"` // optional
+
+	// Queueing deadline and load shedding configuration
+	QueueDeadline         time.Duration `split_words:"true"` // optional
+	QueueSheddingTarget   time.Duration `split_words:"true"` // optional
+	QueueSheddingInterval time.Duration `split_words:"true"` // optional
+
 	// Tracing configuration
 	TracingConfigDebug          bool                      `split_words:"true"` // optional
 	TracingConfigBackend        tracingconfig.BackendType `split_words:"true"` // optional
@@ -217,13 +232,34 @@ func Main(opts ...Option) error {
 	defer reportTicker.Stop()
 
 	stats := netstats.NewRequestStats(time.Now())
+	longLivedStats := netstats.NewRequestStats(time.Now())
+	responseStats := queue.NewResponseStats()
 	go func() {
 		for now := range reportTicker.C {
-			stat := stats.Report(now)
-			protoStatReporter.Report(stat)
+			protoStatReporter.Report(stats.Report(now), longLivedStats.Report(now), responseStats.Report())
 		}
 	}()
 
+	// Scrape the custom application metric from the user container, if configured.
+	if env.ServingCustomMetricName != "" {
+		scraper := queue.NewCustomMetricScraper(env.ServingCustomMetricURL, env.ServingCustomMetricName,
+			&http.Client{Timeout: reportingPeriod})
+		customMetricTicker := time.NewTicker(reportingPeriod)
+		defer customMetricTicker.Stop()
+		protoStatReporter.ClearCustomMetric()
+		go func() {
+			for range customMetricTicker.C {
+				v, err := scraper.Scrape(d.Ctx)
+				if err != nil {
+					logger.Errorw("Failed to scrape the custom metric", zap.Error(err))
+					protoStatReporter.ClearCustomMetric()
+					continue
+				}
+				protoStatReporter.ReportCustomMetric(v)
+			}
+		}()
+	}
+
 	// Setup probe to run for checking user-application healthiness.
 	probe := func() bool { return true }
 	if env.ServingReadinessProbe != "" {
@@ -233,7 +269,7 @@ func Main(opts ...Option) error {
 	// Enable TLS when certificate is mounted.
 	tlsEnabled := exists(logger, certPath) && exists(logger, keyPath)
 
-	mainHandler, drainer := mainHandler(d.Ctx, env, d.Transport, probe, stats, logger)
+	mainHandler, drainer := mainHandler(d.Ctx, env, d.Transport, probe, stats, longLivedStats, responseStats, logger)
 	adminHandler := adminHandler(d.Ctx, logger, drainer)
 
 	// Enable TLS server when activator server certs are mounted.
@@ -372,6 +408,24 @@ func buildBreaker(logger *zap.SugaredLogger, env config) *queue.Breaker {
 		QueueDepth:      queueDepth,
 		MaxConcurrency:  env.ContainerConcurrency,
 		InitialCapacity: env.ContainerConcurrency,
+		QueueDeadline:   env.QueueDeadline,
+	}
+	if env.QueueSheddingTarget > 0 && env.QueueSheddingInterval > env.QueueSheddingTarget {
+		params.SheddingTarget = env.QueueSheddingTarget
+		params.SheddingInterval = env.QueueSheddingInterval
+	} else if env.QueueSheddingTarget != 0 || env.QueueSheddingInterval != 0 {
+		logger.Errorw("Ignoring invalid queue shedding, the target must be positive and shorter than the interval",
+			zap.Duration("target", env.QueueSheddingTarget), zap.Duration("interval", env.QueueSheddingInterval))
+	}
+	if env.QueuePriorityClasses != "" {
+		// The queue depths of the classes replace the queue depth above.
+		classes, err := serving.ParsePriorityClasses(env.QueuePriorityClasses)
+		if err != nil {
+			logger.Errorw("Ignoring invalid priority classes", zap.Error(err))
+		} else {
+			params.Classes = classes
+			params.StrictPriority = env.QueuePriorityQueueing == serving.QueueingStrict
+		}
 	}
 	logger.Infof("Queue container is starting with BreakerParams = %#v", params)
 	return queue.NewBreaker(params)
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/atomic"
+)
+
+// ErrRequestQueueTimeout indicates a request waited in the breaker queue for
+// longer than allowed.
+var ErrRequestQueueTimeout = errors.New("request queue deadline exceeded")
+
+// queueTimeout bounds the time requests wait in the breaker queue.
+//
+// Besides the fixed deadline, it implements the adaptive bound of CoDel: as
+// long as requests got capacity within the target, or the queue was empty, at
+// least once in the last interval the queue is considered to drain and
+// requests wait for up to an interval. Otherwise the queue is standing, and
+// requests that don't get capacity within the target are shed instead of
+// adding to the latency of every request after them.
+type queueTimeout struct {
+	deadline time.Duration
+	target   time.Duration
+	interval time.Duration
+
+	// waiting is the number of requests waiting for capacity.
+	waiting atomic.Int64
+	// lastDrained is the last time, in unix nanoseconds, a request got
+	// capacity within the target or the queue was empty.
+	lastDrained atomic.Int64
+}
+
+// newQueueTimeout returns a queueTimeout or nil if neither the deadline nor
+// the target are set.
+func newQueueTimeout(deadline, target, interval time.Duration, now time.Time) *queueTimeout {
+	if deadline <= 0 && target <= 0 {
+		return nil
+	}
+	q := &queueTimeout{
+		deadline: deadline,
+		target:   target,
+		interval: interval,
+	}
+	q.lastDrained.Store(now.UnixNano())
+	return q
+}
+
+// enqueue records that a request arrived in the queue at now and returns how
+// long it may wait in the queue, or 0 if it may wait for as long as its context
+// allows. The request must leave the queue with either dequeue or abandon.
+func (q *queueTimeout) enqueue(now time.Time) time.Duration {
+	if q.waiting.Inc() == 1 {
+		// The queue was empty, so it didn't stand.
+		q.lastDrained.Store(now.UnixNano())
+	}
+	return q.timeout(now)
+}
+
+// timeout returns how long a request arriving at now may wait in the queue,
+// or 0 if it may wait for as long as its context allows.
+func (q *queueTimeout) timeout(now time.Time) time.Duration {
+	if q.target <= 0 {
+		return q.deadline
+	}
+	timeout := q.interval
+	if now.Sub(time.Unix(0, q.lastDrained.Load())) > q.interval {
+		timeout = q.target
+	}
+	if q.deadline > 0 {
+		timeout = min(timeout, q.deadline)
+	}
+	return timeout
+}
+
+// dequeue records that a request got capacity at now after waiting in the
+// queue for the sojourn duration.
+func (q *queueTimeout) dequeue(now time.Time, sojourn time.Duration) {
+	if q.waiting.Dec() == 0 || sojourn <= q.target {
+		q.lastDrained.Store(now.UnixNano())
+	}
+}
+
+// abandon records that a request left the queue at now without capacity.
+func (q *queueTimeout) abandon(now time.Time) {
+	if q.waiting.Dec() == 0 {
+		q.lastDrained.Store(now.UnixNano())
+	}
+}
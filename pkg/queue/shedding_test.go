@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueueTimeout(t *testing.T) {
+	start := time.Now()
+
+	if q := newQueueTimeout(0, 0, 0, start); q != nil {
+		t.Errorf("newQueueTimeout() = %#v, want nil", q)
+	}
+
+	tests := []struct {
+		name     string
+		deadline time.Duration
+		target   time.Duration
+		interval time.Duration
+		// waiting is whether a request is waiting since the start.
+		waiting bool
+		// drained is how long after the start a request got capacity
+		// within the target, if set.
+		drained time.Duration
+		// at is how long after the start the request arrives.
+		at   time.Duration
+		want time.Duration
+	}{{
+		name:     "deadline",
+		deadline: 5 * time.Second,
+		at:       time.Minute,
+		want:     5 * time.Second,
+	}, {
+		name:     "draining queue",
+		target:   10 * time.Millisecond,
+		interval: 100 * time.Millisecond,
+		waiting:  true,
+		at:       50 * time.Millisecond,
+		want:     100 * time.Millisecond,
+	}, {
+		name:     "standing queue",
+		target:   10 * time.Millisecond,
+		interval: 100 * time.Millisecond,
+		waiting:  true,
+		at:       150 * time.Millisecond,
+		want:     10 * time.Millisecond,
+	}, {
+		name:     "empty queue",
+		target:   10 * time.Millisecond,
+		interval: 100 * time.Millisecond,
+		at:       150 * time.Millisecond,
+		want:     100 * time.Millisecond,
+	}, {
+		name:     "drained again",
+		target:   10 * time.Millisecond,
+		interval: 100 * time.Millisecond,
+		waiting:  true,
+		drained:  100 * time.Millisecond,
+		at:       150 * time.Millisecond,
+		want:     100 * time.Millisecond,
+	}, {
+		name:     "deadline shorter than interval",
+		deadline: 50 * time.Millisecond,
+		target:   10 * time.Millisecond,
+		interval: 100 * time.Millisecond,
+		waiting:  true,
+		at:       50 * time.Millisecond,
+		want:     50 * time.Millisecond,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q := newQueueTimeout(test.deadline, test.target, test.interval, start)
+			if test.waiting {
+				q.enqueue(start)
+			}
+			if test.drained > 0 {
+				q.enqueue(start.Add(test.drained - test.target))
+				q.dequeue(start.Add(test.drained), test.target)
+				// Requests waiting for longer than the target don't drain the queue.
+				q.enqueue(start.Add(test.at - test.target - 1))
+				q.dequeue(start.Add(test.at), test.target+1)
+			}
+			if got := q.enqueue(start.Add(test.at)); got != test.want {
+				t.Errorf("enqueue() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestBreakerShedding(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	b := NewBreaker(BreakerParams{
+		QueueDepth: 10, MaxConcurrency: 1,
+		SheddingTarget: time.Millisecond, SheddingInterval: interval,
+	})
+
+	// Without capacity the queue doesn't drain, so requests wait for up to
+	// an interval and then, once the queue is standing, only the target.
+	start := time.Now()
+	errs := make(chan error, 2)
+	go func() { errs <- b.Maybe(context.Background(), func() {}) }()
+	time.Sleep(interval / 2)
+	go func() { errs <- b.Maybe(context.Background(), func() {}) }()
+	time.Sleep(start.Add(interval + interval/5).Sub(time.Now()))
+	standing := time.Now()
+	if err := b.Maybe(context.Background(), func() {}); !errors.Is(err, ErrRequestQueueTimeout) {
+		t.Fatalf("Maybe() = %v, want %v", err, ErrRequestQueueTimeout)
+	}
+	if waited := time.Since(standing); waited >= interval/5 {
+		t.Errorf("Request waited %v, want less than the rest of the interval", waited)
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, ErrRequestQueueTimeout) {
+			t.Fatalf("Maybe() = %v, want %v", err, ErrRequestQueueTimeout)
+		}
+	}
+	if waited := time.Since(start); waited < interval+interval/2 {
+		t.Errorf("Requests waited %v, want at least an interval each", waited)
+	}
+
+	// Once empty, the queue didn't stand, however long it was idle.
+	time.Sleep(interval)
+	start = time.Now()
+	if err := b.Maybe(context.Background(), func() {}); !errors.Is(err, ErrRequestQueueTimeout) {
+		t.Fatalf("Maybe() = %v, want %v", err, ErrRequestQueueTimeout)
+	}
+	if waited := time.Since(start); waited < interval {
+		t.Errorf("Request waited %v, want at least the interval", waited)
+	}
+
+	// A request that gets capacity right away drains the queue.
+	b.UpdateConcurrency(1)
+	if err := b.Maybe(context.Background(), func() {}); err != nil {
+		t.Fatal("Maybe() =", err)
+	}
+	if got := b.queueTimeout.timeout(time.Now()); got != interval {
+		t.Errorf("timeout() = %v, want %v", got, interval)
+	}
+
+	// The context of the request is still honoured.
+	b.UpdateConcurrency(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.Maybe(ctx, func() {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Maybe() = %v, want %v", err, context.Canceled)
+	}
+}
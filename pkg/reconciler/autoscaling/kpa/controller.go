@@ -19,6 +19,7 @@ package kpa
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 
 	networkingclient "knative.dev/networking/pkg/client/injection/client"
@@ -28,6 +29,8 @@ import (
 	"knative.dev/serving/pkg/client/injection/ducks/autoscaling/v1alpha1/podscalable"
 	metricinformer "knative.dev/serving/pkg/client/injection/informers/autoscaling/v1alpha1/metric"
 	painformer "knative.dev/serving/pkg/client/injection/informers/autoscaling/v1alpha1/podautoscaler"
+	revisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision"
+	routeinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/route"
 	pareconciler "knative.dev/serving/pkg/client/injection/reconciler/autoscaling/v1alpha1/podautoscaler"
 
 	"knative.dev/pkg/configmap"
@@ -37,6 +40,7 @@ import (
 	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/deployment"
 	areconciler "knative.dev/serving/pkg/reconciler/autoscaling"
@@ -56,6 +60,8 @@ func NewController(
 	sksInformer := sksinformer.Get(ctx)
 	podsInformer := filteredpodinformer.Get(ctx, serving.RevisionUID)
 	metricInformer := metricinformer.Get(ctx)
+	revisionInformer := revisioninformer.Get(ctx)
+	routeInformer := routeinformer.Get(ctx)
 	psInformerFactory := podscalable.Get(ctx)
 
 	onlyKPAClass := pkgreconciler.AnnotationFilterFunc(
@@ -68,8 +74,10 @@ func NewController(
 			SKSLister:        sksInformer.Lister(),
 			MetricLister:     metricInformer.Lister(),
 		},
-		podsLister: podsInformer.Lister(),
-		deciders:   deciders,
+		podsLister:     podsInformer.Lister(),
+		revisionLister: revisionInformer.Lister(),
+		routeLister:    routeInformer.Lister(),
+		deciders:       deciders,
 	}
 	impl := pareconciler.NewImpl(ctx, c, autoscaling.KPA, func(impl *controller.Impl) controller.Options {
 		logger.Info("Setting up ConfigMap receivers")
@@ -107,6 +115,22 @@ func NewController(
 		Handler:    controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource("", serving.RevisionLabelKey)),
 	})
 
+	// Whether the PAs report their response stats depends on the rollout
+	// analysis of the Routes of their revisions, so requeue the PAs when
+	// either changes. The PodAutoscaler of a revision has the name of the revision.
+	revisionInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+	routeInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+		r, ok := obj.(*v1.Route)
+		if !ok {
+			return
+		}
+		for _, tt := range r.Status.Traffic {
+			if tt.RevisionName != "" {
+				impl.EnqueueKey(types.NamespacedName{Namespace: r.Namespace, Name: tt.RevisionName})
+			}
+		}
+	}))
+
 	// Have the Deciders enqueue the PAs whose decisions have changed.
 	deciders.Watch(impl.EnqueueKey)
 
@@ -20,19 +20,24 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"go.opencensus.io/stats"
 	"go.uber.org/zap"
 
 	nv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
+	"knative.dev/pkg/apis"
 	"knative.dev/pkg/logging"
 	pkgmetrics "knative.dev/pkg/metrics"
 	"knative.dev/pkg/ptr"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
+	asmetrics "knative.dev/serving/pkg/autoscaler/metrics"
 	"knative.dev/serving/pkg/autoscaler/scaling"
 	pareconciler "knative.dev/serving/pkg/client/injection/reconciler/autoscaling/v1alpha1/podautoscaler"
+	listers "knative.dev/serving/pkg/client/listers/serving/v1"
 	"knative.dev/serving/pkg/metrics"
 	areconciler "knative.dev/serving/pkg/reconciler/autoscaling"
 	"knative.dev/serving/pkg/reconciler/autoscaling/config"
@@ -42,6 +47,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 )
@@ -67,9 +73,11 @@ type podCounts struct {
 type Reconciler struct {
 	*areconciler.Base
 
-	podsLister corev1listers.PodLister
-	deciders   resources.Deciders
-	scaler     *scaler
+	podsLister     corev1listers.PodLister
+	revisionLister listers.RevisionLister
+	routeLister    listers.RouteLister
+	deciders       resources.Deciders
+	scaler         *scaler
 }
 
 // Check that our Reconciler implements the necessary interfaces.
@@ -85,6 +93,8 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pa *autoscalingv1alpha1.
 
 	logger := logging.FromContext(ctx)
 
+	c.reconcileScaleSchedule(ctx, pa, time.Now())
+
 	// We need the SKS object in order to optimize scale to zero
 	// performance. It is OK if SKS is nil at this point.
 	sksName := anames.SKS(pa.Name)
@@ -110,6 +120,18 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, pa *autoscalingv1alpha1.
 	if err != nil {
 		return fmt.Errorf("error reconciling Decider: %w", err)
 	}
+	// Keep the previous summary until the decider makes a valid decision,
+	// e.g. after an autoscaler restart.
+	if d := decider.Status.LastDecision; d.ScaleValid {
+		pa.Status.LastScaleDecision = summarizeDecision(d)
+	}
+	// Only the rollout analysis of the Routes reads the response stats,
+	// so spare the status updates of the other revisions.
+	if c.rolloutAnalyzed(pa) {
+		pa.Status.ResponseStats = summarizeResponses(decider.Status.ResponseStats, decider.Spec.StableWindow)
+	} else {
+		pa.Status.ResponseStats = nil
+	}
 
 	if err := c.ReconcileMetric(ctx, pa, resolveScrapeTarget(ctx, pa)); err != nil {
 		return fmt.Errorf("error reconciling Metric: %w", err)
@@ -195,6 +217,27 @@ func (c *Reconciler) ObserveDeletion(ctx context.Context, key types.NamespacedNa
 	return nil
 }
 
+// reconcileScaleSchedule reports the active window of the scale schedule in
+// the status, from where ScaleBounds picks up its scale bounds, and re-enqueues
+// the PA for when the active window may change next.
+func (c *Reconciler) reconcileScaleSchedule(ctx context.Context, pa *autoscalingv1alpha1.PodAutoscaler, now time.Time) {
+	w, until, next := pa.ScaleSchedule().At(now)
+	if w == nil {
+		pa.Status.ActiveScaleWindow = nil
+	} else {
+		pa.Status.ActiveScaleWindow = &autoscalingv1alpha1.ScaleWindow{
+			Name:     w.Name,
+			MinScale: w.MinScale,
+			MaxScale: w.MaxScale,
+			Until:    metav1.NewTime(until),
+		}
+	}
+	if !next.IsZero() {
+		logging.FromContext(ctx).Debug("Re-evaluating the scale schedule at ", next)
+		c.scaler.enqueueCB(pa, next.Sub(now))
+	}
+}
+
 func (c *Reconciler) reconcileDecider(ctx context.Context, pa *autoscalingv1alpha1.PodAutoscaler) (*scaling.Decider, error) {
 	desiredDecider := resources.MakeDecider(pa, config.FromContext(ctx).Autoscaler)
 	decider, err := c.deciders.Get(ctx, desiredDecider.Namespace, desiredDecider.Name)
@@ -348,3 +391,60 @@ func computeNumActivators(readyPods int, decider *scaling.Decider) int32 {
 
 	return int32(math.Max(minActivators, math.Ceil(capacityToCover/decider.Spec.ActivatorCapacity)))
 }
+
+// rolloutAnalyzed returns whether any Route of the revision of the PA
+// analyzes its rollouts.
+func (c *Reconciler) rolloutAnalyzed(pa *autoscalingv1alpha1.PodAutoscaler) bool {
+	// The PodAutoscaler of a revision has the name of the revision.
+	rev, err := c.revisionLister.Revisions(pa.Namespace).Get(pa.Name)
+	if err != nil {
+		return false
+	}
+	for _, name := range strings.Split(rev.Annotations[serving.RoutesAnnotationKey], ",") {
+		if name == "" {
+			continue
+		}
+		r, err := c.routeLister.Routes(pa.Namespace).Get(name)
+		if err == nil && (r.RolloutMinSuccessPercentage() > 0 || r.RolloutMaxLatency() > 0) {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeResponses turns the rates of the responses over the stable window
+// into counts, or returns nil if there were no responses.
+func summarizeResponses(rs asmetrics.ResponseStats, window time.Duration) *autoscalingv1alpha1.ResponseStats {
+	if rs.ResponseRate <= 0 {
+		return nil
+	}
+	return &autoscalingv1alpha1.ResponseStats{
+		ResponseCount:    int64(math.Round(rs.ResponseRate * window.Seconds())),
+		ServerErrorCount: int64(math.Round(rs.ServerErrorRate * window.Seconds())),
+		MeanLatency:      metav1.Duration{Duration: rs.MeanLatency},
+	}
+}
+
+// summarizeDecision converts the scaling decision of the decider to its
+// summary on the PA status.
+func summarizeDecision(d scaling.ScaleDecision) *autoscalingv1alpha1.ScaleDecision {
+	// Only the outcome of the decision is summarized, as the observed values
+	// and the pod counts derived from them change with every decision and
+	// would cause status updates on their own. They're served with the
+	// decisions.
+	var held []string
+	if d.UndelayedPodCount != d.UnlimitedPodCount {
+		held = append(held, "scale down delayed")
+	}
+	if d.UnlimitedPodCount != d.DesiredPodCount {
+		held = append(held, "scale limited by scaling policies")
+	}
+	return &autoscalingv1alpha1.ScaleDecision{
+		Time:            apis.VolatileTime{Inner: d.Time},
+		ScalingMetric:   d.ScalingMetric,
+		ReadyPodCount:   d.ReadyPodCount,
+		DesiredPodCount: d.DesiredPodCount,
+		PanicMode:       d.InPanicMode,
+		Message:         strings.Join(held, "; "),
+	}
+}
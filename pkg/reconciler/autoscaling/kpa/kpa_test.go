@@ -39,6 +39,7 @@ import (
 	fakemetricinformer "knative.dev/serving/pkg/client/injection/informers/autoscaling/v1alpha1/metric/fake"
 	fakepainformer "knative.dev/serving/pkg/client/injection/informers/autoscaling/v1alpha1/podautoscaler/fake"
 	fakerevisioninformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision/fake"
+	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/route/fake"
 	"knative.dev/serving/pkg/metrics"
 
 	networkingclient "knative.dev/networking/pkg/client/injection/client"
@@ -63,6 +64,7 @@ import (
 
 	nv1a1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	netcfg "knative.dev/networking/pkg/config"
+	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
@@ -80,6 +82,7 @@ import (
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	asconfig "knative.dev/serving/pkg/autoscaler/config"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
+	asmetrics "knative.dev/serving/pkg/autoscaler/metrics"
 	"knative.dev/serving/pkg/autoscaler/scaling"
 	"knative.dev/serving/pkg/deployment"
 	areconciler "knative.dev/serving/pkg/reconciler/autoscaling"
@@ -338,6 +341,130 @@ func TestReconcile(t *testing.T) {
 			defaultSKS,
 			metric(testNamespace, testRevision),
 			defaultDeployment, defaultReady},
+	}, {
+		Name: "steady state, propagate scale decision",
+		Key:  key,
+		Ctx: context.WithValue(context.Background(), deciderKey{},
+			deciderWithDecision(defaultScale, scaling.ScaleDecision{
+				Time:                  decisionTime,
+				ScaleValid:            true,
+				ScalingMetric:         autoscaling.Concurrency,
+				ObservedStableValue:   1000,
+				ObservedPanicValue:    1200,
+				TargetValue:           100,
+				ReadyPodCount:         1,
+				MaxScaleUp:            10,
+				DesiredStablePodCount: 10,
+				DesiredPanicPodCount:  10,
+				UndelayedPodCount:     10,
+				UnlimitedPodCount:     defaultScale,
+				DesiredPodCount:       defaultScale,
+			})),
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1)),
+			defaultSKS,
+			metric(testNamespace, testRevision),
+			defaultDeployment, defaultReady},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1),
+				func(pa *autoscalingv1alpha1.PodAutoscaler) {
+					pa.Status.LastScaleDecision = &autoscalingv1alpha1.ScaleDecision{
+						Time:            apis.VolatileTime{Inner: decisionTime},
+						ScalingMetric:   autoscaling.Concurrency,
+						ReadyPodCount:   1,
+						DesiredPodCount: defaultScale,
+						Message:         "scale down delayed",
+					}
+				}),
+		}},
+	}, {
+		// The observed values of the decisions change with every tick, but
+		// don't update the status unless the outcome changes.
+		Name: "steady state, unchanged scale decision",
+		Key:  key,
+		Ctx: context.WithValue(context.Background(), deciderKey{},
+			deciderWithDecision(defaultScale, scaling.ScaleDecision{
+				Time:                  metav1.NewTime(decisionTime.Add(2 * time.Second)),
+				ScaleValid:            true,
+				ScalingMetric:         autoscaling.Concurrency,
+				ObservedStableValue:   1010,
+				ObservedPanicValue:    1150,
+				TargetValue:           100,
+				ReadyPodCount:         1,
+				MaxScaleUp:            10,
+				DesiredStablePodCount: 11,
+				DesiredPanicPodCount:  12,
+				UndelayedPodCount:     9,
+				UnlimitedPodCount:     defaultScale,
+				DesiredPodCount:       defaultScale,
+				ExcessBurstCapacity:   -7,
+			})),
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1),
+				func(pa *autoscalingv1alpha1.PodAutoscaler) {
+					pa.Status.LastScaleDecision = &autoscalingv1alpha1.ScaleDecision{
+						Time:            apis.VolatileTime{Inner: decisionTime},
+						ScalingMetric:   autoscaling.Concurrency,
+						ReadyPodCount:   1,
+						DesiredPodCount: defaultScale,
+						Message:         "scale down delayed",
+					}
+				}),
+			defaultSKS,
+			metric(testNamespace, testRevision),
+			defaultDeployment, defaultReady},
+	}, {
+		Name: "steady state, propagate response stats",
+		Key:  key,
+		Ctx: context.WithValue(context.Background(), deciderKey{},
+			deciderWithResponseStats(defaultScale, asmetrics.ResponseStats{
+				ResponseRate:    10,
+				ServerErrorRate: 0.5,
+				MeanLatency:     120 * time.Millisecond,
+			})),
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1)),
+			defaultSKS,
+			metric(testNamespace, testRevision),
+			analyzedRevision, analyzingRoute,
+			defaultDeployment, defaultReady},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1),
+				withResponseStats),
+		}},
+	}, {
+		Name: "steady state, no rollout analysis, clear response stats",
+		Key:  key,
+		Ctx: context.WithValue(context.Background(), deciderKey{},
+			deciderWithResponseStats(defaultScale, asmetrics.ResponseStats{
+				ResponseRate:    10,
+				ServerErrorRate: 0.5,
+				MeanLatency:     120 * time.Millisecond,
+			})),
+		Objects: []runtime.Object{
+			kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1),
+				withResponseStats),
+			defaultSKS,
+			metric(testNamespace, testRevision),
+			analyzedRevision,
+			defaultDeployment, defaultReady},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: kpa(testNamespace, testRevision, WithPASKSReady, WithTraffic,
+				markScaleTargetInitialized, WithPAMetricsService(privateSvc),
+				withScales(1, defaultScale), WithPAStatusService(testRevision), WithObservedGeneration(1)),
+		}},
 	}, {
 		Name: "status update retry",
 		Key:  key,
@@ -1209,9 +1336,11 @@ func TestReconcile(t *testing.T) {
 				SKSLister:        listers.GetServerlessServiceLister(),
 				MetricLister:     listers.GetMetricLister(),
 			},
-			podsLister: listers.GetPodsLister(),
-			deciders:   fakeDeciders,
-			scaler:     scaler,
+			podsLister:     listers.GetPodsLister(),
+			revisionLister: listers.GetRevisionLister(),
+			routeLister:    listers.GetRouteLister(),
+			deciders:       fakeDeciders,
+			scaler:         scaler,
 		}
 		return pareconciler.NewReconciler(ctx, logging.FromContext(ctx),
 			servingclient.Get(ctx), listers.GetPodAutoscalerLister(),
@@ -1841,6 +1970,50 @@ func decider(ns, name string, desiredScale, ebc int32) *scaling.Decider {
 	}
 }
 
+var decisionTime = metav1.NewTime(time.Unix(1700000000, 0))
+
+// deciderWithDecision returns the default decider along with its latest scaling decision.
+func deciderWithDecision(desiredScale int32, d scaling.ScaleDecision) *scaling.Decider {
+	decider := resources.MakeDecider(kpa(testNamespace, testRevision), defaultConfig().Autoscaler)
+	decider.Status.DesiredScale = desiredScale
+	decider.Status.LastDecision = d
+	return decider
+}
+
+var (
+	// analyzedRevision is the test revision, routed to by analyzingRoute.
+	analyzedRevision = &v1.Revision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   testNamespace,
+			Name:        testRevision,
+			Annotations: map[string]string{serving.RoutesAnnotationKey: "analyzing"},
+		},
+	}
+	// analyzingRoute is a Route that analyzes its rollouts.
+	analyzingRoute = &v1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   testNamespace,
+			Name:        "analyzing",
+			Annotations: map[string]string{serving.RolloutMinSuccessPercentageKey: "99"},
+		},
+	}
+)
+
+func withResponseStats(pa *autoscalingv1alpha1.PodAutoscaler) {
+	pa.Status.ResponseStats = &autoscalingv1alpha1.ResponseStats{
+		ResponseCount:    3000,
+		ServerErrorCount: 150,
+		MeanLatency:      metav1.Duration{Duration: 120 * time.Millisecond},
+	}
+}
+
+func deciderWithResponseStats(desiredScale int32, rs asmetrics.ResponseStats) *scaling.Decider {
+	decider := resources.MakeDecider(kpa(testNamespace, testRevision), defaultConfig().Autoscaler)
+	decider.Status.DesiredScale = desiredScale
+	decider.Status.ResponseStats = rs
+	return decider
+}
+
 type testConfigStore struct {
 	config *config.Config
 }
@@ -1996,3 +2169,60 @@ func TestComputeActivatorNum(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileScaleSchedule(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	cases := []struct {
+		name        string
+		schedule    string
+		status      *autoscalingv1alpha1.ScaleWindow
+		want        *autoscalingv1alpha1.ScaleWindow
+		wantEnqueue time.Duration
+	}{{
+		name: "no schedule",
+	}, {
+		name:   "schedule removed",
+		status: &autoscalingv1alpha1.ScaleWindow{Name: "day", MinScale: ptr.Int32(3)},
+	}, {
+		name:     "active window",
+		schedule: `[{"name": "day", "start": "0 9 * * *", "duration": "10h", "minScale": 3, "maxScale": 10}]`,
+		want: &autoscalingv1alpha1.ScaleWindow{
+			Name:     "day",
+			MinScale: ptr.Int32(3),
+			MaxScale: ptr.Int32(10),
+			Until:    metav1.NewTime(time.Date(2024, 3, 1, 19, 0, 0, 0, time.UTC)),
+		},
+		wantEnqueue: 6*time.Hour + 30*time.Minute,
+	}, {
+		name:        "inactive window",
+		schedule:    `[{"name": "night", "start": "0 22 * * *", "duration": "8h", "maxScale": 1}]`,
+		status:      &autoscalingv1alpha1.ScaleWindow{Name: "night", MaxScale: ptr.Int32(1)},
+		wantEnqueue: 9*time.Hour + 30*time.Minute,
+	}, {
+		name:     "never active window",
+		schedule: `[{"name": "never", "start": "0 0 30 2 *", "duration": "1h", "minScale": 1}]`,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pa := kpa(testNamespace, testRevision)
+			if tc.schedule != "" {
+				pa.Annotations[autoscaling.ScaleScheduleAnnotationKey] = tc.schedule
+			}
+			pa.Status.ActiveScaleWindow = tc.status
+
+			var enqueued time.Duration
+			c := &Reconciler{scaler: &scaler{enqueueCB: func(_ interface{}, d time.Duration) {
+				enqueued = d
+			}}}
+			c.reconcileScaleSchedule(context.Background(), pa, now)
+
+			if !cmp.Equal(pa.Status.ActiveScaleWindow, tc.want) {
+				t.Error("ActiveScaleWindow mismatch (-want,+got):", cmp.Diff(tc.want, pa.Status.ActiveScaleWindow))
+			}
+			if enqueued != tc.wantEnqueue {
+				t.Errorf("Enqueued after %v, want: %v", enqueued, tc.wantEnqueue)
+			}
+		})
+	}
+}
@@ -18,8 +18,11 @@ package resources
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
 	"knative.dev/serving/pkg/autoscaler/scaling"
@@ -71,6 +74,19 @@ func MakeDecider(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscalerconfig
 		activationScale = mnzr
 	}
 
+	scalingMode := pa.ScalingMode()
+	var predictiveHorizon, seasonalPeriod time.Duration
+	if scalingMode == autoscaling.ScalingModePredictive {
+		predictiveHorizon = autoscaling.PredictiveHorizonDefault
+		if ph, ok := pa.PredictiveHorizon(); ok {
+			predictiveHorizon = ph
+		}
+		seasonalPeriod = autoscaling.SeasonalPeriodDefault
+		if sp, ok := pa.SeasonalPeriod(); ok {
+			seasonalPeriod = sp
+		}
+	}
+
 	return &scaling.Decider{
 		ObjectMeta: *pa.ObjectMeta.DeepCopy(),
 		Spec: scaling.DeciderSpec{
@@ -87,10 +103,35 @@ func MakeDecider(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscalerconfig
 			InitialScale:        GetInitialScale(config, pa),
 			Reachable:           pa.Spec.Reachability != autoscalingv1alpha1.ReachabilityUnreachable,
 			ActivationScale:     activationScale,
+			ScalingMode:         scalingMode,
+			PredictiveHorizon:   predictiveHorizon,
+			SeasonalPeriod:      seasonalPeriod,
+			Algorithm:           pa.ScalingAlgorithm(),
+			AlgorithmParameters: pa.ScalingAlgorithmParameters(),
+			AdditionalMetrics:   additionalMetrics(pa, config),
+			ScaleUpRules:        pa.ScaleUpRules(config),
+			ScaleDownRules:      pa.ScaleDownRules(config),
 		},
 	}
 }
 
+// additionalMetrics returns the additional metrics the PA scales on with their
+// resolved targets, sorted by metric.
+func additionalMetrics(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscalerconfig.Config) []scaling.MetricTarget {
+	targets := resources.ResolveAdditionalMetricTargets(pa, config)
+	if len(targets) == 0 {
+		return nil
+	}
+	ret := make([]scaling.MetricTarget, 0, len(targets))
+	for metric, target := range targets {
+		ret = append(ret, scaling.MetricTarget{Metric: metric, TargetValue: target})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Metric < ret[j].Metric
+	})
+	return ret
+}
+
 // GetInitialScale returns the calculated initial scale based on the autoscaler
 // ConfigMap and PA initial scale annotation value.
 func GetInitialScale(asConfig *autoscalerconfig.Config, pa *autoscalingv1alpha1.PodAutoscaler) int32 {
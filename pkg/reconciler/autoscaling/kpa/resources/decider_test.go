@@ -151,6 +151,34 @@ func TestMakeDecider(t *testing.T) {
 			return &c
 		},
 		want: decider(withTarget(100.0), withPanicThreshold(2.0), withTotal(100), withScaleDownDelay(10*time.Minute), withDeciderScaleDownDelayAnnotation("10m")),
+	}, {
+		name: "with scaling policies from config",
+		pa:   pa(),
+		cfgOpt: func(c autoscalerconfig.Config) *autoscalerconfig.Config {
+			c.ScaleDownRules = scaleDownRules
+			return &c
+		},
+		want: decider(withTarget(100.0), withPanicThreshold(2.0), withTotal(100),
+			func(d *scaling.Decider) {
+				d.Spec.ScaleDownRules = scaleDownRules
+			}),
+	}, {
+		name: "with scaling policies from annotation",
+		pa: pa(func(pa *autoscalingv1alpha1.PodAutoscaler) {
+			pa.Annotations[autoscaling.ScaleUpSelectPolicyAnnotationKey] = "disabled"
+			pa.Annotations[autoscaling.ScaleDownPoliciesAnnotationKey] = "pods=2/1m"
+		}),
+		cfgOpt: func(c autoscalerconfig.Config) *autoscalerconfig.Config {
+			c.ScaleDownRules.SelectPolicy = autoscalerconfig.MinChangePolicySelect
+			return &c
+		},
+		want: decider(withTarget(100.0), withPanicThreshold(2.0), withTotal(100),
+			func(d *scaling.Decider) {
+				d.Spec.ScaleUpRules.SelectPolicy = autoscalerconfig.DisabledPolicySelect
+				d.Spec.ScaleDownRules = scaleDownRules
+				d.Annotations[autoscaling.ScaleUpSelectPolicyAnnotationKey] = "disabled"
+				d.Annotations[autoscaling.ScaleDownPoliciesAnnotationKey] = "pods=2/1m"
+			}),
 	}, {
 		name: "with initial scale",
 		pa: pa(func(pa *autoscalingv1alpha1.PodAutoscaler) {
@@ -171,6 +199,48 @@ func TestMakeDecider(t *testing.T) {
 				d.Spec.ActivationScale = 3
 				d.Annotations[autoscaling.ActivationScaleKey] = "3"
 			}),
+	}, {
+		name: "with predictive scaling mode",
+		pa: pa(func(pa *autoscalingv1alpha1.PodAutoscaler) {
+			pa.Annotations[autoscaling.ScalingModeAnnotationKey] = autoscaling.ScalingModePredictive
+			pa.Annotations[autoscaling.SeasonalPeriodAnnotationKey] = "168h"
+		}),
+		want: decider(withTarget(100.0), withPanicThreshold(2.0), withTotal(100),
+			func(d *scaling.Decider) {
+				d.Spec.ScalingMode = autoscaling.ScalingModePredictive
+				d.Spec.PredictiveHorizon = autoscaling.PredictiveHorizonDefault
+				d.Spec.SeasonalPeriod = 7 * 24 * time.Hour
+				d.Annotations[autoscaling.ScalingModeAnnotationKey] = autoscaling.ScalingModePredictive
+				d.Annotations[autoscaling.SeasonalPeriodAnnotationKey] = "168h"
+			}),
+	}, {
+		name: "with scaling algorithm",
+		pa: pa(func(pa *autoscalingv1alpha1.PodAutoscaler) {
+			pa.Annotations[autoscaling.ScalingAlgorithmAnnotationKey] = "pid"
+			pa.Annotations[autoscaling.ScalingAlgorithmParameterPrefix+"kp"] = "0.5"
+		}),
+		want: decider(withTarget(100.0), withPanicThreshold(2.0), withTotal(100),
+			func(d *scaling.Decider) {
+				d.Spec.Algorithm = "pid"
+				d.Spec.AlgorithmParameters = map[string]string{"kp": "0.5"}
+				d.Annotations[autoscaling.ScalingAlgorithmAnnotationKey] = "pid"
+				d.Annotations[autoscaling.ScalingAlgorithmParameterPrefix+"kp"] = "0.5"
+			}),
+	}, {
+		name: "with additional metrics",
+		pa:   pa(WithMetricAnnotation("queue_depth"), WithTargetAnnotation("10"), WithAdditionalMetricsAnnotation("rps=150,concurrency=20")),
+		want: decider(withTarget(10.0), withPanicThreshold(2.0), withTotal(10),
+			withMetric("queue_depth"), withMetricAnnotation("queue_depth"), withTargetAnnotation("10"),
+			func(d *scaling.Decider) {
+				d.Spec.AdditionalMetrics = []scaling.MetricTarget{{
+					Metric:      autoscaling.Concurrency,
+					TargetValue: 20,
+				}, {
+					Metric:      autoscaling.RPS,
+					TargetValue: 150,
+				}}
+				d.Annotations[autoscaling.AdditionalMetricsAnnotationKey] = "rps=150,concurrency=20"
+			}),
 	}}
 
 	for _, tc := range cases {
@@ -307,6 +377,8 @@ func decider(options ...deciderOption) *scaling.Decider {
 			StableWindow:        config.StableWindow,
 			InitialScale:        1,
 			Reachable:           true,
+			ScalingMode:         autoscaling.ScalingModeReactive,
+			Algorithm:           autoscaling.ScalingAlgorithmDefault,
 		},
 	}
 	for _, fn := range options {
@@ -382,6 +454,15 @@ func withPanicThresholdPercentageAnnotation(percentage string) deciderOption {
 	}
 }
 
+var scaleDownRules = autoscalerconfig.ScalingRules{
+	SelectPolicy: autoscalerconfig.MinChangePolicySelect,
+	Policies: []autoscalerconfig.ScalingPolicy{{
+		Type:   autoscalerconfig.PodsScalingPolicy,
+		Value:  2,
+		Period: time.Minute,
+	}},
+}
+
 var config = &autoscalerconfig.Config{
 	EnableScaleToZero:                  true,
 	ContainerConcurrencyTargetFraction: 1.0,
@@ -29,12 +29,37 @@ import (
 // `target` is the target value of scaling metric that we autoscaler will aim for;
 // `total` is the maximum possible value of scaling metric that is permitted on the pod.
 func ResolveMetricTarget(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscalerconfig.Config) (target, total float64) {
+	annotationTarget, ok := pa.Target()
+	return resolveMetricTarget(pa, config, pa.Metric(), annotationTarget, ok)
+}
+
+// ResolveAdditionalMetricTargets resolves the targets of the metrics the PA
+// scales on in addition to its Metric, the same way ResolveMetricTarget does.
+// It returns the targets keyed by metric.
+func ResolveAdditionalMetricTargets(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscalerconfig.Config) map[string]float64 {
+	metrics := pa.AdditionalMetrics()
+	if len(metrics) == 0 {
+		return nil
+	}
+	ret := make(map[string]float64, len(metrics))
+	for metric, annotationTarget := range metrics {
+		ret[metric], _ = resolveMetricTarget(pa, config, metric, annotationTarget, true)
+	}
+	return ret
+}
+
+func resolveMetricTarget(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscalerconfig.Config,
+	metric string, annotationTarget float64, hasAnnotationTarget bool) (target, total float64) {
 	tu := 0.
 
-	switch pa.Metric() {
-	case autoscaling.RPS:
+	switch {
+	case metric == autoscaling.RPS:
 		total = config.RPSTargetDefault
 		tu = config.TargetUtilization
+	case autoscaling.IsCustomMetric(metric):
+		// Custom metrics have no default target, it must be provided via
+		// annotation, and aim for it exactly unless the utilization is set.
+		tu = 1
 	default:
 		// Concurrency is used by default
 		total = float64(pa.Spec.ContainerConcurrency)
@@ -46,9 +71,9 @@ func ResolveMetricTarget(pa *autoscalingv1alpha1.PodAutoscaler, config *autoscal
 	}
 
 	// Use the target provided via annotation, if applicable.
-	if annotationTarget, ok := pa.Target(); ok {
+	if hasAnnotationTarget {
 		total = annotationTarget
-		if pa.Metric() == autoscaling.Concurrency && pa.Spec.ContainerConcurrency != 0 {
+		if metric == autoscaling.Concurrency && pa.Spec.ContainerConcurrency != 0 {
 			// We pick the smaller value between container concurrency and the annotationTarget
 			// to make sure the autoscaler does not aim for a higher concurrency than the application
 			// can handle per containerConcurrency.
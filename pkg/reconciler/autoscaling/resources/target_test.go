@@ -19,6 +19,8 @@ package resources
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"knative.dev/serving/pkg/apis/autoscaling"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/autoscaler/config/autoscalerconfig"
@@ -170,6 +172,16 @@ func TestResolveMetricTarget(t *testing.T) {
 		pa:         pa(WithMetricAnnotation(autoscaling.RPS), WithTargetAnnotation("300")),
 		wantTarget: 210,
 		wantTotal:  300,
+	}, {
+		name:       "custom metric: with target annotation 10",
+		pa:         pa(WithMetricAnnotation("queue_depth"), WithPAContainerConcurrency(1), WithTargetAnnotation("10")),
+		wantTarget: 10,
+		wantTotal:  10,
+	}, {
+		name:       "custom metric: with target annotation 10 and TU annotation 50%",
+		pa:         pa(WithMetricAnnotation("queue_depth"), WithTargetAnnotation("10"), WithTUAnnotation("50")),
+		wantTarget: 5,
+		wantTotal:  10,
 	}}
 
 	for _, tc := range cases {
@@ -186,3 +198,35 @@ func TestResolveMetricTarget(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveAdditionalMetricTargets(t *testing.T) {
+	cases := []struct {
+		name string
+		pa   *autoscalingv1alpha1.PodAutoscaler
+		want map[string]float64
+	}{{
+		name: "none",
+		pa:   pa(),
+	}, {
+		name: "rps",
+		pa:   pa(WithAdditionalMetricsAnnotation("rps=150")),
+		want: map[string]float64{autoscaling.RPS: 105},
+	}, {
+		name: "rps with TU annotation 50%",
+		pa:   pa(WithAdditionalMetricsAnnotation("rps=150"), WithTUAnnotation("50")),
+		want: map[string]float64{autoscaling.RPS: 75},
+	}, {
+		name: "concurrency limited by container concurrency",
+		pa: pa(WithMetricAnnotation(autoscaling.RPS), WithPAContainerConcurrency(5),
+			WithAdditionalMetricsAnnotation("concurrency=10")),
+		want: map[string]float64{autoscaling.Concurrency: 5},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ResolveAdditionalMetricTargets(tc.pa, config); !cmp.Equal(got, tc.want) {
+				t.Errorf("ResolveAdditionalMetricTargets = %v, want: %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/serving/pkg/apis/serving"
@@ -40,7 +41,8 @@ func collect(
 	ctx context.Context,
 	client clientset.Interface,
 	revisionLister listers.RevisionLister,
-	config *v1.Configuration) pkgreconciler.Event {
+	config *v1.Configuration,
+	retained sets.Set[string]) pkgreconciler.Event {
 	cfg := configns.FromContext(ctx).RevisionGC
 	logger := logging.FromContext(ctx)
 
@@ -58,8 +60,8 @@ func collect(
 		return nil // not enough total revs
 	}
 
-	// Filter out active revs
-	revs = nonactiveRevisions(revs, config)
+	// Filter out active and retained revs
+	revs = nonactiveRevisions(revs, config, retained)
 
 	if len(revs) <= min {
 		return nil // not enough non-active revs
@@ -119,10 +121,10 @@ func collect(
 }
 
 // nonactiveRevisions swaps keeps only non active revisions.
-func nonactiveRevisions(revs []*v1.Revision, config *v1.Configuration) []*v1.Revision {
+func nonactiveRevisions(revs []*v1.Revision, config *v1.Configuration, retained sets.Set[string]) []*v1.Revision {
 	swap := len(revs)
 	for i := 0; i < swap; {
-		if isRevisionActive(revs[i], config) {
+		if isRevisionActive(revs[i], config) || retained.Has(revs[i].Name) {
 			swap--
 			revs[i] = revs[swap]
 		} else {
@@ -536,7 +536,7 @@ func runTest(
 
 	recorderList := ActionRecorderList{client}
 
-	collect(ctx, client, ri.Lister(), cfg)
+	collect(ctx, client, ri.Lister(), cfg, nil)
 
 	actions, err := recorderList.ActionsByVerb()
 	if err != nil {
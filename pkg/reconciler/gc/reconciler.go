@@ -18,8 +18,13 @@ package gc
 
 import (
 	"context"
+	"fmt"
 
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	clientset "knative.dev/serving/pkg/client/clientset/versioned"
 	configreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/configuration"
@@ -32,6 +37,7 @@ type reconciler struct {
 
 	// listers index properties about resources
 	revisionLister listers.RevisionLister
+	serviceLister  listers.ServiceLister
 }
 
 // Check that our reconciler implements configreconciler.Interface
@@ -42,5 +48,32 @@ func (c *reconciler) ReconcileKind(ctx context.Context, config *v1.Configuration
 	ctx, cancel := context.WithTimeout(ctx, pkgreconciler.DefaultTimeout)
 	defer cancel()
 
-	return collect(ctx, c.client, c.revisionLister, config)
+	retained, err := c.retainedRevisions(config)
+	if err != nil {
+		return err
+	}
+	return collect(ctx, c.client, c.revisionLister, config, retained)
+}
+
+// retainedRevisions returns the names of the revisions in the revision
+// history of the Service owning the Configuration, if any.
+func (c *reconciler) retainedRevisions(config *v1.Configuration) (sets.Set[string], error) {
+	name, ok := config.Labels[serving.ServiceLabelKey]
+	if !ok {
+		return nil, nil
+	}
+	service, err := c.serviceLister.Services(config.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get Service: %w", err)
+	}
+	if !metav1.IsControlledBy(config, service) {
+		return nil, nil
+	}
+	retained := make(sets.Set[string], len(service.Status.RevisionHistory))
+	for _, entry := range service.Status.RevisionHistory {
+		retained.Insert(entry.RevisionName)
+	}
+	return retained, nil
 }
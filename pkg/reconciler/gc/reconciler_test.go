@@ -28,8 +28,10 @@ import (
 
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	pkgrec "knative.dev/pkg/reconciler"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	servingclient "knative.dev/serving/pkg/client/injection/client/fake"
 	configreconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/configuration"
@@ -39,6 +41,7 @@ import (
 
 	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/configuration/fake"
 	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/revision/fake"
+	_ "knative.dev/serving/pkg/client/injection/informers/serving/v1/service/fake"
 
 	. "knative.dev/pkg/reconciler/testing"
 	. "knative.dev/serving/pkg/reconciler/testing/v1"
@@ -65,6 +68,15 @@ func TestGCReconcile(t *testing.T) {
 		}}
 
 	fc := clocktest.NewFakePassiveClock(time.Now())
+	svc := DefaultService("keep-history", "foo", WithRevisionHistory(v1.RevisionHistoryEntry{
+		RevisionName: "5554",
+	}, v1.RevisionHistoryEntry{
+		RevisionName: "5556",
+	}))
+	ownedBy := func(cfg *v1.Configuration) {
+		cfg.Labels = map[string]string{serving.ServiceLabelKey: svc.Name}
+		cfg.OwnerReferences = []metav1.OwnerReference{*kmeta.NewControllerRef(svc)}
+	}
 	table := TableTest{{
 		Name: "delete oldest, keep two V2",
 		Objects: []runtime.Object{
@@ -94,12 +106,36 @@ func TestGCReconcile(t *testing.T) {
 			Name: "5554",
 		}},
 		Key: "foo/keep-two",
+	}, {
+		Name: "keep revisions in the service revision history",
+		Objects: []runtime.Object{
+			svc,
+			cfg("keep-history", "foo", 5556,
+				ownedBy,
+				WithLatestCreated("5556"),
+				WithLatestReady("5556"),
+				WithConfigObservedGen),
+			rev("keep-history", "foo", 5554, MarkRevisionReady,
+				WithRevName("5554"),
+				WithRoutingState(v1.RoutingStateReserve, fc),
+				WithRoutingStateModified(oldest)),
+			rev("keep-history", "foo", 5555, MarkRevisionReady,
+				WithRevName("5555"),
+				WithRoutingState(v1.RoutingStateReserve, fc),
+				WithRoutingStateModified(older)),
+			rev("keep-history", "foo", 5556, MarkRevisionReady,
+				WithRevName("5556"),
+				WithRoutingState(v1.RoutingStateActive, fc),
+				WithRoutingStateModified(old)),
+		},
+		Key: "foo/keep-history",
 	}}
 
 	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher) controller.Reconciler {
 		r := &reconciler{
 			client:         servingclient.Get(ctx),
 			revisionLister: listers.GetRevisionLister(),
+			serviceLister:  listers.GetServiceLister(),
 		}
 		return configreconciler.NewReconciler(ctx, logging.FromContext(ctx),
 			servingclient.Get(ctx), listers.GetConfigurationLister(),
@@ -111,6 +111,27 @@ func TestV2Reconcile(t *testing.T) {
 				WithRoutingStateModified(now.Time)),
 		},
 		Key: "default/steady-state",
+	}, {
+		Name: "label rule revision",
+		Objects: []runtime.Object{
+			simpleRunLatest("default", "rule", "the-config", WithRouteFinalizer,
+				WithSpecRules(v1.RouteRule{
+					Headers:      map[string]v1.StringMatch{"X-Cohort": {Exact: "beta"}},
+					RevisionName: "the-revision",
+				})),
+			simpleConfig("default", "the-config",
+				WithConfigAnn("serving.knative.dev/routes", "rule")),
+			rev("default", "the-config",
+				WithRevisionAnn("serving.knative.dev/routes", "rule"),
+				WithRoutingState(v1.RoutingStateActive, clock),
+				WithRoutingStateModified(now.Time)),
+			rev("default", "the-config", WithRevName("the-revision")),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchAddRouteAndServingStateLabel(
+				"default", "the-revision", "rule", now.Time),
+		},
+		Key: "default/rule",
 	}, {
 		Name: "no ready revision",
 		Objects: []runtime.Object{
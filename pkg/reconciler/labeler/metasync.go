@@ -36,9 +36,19 @@ func syncRoutingMeta(ctx context.Context, r *v1.Route, cacc *configurationAccess
 	revisions := sets.New[string]()
 	configs := sets.New[string]()
 
-	// Walk the Route's .status.traffic and .spec.traffic and build a list
-	// of revisions and configurations to label
-	for _, tt := range append(r.Status.Traffic, r.Spec.Traffic...) {
+	// Walk the Route's .status.traffic, .spec.traffic and .spec.rules and
+	// build a list of revisions and configurations to label
+	targets := make([]v1.TrafficTarget, 0,
+		len(r.Status.Traffic)+len(r.Spec.Traffic)+len(r.Spec.Rules))
+	targets = append(append(targets, r.Status.Traffic...), r.Spec.Traffic...)
+	for _, rr := range r.Spec.Rules {
+		// The revisions of the rules receive traffic too. Those of the tags of
+		// the rules are traffic targets already.
+		if rr.RevisionName != "" {
+			targets = append(targets, v1.TrafficTarget{RevisionName: rr.RevisionName})
+		}
+	}
+	for _, tt := range targets {
 		revName := tt.RevisionName
 		configName := tt.ConfigurationName
 
@@ -180,6 +180,30 @@ var (
 		}, {
 			Name:  "METRICS_COLLECTOR_ADDRESS",
 			Value: "",
+		}, {
+			Name:  "SERVING_CUSTOM_METRIC_NAME",
+			Value: "",
+		}, {
+			Name:  "SERVING_CUSTOM_METRIC_URL",
+			Value: "",
+		}, {
+			Name:  "QUEUE_PRIORITY_HEADER",
+			Value: "",
+		}, {
+			Name:  "QUEUE_PRIORITY_CLASSES",
+			Value: "",
+		}, {
+			Name:  "QUEUE_PRIORITY_QUEUEING",
+			Value: "",
+		}, {
+			Name:  "QUEUE_DEADLINE",
+			Value: "0s",
+		}, {
+			Name:  "QUEUE_SHEDDING_TARGET",
+			Value: "0s",
+		}, {
+			Name:  "QUEUE_SHEDDING_INTERVAL",
+			Value: "0s",
 		}, {
 			Name: "HOST_IP",
 			ValueFrom: &corev1.EnvVarSource{
@@ -17,12 +17,15 @@ limitations under the License.
 package resources
 
 import (
+	"strconv"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmeta"
 	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
+	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/reconciler/revision/resources/names"
 )
@@ -46,10 +49,22 @@ func MakePA(rev *v1.Revision) *autoscalingv1alpha1.PodAutoscaler {
 			},
 			ProtocolType: rev.GetProtocol(),
 			Reachability: reachability(rev),
+			WarmScale:    warmScale(rev),
 		},
 	}
 }
 
+// warmScale returns the warm scale the Route controller set on the revision,
+// or 0 if none or invalid.
+func warmScale(rev *v1.Revision) int32 {
+	if _, s, ok := serving.WarmScaleAnnotation.Get(rev.Annotations); ok {
+		if ws, err := strconv.ParseInt(s, 10, 32); err == nil && ws > 0 {
+			return int32(ws)
+		}
+	}
+	return 0
+}
+
 func reachability(rev *v1.Revision) autoscalingv1alpha1.ReachabilityType {
 	// check infra failures
 	conds := []apis.ConditionType{
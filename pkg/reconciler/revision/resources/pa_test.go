@@ -364,6 +364,53 @@ func TestMakePA(t *testing.T) {
 				Reachability: autoscalingv1alpha1.ReachabilityUnreachable,
 			},
 		},
+	}, {
+		name: "warm scale",
+		rev: &v1.Revision{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "green",
+				UID:       "2468",
+				Labels: map[string]string{
+					serving.RoutingStateLabelKey: string(v1.RoutingStateActive),
+				},
+				Annotations: map[string]string{
+					serving.WarmScaleAnnotationKey: "3",
+				},
+			},
+		},
+		want: &autoscalingv1alpha1.PodAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "green",
+				Labels: map[string]string{
+					serving.RevisionLabelKey: "green",
+					serving.RevisionUID:      "2468",
+					AppLabelKey:              "green",
+				},
+				Annotations: map[string]string{
+					serving.WarmScaleAnnotationKey: "3",
+				},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion:         v1.SchemeGroupVersion.String(),
+					Kind:               "Revision",
+					Name:               "green",
+					UID:                "2468",
+					Controller:         ptr.Bool(true),
+					BlockOwnerDeletion: ptr.Bool(true),
+				}},
+			},
+			Spec: autoscalingv1alpha1.PodAutoscalerSpec{
+				ScaleTargetRef: corev1.ObjectReference{
+					APIVersion: "apps/v1",
+					Kind:       "Deployment",
+					Name:       "green-deployment",
+				},
+				ProtocolType: networking.ProtocolHTTP1,
+				Reachability: autoscalingv1alpha1.ReachabilityReachable,
+				WarmScale:    3,
+			},
+		},
 	}}
 
 	for _, test := range tests {
@@ -19,8 +19,10 @@ package resources
 import (
 	"fmt"
 	"math"
+	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -33,6 +35,7 @@ import (
 	"knative.dev/pkg/profiling"
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -216,12 +219,33 @@ func resourceFromAnnotation(m map[string]string, key kmap.KeyPriority) (resource
 	return q, err == nil
 }
 
+func durationFromAnnotation(m map[string]string, key kmap.KeyPriority) time.Duration {
+	_, v, _ := key.Get(m)
+	d, _ := time.ParseDuration(v)
+	return d
+}
+
 func fractionFromPercentage(m map[string]string, key kmap.KeyPriority) (float64, bool) {
 	_, v, _ := key.Get(m)
 	value, err := strconv.ParseFloat(v, 64)
 	return value / 100, err == nil
 }
 
+// customMetric returns the name of the custom application metric the revision
+// scales on and the URL the queue proxy scrapes it from, if any.
+func customMetric(rev *v1.Revision, userPort int32) (name, url string) {
+	_, metric, _ := autoscaling.MetricAnnotation.Get(rev.Annotations)
+	_, path, ok := autoscaling.CustomMetricPathAnnotation.Get(rev.Annotations)
+	if !ok || !autoscaling.IsCustomMetric(metric) {
+		return "", ""
+	}
+	port := strconv.Itoa(int(userPort))
+	if _, p, ok := autoscaling.CustomMetricPortAnnotation.Get(rev.Annotations); ok {
+		port = p
+	}
+	return metric, "http://" + net.JoinHostPort(localAddress, port) + path
+}
+
 // makeQueueContainer creates the container spec for the queue sidecar.
 func makeQueueContainer(rev *v1.Revision, cfg *config.Config) (*corev1.Container, error) {
 	configName := ""
@@ -305,6 +329,9 @@ func makeQueueContainer(rev *v1.Revision, cfg *config.Config) (*corev1.Container
 	}
 
 	fullDuplexFeature, fullDuplexExists := rev.Annotations[apicfg.AllowHTTPFullDuplexFeatureKey]
+	customMetricName, customMetricURL := customMetric(rev, userPort)
+	_, priorityClasses, _ := serving.QueueSidecarPriorityClassesAnnotation.Get(rev.Annotations)
+	_, priorityQueueing, _ := serving.QueueSidecarPriorityQueueingAnnotation.Get(rev.Annotations)
 
 	useQPResourceDefaults := cfg.Features.QueueProxyResourceDefaults == apicfg.Enabled
 	c := &corev1.Container{
@@ -410,6 +437,30 @@ func makeQueueContainer(rev *v1.Revision, cfg *config.Config) (*corev1.Container
 		}, {
 			Name:  "METRICS_COLLECTOR_ADDRESS",
 			Value: cfg.Observability.MetricsCollectorAddress,
+		}, {
+			Name:  "SERVING_CUSTOM_METRIC_NAME",
+			Value: customMetricName,
+		}, {
+			Name:  "SERVING_CUSTOM_METRIC_URL",
+			Value: customMetricURL,
+		}, {
+			Name:  "QUEUE_PRIORITY_HEADER",
+			Value: cfg.Deployment.QueueSidecarPriorityHeader,
+		}, {
+			Name:  "QUEUE_PRIORITY_CLASSES",
+			Value: priorityClasses,
+		}, {
+			Name:  "QUEUE_PRIORITY_QUEUEING",
+			Value: priorityQueueing,
+		}, {
+			Name:  "QUEUE_DEADLINE",
+			Value: durationFromAnnotation(rev.Annotations, serving.QueueSidecarQueueDeadlineAnnotation).String(),
+		}, {
+			Name:  "QUEUE_SHEDDING_TARGET",
+			Value: durationFromAnnotation(rev.Annotations, serving.QueueSidecarSheddingTargetAnnotation).String(),
+		}, {
+			Name:  "QUEUE_SHEDDING_INTERVAL",
+			Value: durationFromAnnotation(rev.Annotations, serving.QueueSidecarSheddingIntervalAnnotation).String(),
 		}, {
 			Name: "HOST_IP",
 			ValueFrom: &corev1.EnvVarSource{
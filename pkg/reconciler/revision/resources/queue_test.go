@@ -41,6 +41,7 @@ import (
 	"knative.dev/pkg/ptr"
 	"knative.dev/pkg/system"
 	tracingconfig "knative.dev/pkg/tracing/config"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -400,6 +401,69 @@ func TestMakeQueueContainer(t *testing.T) {
 				"ENABLE_HTTP_FULL_DUPLEX": "true",
 			})
 		}),
+	}, {
+		name: "custom metric",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			WithRevisionAnnotations(map[string]string{
+				autoscaling.MetricAnnotationKey:           "queue_depth",
+				autoscaling.CustomMetricPathAnnotationKey: "/metrics",
+			})),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"SERVING_CUSTOM_METRIC_NAME": "queue_depth",
+				"SERVING_CUSTOM_METRIC_URL":  "http://127.0.0.1:8080/metrics",
+			})
+		}),
+	}, {
+		name: "custom metric on a separate port",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			WithRevisionAnnotations(map[string]string{
+				autoscaling.MetricAnnotationKey:           "queue_depth",
+				autoscaling.CustomMetricPathAnnotationKey: "/stats/prometheus",
+				autoscaling.CustomMetricPortAnnotationKey: "9000",
+			})),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"SERVING_CUSTOM_METRIC_NAME": "queue_depth",
+				"SERVING_CUSTOM_METRIC_URL":  "http://127.0.0.1:9000/stats/prometheus",
+			})
+		}),
+	}, {
+		name: "priority classes",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			WithRevisionAnnotations(map[string]string{
+				serving.QueueSidecarPriorityClassesAnnotationKey:  "high=3/10,low=1/50",
+				serving.QueueSidecarPriorityQueueingAnnotationKey: serving.QueueingStrict,
+			})),
+		dc: deployment.Config{
+			QueueSidecarPriorityHeader: "X-Priority",
+		},
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"QUEUE_PRIORITY_HEADER":   "X-Priority",
+				"QUEUE_PRIORITY_CLASSES":  "high=3/10,low=1/50",
+				"QUEUE_PRIORITY_QUEUEING": "strict",
+			})
+		}),
+	}, {
+		name: "queue deadline and shedding",
+		rev: revision("bar", "foo",
+			withContainers(containers),
+			WithRevisionAnnotations(map[string]string{
+				serving.QueueSidecarQueueDeadlineAnnotationKey:    "5s",
+				serving.QueueSidecarSheddingTargetAnnotationKey:   "50ms",
+				serving.QueueSidecarSheddingIntervalAnnotationKey: "1s",
+			})),
+		want: queueContainer(func(c *corev1.Container) {
+			c.Env = env(map[string]string{
+				"QUEUE_DEADLINE":          "5s",
+				"QUEUE_SHEDDING_TARGET":   "50ms",
+				"QUEUE_SHEDDING_INTERVAL": "1s",
+			})
+		}),
 	}, {
 		name: "set root ca",
 		rev: revision("bar", "foo",
@@ -1061,6 +1125,14 @@ var defaultEnv = map[string]string{
 	"REVISION_RESPONSE_START_TIMEOUT_SECONDS":          "0",
 	"REVISION_IDLE_TIMEOUT_SECONDS":                    "0",
 	"SERVING_CONFIGURATION":                            "",
+	"SERVING_CUSTOM_METRIC_NAME":                       "",
+	"SERVING_CUSTOM_METRIC_URL":                        "",
+	"QUEUE_PRIORITY_HEADER":                            "",
+	"QUEUE_PRIORITY_CLASSES":                           "",
+	"QUEUE_PRIORITY_QUEUEING":                          "",
+	"QUEUE_DEADLINE":                                   "0s",
+	"QUEUE_SHEDDING_TARGET":                            "0s",
+	"QUEUE_SHEDDING_INTERVAL":                          "0s",
 	"SERVING_ENABLE_PROBE_REQUEST_LOG":                 "false",
 	"SERVING_ENABLE_REQUEST_LOG":                       "false",
 	"SERVING_LOGGING_CONFIG":                           "",
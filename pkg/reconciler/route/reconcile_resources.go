@@ -19,7 +19,11 @@ package route
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -32,12 +36,14 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"knative.dev/networking/pkg/apis/networking"
 	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	"knative.dev/serving/pkg/reconciler/route/config"
@@ -46,38 +52,50 @@ import (
 	"knative.dev/serving/pkg/reconciler/route/traffic"
 )
 
+// rollout returns the rollout of the traffic of the Route, stepped from its
+// previous state as recorded on the Ingress. Without an Ingress yet there is
+// no previous state and the traffic is routed as configured.
+func (c *Reconciler) rollout(ctx context.Context, r *v1.Route, tc *traffic.Config) (*traffic.Rollout, error) {
+	ingress, err := c.ingressLister.Ingresses(r.Namespace).Get(names.Ingress(r))
+	if apierrs.IsNotFound(err) {
+		return tc.BuildRollout(), nil
+	} else if err != nil {
+		return nil, err
+	}
+	// Ingress exists. We need to compute the rollout spec diff.
+	return c.reconcileRollout(ctx, r, tc, ingress), nil
+}
+
 func (c *Reconciler) reconcileIngress(
 	ctx context.Context, r *v1.Route, tc *traffic.Config,
+	effectiveRO *traffic.Rollout,
 	tls []netv1alpha1.IngressTLS,
 	ingressClass string,
 	acmeChallenges ...netv1alpha1.HTTP01Challenge,
-) (*netv1alpha1.Ingress, *traffic.Rollout, error) {
+) (*netv1alpha1.Ingress, error) {
 	recorder := controller.GetEventRecorder(ctx)
-	var effectiveRO *traffic.Rollout
 
 	ingress, err := c.ingressLister.Ingresses(r.Namespace).Get(names.Ingress(r))
 	if apierrs.IsNotFound(err) {
-		desired, err := resources.MakeIngress(ctx, r, tc, tls, ingressClass, acmeChallenges...)
+		desired, err := resources.MakeIngressWithRollout(ctx, r, tc, effectiveRO, tls, ingressClass, acmeChallenges...)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		ingress, err = c.netclient.NetworkingV1alpha1().Ingresses(desired.Namespace).Create(ctx, desired, metav1.CreateOptions{})
 		if err != nil {
 			recorder.Eventf(r, corev1.EventTypeWarning, "CreationFailed", "Failed to create Ingress: %v", err)
-			return nil, nil, fmt.Errorf("failed to create Ingress: %w", err)
+			return nil, fmt.Errorf("failed to create Ingress: %w", err)
 		}
 
 		recorder.Eventf(r, corev1.EventTypeNormal, "Created", "Created Ingress %q", ingress.GetName())
-		return ingress, tc.BuildRollout(), nil
+		return ingress, nil
 	} else if err != nil {
-		return nil, nil, err
+		return nil, err
 	} else {
-		// Ingress exists. We need to compute the rollout spec diff.
-		effectiveRO = c.reconcileRollout(ctx, r, tc, ingress)
 		desired, err := resources.MakeIngressWithRollout(ctx, r, tc, effectiveRO,
 			tls, ingressClass, acmeChallenges...)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 		if !equality.Semantic.DeepEqual(ingress.Spec, desired.Spec) ||
@@ -99,13 +117,13 @@ func (c *Reconciler) reconcileIngress(
 			updated, err := c.netclient.NetworkingV1alpha1().Ingresses(origin.Namespace).Update(
 				ctx, origin, metav1.UpdateOptions{})
 			if err != nil {
-				return nil, nil, fmt.Errorf("failed to update Ingress: %w", err)
+				return nil, fmt.Errorf("failed to update Ingress: %w", err)
 			}
-			return updated, effectiveRO, nil
+			return updated, nil
 		}
 	}
 
-	return ingress, effectiveRO, err
+	return ingress, err
 }
 
 func (c *Reconciler) deleteOrphanedServices(ctx context.Context, r *v1.Route, activeServices []resources.ServicePair) error {
@@ -339,8 +357,15 @@ func (c *Reconciler) reconcileRollout(
 		rd = cfg.Network.RolloutDurationSecs
 	}
 	curRO := tc.BuildRollout()
-	// When rollout is disabled just create the baseline annotation.
-	if rd <= 0 {
+	blueGreen := r.RolloutStrategy() == serving.RolloutStrategyBlueGreen
+	if blueGreen {
+		curRO.UseBlueGreen()
+	}
+	// When rollout is disabled just create the baseline annotation,
+	// unless some configurations roll out per their custom schedule,
+	// or the new revisions wait to be promoted.
+	if rd <= 0 && !curRO.HasSteps() && !blueGreen {
+		r.Status.ClearRolloutAnalysis()
 		return curRO
 	}
 	// Get the current rollout state as described by the traffic.
@@ -364,7 +389,29 @@ func (c *Reconciler) reconcileRollout(
 		prevRO.ObserveReady(ctx, now, float64(rd))
 	}
 
+	control := r.RolloutControl()
+	if prevRO != nil {
+		if control == serving.RolloutControlPause {
+			prevRO.Pause(now)
+		} else {
+			prevRO.Resume(now)
+		}
+	}
+	// Check the new revisions before they get more traffic, unless
+	// the operator took over the rollout, or they get all of it at once.
+	if control == serving.RolloutControlResume && !blueGreen {
+		c.analyzeRollout(ctx, r, prevRO, now)
+	}
+
 	effectiveRO, nextStepTime := curRO.Step(ctx, prevRO, now)
+	switch control {
+	case serving.RolloutControlPause:
+		// Also pause the rollouts that just started.
+		effectiveRO.Pause(now)
+	case serving.RolloutControlPromote:
+		effectiveRO.Promote(now, r.BlueGreenRollbackWindow())
+		nextStepTime = effectiveRO.KeepWarmUntil()
+	}
 	if nextStepTime > 0 {
 		nextStepTime -= now
 		c.enqueueAfter(r, time.Duration(nextStepTime))
@@ -380,3 +427,176 @@ func (c *Reconciler) reconcileRollout(
 	}
 	return effectiveRO
 }
+
+// reconcileCutovers exposes the revisions the blue/green rollouts keep warm
+// under tags of their own, and sets the warm scale annotation on them, to keep
+// them at the scale of the revisions that serve the traffic.
+//
+// This makes the Route controller a writer of the metadata of the Revisions,
+// next to the labeler. Several Routes may keep the same revision warm, so each
+// Route only sets its own entry of the warm scale routes annotation, and the
+// warm scale annotation is the largest scale of the entries left. The patches
+// are conditional on the resource version of the revision, so that the
+// concurrent updates of the Routes don't lose each other's entries.
+func (c *Reconciler) reconcileCutovers(ctx context.Context, r *v1.Route, tc *traffic.Config, ro *traffic.Rollout) error {
+	warm := ro.WarmRevisions(c.clock.Now().UnixNano())
+	if len(warm) > 0 {
+		for _, name := range sets.List(sets.KeySet(warm)) {
+			rev, err := c.revisionLister.Revisions(r.Namespace).Get(name)
+			if apierrs.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if _, ok := tc.Targets[warm[name].Tag]; !ok {
+				tc.AddRevisionTag(warm[name].Tag, rev)
+			}
+		}
+		var err error
+		if r.Status.Traffic, err = tc.GetRevisionTrafficTargets(ctx, r, ro); err != nil {
+			return err
+		}
+	}
+
+	for _, cfg := range tc.Configurations {
+		revs, err := c.revisionLister.Revisions(r.Namespace).List(labels.SelectorFromSet(labels.Set{
+			serving.ConfigurationLabelKey: cfg.Name,
+		}))
+		if err != nil {
+			return err
+		}
+		sort.Slice(revs, func(i, j int) bool { return revs[i].Name < revs[j].Name })
+		for _, rev := range revs {
+			scales := parseWarmScales(rev.Annotations[serving.WarmScaleRoutesAnnotationKey])
+			if w, warmed := warm[rev.Name]; warmed {
+				scales[r.Name] = c.warmScale(r.Namespace, w.ScaleOf)
+			} else if _, ok := scales[r.Name]; ok {
+				delete(scales, r.Name)
+			} else {
+				continue
+			}
+			var value, routes string
+			if len(scales) > 0 {
+				value, routes = formatWarmScales(scales)
+			}
+			if value == rev.Annotations[serving.WarmScaleAnnotationKey] &&
+				routes == rev.Annotations[serving.WarmScaleRoutesAnnotationKey] {
+				continue
+			}
+			// Nil values remove the annotations.
+			annotations := map[string]interface{}{
+				serving.WarmScaleAnnotationKey:       nil,
+				serving.WarmScaleRoutesAnnotationKey: nil,
+			}
+			if len(scales) > 0 {
+				annotations[serving.WarmScaleAnnotationKey] = value
+				annotations[serving.WarmScaleRoutesAnnotationKey] = routes
+			}
+			patch, err := json.Marshal(map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"resourceVersion": rev.ResourceVersion,
+					"annotations":     annotations,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := c.client.ServingV1().Revisions(r.Namespace).Patch(
+				ctx, rev.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+				return fmt.Errorf("failed to update the warm scale of Revision %q: %w", rev.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// parseWarmScales parses the route=scale entries of the warm scale routes
+// annotation, skipping the malformed ones.
+func parseWarmScales(s string) map[string]int32 {
+	scales := make(map[string]int32)
+	for _, entry := range strings.Split(s, ",") {
+		route, scale, ok := strings.Cut(entry, "=")
+		if !ok || route == "" {
+			continue
+		}
+		if n, err := strconv.ParseInt(scale, 10, 32); err == nil && n > 0 {
+			scales[route] = int32(n)
+		}
+	}
+	return scales
+}
+
+// formatWarmScales returns the largest of the scales, and the route=scale
+// entries sorted by route, for the warm scale annotations.
+func formatWarmScales(scales map[string]int32) (string, string) {
+	var largest int32
+	entries := make([]string, 0, len(scales))
+	for _, route := range sets.List(sets.KeySet(scales)) {
+		largest = max(largest, scales[route])
+		entries = append(entries, route+"="+strconv.Itoa(int(scales[route])))
+	}
+	return strconv.Itoa(int(largest)), strings.Join(entries, ",")
+}
+
+// warmScale returns the scale of the revision, as reported by its
+// PodAutoscaler, and at least 1.
+func (c *Reconciler) warmScale(ns, rev string) int32 {
+	// The PodAutoscaler of a revision has the name of the revision.
+	pa, err := c.paLister.PodAutoscalers(ns).Get(rev)
+	if err != nil || pa.Status.ActualScale == nil || *pa.Status.ActualScale < 1 {
+		return 1
+	}
+	return *pa.Status.ActualScale
+}
+
+// analyzeRollout checks that the new revisions of the rollouts that are due
+// for their next step meet the SLOs of the Route, and pauses or rolls back
+// the ones that don't. The result is reflected in the status of the Route.
+func (c *Reconciler) analyzeRollout(ctx context.Context, r *v1.Route, ro *traffic.Rollout, nowTS int64) {
+	minSuccess, maxLatency := r.RolloutMinSuccessPercentage(), r.RolloutMaxLatency()
+	if minSuccess == 0 && maxLatency == 0 {
+		r.Status.ClearRolloutAnalysis()
+		return
+	}
+	if ro == nil {
+		return
+	}
+
+	analyzed := false
+	rollback := r.RolloutAnalysisAction() == serving.RolloutAnalysisActionRollback
+	err := ro.Analyze(ctx, nowTS, rollback, func(rev string) error {
+		analyzed = true
+		// The PodAutoscaler of a revision has the name of the revision.
+		pa, err := c.paLister.PodAutoscalers(r.Namespace).Get(rev)
+		if err != nil || pa.Status.ResponseStats == nil {
+			// The revision might not have got any traffic yet.
+			return fmt.Errorf("revision %q has no response stats yet: %w", rev, traffic.ErrNoAnalysisData)
+		}
+		return checkResponseStats(rev, pa.Status.ResponseStats, minSuccess, maxLatency)
+	})
+	switch {
+	case errors.Is(err, traffic.ErrNoAnalysisData):
+		r.Status.MarkRolloutAnalysisPending(err.Error())
+	case err != nil && rollback:
+		r.Status.MarkRolledBack(err.Error())
+	case err != nil:
+		r.Status.MarkRolloutPaused(err.Error())
+	case analyzed:
+		r.Status.MarkRolloutAnalysisSucceeded()
+	}
+}
+
+// checkResponseStats returns an error if the response stats of the revision
+// don't meet the minimum success percentage or the maximum latency, when set.
+func checkResponseStats(rev string, rs *autoscalingv1alpha1.ResponseStats, minSuccess float64, maxLatency time.Duration) error {
+	if minSuccess > 0 && rs.ResponseCount > 0 {
+		success := 100 * float64(rs.ResponseCount-rs.ServerErrorCount) / float64(rs.ResponseCount)
+		if success < minSuccess {
+			return fmt.Errorf("revision %q has a success rate of %.2f%%, below %g%%", rev, success, minSuccess)
+		}
+	}
+	if maxLatency > 0 && rs.MeanLatency.Duration > maxLatency {
+		return fmt.Errorf("revision %q has a mean latency of %v, above %v", rev, rs.MeanLatency.Duration, maxLatency)
+	}
+	return nil
+}
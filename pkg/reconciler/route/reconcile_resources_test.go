@@ -53,7 +53,7 @@ func TestReconcileIngressInsert(t *testing.T) {
 
 	r := Route("test-ns", "test-route")
 	tc, tls := testIngressParams(t, r)
-	_, ro, err := reconciler.reconcileIngress(updateContext(ctx, 0), r, tc, tls, "foo-ingress")
+	_, ro, err := reconcileIngressWithRollout(updateContext(ctx, 0), reconciler, r, tc, tls, "foo-ingress")
 	if err != nil {
 		t.Error("Unexpected error:", err)
 	}
@@ -110,7 +110,7 @@ func TestReconcileIngressUpdateReenqueueRollout(t *testing.T) {
 				duration = d
 			}
 			ctx := updateContext(baseCtx, rd)
-			_, ro, err := reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress-class")
+			_, ro, err := reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress-class")
 			if err != nil {
 				t.Fatal("Unexpected error:", err)
 			}
@@ -125,7 +125,7 @@ func TestReconcileIngressUpdateReenqueueRollout(t *testing.T) {
 			// Now we have initial version. Let's make a rollout.
 			tc.Targets[traffic.DefaultTarget][1].RevisionName = "miercoles"
 
-			_, updRO, err := reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress-class")
+			_, updRO, err := reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress-class")
 			if err != nil {
 				t.Error("Unexpected error:", err)
 			}
@@ -175,7 +175,7 @@ func TestReconcileIngressUpdateReenqueueRollout(t *testing.T) {
 			stepSize := math.Max(1, math.Round((allocatedTraffic-1)/steps)) // we round the step size.
 			stepDuration := time.Duration(int(totalDuration * float64(time.Second) / steps))
 
-			_, updRO, err = reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress")
+			_, updRO, err = reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress")
 			if err != nil {
 				t.Error("Unexpected error:", err)
 			}
@@ -272,7 +272,7 @@ func TestReconcileIngressUpdateReenqueueRolloutAnnotation(t *testing.T) {
 				duration = d
 			}
 			ctx := updateContext(baseCtx, 311 /*rolloutDuration*/) // This should be ignored, since we set annotation.
-			_, ro, err := reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress-class")
+			_, ro, err := reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress-class")
 			if err != nil {
 				t.Fatal("Unexpected error:", err)
 			}
@@ -287,7 +287,7 @@ func TestReconcileIngressUpdateReenqueueRolloutAnnotation(t *testing.T) {
 			// Now we have initial version. Let's make a rollout.
 			tc.Targets[traffic.DefaultTarget][1].RevisionName = "miercoles"
 
-			_, updRO, err := reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress-class")
+			_, updRO, err := reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress-class")
 			if err != nil {
 				t.Error("Unexpected error:", err)
 			}
@@ -337,7 +337,7 @@ func TestReconcileIngressUpdateReenqueueRolloutAnnotation(t *testing.T) {
 			stepSize := math.Max(1, math.Round((allocatedTraffic-1)/steps)) // we round the step size.
 			stepDuration := time.Duration(int(totalDuration * float64(time.Second) / steps))
 
-			_, updRO, err = reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress")
+			_, updRO, err = reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress")
 			if err != nil {
 				t.Error("Unexpected error:", err)
 			}
@@ -394,7 +394,7 @@ func TestReconcileIngressUpdateNoRollout(t *testing.T) {
 	r := Route("test-ns", "test-route")
 
 	tc, tls := testIngressParams(t, r)
-	if _, _, err := reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress"); err != nil {
+	if _, _, err := reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress"); err != nil {
 		t.Error("Unexpected error:", err)
 	}
 
@@ -404,7 +404,7 @@ func TestReconcileIngressUpdateNoRollout(t *testing.T) {
 	tc, tls = testIngressParams(t, r, func(tc *traffic.Config) {
 		tc.Targets[traffic.DefaultTarget][0].RevisionName = "revision2"
 	})
-	if _, _, err := reconciler.reconcileIngress(ctx, r, tc, tls, "foo-ingress"); err != nil {
+	if _, _, err := reconcileIngressWithRollout(ctx, reconciler, r, tc, tls, "foo-ingress"); err != nil {
 		t.Error("Unexpected error:", err)
 	}
 
@@ -441,7 +441,7 @@ func TestReconcileIngressUpdate(t *testing.T) {
 	r := Route("test-ns", "test-route")
 
 	tc, tls := testIngressParams(t, r)
-	if _, _, err := reconciler.reconcileIngress(updateContext(ctx, 0), r, tc, tls, "foo-ingress"); err != nil {
+	if _, _, err := reconcileIngressWithRollout(updateContext(ctx, 0), reconciler, r, tc, tls, "foo-ingress"); err != nil {
 		t.Error("Unexpected error:", err)
 	}
 
@@ -457,7 +457,7 @@ func TestReconcileIngressUpdate(t *testing.T) {
 			},
 		})
 	})
-	if _, _, err := reconciler.reconcileIngress(updateContext(ctx, 0), r, tc, tls, "foo-ingress"); err != nil {
+	if _, _, err := reconcileIngressWithRollout(updateContext(ctx, 0), reconciler, r, tc, tls, "foo-ingress"); err != nil {
 		t.Error("Unexpected error:", err)
 	}
 
@@ -511,7 +511,7 @@ func TestReconcileIngressRolloutDeserializeFail(t *testing.T) {
 			ingClient := fakenetworkingclient.Get(ctx).NetworkingV1alpha1().Ingresses(ing.Namespace)
 			ingClient.Create(ctx, ing, metav1.CreateOptions{})
 			fakeingressinformer.Get(ctx).Informer().GetIndexer().Add(ing)
-			if _, _, err := reconciler.reconcileIngress(ctx, r, traffic, tls, "foo-ingress"); err != nil {
+			if _, _, err := reconcileIngressWithRollout(ctx, reconciler, r, traffic, tls, "foo-ingress"); err != nil {
 				t.Error("Unexpected error:", err)
 			}
 			ing, err = ingClient.Get(ctx, ing.Name, metav1.GetOptions{})
@@ -596,14 +596,14 @@ func TestReconcileIngressClassAnnotation(t *testing.T) {
 
 	r := Route("test-ns", "test-route")
 	tc, tls := testIngressParams(t, r)
-	if _, _, err := reconciler.reconcileIngress(updateContext(ctx, 0), r, tc, tls, "foo-ingress"); err != nil {
+	if _, _, err := reconcileIngressWithRollout(updateContext(ctx, 0), reconciler, r, tc, tls, "foo-ingress"); err != nil {
 		t.Error("Unexpected error:", err)
 	}
 
 	updated := getRouteIngressFromClient(ctx, t, r)
 	fakeingressinformer.Get(ctx).Informer().GetIndexer().Add(updated)
 
-	if _, _, err := reconciler.reconcileIngress(updateContext(ctx, 0), r, tc, tls, expClass); err != nil {
+	if _, _, err := reconcileIngressWithRollout(updateContext(ctx, 0), reconciler, r, tc, tls, expClass); err != nil {
 		t.Error("Unexpected error:", err)
 	}
 
@@ -614,6 +614,18 @@ func TestReconcileIngressClassAnnotation(t *testing.T) {
 	}
 }
 
+// reconcileIngressWithRollout steps the rollout of the route and reconciles
+// its ingress, like ReconcileKind does.
+func reconcileIngressWithRollout(ctx context.Context, c *Reconciler, r *v1.Route, tc *traffic.Config,
+	tls []netv1alpha1.IngressTLS, ingressClass string) (*netv1alpha1.Ingress, *traffic.Rollout, error) {
+	ro, err := c.rollout(ctx, r, tc)
+	if err != nil {
+		return nil, nil, err
+	}
+	ingress, err := c.reconcileIngress(ctx, r, tc, ro, tls, ingressClass)
+	return ingress, ro, err
+}
+
 func updateContext(ctx context.Context, rolloutDurationSecs int) context.Context {
 	cfg := reconcilerTestConfig()
 	cfg.Network.RolloutDurationSecs = rolloutDurationSecs
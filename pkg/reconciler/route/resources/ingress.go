@@ -19,6 +19,7 @@ package resources
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"sort"
 
 	"github.com/davecgh/go-spew/spew"
@@ -85,6 +86,10 @@ func MakeIngressWithRollout(
 	if err != nil {
 		return nil, err
 	}
+	annotations := map[string]string{
+		networking.IngressClassAnnotationKey: ingressClass,
+		networking.RolloutAnnotationKey:      serializeRollout(ctx, ro),
+	}
 	return &netv1alpha1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      names.Ingress(r),
@@ -93,10 +98,8 @@ func MakeIngressWithRollout(
 				serving.RouteLabelKey:          r.Name,
 				serving.RouteNamespaceLabelKey: r.Namespace,
 			}),
-			Annotations: kmeta.FilterMap(kmeta.UnionMaps(map[string]string{
-				networking.IngressClassAnnotationKey: ingressClass,
-				networking.RolloutAnnotationKey:      serializeRollout(ctx, ro),
-			}, r.GetAnnotations()), ExcludedAnnotations.Has),
+			Annotations: kmeta.FilterMap(kmeta.UnionMaps(annotations,
+				r.GetAnnotations()), ExcludedAnnotations.Has),
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(r)},
 		},
 		Spec: spec,
@@ -114,6 +117,59 @@ func serializeRollout(ctx context.Context, r *traffic.Rollout) string {
 	return string(sr)
 }
 
+// sessionAffinityKey returns the session key of the Route with session
+// affinity, or "" if the Route has none or the key is malformed.
+func sessionAffinityKey(r *servingv1.Route) string {
+	key := serving.SessionAffinityAnnotation.Value(r.Annotations)
+	if _, _, err := servingv1.ParseHashKey(key); err != nil {
+		return ""
+	}
+	return key
+}
+
+// appendSessionAffinityHeader appends the session key of the Route to the
+// requests of every split of the paths, for the revisions to return their
+// names to the sessions.
+func appendSessionAffinityHeader(paths []netv1alpha1.HTTPIngressPath, key string) {
+	for i := range paths {
+		for j := range paths[i].Splits {
+			split := &paths[i].Splits[j]
+			if split.AppendHeaders == nil {
+				split.AppendHeaders = make(map[string]string, 1)
+			}
+			split.AppendHeaders[servingnetworking.SessionAffinityHeaderName] = key
+		}
+	}
+}
+
+// makeSessionIngressPaths builds the ingress paths that route the requests
+// with the session revision header to the revision of the split of the path
+// that it names, so that the sessions stick to the revision that served them
+// first instead of being split again.
+func makeSessionIngressPaths(path *netv1alpha1.HTTPIngressPath) []netv1alpha1.HTTPIngressPath {
+	if len(path.Splits) < 2 {
+		return nil
+	}
+	paths := make([]netv1alpha1.HTTPIngressPath, 0, len(path.Splits))
+	seen := sets.New[string]()
+	for _, split := range path.Splits {
+		if seen.Has(split.ServiceName) {
+			continue
+		}
+		seen.Insert(split.ServiceName)
+		split.Percent = 100
+		split.AppendHeaders = maps.Clone(split.AppendHeaders)
+		paths = append(paths, netv1alpha1.HTTPIngressPath{
+			Headers: map[string]netv1alpha1.HeaderMatch{
+				servingnetworking.SessionRevisionHeaderName: {Exact: split.ServiceName},
+			},
+			AppendHeaders: maps.Clone(path.AppendHeaders),
+			Splits:        []netv1alpha1.IngressBackendSplit{split},
+		})
+	}
+	return paths
+}
+
 // makeIngressSpec builds a new IngressSpec from inputs.
 func makeIngressSpec(
 	ctx context.Context,
@@ -136,6 +192,7 @@ func makeIngressSpec(
 
 	featuresConfig := config.FromContextOrDefaults(ctx).Features
 	networkConfig := config.FromContextOrDefaults(ctx).Network
+	sessionKey := sessionAffinityKey(r)
 
 	for _, name := range names {
 		visibilities := []netv1alpha1.IngressVisibility{netv1alpha1.IngressVisibilityClusterLocal}
@@ -183,6 +240,23 @@ func makeIngressSpec(
 					rule.HTTP.Paths[0].AppendHeaders[netheader.RouteTagKey] = name
 				}
 			}
+			if sessionKey != "" {
+				// The split path is the last one, and the sessions pinned to
+				// one of its revisions skip it.
+				last := len(rule.HTTP.Paths) - 1
+				split := rule.HTTP.Paths[last]
+				rule.HTTP.Paths = append(append(rule.HTTP.Paths[:last:last],
+					makeSessionIngressPaths(&split)...), split)
+			}
+			if name == traffic.DefaultTarget && featuresConfig.RouteRules == apicfg.Enabled {
+				// The rules take precedence over the traffic split, so their
+				// paths come first.
+				rule.HTTP.Paths = append(
+					makeRuleIngressPaths(r.Namespace, tc, ro, networkConfig.SystemInternalTLSEnabled()), rule.HTTP.Paths...)
+			}
+			if sessionKey != "" {
+				appendSessionAffinityHeader(rule.HTTP.Paths, sessionKey)
+			}
 			// If this is a public rule, we need to configure ACME challenge paths.
 			if visibility == netv1alpha1.IngressVisibilityExternalIP {
 				paths, hosts := MakeACMEIngressPaths(acmeChallenges, domains)
@@ -281,6 +355,45 @@ func makeTagBasedRoutingIngressPaths(ns string, tc *traffic.Config, ro *traffic.
 	return paths
 }
 
+// makeRuleIngressPaths builds the ingress paths of the rules, which match the
+// exact header matches of the rules. The rules with no valid condition are
+// skipped, as their paths would match more requests than the rules do.
+func makeRuleIngressPaths(ns string, tc *traffic.Config, ro *traffic.Rollout, encryption bool) []netv1alpha1.HTTPIngressPath {
+	paths := make([]netv1alpha1.HTTPIngressPath, 0, len(tc.Rules))
+
+	for _, rule := range tc.Rules {
+		headers := makeRuleHeaderMatches(rule.RouteRule)
+		if headers == nil {
+			continue
+		}
+		var roCfgs []*traffic.ConfigurationRollout
+		if rule.Tag != "" {
+			roCfgs = ro.RolloutsByTag(rule.Tag)
+		}
+		path := makeBaseIngressPath(ns, rule.Targets, roCfgs, encryption)
+		path.Headers = headers
+		paths = append(paths, *path)
+	}
+
+	return paths
+}
+
+// makeRuleHeaderMatches builds the header matches of the ingress path of the
+// rule, or returns nil if the rule has no valid conditions.
+func makeRuleHeaderMatches(rule servingv1.RouteRule) map[string]netv1alpha1.HeaderMatch {
+	if len(rule.Headers) == 0 {
+		return nil
+	}
+	headers := make(map[string]netv1alpha1.HeaderMatch, len(rule.Headers))
+	for name, h := range rule.Headers {
+		if h.Exact == "" {
+			return nil
+		}
+		headers[name] = netv1alpha1.HeaderMatch{Exact: h.Exact}
+	}
+	return headers
+}
+
 func rolloutConfig(cfgName string, ros []*traffic.ConfigurationRollout) *traffic.ConfigurationRollout {
 	idx := sort.Search(len(ros), func(i int) bool {
 		return ros[i].ConfigurationName >= cfgName
@@ -311,7 +424,10 @@ func makeBaseIngressPath(ns string, targets traffic.RevisionTargets,
 		} else {
 			servicePort = intstr.FromInt(networking.ServicePort(t.Protocol))
 		}
-		if cfg == nil || len(cfg.Revisions) < 2 {
+		// The previous revisions of a blue/green rollout keep the traffic
+		// while its green revision waits to be promoted.
+		cutover := cfg != nil && cfg.Cutover != nil && cfg.Cutover.Green != "" && len(cfg.Revisions) > 0
+		if cfg == nil || len(cfg.Revisions) < 2 && !cutover {
 			// No rollout in progress.
 			splits = append(splits, netv1alpha1.IngressBackendSplit{
 				IngressBackend: netv1alpha1.IngressBackend{
@@ -40,6 +40,7 @@ import (
 	apicfg "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
+	servingnetworking "knative.dev/serving/pkg/networking"
 	"knative.dev/serving/pkg/reconciler/route/config"
 	"knative.dev/serving/pkg/reconciler/route/traffic"
 
@@ -475,6 +476,225 @@ func TestIngressNoKubectlAnnotation(t *testing.T) {
 	}
 }
 
+func TestMakeIngressWithRules(t *testing.T) {
+	r := Route(ns, testRouteName, WithRouteUID("1234-5678"), WithURL)
+	tc := &traffic.Config{
+		Targets: map[string]traffic.RevisionTargets{
+			traffic.DefaultTarget: {{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: "config",
+					RevisionName:      "v1",
+					Percent:           ptr.Int64(100),
+				},
+			}},
+		},
+		Rules: []traffic.RuleTarget{{
+			RouteRule: v1.RouteRule{
+				Headers:      map[string]v1.StringMatch{"X-Cohort": {Exact: "beta"}},
+				RevisionName: "v2",
+			},
+			Targets: traffic.RevisionTargets{{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: "config",
+					RevisionName:      "v2",
+					Percent:           ptr.Int64(100),
+				},
+			}},
+		}, {
+			// The paths of these rules would match more requests than the
+			// rules do, so they are skipped.
+			RouteRule: v1.RouteRule{
+				Headers: map[string]v1.StringMatch{
+					"X-Cohort": {Exact: "alpha"},
+					"X-User":   {},
+				},
+				RevisionName: "v2",
+			},
+			Targets: traffic.RevisionTargets{{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: "config",
+					RevisionName:      "v2",
+					Percent:           ptr.Int64(100),
+				},
+			}},
+		}, {
+			RouteRule: v1.RouteRule{
+				RevisionName: "v2",
+			},
+			Targets: traffic.RevisionTargets{{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: "config",
+					RevisionName:      "v2",
+					Percent:           ptr.Int64(100),
+				},
+			}},
+		}, {
+			RouteRule: v1.RouteRule{
+				Headers:      map[string]v1.StringMatch{"X-Cohort": {Exact: "gamma"}},
+				RevisionName: "v3",
+			},
+			Targets: traffic.RevisionTargets{{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: "config",
+					RevisionName:      "v3",
+					Percent:           ptr.Int64(100),
+				},
+			}},
+		}},
+	}
+
+	split := func(rev string) []netv1alpha1.IngressBackendSplit {
+		return []netv1alpha1.IngressBackendSplit{{
+			IngressBackend: netv1alpha1.IngressBackend{
+				ServiceNamespace: ns,
+				ServiceName:      rev,
+				ServicePort:      intstr.FromInt(80),
+			},
+			Percent: 100,
+			AppendHeaders: map[string]string{
+				"Knative-Serving-Revision":  rev,
+				"Knative-Serving-Namespace": ns,
+			},
+		}}
+	}
+	wantPaths := []netv1alpha1.HTTPIngressPath{{
+		Headers: map[string]netv1alpha1.HeaderMatch{"X-Cohort": {Exact: "beta"}},
+		Splits:  split("v2"),
+	}, {
+		Headers: map[string]netv1alpha1.HeaderMatch{"X-Cohort": {Exact: "gamma"}},
+		Splits:  split("v3"),
+	}, {
+		Splits: split("v1"),
+	}}
+
+	t.Run("enabled", func(t *testing.T) {
+		ctx := testContext()
+		config.FromContext(ctx).Features.RouteRules = apicfg.Enabled
+		ing, err := MakeIngress(ctx, r, tc, nil, testIngressClass)
+		if err != nil {
+			t.Fatal("Unexpected error", err)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if !cmp.Equal(wantPaths, rule.HTTP.Paths) {
+				t.Errorf("Unexpected paths for %v (-want, +got): %s", rule.Hosts, cmp.Diff(wantPaths, rule.HTTP.Paths))
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ing, err := MakeIngress(testContext(), r, tc, nil, testIngressClass)
+		if err != nil {
+			t.Fatal("Unexpected error", err)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if got, want := len(rule.HTTP.Paths), 1; got != want {
+				t.Errorf("|paths| for %v = %d, want: %d", rule.Hosts, got, want)
+			}
+		}
+	})
+}
+
+func TestMakeIngressWithSessionAffinity(t *testing.T) {
+	tc := &traffic.Config{
+		Targets: map[string]traffic.RevisionTargets{
+			traffic.DefaultTarget: {{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: "config",
+					RevisionName:      "v1",
+					Percent:           ptr.Int64(90),
+				},
+			}, {
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: "config",
+					RevisionName:      "v2",
+					Percent:           ptr.Int64(10),
+				},
+			}},
+		},
+	}
+
+	t.Run("sticky", func(t *testing.T) {
+		r := Route(ns, testRouteName, WithRouteUID("1234-5678"), WithURL,
+			WithRouteAnnotation(map[string]string{serving.SessionAffinityAnnotationKey: "cookie:session"}))
+		ing, err := MakeIngress(testContext(), r, tc, nil, testIngressClass)
+		if err != nil {
+			t.Fatal("Unexpected error", err)
+		}
+		for _, rule := range ing.Spec.Rules {
+			for _, path := range rule.HTTP.Paths {
+				for _, split := range path.Splits {
+					if got, want := split.AppendHeaders[servingnetworking.SessionAffinityHeaderName], "cookie:session"; got != want {
+						t.Errorf("Header %s of %s = %q, want: %q", servingnetworking.SessionAffinityHeaderName, split.ServiceName, got, want)
+					}
+				}
+			}
+			// The sessions pinned to a revision go to it, and the others
+			// are split.
+			var pinned []string
+			for _, path := range rule.HTTP.Paths[:len(rule.HTTP.Paths)-1] {
+				if len(path.Splits) != 1 || path.Splits[0].Percent != 100 {
+					t.Fatalf("Splits of the session path = %#v, want a single split", path.Splits)
+				}
+				rev := path.Splits[0].ServiceName
+				if got, want := path.Headers[servingnetworking.SessionRevisionHeaderName].Exact, rev; got != want {
+					t.Errorf("Header match %s of %s = %q, want: %q", servingnetworking.SessionRevisionHeaderName, rev, got, want)
+				}
+				pinned = append(pinned, rev)
+			}
+			if want := []string{"v1", "v2"}; !cmp.Equal(pinned, want) {
+				t.Errorf("Pinned revisions = %v, want: %v", pinned, want)
+			}
+			if split := rule.HTTP.Paths[len(rule.HTTP.Paths)-1]; len(split.Headers) != 0 || len(split.Splits) != 2 {
+				t.Errorf("Split path = %#v, want a split without header matches", split)
+			}
+		}
+	})
+
+	t.Run("single revision", func(t *testing.T) {
+		r := Route(ns, testRouteName, WithRouteUID("1234-5678"), WithURL,
+			WithRouteAnnotation(map[string]string{serving.SessionAffinityAnnotationKey: "cookie:session"}))
+		tc := &traffic.Config{
+			Targets: map[string]traffic.RevisionTargets{
+				traffic.DefaultTarget: {{
+					TrafficTarget: v1.TrafficTarget{
+						ConfigurationName: "config",
+						RevisionName:      "v1",
+						Percent:           ptr.Int64(100),
+					},
+				}},
+			},
+		}
+		ing, err := MakeIngress(testContext(), r, tc, nil, testIngressClass)
+		if err != nil {
+			t.Fatal("Unexpected error", err)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if got, want := len(rule.HTTP.Paths), 1; got != want {
+				t.Errorf("|paths| for %v = %d, want: %d", rule.Hosts, got, want)
+			}
+		}
+	})
+
+	t.Run("malformed key", func(t *testing.T) {
+		r := Route(ns, testRouteName, WithRouteUID("1234-5678"), WithURL,
+			WithRouteAnnotation(map[string]string{serving.SessionAffinityAnnotationKey: "query:session"}))
+		ing, err := MakeIngress(testContext(), r, tc, nil, testIngressClass)
+		if err != nil {
+			t.Fatal("Unexpected error", err)
+		}
+		for _, rule := range ing.Spec.Rules {
+			if got, want := len(rule.HTTP.Paths), 1; got != want {
+				t.Errorf("|paths| for %v = %d, want: %d", rule.Hosts, got, want)
+			}
+			for _, split := range rule.HTTP.Paths[0].Splits {
+				if v, ok := split.AppendHeaders[servingnetworking.SessionAffinityHeaderName]; ok {
+					t.Errorf("Header %s of %s = %q, want empty", servingnetworking.SessionAffinityHeaderName, split.ServiceName, v)
+				}
+			}
+		}
+	})
+}
+
 func TestMakeIngressSpecCorrectRules(t *testing.T) {
 	targets := map[string]traffic.RevisionTargets{
 		traffic.DefaultTarget: {{
@@ -899,6 +1119,67 @@ func TestMakeIngressRuleVanilla(t *testing.T) {
 	}
 }
 
+func TestMakeBaseIngressPathRolloutOfOne(t *testing.T) {
+	targets := traffic.RevisionTargets{{
+		TrafficTarget: v1.TrafficTarget{
+			ConfigurationName: "config",
+			RevisionName:      "revision-shark",
+			LatestRevision:    ptr.Bool(true),
+			Percent:           ptr.Int64(100),
+		},
+	}}
+	split := func(rev string) netv1alpha1.IngressBackendSplit {
+		return netv1alpha1.IngressBackendSplit{
+			IngressBackend: netv1alpha1.IngressBackend{
+				ServiceNamespace: ns,
+				ServiceName:      rev,
+				ServicePort:      intstr.FromInt(80),
+			},
+			Percent: 100,
+			AppendHeaders: map[string]string{
+				"Knative-Serving-Revision":  rev,
+				"Knative-Serving-Namespace": ns,
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		cutover *traffic.Cutover
+		want    string
+	}{{
+		// The traffic goes to the target, as when no rollout is in progress.
+		name: "no cutover",
+		want: "revision-shark",
+	}, {
+		name:    "green revision waits to be promoted",
+		cutover: &traffic.Cutover{Green: "revision-shark"},
+		want:    "revision-whale",
+	}, {
+		name:    "blue revision kept warm",
+		cutover: &traffic.Cutover{Blue: "revision-whale"},
+		want:    "revision-shark",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			roCfgs := []*traffic.ConfigurationRollout{{
+				ConfigurationName: "config",
+				Percent:           100,
+				Revisions: []traffic.RevisionRollout{{
+					RevisionName: "revision-whale",
+					Percent:      100,
+				}},
+				Cutover: tc.cutover,
+			}}
+			want := &netv1alpha1.HTTPIngressPath{
+				Splits: []netv1alpha1.IngressBackendSplit{split(tc.want)},
+			}
+			if got := makeBaseIngressPath(ns, targets, roCfgs, false /* internal encryption */); !cmp.Equal(got, want) {
+				t.Error("Unexpected path (-want, +got):", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
 // One active target and a target of zero percent.
 func TestMakeIngressRuleZeroPercentTarget(t *testing.T) {
 	targets := []traffic.RevisionTarget{{
@@ -48,6 +48,7 @@ import (
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
 	clientset "knative.dev/serving/pkg/client/clientset/versioned"
 	routereconciler "knative.dev/serving/pkg/client/injection/reconciler/serving/v1/route"
+	palisters "knative.dev/serving/pkg/client/listers/autoscaling/v1alpha1"
 	listers "knative.dev/serving/pkg/client/listers/serving/v1"
 	kaccessor "knative.dev/serving/pkg/reconciler/accessor"
 	networkaccessor "knative.dev/serving/pkg/reconciler/accessor/networking"
@@ -73,6 +74,7 @@ type Reconciler struct {
 	endpointsLister     corev1listers.EndpointsLister
 	ingressLister       networkinglisters.IngressLister
 	certificateLister   networkinglisters.CertificateLister
+	paLister            palisters.PodAutoscalerLister
 	tracker             tracker.Interface
 
 	clock        clock.PassiveClock
@@ -131,6 +133,16 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, r *v1.Route) pkgreconcil
 		},
 	}
 
+	// Step the rollout first, since the blue/green rollouts expose the
+	// revisions they keep warm under tags of their own.
+	effectiveRO, err := c.rollout(ctx, r, traffic)
+	if err != nil {
+		return err
+	}
+	if err := c.reconcileCutovers(ctx, r, traffic, effectiveRO); err != nil {
+		return err
+	}
+
 	logger.Info("Creating placeholder k8s services")
 	services, err := c.reconcilePlaceholderServices(ctx, r, traffic.Targets)
 	if err != nil {
@@ -142,11 +154,12 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, r *v1.Route) pkgreconcil
 		return err
 	}
 	// Reconcile ingress and its children resources.
-	ingress, effectiveRO, err := c.reconcileIngress(ctx, r, traffic, tls, ingressClassForRoute(ctx, r), acmeChallenges...)
+	ingress, err := c.reconcileIngress(ctx, r, traffic, effectiveRO, tls, ingressClassForRoute(ctx, r), acmeChallenges...)
 	if err != nil {
 		return err
 	}
 
+	r.Status.Rollouts = effectiveRO.Status()
 	roInProgress := !effectiveRO.Done()
 	if ingress.GetObjectMeta().GetGeneration() != ingress.Status.ObservedGeneration {
 		r.Status.MarkIngressNotConfigured()
@@ -175,6 +188,14 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, r *v1.Route) pkgreconcil
 		}
 		return nil
 	}
+	if effectiveRO.RolledBack() {
+		// The traffic stays with the revisions before the rolled back ones,
+		// so reflect the rollout rather than the spec in the status.
+		r.Status.Traffic, err = traffic.GetRevisionTrafficTargets(ctx, r, effectiveRO)
+		if err != nil {
+			return err
+		}
+	}
 
 	logger.Info("Route successfully synced")
 	return nil
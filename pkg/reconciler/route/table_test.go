@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	clientgotesting "k8s.io/client-go/testing"
 	clocktest "k8s.io/utils/clock/testing"
@@ -46,6 +47,7 @@ import (
 	"knative.dev/pkg/ptr"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/tracker"
+	autoscalingv1alpha1 "knative.dev/serving/pkg/apis/autoscaling/v1alpha1"
 	cfgmap "knative.dev/serving/pkg/apis/config"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -389,17 +391,829 @@ func TestReconcile(t *testing.T) {
 			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "becomes-ready"),
 		},
 		Key: "default/becomes-ready",
+	}, {
+		Name: "route only clears its own warm scales",
+		Objects: []runtime.Object{
+			Route("default", "becomes-ready", WithConfigTarget("config"), WithRouteGeneration(2009)),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config")),
+			// Kept warm by this Route before.
+			rev("default", "config", 2, MarkRevisionReady, WithRevName("config-00002"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config"),
+				WithRevisionAnn(serving.WarmScaleAnnotationKey, "3"),
+				WithRevisionAnn(serving.WarmScaleRoutesAnnotationKey, "becomes-ready=3")),
+			// Kept warm by another Route of the configuration.
+			rev("default", "config", 3, MarkRevisionReady, WithRevName("config-00003"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config"),
+				WithRevisionAnn(serving.WarmScaleAnnotationKey, "3"),
+				WithRevisionAnn(serving.WarmScaleRoutesAnnotationKey, "blue-green=3")),
+			simpleIngress(
+				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "becomes-ready", WithConfigTarget("config")), ""),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			warmScalePatch("default", "config-00002", "null", "null"),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{
+			{Object: simpleK8sService(Route("default", "becomes-ready", WithConfigTarget("config")))},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "becomes-ready", WithConfigTarget("config"),
+				// Populated by reconciliation when the route becomes ready.
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkIngressReady, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(100),
+						LatestRevision: ptr.Bool(true),
+					})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "becomes-ready"),
+		},
+		Key: "default/becomes-ready",
+	}, {
+		Name: "route clears its warm scale of a revision another Route keeps warm",
+		Objects: []runtime.Object{
+			Route("default", "becomes-ready", WithConfigTarget("config"), WithRouteGeneration(2009)),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config")),
+			// Kept warm by both this Route before, and another Route of
+			// the configuration, at a smaller scale.
+			Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen),
+			rev("default", "config", 2, MarkRevisionReady, WithRevName("config-00002"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config"),
+				WithRevisionAnn(serving.WarmScaleAnnotationKey, "5"),
+				WithRevisionAnn(serving.WarmScaleRoutesAnnotationKey, "becomes-ready=5,blue-green=3")),
+			simpleIngress(
+				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "becomes-ready", WithConfigTarget("config")), ""),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			// The revision stays warm at the scale of the other Route.
+			warmScalePatch("default", "config-00002", `"3"`, `"blue-green=3"`),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{
+			{Object: simpleK8sService(Route("default", "becomes-ready", WithConfigTarget("config")))},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "becomes-ready", WithConfigTarget("config"),
+				// Populated by reconciliation when the route becomes ready.
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkIngressReady, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(100),
+						LatestRevision: ptr.Bool(true),
+					})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "becomes-ready"),
+		},
+		Key: "default/becomes-ready",
 	}, {
 		Name: "simple route rollout when ingress becomes ready",
 		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
 		Objects: []runtime.Object{
-			Route("default", "becomes-ready", WithConfigTarget("config"),
-				WithRouteGeneration(2009), MarkIngressNotConfigured),
+			Route("default", "becomes-ready", WithConfigTarget("config"),
+				WithRouteGeneration(2009), MarkIngressNotConfigured),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
+			simpleIngress(
+				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 99,
+				}, {
+					RevisionName: "config-00001", Percent: 1,
+				}}, fakeCurTime.Add(-3*time.Second)),
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "becomes-ready", WithConfigTarget("config")), ""),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			// ingress should be updated with the new rollout data.
+			Object: ingressWithRollout(
+				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00000",
+							Percent:      99,
+						}, {
+							RevisionName: "config-00001",
+							Percent:      1,
+						}},
+					}},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 99,
+				}, {
+					RevisionName: "config-00001", Percent: 1,
+				}}, fakeCurTime.Add(-3*time.Second),
+					func(r *traffic.Rollout) {
+						const numSteps = (120 - 3) / 3 // 39
+						// Step duration is 3s (duration - 3)/ numSteps = 120/40)
+						r.Configurations[0].StepParams.StepDuration = int64(time.Second) * (120 - 3) / numSteps   // 3s in ns.
+						r.Configurations[0].StepParams.StepSize = int(math.Round((100. - 1) / float64(numSteps))) // 3
+						// StepDuration is 3, and so next step is `now` + 3.
+						r.Configurations[0].StepParams.NextStepTime = fakeCurTime.Add(3 * time.Second).UnixNano()
+					},
+				),
+				withReadyIngress,
+			),
+		}, {
+			Object: simpleK8sService(
+				Route("default", "becomes-ready", WithConfigTarget("config")),
+			),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "becomes-ready", WithConfigTarget("config"),
+				// Populated by reconciliation when the route becomes ready.
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkInRollout, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00000",
+						Percent:        ptr.Int64(99),
+						LatestRevision: ptr.Bool(true),
+					},
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(1),
+						LatestRevision: ptr.Bool(true),
+					}), WithStatusRollouts(v1.RolloutStatus{
+					ConfigurationName: "config",
+					RevisionName:      "config-00001",
+					Percent:           1,
+					NextStepTime:      stepTime(fakeCurTime.Add(3 * time.Second)),
+					RemainingSteps:    33,
+				})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "becomes-ready"),
+		},
+		Key: "default/becomes-ready",
+	}, {
+		Name: "simple route rollout and rollout ends",
+		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
+		Objects: []runtime.Object{
+			Route("default", "becomes-ready", WithConfigTarget("config"),
+				WithRouteGeneration(2009), MarkInRollout),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
+			simpleIngress(
+				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 1,
+				}, {
+					RevisionName: "config-00001", Percent: 99,
+				}}, fakeCurTime.Add(-3*time.Second),
+					withStepParams(traffic.RolloutParams{
+						NextStepTime: fakeCurTime.Add(-time.Second).UnixNano(),
+						StepSize:     4,
+						StartTime:    fakeCurTime.Add(-time.Hour).UnixNano(),
+						StepDuration: int64(time.Second),
+					})),
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "becomes-ready", WithConfigTarget("config")), ""),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			// ingress should be updated with the new rollout data.
+			Object: simpleIngress(
+				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				withReadyIngress,
+			),
+		}, {
+			Object: simpleK8sService(
+				Route("default", "becomes-ready", WithConfigTarget("config")),
+			),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "becomes-ready", WithConfigTarget("config"),
+				// Populated by reconciliation when the route becomes ready.
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkIngressReady, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(100),
+						LatestRevision: ptr.Bool(true),
+					})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "becomes-ready"),
+		},
+		Key: "default/becomes-ready",
+	}, {
+		Name: "route rollout rolled back on failed analysis",
+		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
+		Objects: []runtime.Object{
+			Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis,
+				WithRouteGeneration(2009), MarkInRollout),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
+			paWithResponseStats("default", "config-00001", 100, 10),
+			simpleIngress(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 90,
+				}, {
+					RevisionName: "config-00001", Percent: 10,
+				}}, fakeCurTime.Add(-3*time.Second),
+					withStepParams(traffic.RolloutParams{
+						NextStepTime: fakeCurTime.Add(-time.Second).UnixNano(),
+						StepSize:     10,
+						StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+						StepDuration: int64(10 * time.Second),
+					})),
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis), ""),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			// The traffic goes back to the previous revision.
+			Object: ingressWithRollout(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Percent:           100,
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00000",
+							Percent:      100,
+						}},
+						RolledBackRevision: "config-00001",
+					}},
+				},
+				withReadyIngress,
+			),
+		}, {
+			Object: simpleK8sService(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis),
+			),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis,
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkIngressReady, func(r *v1.Route) {
+					r.Status.MarkRolledBack(`revision "config-00001" has a success rate of 90.00%, below 99%`)
+				}, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00000",
+						Percent:        ptr.Int64(100),
+						LatestRevision: ptr.Bool(true),
+					})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "analyzed"),
+		},
+		Key: "default/analyzed",
+	}, {
+		Name: "route rollout paused on failed analysis",
+		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
+		Objects: []runtime.Object{
+			Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, withPausingAnalysis,
+				WithRouteGeneration(2009), MarkInRollout),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
+			paWithResponseStats("default", "config-00001", 100, 10),
+			simpleIngress(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, withPausingAnalysis, WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 90,
+				}, {
+					RevisionName: "config-00001", Percent: 10,
+				}}, fakeCurTime.Add(-3*time.Second),
+					withStepParams(traffic.RolloutParams{
+						NextStepTime: fakeCurTime.Add(-time.Second).UnixNano(),
+						StepSize:     10,
+						StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+						StepDuration: int64(10 * time.Second),
+					})),
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, withPausingAnalysis), ""),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			// The next step is a whole step away again.
+			Object: ingressWithRollout(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, withPausingAnalysis, WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Percent:           100,
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00000",
+							Percent:      90,
+						}, {
+							RevisionName: "config-00001",
+							Percent:      10,
+						}},
+						StepParams: traffic.RolloutParams{
+							NextStepTime: fakeCurTime.Add(10 * time.Second).UnixNano(),
+							StepSize:     10,
+							StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+							StepDuration: int64(10 * time.Second),
+						},
+					}},
+				},
+				withReadyIngress,
+			),
+		}, {
+			Object: simpleK8sService(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, withPausingAnalysis),
+			),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, withPausingAnalysis,
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkInRollout, func(r *v1.Route) {
+					r.Status.MarkRolloutPaused(`revision "config-00001" has a success rate of 90.00%, below 99%`)
+				}, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00000",
+						Percent:        ptr.Int64(90),
+						LatestRevision: ptr.Bool(true),
+					},
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(10),
+						LatestRevision: ptr.Bool(true),
+					}), WithStatusRollouts(v1.RolloutStatus{
+					ConfigurationName: "config",
+					RevisionName:      "config-00001",
+					Percent:           10,
+					NextStepTime:      stepTime(fakeCurTime.Add(10 * time.Second)),
+					RemainingSteps:    9,
+				})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "analyzed"),
+		},
+		Key: "default/analyzed",
+	}, {
+		Name: "route rollout paused without response stats",
+		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
+		Objects: []runtime.Object{
+			Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis,
+				WithRouteGeneration(2009), MarkInRollout),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
+			simpleIngress(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 90,
+				}, {
+					RevisionName: "config-00001", Percent: 10,
+				}}, fakeCurTime.Add(-3*time.Second),
+					withStepParams(traffic.RolloutParams{
+						NextStepTime: fakeCurTime.Add(-time.Second).UnixNano(),
+						StepSize:     10,
+						StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+						StepDuration: int64(10 * time.Second),
+					})),
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis), ""),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			// The revision is not rolled back, but the next step is a
+			// whole step away again.
+			Object: ingressWithRollout(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis, WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Percent:           100,
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00000",
+							Percent:      90,
+						}, {
+							RevisionName: "config-00001",
+							Percent:      10,
+						}},
+						StepParams: traffic.RolloutParams{
+							NextStepTime: fakeCurTime.Add(10 * time.Second).UnixNano(),
+							StepSize:     10,
+							StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+							StepDuration: int64(10 * time.Second),
+							NoDataTime:   fakeCurTime.UnixNano(),
+						},
+					}},
+				},
+				withReadyIngress,
+			),
+		}, {
+			Object: simpleK8sService(
+				Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis),
+			),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "analyzed", WithConfigTarget("config"), withRolloutAnalysis,
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkInRollout, func(r *v1.Route) {
+					r.Status.MarkRolloutAnalysisPending(`revision "config-00001" has no response stats yet: no data to analyze`)
+				}, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00000",
+						Percent:        ptr.Int64(90),
+						LatestRevision: ptr.Bool(true),
+					},
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(10),
+						LatestRevision: ptr.Bool(true),
+					}), WithStatusRollouts(v1.RolloutStatus{
+					ConfigurationName: "config",
+					RevisionName:      "config-00001",
+					Percent:           10,
+					NextStepTime:      stepTime(fakeCurTime.Add(10 * time.Second)),
+					RemainingSteps:    9,
+				})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "analyzed"),
+		},
+		Key: "default/analyzed",
+	}, {
+		Name: "route rollout paused by annotation",
+		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
+		Objects: []runtime.Object{
+			Route("default", "paused", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPause),
+				WithRouteGeneration(2009), MarkInRollout),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
+			simpleIngress(
+				Route("default", "paused", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPause), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 90,
+				}, {
+					RevisionName: "config-00001", Percent: 10,
+				}}, fakeCurTime.Add(-3*time.Second),
+					withStepParams(traffic.RolloutParams{
+						NextStepTime: fakeCurTime.Add(-time.Second).UnixNano(),
+						StepSize:     10,
+						StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+						StepDuration: int64(10 * time.Second),
+					})),
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "paused", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPause)), ""),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			// The traffic split stays as is, though the step is due.
+			Object: ingressWithRollout(
+				Route("default", "paused", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPause), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Percent:           100,
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00000",
+							Percent:      90,
+						}, {
+							RevisionName: "config-00001",
+							Percent:      10,
+						}},
+						StepParams: traffic.RolloutParams{
+							NextStepTime: fakeCurTime.Add(-time.Second).UnixNano(),
+							StepSize:     10,
+							StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+							StepDuration: int64(10 * time.Second),
+							PauseTime:    fakeCurTime.UnixNano(),
+						},
+					}},
+				},
+				withReadyIngress,
+			),
+		}, {
+			Object: simpleK8sService(
+				Route("default", "paused", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPause)),
+			),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "paused", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPause),
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkInRollout, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00000",
+						Percent:        ptr.Int64(90),
+						LatestRevision: ptr.Bool(true),
+					},
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(10),
+						LatestRevision: ptr.Bool(true),
+					}), WithStatusRollouts(v1.RolloutStatus{
+					ConfigurationName: "config",
+					RevisionName:      "config-00001",
+					Percent:           10,
+					Paused:            true,
+					RemainingSteps:    9,
+				})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "paused"),
+		},
+		Key: "default/paused",
+	}, {
+		Name: "route rollout promoted by annotation",
+		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
+		Objects: []runtime.Object{
+			Route("default", "promoted", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPromote),
+				WithRouteGeneration(2009), MarkInRollout),
+			cfg("default", "config",
+				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
+			simpleIngress(
+				Route("default", "promoted", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPromote), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				simpleRollout("config", []traffic.RevisionRollout{{
+					RevisionName: "config-00000", Percent: 90,
+				}, {
+					RevisionName: "config-00001", Percent: 10,
+				}}, fakeCurTime.Add(-3*time.Second),
+					withStepParams(traffic.RolloutParams{
+						NextStepTime: fakeCurTime.Add(time.Minute).UnixNano(),
+						StepSize:     10,
+						StartTime:    fakeCurTime.Add(-time.Minute).UnixNano(),
+						StepDuration: int64(10 * time.Second),
+					})),
+				withReadyIngress,
+			),
+		},
+		WantCreates: []runtime.Object{
+			simplePlaceholderK8sService(getContext(), Route("default", "promoted", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPromote)), ""),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			// The new revision gets all the traffic right away.
+			Object: simpleIngress(
+				Route("default", "promoted", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPromote), WithURL),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "config",
+								RevisionName:      "config-00001",
+								Percent:           ptr.Int64(100),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+				withReadyIngress,
+			),
+		}, {
+			Object: simpleK8sService(
+				Route("default", "promoted", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPromote)),
+			),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "promoted", WithConfigTarget("config"), withRolloutControl(serving.RolloutControlPromote),
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				WithRouteGeneration(2009), WithRouteObservedGeneration,
+				MarkTrafficAssigned, MarkIngressReady, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(100),
+						LatestRevision: ptr.Bool(true),
+					})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "promoted"),
+		},
+		Key: "default/promoted",
+	}, {
+		Name: "route blue/green rollout waits for promotion",
+		Objects: []runtime.Object{
+			Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
+				WithRouteGeneration(2009), MarkInRollout),
 			cfg("default", "config",
-				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
-			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
-			simpleIngress(
-				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				WithConfigGeneration(2), WithLatestCreated("config-00002"), WithLatestReady("config-00002")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config")),
+			rev("default", "config", 2, MarkRevisionReady, WithRevName("config-00002"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config")),
+			paWithActualScale("default", "config-00001", 3),
+			ingressWithRollout(
+				Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen, WithURL),
 				&traffic.Config{
 					Targets: map[string]traffic.RevisionTargets{
 						traffic.DefaultTarget: {{
@@ -412,166 +1226,215 @@ func TestReconcile(t *testing.T) {
 						}},
 					},
 				},
-				simpleRollout("config", []traffic.RevisionRollout{{
-					RevisionName: "config-00000", Percent: 99,
-				}, {
-					RevisionName: "config-00001", Percent: 1,
-				}}, fakeCurTime.Add(-3*time.Second)),
-				withReadyIngress,
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Percent:           100,
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00001",
+							Percent:      100,
+						}},
+					}},
+				},
 			),
 		},
 		WantCreates: []runtime.Object{
-			simplePlaceholderK8sService(getContext(), Route("default", "becomes-ready", WithConfigTarget("config")), ""),
+			simplePlaceholderK8sService(getContext(), Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen), ""),
+			simplePlaceholderK8sService(getContext(), Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen), "green-config-00002"),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			warmScalePatch("default", "config-00002", `"3"`, `"blue-green=3"`),
 		},
 		WantUpdates: []clientgotesting.UpdateActionImpl{{
-			// ingress should be updated with the new rollout data.
+			// The traffic stays with the previous revision, and the new one
+			// is exposed under a tag of its own.
 			Object: ingressWithRollout(
-				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen, WithURL),
 				&traffic.Config{
 					Targets: map[string]traffic.RevisionTargets{
 						traffic.DefaultTarget: {{
 							TrafficTarget: v1.TrafficTarget{
 								ConfigurationName: "config",
-								RevisionName:      "config-00001",
+								RevisionName:      "config-00002",
 								Percent:           ptr.Int64(100),
 								LatestRevision:    ptr.Bool(true),
 							},
 						}},
+						"green-config-00002": {{
+							TrafficTarget: v1.TrafficTarget{
+								Tag:            "green-config-00002",
+								RevisionName:   "config-00002",
+								Percent:        ptr.Int64(100),
+								LatestRevision: ptr.Bool(false),
+							},
+						}},
 					},
 				},
 				&traffic.Rollout{
 					Configurations: []*traffic.ConfigurationRollout{{
 						ConfigurationName: "config",
+						Percent:           100,
 						Revisions: []traffic.RevisionRollout{{
-							RevisionName: "config-00000",
-							Percent:      99,
-						}, {
 							RevisionName: "config-00001",
-							Percent:      1,
+							Percent:      100,
 						}},
+						Cutover: &traffic.Cutover{Green: "config-00002"},
 					}},
 				},
-				simpleRollout("config", []traffic.RevisionRollout{{
-					RevisionName: "config-00000", Percent: 99,
-				}, {
-					RevisionName: "config-00001", Percent: 1,
-				}}, fakeCurTime.Add(-3*time.Second),
-					func(r *traffic.Rollout) {
-						const numSteps = (120 - 3) / 3 // 39
-						// Step duration is 3s (duration - 3)/ numSteps = 120/40)
-						r.Configurations[0].StepParams.StepDuration = int64(time.Second) * (120 - 3) / numSteps   // 3s in ns.
-						r.Configurations[0].StepParams.StepSize = int(math.Round((100. - 1) / float64(numSteps))) // 3
-						// StepDuration is 3, and so next step is `now` + 3.
-						r.Configurations[0].StepParams.NextStepTime = fakeCurTime.Add(3 * time.Second).UnixNano()
-					},
-				),
-				withReadyIngress,
-			),
-		}, {
-			Object: simpleK8sService(
-				Route("default", "becomes-ready", WithConfigTarget("config")),
 			),
 		}},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: Route("default", "becomes-ready", WithConfigTarget("config"),
-				// Populated by reconciliation when the route becomes ready.
+			Object: Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
 				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
 				WithRouteGeneration(2009), WithRouteObservedGeneration,
 				MarkTrafficAssigned, MarkInRollout, WithStatusTraffic(
 					v1.TrafficTarget{
-						RevisionName:   "config-00000",
-						Percent:        ptr.Int64(99),
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(100),
 						LatestRevision: ptr.Bool(true),
 					},
 					v1.TrafficTarget{
-						RevisionName:   "config-00001",
-						Percent:        ptr.Int64(1),
-						LatestRevision: ptr.Bool(true),
-					})),
+						Tag:            "green-config-00002",
+						RevisionName:   "config-00002",
+						Percent:        ptr.Int64(0),
+						LatestRevision: ptr.Bool(false),
+						URL: &apis.URL{
+							Scheme: "http",
+							Host:   "green-config-00002-blue-green.default.example.com",
+						},
+					}), WithStatusRollouts(v1.RolloutStatus{
+					ConfigurationName: "config",
+					RevisionName:      "config-00002",
+				})),
 		}},
 		WantEvents: []string{
-			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "becomes-ready"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "blue-green"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "green-config-00002-blue-green"),
 		},
-		Key: "default/becomes-ready",
+		Key: "default/blue-green",
 	}, {
-		Name: "simple route rollout and rollout ends",
-		Ctx:  context.WithValue(context.Background(), rolloutDurationKey, 120),
+		Name: "route blue/green rollout promoted by annotation",
 		Objects: []runtime.Object{
-			Route("default", "becomes-ready", WithConfigTarget("config"),
+			Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
+				withRolloutControl(serving.RolloutControlPromote), withRollbackWindow("10m"),
 				WithRouteGeneration(2009), MarkInRollout),
 			cfg("default", "config",
-				WithConfigGeneration(1), WithLatestCreated("config-00001"), WithLatestReady("config-00001")),
-			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001")),
-			simpleIngress(
-				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+				WithConfigGeneration(2), WithLatestCreated("config-00002"), WithLatestReady("config-00002")),
+			rev("default", "config", 1, MarkRevisionReady, WithRevName("config-00001"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config")),
+			rev("default", "config", 2, MarkRevisionReady, WithRevName("config-00002"),
+				WithRevisionLabel(serving.ConfigurationLabelKey, "config"),
+				WithRevisionAnn(serving.WarmScaleAnnotationKey, "3"),
+				WithRevisionAnn(serving.WarmScaleRoutesAnnotationKey, "blue-green=3")),
+			paWithActualScale("default", "config-00002", 3),
+			ingressWithRollout(
+				Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
+					withRolloutControl(serving.RolloutControlPromote), withRollbackWindow("10m"), WithURL),
 				&traffic.Config{
 					Targets: map[string]traffic.RevisionTargets{
 						traffic.DefaultTarget: {{
 							TrafficTarget: v1.TrafficTarget{
 								ConfigurationName: "config",
-								RevisionName:      "config-00001",
+								RevisionName:      "config-00002",
 								Percent:           ptr.Int64(100),
 								LatestRevision:    ptr.Bool(true),
 							},
 						}},
 					},
 				},
-				simpleRollout("config", []traffic.RevisionRollout{{
-					RevisionName: "config-00000", Percent: 1,
-				}, {
-					RevisionName: "config-00001", Percent: 99,
-				}}, fakeCurTime.Add(-3*time.Second),
-					withStepParams(traffic.RolloutParams{
-						NextStepTime: fakeCurTime.Add(-time.Second).UnixNano(),
-						StepSize:     4,
-						StartTime:    fakeCurTime.Add(-time.Hour).UnixNano(),
-						StepDuration: int64(time.Second),
-					})),
-				withReadyIngress,
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Percent:           100,
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00001",
+							Percent:      100,
+						}},
+						Cutover: &traffic.Cutover{Green: "config-00002"},
+					}},
+				},
 			),
 		},
 		WantCreates: []runtime.Object{
-			simplePlaceholderK8sService(getContext(), Route("default", "becomes-ready", WithConfigTarget("config")), ""),
+			simplePlaceholderK8sService(getContext(), Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
+				withRolloutControl(serving.RolloutControlPromote), withRollbackWindow("10m")), ""),
+			simplePlaceholderK8sService(getContext(), Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
+				withRolloutControl(serving.RolloutControlPromote), withRollbackWindow("10m")), "blue-config-00001"),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			// The previous revision is kept warm at the scale of the promoted
+			// one, which is not kept warm anymore.
+			warmScalePatch("default", "config-00001", `"3"`, `"blue-green=3"`),
+			warmScalePatch("default", "config-00002", "null", "null"),
 		},
 		WantUpdates: []clientgotesting.UpdateActionImpl{{
-			// ingress should be updated with the new rollout data.
-			Object: simpleIngress(
-				Route("default", "becomes-ready", WithConfigTarget("config"), WithURL),
+			// The new revision gets all the traffic at once.
+			Object: ingressWithRollout(
+				Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
+					withRolloutControl(serving.RolloutControlPromote), withRollbackWindow("10m"), WithURL),
 				&traffic.Config{
 					Targets: map[string]traffic.RevisionTargets{
 						traffic.DefaultTarget: {{
 							TrafficTarget: v1.TrafficTarget{
 								ConfigurationName: "config",
-								RevisionName:      "config-00001",
+								RevisionName:      "config-00002",
 								Percent:           ptr.Int64(100),
 								LatestRevision:    ptr.Bool(true),
 							},
 						}},
+						"blue-config-00001": {{
+							TrafficTarget: v1.TrafficTarget{
+								Tag:            "blue-config-00001",
+								RevisionName:   "config-00001",
+								Percent:        ptr.Int64(100),
+								LatestRevision: ptr.Bool(false),
+							},
+						}},
 					},
 				},
-				withReadyIngress,
-			),
-		}, {
-			Object: simpleK8sService(
-				Route("default", "becomes-ready", WithConfigTarget("config")),
+				&traffic.Rollout{
+					Configurations: []*traffic.ConfigurationRollout{{
+						ConfigurationName: "config",
+						Percent:           100,
+						Revisions: []traffic.RevisionRollout{{
+							RevisionName: "config-00002",
+							Percent:      100,
+						}},
+						Cutover: &traffic.Cutover{
+							Blue:          "config-00001",
+							KeepWarmUntil: fakeCurTime.Add(10 * time.Minute).UnixNano(),
+						},
+					}},
+				},
 			),
 		}},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
-			Object: Route("default", "becomes-ready", WithConfigTarget("config"),
-				// Populated by reconciliation when the route becomes ready.
+			Object: Route("default", "blue-green", WithConfigTarget("config"), withBlueGreen,
+				withRolloutControl(serving.RolloutControlPromote), withRollbackWindow("10m"),
 				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
 				WithRouteGeneration(2009), WithRouteObservedGeneration,
-				MarkTrafficAssigned, MarkIngressReady, WithStatusTraffic(
+				MarkTrafficAssigned, MarkIngressNotConfigured, WithStatusTraffic(
 					v1.TrafficTarget{
-						RevisionName:   "config-00001",
+						RevisionName:   "config-00002",
 						Percent:        ptr.Int64(100),
 						LatestRevision: ptr.Bool(true),
+					},
+					v1.TrafficTarget{
+						Tag:            "blue-config-00001",
+						RevisionName:   "config-00001",
+						Percent:        ptr.Int64(0),
+						LatestRevision: ptr.Bool(false),
+						URL: &apis.URL{
+							Scheme: "http",
+							Host:   "blue-config-00001-blue-green.default.example.com",
+						},
 					})),
 		}},
 		WantEvents: []string{
-			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "becomes-ready"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "blue-green"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "blue-config-00001-blue-green"),
 		},
-		Key: "default/becomes-ready",
+		Key: "default/blue-green",
 	}, {
 		Name: "failure creating k8s placeholder service",
 		// We induce a failure creating the placeholder service.
@@ -934,7 +1797,11 @@ func TestReconcile(t *testing.T) {
 						RevisionName:   "config-00002",
 						Percent:        ptr.Int64(1),
 						LatestRevision: ptr.Bool(true),
-					})),
+					}), WithStatusRollouts(v1.RolloutStatus{
+					ConfigurationName: "config",
+					RevisionName:      "config-00002",
+					Percent:           1,
+				})),
 		}},
 		Key: "default/new-latest-ready",
 	}, {
@@ -1660,6 +2527,96 @@ func TestReconcile(t *testing.T) {
 			Eventf(corev1.EventTypeNormal, "Created", "Created Ingress %q", "named-traffic-split"),
 		},
 		Key: "default/named-traffic-split",
+	}, {
+		Name: "sticky traffic split becomes ready",
+		Objects: []runtime.Object{
+			Route("default", "sticky-traffic-split", WithRouteGeneration(1), WithSpecTraffic(
+				v1.TrafficTarget{
+					ConfigurationName: "blue",
+					Percent:           ptr.Int64(90),
+				}, v1.TrafficTarget{
+					ConfigurationName: "green",
+					Percent:           ptr.Int64(10),
+				}), WithRouteUID("34-78"), withSessionAffinity, WithRouteFinalizer),
+			cfg("default", "blue",
+				WithConfigGeneration(1), WithLatestCreated("blue-00001"), WithLatestReady("blue-00001")),
+			cfg("default", "green",
+				WithConfigGeneration(1), WithLatestCreated("green-00001"), WithLatestReady("green-00001")),
+			rev("default", "blue", 1, MarkRevisionReady, WithRevName("blue-00001")),
+			rev("default", "green", 1, MarkRevisionReady, WithRevName("green-00001")),
+		},
+		WantCreates: []runtime.Object{
+			simpleIngress(
+				Route("default", "sticky-traffic-split", WithURL, WithRouteGeneration(1), WithSpecTraffic(
+					v1.TrafficTarget{
+						ConfigurationName: "blue",
+						Percent:           ptr.Int64(90),
+					}, v1.TrafficTarget{
+						ConfigurationName: "green",
+						Percent:           ptr.Int64(10),
+					}), WithRouteUID("34-78"), withSessionAffinity),
+				&traffic.Config{
+					Targets: map[string]traffic.RevisionTargets{
+						traffic.DefaultTarget: {{
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "blue",
+								RevisionName:      "blue-00001",
+								Percent:           ptr.Int64(90),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}, {
+							TrafficTarget: v1.TrafficTarget{
+								ConfigurationName: "green",
+								RevisionName:      "green-00001",
+								Percent:           ptr.Int64(10),
+								LatestRevision:    ptr.Bool(true),
+							},
+						}},
+					},
+				},
+			),
+			simplePlaceholderK8sService(
+				getContext(),
+				Route("default", "sticky-traffic-split", WithRouteGeneration(1),
+					WithSpecTraffic(
+						v1.TrafficTarget{
+							ConfigurationName: "blue",
+							Percent:           ptr.Int64(90),
+						},
+						v1.TrafficTarget{
+							ConfigurationName: "green",
+							Percent:           ptr.Int64(10),
+						}), WithRouteUID("34-78"), withSessionAffinity, WithRouteFinalizer),
+				"",
+			),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: Route("default", "sticky-traffic-split", WithRouteFinalizer,
+				WithRouteGeneration(1), WithRouteObservedGeneration,
+				WithSpecTraffic(v1.TrafficTarget{
+					ConfigurationName: "blue",
+					Percent:           ptr.Int64(90),
+				}, v1.TrafficTarget{
+					ConfigurationName: "green",
+					Percent:           ptr.Int64(10),
+				}), WithRouteUID("34-78"), withSessionAffinity,
+				WithURL, WithAddress, WithRouteConditionsExternalDomainTLSDisabled,
+				MarkTrafficAssigned, MarkIngressNotConfigured, WithStatusTraffic(
+					v1.TrafficTarget{
+						RevisionName:   "blue-00001",
+						Percent:        ptr.Int64(90),
+						LatestRevision: ptr.Bool(true),
+					}, v1.TrafficTarget{
+						RevisionName:   "green-00001",
+						Percent:        ptr.Int64(10),
+						LatestRevision: ptr.Bool(true),
+					})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "Created", "Created placeholder service %q", "sticky-traffic-split"),
+			Eventf(corev1.EventTypeNormal, "Created", "Created Ingress %q", "sticky-traffic-split"),
+		},
+		Key: "default/sticky-traffic-split",
 	}, {
 		Name: "same revision targets",
 		Objects: []runtime.Object{
@@ -3208,6 +4165,7 @@ func NewTestReconciler(ctx context.Context, listers *Listers, cmw configmap.Watc
 		endpointsLister:     listers.GetEndpointsLister(),
 		ingressLister:       listers.GetIngressLister(),
 		certificateLister:   listers.GetCertificateLister(),
+		paLister:            listers.GetPodAutoscalerLister(),
 		tracker:             ctx.Value(TrackerKey).(tracker.Interface),
 		clock:               clocktest.NewFakePassiveClock(fakeCurTime),
 		enqueueAfter:        func(interface{}, time.Duration) {},
@@ -3326,6 +4284,85 @@ func k8sEndpointsWithIngress(r *v1.Route, ing *netv1alpha1.Ingress) *corev1.Endp
 
 }
 
+func withSessionAffinity(r *v1.Route) {
+	r.Annotations = kmeta.UnionMaps(r.Annotations,
+		map[string]string{serving.SessionAffinityAnnotationKey: "cookie:session"})
+}
+
+func withRolloutAnalysis(r *v1.Route) {
+	r.Annotations = kmeta.UnionMaps(r.Annotations,
+		map[string]string{serving.RolloutMinSuccessPercentageKey: "99"})
+}
+
+func withRolloutControl(control string) RouteOption {
+	return func(r *v1.Route) {
+		r.Annotations = kmeta.UnionMaps(r.Annotations,
+			map[string]string{serving.RolloutControlKey: control})
+	}
+}
+
+func withBlueGreen(r *v1.Route) {
+	r.Annotations = kmeta.UnionMaps(r.Annotations,
+		map[string]string{serving.RolloutStrategyKey: serving.RolloutStrategyBlueGreen})
+}
+
+func withRollbackWindow(window string) RouteOption {
+	return func(r *v1.Route) {
+		r.Annotations = kmeta.UnionMaps(r.Annotations,
+			map[string]string{serving.BlueGreenRollbackWindowKey: window})
+	}
+}
+
+func warmScalePatch(namespace, name, value, routes string) clientgotesting.PatchActionImpl {
+	return clientgotesting.PatchActionImpl{
+		ActionImpl: clientgotesting.ActionImpl{
+			Namespace: namespace,
+			Verb:      "patch",
+			Resource:  v1.SchemeGroupVersion.WithResource("revisions"),
+		},
+		Name:      name,
+		PatchType: types.MergePatchType,
+		Patch: []byte(`{"metadata":{"annotations":{"` + serving.WarmScaleAnnotationKey + `":` + value +
+			`,"` + serving.WarmScaleRoutesAnnotationKey + `":` + routes + `},"resourceVersion":""}}`),
+	}
+}
+
+func withPausingAnalysis(r *v1.Route) {
+	r.Annotations = kmeta.UnionMaps(r.Annotations,
+		map[string]string{serving.RolloutAnalysisActionKey: serving.RolloutAnalysisActionPause})
+}
+
+func stepTime(t time.Time) *metav1.Time {
+	return &metav1.Time{Time: time.Unix(0, t.UnixNano())}
+}
+
+func paWithActualScale(namespace, name string, scale int32) *autoscalingv1alpha1.PodAutoscaler {
+	return &autoscalingv1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Status: autoscalingv1alpha1.PodAutoscalerStatus{
+			ActualScale: ptr.Int32(scale),
+		},
+	}
+}
+
+func paWithResponseStats(namespace, name string, responses, serverErrors int64) *autoscalingv1alpha1.PodAutoscaler {
+	return &autoscalingv1alpha1.PodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+		Status: autoscalingv1alpha1.PodAutoscalerStatus{
+			ResponseStats: &autoscalingv1alpha1.ResponseStats{
+				ResponseCount:    responses,
+				ServerErrorCount: serverErrors,
+			},
+		},
+	}
+}
+
 func simpleIngress(r *v1.Route, tc *traffic.Config, io ...IngressOption) *netv1alpha1.Ingress {
 	return ingressWithTLS(r, tc, nil /*tls*/, nil /*challenges*/, io...)
 }
@@ -23,12 +23,16 @@ package traffic
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
 	"sort"
 	"time"
 
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/logging"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
 )
 
 // Rollout encapsulates the current rollout state of the system.
@@ -64,6 +68,40 @@ type ConfigurationRollout struct {
 
 	// StepParams describes rollout params for the configuration.
 	StepParams RolloutParams `json:"stepParams"`
+
+	// RolledBackRevision is the revision whose rollout was rolled back since
+	// it failed the rollout analysis. The traffic stays with the previous
+	// revisions until the configuration has a newer revision.
+	RolledBackRevision string `json:"rolledBackRevision,omitempty"`
+
+	// Schedule is the custom rollout steps of the configuration, if any.
+	// It's copied to the StepParams when a new rollout starts, so that
+	// changing it doesn't affect the rollouts in progress.
+	Schedule []RolloutStep `json:"-"`
+
+	// Cutover is the state of the blue/green rollout of the configuration,
+	// if any.
+	Cutover *Cutover `json:"cutover,omitempty"`
+
+	// BlueGreen is set when the new revisions of the configuration are
+	// rolled out with a blue/green cutover rather than gradually.
+	BlueGreen bool `json:"-"`
+}
+
+// Cutover describes a blue/green rollout. The green revision is kept warm
+// and receives no traffic until the rollout is promoted, when it receives all
+// the traffic at once. The blue revision, which served the traffic before, is
+// kept warm for the rollback window of the Route after that.
+type Cutover struct {
+	// Green is the revision waiting to be promoted, if any.
+	Green string `json:"green,omitempty"`
+
+	// Blue is the revision that served the traffic before the promotion,
+	// while it's kept warm.
+	Blue string `json:"blue,omitempty"`
+
+	// KeepWarmUntil is the Unix timestamp in ns until when Blue is kept warm.
+	KeepWarmUntil int64 `json:"keepWarmUntil,omitempty"`
 }
 
 // RolloutParams contains the timing and sizing parameters for the
@@ -84,6 +122,43 @@ type RolloutParams struct {
 
 	// How much traffic to move in a single step.
 	StepSize int `json:"stepSize,omitempty"`
+
+	// Steps is the custom schedule of the rollout. If it's empty, the traffic
+	// moves in equal steps over the rollout duration.
+	Steps []RolloutStep `json:"steps,omitempty"`
+
+	// StepIndex is the index of the step in Steps the rollout moves to next.
+	// len(Steps) means that the next move gives all the traffic to the newest
+	// revision.
+	StepIndex int `json:"stepIndex,omitempty"`
+
+	// PauseTime is the Unix timestamp in ns when the rollout was paused,
+	// or 0 if it is not paused.
+	PauseTime int64 `json:"pauseTime,omitempty"`
+
+	// NoDataTime is the Unix timestamp in ns since when the analysis of the
+	// newest revision has had no data, or 0 if it has data.
+	NoDataTime int64 `json:"noDataTime,omitempty"`
+}
+
+// RolloutStep is a step of a custom rollout schedule.
+type RolloutStep struct {
+	// Percent is the share of the configuration traffic that the newest
+	// revision receives at this step.
+	Percent int `json:"percent"`
+
+	// Hold is the number of nanoseconds the rollout stays at this step.
+	Hold int64 `json:"hold"`
+}
+
+// WarmRevision describes a revision that a blue/green rollout keeps warm.
+type WarmRevision struct {
+	// Tag the revision is exposed under, e.g. to smoke test it.
+	Tag string
+
+	// ScaleOf is the revision serving the traffic of the configuration,
+	// whose scale the revision is kept at.
+	ScaleOf string
 }
 
 // RevisionRollout describes the revision in the config rollout.
@@ -125,8 +200,190 @@ func (cur *Rollout) Done() bool {
 // done returns true if there is no active rollout going on
 // for the configuration.
 func (cur *ConfigurationRollout) done() bool {
-	// Zero or just one revision.
-	return len(cur.Revisions) < 2
+	// Zero or just one revision, and no revision waiting to be promoted.
+	return len(cur.Revisions) < 2 && cur.green() == ""
+}
+
+// green returns the revision of the blue/green rollout that waits to be
+// promoted, if any.
+func (cur *ConfigurationRollout) green() string {
+	if cur.Cutover == nil {
+		return ""
+	}
+	return cur.Cutover.Green
+}
+
+// UseBlueGreen makes the Configuration rollouts in this Rollout roll out
+// their new revisions with a blue/green cutover.
+func (cur *Rollout) UseBlueGreen() {
+	for _, c := range cur.Configurations {
+		c.BlueGreen = true
+	}
+}
+
+// WarmRevisions returns the revisions of the blue/green rollouts that are
+// kept warm, i.e. the green revisions waiting to be promoted and the blue
+// revisions in their rollback window, keyed by revision name.
+func (cur *Rollout) WarmRevisions(nowTS int64) map[string]WarmRevision {
+	ret := map[string]WarmRevision{}
+	for _, c := range cur.Configurations {
+		co := c.Cutover
+		if co == nil || len(c.Revisions) == 0 {
+			continue
+		}
+		active := c.Revisions[len(c.Revisions)-1].RevisionName
+		if co.Green != "" {
+			ret[co.Green] = WarmRevision{Tag: "green-" + co.Green, ScaleOf: active}
+		}
+		if co.Blue != "" && nowTS < co.KeepWarmUntil {
+			ret[co.Blue] = WarmRevision{Tag: "blue-" + co.Blue, ScaleOf: active}
+		}
+	}
+	return ret
+}
+
+// KeepWarmUntil returns the earliest Unix timestamp in ns until when a blue
+// revision of the blue/green rollouts is kept warm, or 0 if there is none.
+func (cur *Rollout) KeepWarmUntil() int64 {
+	ret := int64(0)
+	for _, c := range cur.Configurations {
+		if co := c.Cutover; co != nil && co.Blue != "" && (ret == 0 || co.KeepWarmUntil < ret) {
+			ret = co.KeepWarmUntil
+		}
+	}
+	return ret
+}
+
+// HasSteps returns true if any of the Configuration rollouts
+// in this Rollout has a custom rollout schedule.
+func (cur *Rollout) HasSteps() bool {
+	for i := range cur.Configurations {
+		if len(cur.Configurations[i].Schedule) > 0 || len(cur.Configurations[i].StepParams.Steps) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RolledBack returns true if any of the Configuration rollouts
+// in this Rollout was rolled back.
+func (cur *Rollout) RolledBack() bool {
+	for i := range cur.Configurations {
+		if cur.Configurations[i].RolledBackRevision != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Pause pauses the Configuration rollouts in progress, which don't step
+// until they are resumed.
+func (cur *Rollout) Pause(nowTS int64) {
+	for _, c := range cur.Configurations {
+		if !c.done() && c.StepParams.PauseTime == 0 {
+			c.StepParams.PauseTime = nowTS
+		}
+	}
+}
+
+// Resume resumes the paused Configuration rollouts. The time they were
+// paused for is added to their next step time, so that they carry on from
+// where they were paused.
+func (cur *Rollout) Resume(nowTS int64) {
+	for _, c := range cur.Configurations {
+		if c.StepParams.PauseTime == 0 {
+			continue
+		}
+		if c.StepParams.NextStepTime > 0 {
+			c.StepParams.NextStepTime += nowTS - c.StepParams.PauseTime
+		}
+		if c.StepParams.NoDataTime > 0 {
+			c.StepParams.NoDataTime += nowTS - c.StepParams.PauseTime
+		}
+		c.StepParams.PauseTime = 0
+	}
+}
+
+// Promote completes the Configuration rollouts in progress, giving all
+// the traffic of each configuration to its newest revision, or to its green
+// revision for the blue/green rollouts. The revisions that served the traffic
+// of the blue/green rollouts before are kept warm for keepWarm.
+func (cur *Rollout) Promote(nowTS int64, keepWarm time.Duration) {
+	for _, c := range cur.Configurations {
+		if c.done() {
+			continue
+		}
+		if c.green() != "" {
+			c.promoteGreen(nowTS, keepWarm)
+			continue
+		}
+		newest := c.Revisions[len(c.Revisions)-1]
+		newest.Percent = c.Percent
+		c.Revisions = []RevisionRollout{newest}
+		c.StepParams = RolloutParams{}
+	}
+}
+
+// promoteGreen gives all the traffic of the configuration to the green
+// revision of its blue/green rollout.
+func (cur *ConfigurationRollout) promoteGreen(nowTS int64, keepWarm time.Duration) {
+	green := cur.Cutover.Green
+	cur.Cutover = nil
+	if keepWarm > 0 && len(cur.Revisions) > 0 {
+		cur.Cutover = &Cutover{
+			Blue:          cur.Revisions[len(cur.Revisions)-1].RevisionName,
+			KeepWarmUntil: nowTS + int64(keepWarm),
+		}
+	}
+	cur.Revisions = []RevisionRollout{{RevisionName: green, Percent: cur.Percent}}
+	cur.StepParams = RolloutParams{}
+}
+
+// Status returns the state of the Configuration rollouts in progress,
+// as reflected in the status of the Route.
+func (cur *Rollout) Status() []v1.RolloutStatus {
+	var ret []v1.RolloutStatus
+	for _, c := range cur.Configurations {
+		if c.done() {
+			continue
+		}
+		if green := c.green(); green != "" {
+			// The green revision receives no traffic until it's promoted.
+			ret = append(ret, v1.RolloutStatus{
+				ConfigurationName: c.ConfigurationName,
+				Tag:               c.Tag,
+				RevisionName:      green,
+			})
+			continue
+		}
+		newest := c.Revisions[len(c.Revisions)-1]
+		rs := v1.RolloutStatus{
+			ConfigurationName: c.ConfigurationName,
+			Tag:               c.Tag,
+			RevisionName:      newest.RevisionName,
+			Percent:           int64(newest.Percent),
+			Paused:            c.StepParams.PauseTime > 0,
+		}
+		// Until the ingress is ready the steps are not known yet.
+		if c.StepParams.StepSize > 0 {
+			rs.RemainingSteps = int64(c.remainingSteps())
+			if !rs.Paused {
+				rs.NextStepTime = &metav1.Time{Time: time.Unix(0, c.StepParams.NextStepTime)}
+			}
+		}
+		ret = append(ret, rs)
+	}
+	return ret
+}
+
+// remainingSteps returns the number of steps before the newest revision
+// receives all the traffic of the configuration.
+func (cur *ConfigurationRollout) remainingSteps() int {
+	if len(cur.StepParams.Steps) > 0 {
+		return len(cur.StepParams.Steps) - cur.StepParams.StepIndex + 1
+	}
+	rem := cur.Percent - cur.Revisions[len(cur.Revisions)-1].Percent
+	return (rem + cur.StepParams.StepSize - 1) / cur.StepParams.StepSize
 }
 
 // Validate validates current rollout for inconsistencies.
@@ -142,6 +399,10 @@ func (cur *Rollout) Validate() bool {
 		if c.StepParams.StepSize < 0 || c.StepParams.StepSize > c.Percent {
 			return false
 		}
+		// Ensure the custom schedule is consistent.
+		if c.StepParams.StepIndex < 0 || c.StepParams.StepIndex > len(c.StepParams.Steps) {
+			return false
+		}
 		// If total % values in the revision do not add up — discard.
 		tot := 0
 		for _, r := range c.Revisions {
@@ -156,23 +417,99 @@ func (cur *Rollout) Validate() bool {
 
 // ObserveReady traverses the configs and the ones that are in rollout
 // but have not observed step time yet, will have it set, to
-// max(1, nowTS-cfg.StartTime).
+// max(1, nowTS-cfg.StartTime), or to the hold of the first step of
+// their custom schedule.
 func (cur *Rollout) ObserveReady(ctx context.Context, nowTS int64, durationSecs float64) {
 	logger := logging.FromContext(ctx)
 	for i := range cur.Configurations {
 		c := cur.Configurations[i]
-		if c.StepParams.StepDuration == 0 && c.StepParams.StartTime > 0 {
+		switch {
+		case c.StepParams.StepDuration == 0 && c.StepParams.StartTime > 0 && len(c.StepParams.Steps) > 0:
+			c.observeSteps(nowTS)
+			logger.Debugf("Computed scheduled rollout properties for %s: %#v", c.ConfigurationName, c.StepParams)
+		case c.StepParams.StepDuration == 0 && c.StepParams.StartTime > 0:
 			// In really ceil(nowTS-params.StartTime) should always give 1s, but
 			// given possible time drift, we'll ensure that at least 1s is returned.
 			minStepSec := math.Max(1, math.Ceil(time.Duration(nowTS-c.StepParams.StartTime).Seconds()))
 			c.computeProperties(float64(nowTS), minStepSec, durationSecs)
 			logger.Debugf("Computed rollout properties for %s: %#v", c.ConfigurationName, c.StepParams)
-		} else {
+		default:
 			logger.Debugf("Existing rollout properties for %s: %#v", c.ConfigurationName, c.StepParams)
 		}
 	}
 }
 
+// ErrNoAnalysisData is returned by the checks of Analyze when there is no
+// data about the revision to analyze yet.
+var ErrNoAnalysisData = errors.New("no data to analyze")
+
+// NoAnalysisDataTimeout is how long the rollouts are paused for the lack
+// of data about their newest revision, before it fails the analysis.
+const NoAnalysisDataTimeout = 10 * time.Minute
+
+// Analyze checks the newest revision of the configs that are in rollout
+// and due for their next step with check, before they are stepped.
+// The rollouts whose newest revision fails the check are paused until
+// their next step time, or rolled back to the previous revision if
+// rollback is set. The rollouts whose newest revision has no data yet,
+// i.e. whose check returns ErrNoAnalysisData, are always paused, until
+// NoAnalysisDataTimeout, after which the revision fails the check.
+// Analyze returns the first error of check, preferring the failures
+// to the lack of data.
+func (cur *Rollout) Analyze(ctx context.Context, nowTS int64, rollback bool, check func(revision string) error) error {
+	logger := logging.FromContext(ctx)
+	var ret error
+	for i := range cur.Configurations {
+		c := cur.Configurations[i]
+		// Not yet stepping, paused, or not yet due for the next step.
+		if c.done() || c.StepParams.StepSize == 0 || c.StepParams.PauseTime > 0 || nowTS < c.StepParams.NextStepTime {
+			continue
+		}
+		rev := c.Revisions[len(c.Revisions)-1].RevisionName
+		err := check(rev)
+		noData := errors.Is(err, ErrNoAnalysisData)
+		switch {
+		case !noData:
+			c.StepParams.NoDataTime = 0
+		case c.StepParams.NoDataTime == 0:
+			c.StepParams.NoDataTime = nowTS
+		case nowTS-c.StepParams.NoDataTime >= int64(NoAnalysisDataTimeout):
+			err = fmt.Errorf("revision %q has had no data to analyze for %v", rev, NoAnalysisDataTimeout)
+			noData = false
+		}
+		if err == nil {
+			continue
+		}
+		if ret == nil || (errors.Is(ret, ErrNoAnalysisData) && !noData) {
+			ret = err
+		}
+		if rollback && !noData {
+			logger.Infof("Rolling back revision %s of config %s: %v", rev, c.ConfigurationName, err)
+			c.rollBack(nowTS)
+		} else {
+			logger.Infof("Pausing the rollout of revision %s of config %s: %v", rev, c.ConfigurationName, err)
+			c.StepParams.NextStepTime = nowTS + c.StepParams.StepDuration
+		}
+	}
+	return ret
+}
+
+// rollBack removes the newest revision from the rollout and gives its
+// traffic to the previous revision, which carries on the rollout, if
+// there are older revisions still.
+func (cur *ConfigurationRollout) rollBack(nowTS int64) {
+	n := len(cur.Revisions)
+	cur.RolledBackRevision = cur.Revisions[n-1].RevisionName
+	cur.Revisions[n-2].Percent += cur.Revisions[n-1].Percent
+	cur.Revisions = cur.Revisions[:n-1]
+	if len(cur.Revisions) > 1 {
+		cur.StepParams.NextStepTime = nowTS + cur.StepParams.StepDuration
+		cur.StepParams.NoDataTime = 0
+	} else {
+		cur.StepParams = RolloutParams{}
+	}
+}
+
 // Step merges this rollout object with the previous state and
 // returns a new Rollout object representing the merged state.
 // At the end of the call the returned object will contain the
@@ -229,10 +566,14 @@ func (cur *Rollout) Step(ctx context.Context, prev *Rollout, nowTS int64) (*Roll
 				case p > 1:
 					sc := stepConfig(ccfgs[i], pcfgs[j], nowTS, logger)
 					ret = append(ret, sc)
-					// Keep the minimum value if it is not 0.
-					if nst := sc.StepParams.NextStepTime; nst > 0 && nst < returnTS {
+					// Keep the minimum value if it is not 0, unless paused.
+					if nst := sc.StepParams.NextStepTime; nst > 0 && nst < returnTS && sc.StepParams.PauseTime == 0 {
 						returnTS = nst
 					}
+					// Wake up to let the blue revision cool down, too.
+					if co := sc.Cutover; co != nil && co.Blue != "" && co.KeepWarmUntil < returnTS {
+						returnTS = co.KeepWarmUntil
+					}
 				case p == 1:
 					// Skip all the work if it's a common A/B scenario where the test config
 					// receives just 1% of traffic.
@@ -352,9 +693,12 @@ func stepRevisions(goal *ConfigurationRollout, nowTS int64) {
 	// And cull the tail portion of it.
 	goal.Revisions = goal.Revisions[:writePos+1]
 	// Also set the next time.
-	if len(goal.Revisions) > 1 {
+	switch {
+	case len(goal.Revisions) > 1 && len(goal.StepParams.Steps) > 0:
+		goal.holdStep(nowTS)
+	case len(goal.Revisions) > 1:
 		goal.StepParams.NextStepTime = nowTS + goal.StepParams.StepDuration
-	} else {
+	default:
 		// This is the last step, we're done! Clear the params out.
 		goal.StepParams = RolloutParams{}
 	}
@@ -377,11 +721,21 @@ func stepConfig(goal, prev *ConfigurationRollout, nowTS int64, logger *zap.Sugar
 	if len(prev.Revisions) > 0 {
 		adjustPercentage(goal.Percent, prev, logger)
 	}
+	if goal.BlueGreen {
+		return stepCutover(ret, prev, nowTS, logger)
+	}
 	// goal will always have just one revision in the list – the current desired revision.
 	// If it matches the last revision of the previous rollout state (or there were no revisions)
 	// then no new rollout has begun for this configuration.
-	if len(prev.Revisions) == 0 || goal.Revisions[0].RevisionName == prev.Revisions[pc-1].RevisionName {
+	// Neither has it, if the goal revision is the one that was rolled back.
+	rolledBack := len(prev.Revisions) > 0 && goal.Revisions[0].RevisionName == prev.RolledBackRevision
+	if len(prev.Revisions) == 0 || rolledBack || goal.Revisions[0].RevisionName == prev.Revisions[pc-1].RevisionName {
 		logger.Debug("No new revision to roll out for config: ", goal.ConfigurationName)
+		if rolledBack {
+			// Keep the traffic with the revisions before the rolled back one.
+			ret.Revisions = prev.Revisions
+			ret.RolledBackRevision = prev.RolledBackRevision
+		}
 		// So if |prev.revisions| == 0 => then there was no rollout —
 		// nothing is required to step.
 		// If |prev.revisions| == 1 and it matches current revision then
@@ -396,7 +750,8 @@ func stepConfig(goal, prev *ConfigurationRollout, nowTS int64, logger *zap.Sugar
 			ret.StepParams = prev.StepParams
 			// We might end up here before `ObserveReady` is called.
 			// In that case don't step individual revisions just yet.
-			if ret.StepParams.StepSize > 0 {
+			// Neither step the paused rollouts.
+			if ret.StepParams.StepSize > 0 && ret.StepParams.PauseTime == 0 {
 				// adjustPercentage above would've already accounted if target for the
 				// whole Configuration changed up or down. So here we should just redistribute
 				// the existing values.
@@ -412,6 +767,7 @@ func stepConfig(goal, prev *ConfigurationRollout, nowTS int64, logger *zap.Sugar
 	logger.Debugf("Starting a new revision rollout for configuration %s and revision %s at %d",
 		goal.ConfigurationName, goal.Revisions[0].RevisionName, nowTS)
 	ret.StepParams.StartTime = nowTS
+	ret.StepParams.Steps = goal.Schedule
 
 	// Go backwards and find first revision with traffic assignment > 0.
 	// Reduce it by one, so we can give that 1% to the new revision.
@@ -445,6 +801,33 @@ func stepConfig(goal, prev *ConfigurationRollout, nowTS int64, logger *zap.Sugar
 	return ret
 }
 
+// stepCutover computes the blue/green rollout of the configuration from its
+// previous state. The traffic stays with the previous revisions while a new
+// revision waits to be promoted, and a new revision replaces the previous
+// green revision, if any.
+func stepCutover(ret, prev *ConfigurationRollout, nowTS int64, logger *zap.SugaredLogger) *ConfigurationRollout {
+	ret.BlueGreen = true
+	pc := len(prev.Revisions)
+	goalRev := ret.Revisions[0].RevisionName
+	switch {
+	case pc == 0:
+		// No traffic to cut over from.
+	case goalRev == prev.Revisions[pc-1].RevisionName:
+		// The goal revision serves the traffic already, keep the previous one
+		// warm until the rollback window is over.
+		if co := prev.Cutover; co != nil && co.Blue != "" && nowTS < co.KeepWarmUntil {
+			ret.Cutover = co
+		}
+	default:
+		if prev.green() != goalRev {
+			logger.Infof("Revision %s of config %s waits to be promoted", goalRev, ret.ConfigurationName)
+		}
+		ret.Revisions = prev.Revisions
+		ret.Cutover = &Cutover{Green: goalRev}
+	}
+	return ret
+}
+
 // computeProperties computes the time between steps, each step size
 // and next reconcile time. This is invoked when the rollout just starts.
 // nowTS current unix timestamp in ns.
@@ -484,6 +867,43 @@ func (cur *ConfigurationRollout) computeProperties(nowTS, minStepSec, durationSe
 	cur.StepParams.NextStepTime = int64(nowTS + stepDuration)
 }
 
+// observeSteps sets up the rollout per its custom schedule, when it just
+// starts. If the newest revision is still short of the first step, it moves
+// there right away, otherwise it holds at the first step.
+func (cur *ConfigurationRollout) observeSteps(nowTS int64) {
+	cur.StepParams.StepIndex = 0
+	if need := cur.stepShare(0) - cur.Revisions[len(cur.Revisions)-1].Percent; need > 0 {
+		cur.StepParams.StepSize = need
+		cur.StepParams.StepDuration = cur.StepParams.Steps[0].Hold
+		cur.StepParams.NextStepTime = nowTS
+		return
+	}
+	cur.holdStep(nowTS)
+}
+
+// holdStep holds the rollout at the current step of its custom schedule,
+// after which it moves to the following one.
+func (cur *ConfigurationRollout) holdStep(nowTS int64) {
+	params := &cur.StepParams
+	if params.StepIndex < len(params.Steps) {
+		params.StepDuration = params.Steps[params.StepIndex].Hold
+		params.StepIndex++
+	}
+	params.NextStepTime = nowTS + params.StepDuration
+	// Move at least 1%, so that the rollout always progresses.
+	params.StepSize = max(1, cur.stepShare(params.StepIndex)-cur.Revisions[len(cur.Revisions)-1].Percent)
+}
+
+// stepShare returns the share of the route traffic the newest revision
+// receives at the i-th step of the custom schedule, or all the traffic of the
+// configuration past the last step.
+func (cur *ConfigurationRollout) stepShare(i int) int {
+	if i >= len(cur.StepParams.Steps) {
+		return cur.Percent
+	}
+	return max(1, int(math.Round(float64(cur.StepParams.Steps[i].Percent*cur.Percent)/100)))
+}
+
 // sortRollout sorts the rollout based on tag so it's consistent
 // from run to run, since input to the process is map iterator.
 func sortRollout(r *Rollout) {
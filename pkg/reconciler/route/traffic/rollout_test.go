@@ -23,13 +23,17 @@ package traffic
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	. "knative.dev/pkg/logging/testing"
+	v1 "knative.dev/serving/pkg/apis/serving/v1"
 )
 
 func TestStep(t *testing.T) {
@@ -988,6 +992,217 @@ func TestStep(t *testing.T) {
 				}},
 			}},
 		},
+	}, {
+		name: "rolled back, same goal",
+		cur: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "sticky-fingers",
+					Percent:      100,
+				}},
+			}},
+		},
+		prev: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "let-it-bleed",
+					Percent:      100,
+				}},
+				RolledBackRevision: "sticky-fingers",
+			}},
+		},
+		want: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "let-it-bleed",
+					Percent:      100,
+				}},
+				RolledBackRevision: "sticky-fingers",
+			}},
+		},
+	}, {
+		name: "rolled back, new goal",
+		cur: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "exile-on-main-st",
+					Percent:      100,
+				}},
+			}},
+		},
+		prev: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "let-it-bleed",
+					Percent:      100,
+				}},
+				RolledBackRevision: "sticky-fingers",
+			}},
+		},
+		want: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "let-it-bleed",
+					Percent:      99,
+				}, {
+					RevisionName: "exile-on-main-st",
+					Percent:      1,
+				}},
+				StepParams: RolloutParams{
+					StartTime: now,
+				},
+			}},
+		},
+	}, {
+		name: "new rollout, custom schedule",
+		cur: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "some-girls",
+					Percent:      100,
+				}},
+				Schedule: []RolloutStep{{Percent: 5, Hold: 100}, {Percent: 25, Hold: 200}},
+			}},
+		},
+		prev: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "black-and-blue",
+					Percent:      100,
+				}},
+			}},
+		},
+		want: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "black-and-blue",
+					Percent:      99,
+				}, {
+					RevisionName: "some-girls",
+					Percent:      1,
+				}},
+				StepParams: RolloutParams{
+					StartTime: now,
+					Steps:     []RolloutStep{{Percent: 5, Hold: 100}, {Percent: 25, Hold: 200}},
+				},
+			}},
+		},
+	}, {
+		name: "custom schedule, step",
+		cur: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "some-girls",
+					Percent:      100,
+				}},
+			}},
+		},
+		prev: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "black-and-blue",
+					Percent:      95,
+				}, {
+					RevisionName: "some-girls",
+					Percent:      5,
+				}},
+				StepParams: RolloutParams{
+					StartTime:    now - 200,
+					NextStepTime: now,
+					StepDuration: 100,
+					StepSize:     20,
+					Steps:        []RolloutStep{{Percent: 5, Hold: 100}, {Percent: 25, Hold: 200}},
+					StepIndex:    1,
+				},
+			}},
+		},
+		want: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "black-and-blue",
+					Percent:      75,
+				}, {
+					RevisionName: "some-girls",
+					Percent:      25,
+				}},
+				StepParams: RolloutParams{
+					StartTime:    now - 200,
+					NextStepTime: now + 200,
+					StepDuration: 200,
+					StepSize:     75,
+					Steps:        []RolloutStep{{Percent: 5, Hold: 100}, {Percent: 25, Hold: 200}},
+					StepIndex:    2,
+				},
+			}},
+		},
+		wantNextStep: now + 200,
+	}, {
+		name: "custom schedule, last step",
+		cur: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "some-girls",
+					Percent:      100,
+				}},
+			}},
+		},
+		prev: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "black-and-blue",
+					Percent:      75,
+				}, {
+					RevisionName: "some-girls",
+					Percent:      25,
+				}},
+				StepParams: RolloutParams{
+					StartTime:    now - 400,
+					NextStepTime: now,
+					StepDuration: 200,
+					StepSize:     75,
+					Steps:        []RolloutStep{{Percent: 5, Hold: 100}, {Percent: 25, Hold: 200}},
+					StepIndex:    2,
+				},
+			}},
+		},
+		want: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "mick",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "some-girls",
+					Percent:      100,
+				}},
+			}},
+		},
 	}}
 
 	for _, tc := range tests {
@@ -1129,6 +1344,651 @@ func TestObserveReady(t *testing.T) {
 
 }
 
+func TestObserveReadySteps(t *testing.T) {
+	const now = 200620092020
+	steps := func(s ...RolloutStep) []RolloutStep { return s }
+	ro := Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: "short of first step",
+			Percent:           50,
+			Revisions: []RevisionRollout{{
+				RevisionName: "goats-head-soup",
+				Percent:      49,
+			}, {
+				RevisionName: "its-only-rock-n-roll",
+				Percent:      1,
+			}},
+			StepParams: RolloutParams{
+				StartTime: now - 1,
+				Steps:     steps(RolloutStep{Percent: 10, Hold: 10}, RolloutStep{Percent: 50, Hold: 20}),
+			},
+		}, {
+			ConfigurationName: "at first step",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "goats-head-soup",
+				Percent:      99,
+			}, {
+				RevisionName: "its-only-rock-n-roll",
+				Percent:      1,
+			}},
+			StepParams: RolloutParams{
+				StartTime: now - 1,
+				Steps:     steps(RolloutStep{Percent: 1, Hold: 10}, RolloutStep{Percent: 20, Hold: 20}),
+			},
+		}},
+	}
+	want := []RolloutParams{{
+		StartTime:    now - 1,
+		NextStepTime: now,
+		StepDuration: 10,
+		StepSize:     4,
+		Steps:        steps(RolloutStep{Percent: 10, Hold: 10}, RolloutStep{Percent: 50, Hold: 20}),
+	}, {
+		StartTime:    now - 1,
+		NextStepTime: now + 10,
+		StepDuration: 10,
+		StepSize:     19,
+		Steps:        steps(RolloutStep{Percent: 1, Hold: 10}, RolloutStep{Percent: 20, Hold: 20}),
+		StepIndex:    1,
+	}}
+
+	ro.ObserveReady(TestContextWithLogger(t), now, 0)
+	for i, c := range ro.Configurations {
+		if !cmp.Equal(c.StepParams, want[i]) {
+			t.Errorf("ObserveReady(%s) generated mismatched params: diff(-want,+got):\n%s",
+				c.ConfigurationName, cmp.Diff(want[i], c.StepParams))
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	const now = 2020
+	rollout := func() *Rollout {
+		return &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "due",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "beggars-banquet",
+					Percent:      70,
+				}, {
+					RevisionName: "let-it-bleed",
+					Percent:      30,
+				}},
+				StepParams: RolloutParams{
+					StartTime:    1982,
+					NextStepTime: now,
+					StepDuration: 10,
+					StepSize:     10,
+				},
+			}, {
+				ConfigurationName: "not-due",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "aftermath",
+					Percent:      90,
+				}, {
+					RevisionName: "between-the-buttons",
+					Percent:      10,
+				}},
+				StepParams: RolloutParams{
+					StartTime:    1982,
+					NextStepTime: now + 1,
+					StepDuration: 10,
+					StepSize:     10,
+				},
+			}, {
+				ConfigurationName: "three-revisions",
+				Percent:           100,
+				Revisions: []RevisionRollout{{
+					RevisionName: "some-girls",
+					Percent:      50,
+				}, {
+					RevisionName: "tattoo-you",
+					Percent:      45,
+				}, {
+					RevisionName: "undercover",
+					Percent:      5,
+				}},
+				StepParams: RolloutParams{
+					StartTime:    1982,
+					NextStepTime: now - 1,
+					StepDuration: 10,
+					StepSize:     5,
+				},
+			}},
+		}
+	}
+
+	t.Run("pass", func(t *testing.T) {
+		ro := rollout()
+		var checked []string
+		err := ro.Analyze(TestContextWithLogger(t), now, true /*rollback*/, func(rev string) error {
+			checked = append(checked, rev)
+			return nil
+		})
+		if err != nil {
+			t.Error("Analyze() =", err)
+		}
+		if want := []string{"let-it-bleed", "undercover"}; !cmp.Equal(checked, want) {
+			t.Errorf("Checked revisions = %v, want: %v", checked, want)
+		}
+		if want := rollout(); !cmp.Equal(ro, want) {
+			t.Errorf("Analyze() changed the rollout, diff(-want,+got):\n%s", cmp.Diff(want, ro))
+		}
+	})
+
+	t.Run("pause", func(t *testing.T) {
+		ro := rollout()
+		err := ro.Analyze(TestContextWithLogger(t), now, false /*rollback*/, func(rev string) error {
+			return errors.New(rev + " is failing")
+		})
+		if got, want := fmt.Sprint(err), "let-it-bleed is failing"; got != want {
+			t.Errorf("Analyze() = %s, want: %s", got, want)
+		}
+		want := rollout()
+		want.Configurations[0].StepParams.NextStepTime = now + 10
+		want.Configurations[2].StepParams.NextStepTime = now + 10
+		if !cmp.Equal(ro, want) {
+			t.Errorf("Wrong paused rollout, diff(-want,+got):\n%s", cmp.Diff(want, ro))
+		}
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		ro := rollout()
+		err := ro.Analyze(TestContextWithLogger(t), now, true /*rollback*/, func(rev string) error {
+			if rev == "let-it-bleed" {
+				return fmt.Errorf("%s: %w", rev, ErrNoAnalysisData)
+			}
+			return errors.New(rev + " is failing")
+		})
+		// The failure is reported rather than the lack of data.
+		if got, want := fmt.Sprint(err), "undercover is failing"; got != want {
+			t.Errorf("Analyze() = %s, want: %s", got, want)
+		}
+		// The revision without data is paused rather than rolled back.
+		want := rollout()
+		want.Configurations[0].StepParams.NextStepTime = now + 10
+		want.Configurations[0].StepParams.NoDataTime = now
+		want.Configurations[2].Revisions = []RevisionRollout{{
+			RevisionName: "some-girls",
+			Percent:      50,
+		}, {
+			RevisionName: "tattoo-you",
+			Percent:      50,
+		}}
+		want.Configurations[2].StepParams.NextStepTime = now + 10
+		want.Configurations[2].RolledBackRevision = "undercover"
+		if !cmp.Equal(ro, want) {
+			t.Errorf("Wrong analyzed rollout, diff(-want,+got):\n%s", cmp.Diff(want, ro))
+		}
+	})
+
+	t.Run("no data timeout", func(t *testing.T) {
+		noData := func(rev string) error {
+			return fmt.Errorf("%s: %w", rev, ErrNoAnalysisData)
+		}
+		ro := rollout()
+		ro.Configurations[0].StepParams.NoDataTime = now - int64(NoAnalysisDataTimeout) + 1
+		ro.Configurations[2].StepParams.NoDataTime = now - int64(NoAnalysisDataTimeout)
+		err := ro.Analyze(TestContextWithLogger(t), now, true /*rollback*/, noData)
+		if got, want := fmt.Sprint(err), `revision "undercover" has had no data to analyze for 10m0s`; got != want {
+			t.Errorf("Analyze() = %s, want: %s", got, want)
+		}
+		// Only the revision without data for too long is rolled back.
+		want := rollout()
+		want.Configurations[0].StepParams.NextStepTime = now + 10
+		want.Configurations[0].StepParams.NoDataTime = now - int64(NoAnalysisDataTimeout) + 1
+		want.Configurations[2].Revisions = []RevisionRollout{{
+			RevisionName: "some-girls",
+			Percent:      50,
+		}, {
+			RevisionName: "tattoo-you",
+			Percent:      50,
+		}}
+		want.Configurations[2].StepParams.NextStepTime = now + 10
+		want.Configurations[2].RolledBackRevision = "undercover"
+		if !cmp.Equal(ro, want) {
+			t.Errorf("Wrong analyzed rollout, diff(-want,+got):\n%s", cmp.Diff(want, ro))
+		}
+
+		// The data resets the timeout.
+		ro = rollout()
+		ro.Configurations[0].StepParams.NoDataTime = now - 1
+		if err := ro.Analyze(TestContextWithLogger(t), now, true /*rollback*/, func(string) error {
+			return nil
+		}); err != nil {
+			t.Error("Analyze() =", err)
+		}
+		if got := ro.Configurations[0].StepParams.NoDataTime; got != 0 {
+			t.Errorf("NoDataTime = %d, want: 0", got)
+		}
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		ro := rollout()
+		if err := ro.Analyze(TestContextWithLogger(t), now, true /*rollback*/, func(rev string) error {
+			return errors.New(rev + " is failing")
+		}); err == nil {
+			t.Error("Analyze() = nil, want an error")
+		}
+		want := rollout()
+		want.Configurations[0].Revisions = []RevisionRollout{{
+			RevisionName: "beggars-banquet",
+			Percent:      100,
+		}}
+		want.Configurations[0].StepParams = RolloutParams{}
+		want.Configurations[0].RolledBackRevision = "let-it-bleed"
+		want.Configurations[2].Revisions = []RevisionRollout{{
+			RevisionName: "some-girls",
+			Percent:      50,
+		}, {
+			RevisionName: "tattoo-you",
+			Percent:      50,
+		}}
+		want.Configurations[2].StepParams.NextStepTime = now + 10
+		want.Configurations[2].RolledBackRevision = "undercover"
+		if !cmp.Equal(ro, want) {
+			t.Errorf("Wrong rolled back rollout, diff(-want,+got):\n%s", cmp.Diff(want, ro))
+		}
+		if !ro.Validate() {
+			t.Errorf("Analyze() returned an invalid rollout:\n%#v", ro)
+		}
+		if !ro.RolledBack() {
+			t.Error("RolledBack() = false")
+		}
+	})
+}
+
+func TestPauseResume(t *testing.T) {
+	const now = 2020
+	ro := &Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: "done",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "tattoo-you",
+				Percent:      100,
+			}},
+		}, {
+			ConfigurationName: "in-progress",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "tattoo-you",
+				Percent:      80,
+			}, {
+				RevisionName: "undercover",
+				Percent:      20,
+			}},
+			StepParams: RolloutParams{
+				StartTime:    now - 30,
+				NextStepTime: now + 5,
+				StepDuration: 10,
+				StepSize:     10,
+			},
+		}},
+	}
+
+	ro.Pause(now)
+	if got := ro.Configurations[0].StepParams.PauseTime; got != 0 {
+		t.Errorf("PauseTime of the done rollout = %d, want: 0", got)
+	}
+	if got, want := ro.Configurations[1].StepParams.PauseTime, int64(now); got != want {
+		t.Errorf("PauseTime = %d, want: %d", got, want)
+	}
+	// Pausing again doesn't reset the pause time.
+	ro.Pause(now + 10)
+	if got, want := ro.Configurations[1].StepParams.PauseTime, int64(now); got != want {
+		t.Errorf("PauseTime = %d, want: %d", got, want)
+	}
+
+	// The paused rollout doesn't step, though it's due.
+	got, nextStep := (&Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: "in-progress",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "undercover",
+				Percent:      100,
+			}},
+		}},
+	}).Step(TestContextWithLogger(t), &Rollout{Configurations: ro.Configurations[1:]}, now+100)
+	if nextStep != 0 {
+		t.Errorf("NextStepTime = %d, want: 0", nextStep)
+	}
+	if got, want := got.Configurations[0].Revisions, ro.Configurations[1].Revisions; !cmp.Equal(got, want) {
+		t.Errorf("Paused rollout stepped, diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+
+	// Resuming carries on from where it was paused.
+	ro.Resume(now + 100)
+	want := RolloutParams{
+		StartTime:    now - 30,
+		NextStepTime: now + 105,
+		StepDuration: 10,
+		StepSize:     10,
+	}
+	if got := ro.Configurations[1].StepParams; !cmp.Equal(got, want) {
+		t.Errorf("Resumed rollout mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
+
+func TestPromote(t *testing.T) {
+	ro := &Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: "done",
+			Percent:           40,
+			Revisions: []RevisionRollout{{
+				RevisionName: "tattoo-you",
+				Percent:      40,
+			}},
+		}, {
+			ConfigurationName: "in-progress",
+			Percent:           60,
+			Revisions: []RevisionRollout{{
+				RevisionName: "tattoo-you",
+				Percent:      30,
+			}, {
+				RevisionName: "undercover",
+				Percent:      20,
+			}, {
+				RevisionName: "dirty-work",
+				Percent:      10,
+			}},
+			StepParams: RolloutParams{
+				StartTime:    1981,
+				NextStepTime: 1986,
+				StepDuration: 5,
+				StepSize:     10,
+			},
+		}, {
+			ConfigurationName: "pending",
+			Tag:               "blue-green",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "emotional-rescue",
+				Percent:      100,
+			}},
+			Cutover: &Cutover{Green: "some-girls"},
+		}},
+	}
+	want := &Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: "done",
+			Percent:           40,
+			Revisions: []RevisionRollout{{
+				RevisionName: "tattoo-you",
+				Percent:      40,
+			}},
+		}, {
+			ConfigurationName: "in-progress",
+			Percent:           60,
+			Revisions: []RevisionRollout{{
+				RevisionName: "dirty-work",
+				Percent:      60,
+			}},
+		}, {
+			ConfigurationName: "pending",
+			Tag:               "blue-green",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "some-girls",
+				Percent:      100,
+			}},
+			Cutover: &Cutover{Blue: "emotional-rescue", KeepWarmUntil: 1986 + int64(time.Minute)},
+		}},
+	}
+
+	ro.Promote(1986, time.Minute)
+	if !cmp.Equal(ro, want) {
+		t.Errorf("Promoted rollout mismatch, diff(-want,+got):\n%s", cmp.Diff(want, ro))
+	}
+}
+
+func TestStepBlueGreen(t *testing.T) {
+	const now = 1976
+	blue := func(keepWarmUntil int64) *Cutover {
+		return &Cutover{Blue: "black-and-blue", KeepWarmUntil: keepWarmUntil}
+	}
+	tests := []struct {
+		name string
+		prev *ConfigurationRollout
+		goal string
+		want *ConfigurationRollout
+	}{{
+		name: "no new revision",
+		prev: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "black-and-blue", Percent: 100}},
+		},
+		goal: "black-and-blue",
+		want: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "black-and-blue", Percent: 100}},
+		},
+	}, {
+		name: "new revision waits for promotion",
+		prev: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "black-and-blue", Percent: 100}},
+		},
+		goal: "some-girls",
+		want: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "black-and-blue", Percent: 100}},
+			Cutover:   &Cutover{Green: "some-girls"},
+		},
+	}, {
+		name: "newer revision replaces the green one",
+		prev: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "black-and-blue", Percent: 100}},
+			Cutover:   &Cutover{Green: "some-girls"},
+		},
+		goal: "emotional-rescue",
+		want: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "black-and-blue", Percent: 100}},
+			Cutover:   &Cutover{Green: "emotional-rescue"},
+		},
+	}, {
+		name: "gradual rollout in progress is held",
+		prev: &ConfigurationRollout{
+			Revisions: []RevisionRollout{
+				{RevisionName: "black-and-blue", Percent: 70},
+				{RevisionName: "some-girls", Percent: 30},
+			},
+			StepParams: RolloutParams{StartTime: now - 10, NextStepTime: now, StepDuration: 10, StepSize: 10},
+		},
+		goal: "emotional-rescue",
+		want: &ConfigurationRollout{
+			Revisions: []RevisionRollout{
+				{RevisionName: "black-and-blue", Percent: 70},
+				{RevisionName: "some-girls", Percent: 30},
+			},
+			Cutover: &Cutover{Green: "emotional-rescue"},
+		},
+	}, {
+		name: "blue revision kept warm",
+		prev: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "some-girls", Percent: 100}},
+			Cutover:   blue(now + 1),
+		},
+		goal: "some-girls",
+		want: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "some-girls", Percent: 100}},
+			Cutover:   blue(now + 1),
+		},
+	}, {
+		name: "blue revision cools down",
+		prev: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "some-girls", Percent: 100}},
+			Cutover:   blue(now),
+		},
+		goal: "some-girls",
+		want: &ConfigurationRollout{
+			Revisions: []RevisionRollout{{RevisionName: "some-girls", Percent: 100}},
+		},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.prev.ConfigurationName, tc.prev.Percent = "stones", 100
+			tc.want.ConfigurationName, tc.want.Percent, tc.want.BlueGreen = "stones", 100, true
+			cur := &Rollout{
+				Configurations: []*ConfigurationRollout{{
+					ConfigurationName: "stones",
+					Percent:           100,
+					Revisions:         []RevisionRollout{{RevisionName: tc.goal, Percent: 100}},
+				}},
+			}
+			cur.UseBlueGreen()
+			got, _ := cur.Step(TestContextWithLogger(t), &Rollout{
+				Configurations: []*ConfigurationRollout{tc.prev},
+			}, now)
+			if want := (&Rollout{Configurations: []*ConfigurationRollout{tc.want}}); !cmp.Equal(got, want) {
+				t.Errorf("Step mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestWarmRevisions(t *testing.T) {
+	const now = 1976
+	ro := &Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: "gradual",
+			Percent:           50,
+			Revisions:         []RevisionRollout{{RevisionName: "tattoo-you", Percent: 50}},
+		}, {
+			ConfigurationName: "pending",
+			Percent:           25,
+			Revisions:         []RevisionRollout{{RevisionName: "black-and-blue", Percent: 25}},
+			Cutover:           &Cutover{Green: "some-girls"},
+		}, {
+			ConfigurationName: "promoted",
+			Percent:           15,
+			Revisions:         []RevisionRollout{{RevisionName: "emotional-rescue", Percent: 15}},
+			Cutover:           &Cutover{Blue: "goats-head-soup", KeepWarmUntil: now + 5},
+		}, {
+			ConfigurationName: "cooled-down",
+			Percent:           10,
+			Revisions:         []RevisionRollout{{RevisionName: "undercover", Percent: 10}},
+			Cutover:           &Cutover{Blue: "dirty-work", KeepWarmUntil: now},
+		}},
+	}
+	want := map[string]WarmRevision{
+		"some-girls":      {Tag: "green-some-girls", ScaleOf: "black-and-blue"},
+		"goats-head-soup": {Tag: "blue-goats-head-soup", ScaleOf: "emotional-rescue"},
+	}
+	if got := ro.WarmRevisions(now); !cmp.Equal(got, want) {
+		t.Errorf("WarmRevisions mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+	if got, want := ro.KeepWarmUntil(), int64(now); got != want {
+		t.Errorf("KeepWarmUntil = %d, want: %d", got, want)
+	}
+	if ro.Done() {
+		t.Error("Done() = true with a revision waiting to be promoted")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	const now = 2020
+	ro := &Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: "done",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "steel-wheels",
+				Percent:      100,
+			}},
+		}, {
+			ConfigurationName: "not-observed",
+			Tag:               "voodoo",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "steel-wheels",
+				Percent:      99,
+			}, {
+				RevisionName: "voodoo-lounge",
+				Percent:      1,
+			}},
+			StepParams: RolloutParams{
+				StartTime: now,
+			},
+		}, {
+			ConfigurationName: "linear",
+			Tag:               "voodoo",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "steel-wheels",
+				Percent:      75,
+			}, {
+				RevisionName: "voodoo-lounge",
+				Percent:      25,
+			}},
+			StepParams: RolloutParams{
+				StartTime:    now - 20,
+				NextStepTime: now + 10,
+				StepDuration: 10,
+				StepSize:     10,
+			},
+		}, {
+			ConfigurationName: "scheduled",
+			Tag:               "voodoo",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "steel-wheels",
+				Percent:      95,
+			}, {
+				RevisionName: "voodoo-lounge",
+				Percent:      5,
+			}},
+			StepParams: RolloutParams{
+				StartTime:    now - 20,
+				NextStepTime: now + 10,
+				StepDuration: 10,
+				StepSize:     20,
+				Steps:        []RolloutStep{{Percent: 5, Hold: 10}, {Percent: 25, Hold: 10}},
+				StepIndex:    1,
+				PauseTime:    now,
+			},
+		}, {
+			ConfigurationName: "blue-green",
+			Tag:               "voodoo",
+			Percent:           100,
+			Revisions: []RevisionRollout{{
+				RevisionName: "steel-wheels",
+				Percent:      100,
+			}},
+			Cutover: &Cutover{Green: "voodoo-lounge"},
+		}},
+	}
+	want := []v1.RolloutStatus{{
+		ConfigurationName: "not-observed",
+		Tag:               "voodoo",
+		RevisionName:      "voodoo-lounge",
+		Percent:           1,
+	}, {
+		ConfigurationName: "linear",
+		Tag:               "voodoo",
+		RevisionName:      "voodoo-lounge",
+		Percent:           25,
+		NextStepTime:      &metav1.Time{Time: time.Unix(0, now+10)},
+		RemainingSteps:    8,
+	}, {
+		ConfigurationName: "scheduled",
+		Tag:               "voodoo",
+		RevisionName:      "voodoo-lounge",
+		Percent:           5,
+		Paused:            true,
+		RemainingSteps:    2,
+	}, {
+		ConfigurationName: "blue-green",
+		Tag:               "voodoo",
+		RevisionName:      "voodoo-lounge",
+	}}
+
+	if got := ro.Status(); !cmp.Equal(got, want) {
+		t.Errorf("Status mismatch, diff(-want,+got):\n%s", cmp.Diff(want, got))
+	}
+}
+
 func TestAdjustPercentage(t *testing.T) {
 	tests := []struct {
 		name string
@@ -1287,6 +2147,22 @@ func TestValidateFailures(t *testing.T) {
 				}},
 			}},
 		},
+	}, {
+		name: "step index past the schedule",
+		r: &Rollout{
+			Configurations: []*ConfigurationRollout{{
+				ConfigurationName: "keith",
+				Percent:           71,
+				StepParams: RolloutParams{
+					Steps:     []RolloutStep{{Percent: 10, Hold: 1}},
+					StepIndex: 2,
+				},
+				Revisions: []RevisionRollout{{
+					RevisionName: "black-on-blue",
+					Percent:      71,
+				}},
+			}},
+		},
 	}, {
 		name: "step negative",
 		r: &Rollout{
@@ -1438,6 +2314,14 @@ func TestJSONRoundtrip(t *testing.T) {
 				RevisionName: "flowers",
 				Percent:      17,
 			}},
+			StepParams: RolloutParams{
+				StartTime:    1967,
+				NextStepTime: 1968,
+				StepDuration: 1,
+				StepSize:     33,
+				Steps:        []RolloutStep{{Percent: 10, Hold: 1}, {Percent: 50, Hold: 1}},
+				StepIndex:    1,
+			},
 		}},
 	}
 
@@ -48,6 +48,13 @@ type RevisionTarget struct {
 // RevisionTargets is a collection of revision targets.
 type RevisionTargets []RevisionTarget
 
+// A RuleTarget adds the revision targets the requests matching a
+// v1.RouteRule are routed to: its Revision, or the targets of its tag.
+type RuleTarget struct {
+	v1.RouteRule
+	Targets RevisionTargets
+}
+
 // Config encapsulates details of our traffic so that we don't need to make API calls, or use details of the
 // route beyond its ObjectMeta to make routing changes.
 type Config struct {
@@ -60,6 +67,9 @@ type Config struct {
 	// Visibility of the traffic targets.
 	Visibility map[string]netv1alpha1.IngressVisibility
 
+	// Rules are the rules routing the matching requests, in order.
+	Rules []RuleTarget
+
 	// A list traffic targets, flattened to the Revision level.  This
 	// is used to populate the Route.Status.TrafficTarget field.
 	revisionTargets RevisionTargets
@@ -83,6 +93,27 @@ func BuildTrafficConfiguration(configLister listers.ConfigurationLister, revList
 	return newBuilder(configLister, revLister, r).build()
 }
 
+// AddRevisionTag exposes the revision under the tag, at a dedicated url with
+// the visibility of the Route, without routing any of the Route traffic to it.
+func (cfg *Config) AddRevisionTag(tag string, rev *v1.Revision) {
+	target := RevisionTarget{
+		TrafficTarget: v1.TrafficTarget{
+			Tag:            tag,
+			RevisionName:   rev.Name,
+			LatestRevision: ptr.Bool(false),
+		},
+		Protocol: rev.GetProtocol(),
+	}
+	target.Percent = ptr.Int64(100)
+	cfg.Targets[tag] = RevisionTargets{target}
+	target.Percent = ptr.Int64(0)
+	cfg.revisionTargets = append(cfg.revisionTargets, target)
+	cfg.Revisions[rev.Name] = rev
+	if cfg.Visibility != nil {
+		cfg.Visibility[tag] = cfg.Visibility[DefaultTarget]
+	}
+}
+
 func rolloutConfig(cfgName string, ros []*ConfigurationRollout) *ConfigurationRollout {
 	for _, ro := range ros {
 		if ro.ConfigurationName == cfgName {
@@ -192,6 +223,9 @@ type configBuilder struct {
 	// revisionTargets is the original list of targets, at the Revision level.
 	revisionTargets RevisionTargets
 
+	// rules is the list of rule targets.
+	rules []RuleTarget
+
 	// configurations contains all the referred Configuration, keyed by their name.
 	configurations map[string]*v1.Configuration
 	// revisions contains all the referred Revision, keyed by their name.
@@ -228,7 +262,7 @@ func (cfg *Config) BuildRollout() *Rollout {
 	rollout := &Rollout{}
 
 	for tag, targets := range cfg.Targets {
-		buildRolloutForTag(rollout, tag, targets)
+		buildRolloutForTag(rollout, tag, targets, cfg.Configurations)
 	}
 	sortRollout(rollout)
 	return rollout
@@ -236,7 +270,7 @@ func (cfg *Config) BuildRollout() *Rollout {
 
 // buildRolloutForTag builds the current rollout state.
 // It is expected to be invoked after applySpecTraffic.
-func buildRolloutForTag(r *Rollout, tag string, rts RevisionTargets) {
+func buildRolloutForTag(r *Rollout, tag string, rts RevisionTargets, configs map[string]*v1.Configuration) {
 	// Only main target will have more than 1 element here.
 	for _, rt := range rts {
 		// Skip if it's revision target.
@@ -260,10 +294,33 @@ func buildRolloutForTag(r *Rollout, tag string, rts RevisionTargets) {
 				// during rollout it will be overridden by the rollout logic.
 				Percent: int(*rt.Percent),
 			}},
+			Schedule: rolloutSchedule(configs[rt.ConfigurationName]),
 		})
 	}
 }
 
+// rolloutSchedule returns the custom rollout steps of the configuration, or
+// nil if it rolls out linearly.
+func rolloutSchedule(cfg *v1.Configuration) []RolloutStep {
+	if cfg == nil {
+		return nil
+	}
+	_, v, ok := serving.RolloutStepsAnnotation.Get(cfg.Annotations)
+	if !ok {
+		return nil
+	}
+	// The webhook should've declined the invalid values for this annotation.
+	steps, err := serving.ParseRolloutSteps(v)
+	if err != nil {
+		return nil
+	}
+	ret := make([]RolloutStep, len(steps))
+	for i, s := range steps {
+		ret[i] = RolloutStep{Percent: s.Percent, Hold: int64(s.Hold)}
+	}
+	return ret
+}
+
 func (cb *configBuilder) applySpecTraffic() error {
 	traffic := cb.route.Spec.Traffic
 	for i := range traffic {
@@ -275,6 +332,16 @@ func (cb *configBuilder) applySpecTraffic() error {
 	return nil
 }
 
+func (cb *configBuilder) applySpecRules() error {
+	rules := cb.route.Spec.Rules
+	for i := range rules {
+		if err := cb.addRuleTarget(&rules[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (cb *configBuilder) getConfiguration(name string) (*v1.Configuration, error) {
 	config, ok := cb.configurations[name]
 	if !ok {
@@ -320,6 +387,39 @@ func (cb *configBuilder) addTrafficTarget(tt *v1.TrafficTarget) (err error) {
 	} else if tt.ConfigurationName != "" {
 		err = cb.addConfigurationTarget(tt)
 	}
+	return cb.handleTargetError(err)
+}
+
+// addRuleTarget adds a rule target. The Revision of a rule has to be ready
+// like that of a traffic target, while the targets of a tag are looked up
+// once all the traffic targets are known.
+func (cb *configBuilder) addRuleTarget(rr *v1.RouteRule) error {
+	rt := RuleTarget{RouteRule: *rr.DeepCopy()}
+	if rr.RevisionName != "" {
+		rev, err := cb.getRevision(rr.RevisionName)
+		if err == nil && !rev.IsReady() {
+			err = errUnreadyRevision(rev)
+		}
+		if err != nil {
+			return cb.handleTargetError(err)
+		}
+		rt.Targets = RevisionTargets{{
+			TrafficTarget: v1.TrafficTarget{
+				ConfigurationName: rev.Labels[serving.ConfigurationLabelKey],
+				RevisionName:      rev.Name,
+				Percent:           ptr.Int64(100),
+				LatestRevision:    ptr.Bool(false),
+			},
+			Protocol: rev.GetProtocol(),
+		}}
+	}
+	cb.rules = append(cb.rules, rt)
+	return nil
+}
+
+// handleTargetError records the missing targets, and defers the TargetErrors.
+// It returns the other errors.
+func (cb *configBuilder) handleTargetError(err error) error {
 	if err != nil {
 		var errMissingTarget *missingTargetError
 		if errors.As(err, &errMissingTarget) {
@@ -423,12 +523,23 @@ func (cb *configBuilder) build() (*Config, error) {
 	if err := cb.applySpecTraffic(); err != nil {
 		return nil, err
 	}
+	if err := cb.applySpecRules(); err != nil {
+		return nil, err
+	}
 	if cb.deferredTargetErr != nil {
 		cb.targets = nil
 		cb.revisionTargets = nil
+		cb.rules = nil
+	}
+	targets := consolidateAll(cb.targets)
+	for i := range cb.rules {
+		if tag := cb.rules[i].Tag; tag != "" {
+			cb.rules[i].Targets = targets[tag]
+		}
 	}
 	return &Config{
-		Targets:         consolidateAll(cb.targets),
+		Targets:         targets,
+		Rules:           cb.rules,
 		revisionTargets: cb.revisionTargets,
 		Configurations:  cb.configurations,
 		Revisions:       cb.revisions,
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	net "knative.dev/networking/pkg/apis/networking"
+	netv1alpha1 "knative.dev/networking/pkg/apis/networking/v1alpha1"
 	netcfg "knative.dev/networking/pkg/config"
 	"knative.dev/pkg/ptr"
 	"knative.dev/serving/pkg/apis/serving"
@@ -199,9 +201,9 @@ func TestBuildTrafficConfigurationVanilla(t *testing.T) {
 	}
 }
 
-func testRouteWithTrafficTargets(trafficTarget RouteOption) *v1.Route {
+func testRouteWithTrafficTargets(opts ...RouteOption) *v1.Route {
 	return Route(testNamespace, "test-route",
-		WithRouteLabel(map[string]string{"route": "test-route"}), trafficTarget)
+		append([]RouteOption{WithRouteLabel(map[string]string{"route": "test-route"})}, opts...)...)
 }
 
 func TestBuildTrafficConfigurationNoNameRevision(t *testing.T) {
@@ -1347,6 +1349,83 @@ func TestBuildTrafficConfigurationMissingRevision(t *testing.T) {
 	}
 }
 
+func TestBuildTrafficConfigurationRules(t *testing.T) {
+	oldTarget := RevisionTarget{
+		TrafficTarget: v1.TrafficTarget{
+			ConfigurationName: goodConfig.Name,
+			RevisionName:      goodOldRev.Name,
+			Percent:           ptr.Int64(100),
+			LatestRevision:    ptr.Bool(false),
+		},
+		Protocol: net.ProtocolHTTP1,
+	}
+	betaTarget := RevisionTarget{
+		TrafficTarget: v1.TrafficTarget{
+			Tag:               "beta",
+			ConfigurationName: niceConfig.Name,
+			RevisionName:      niceNewRev.Name,
+			Percent:           ptr.Int64(0),
+			LatestRevision:    ptr.Bool(false),
+		},
+		Protocol: net.ProtocolH2C,
+	}
+	// The tagged targets get all the traffic of their tag.
+	betaTagTarget := betaTarget
+	betaTagTarget.Percent = ptr.Int64(100)
+	beta := v1.RouteRule{
+		Headers: map[string]v1.StringMatch{"X-Cohort": {Exact: "beta"}},
+		Tag:     "beta",
+	}
+	canary := v1.RouteRule{
+		Headers:      map[string]v1.StringMatch{"X-Canary": {Exact: "true"}},
+		RevisionName: goodNewRev.Name,
+	}
+	expected := &Config{
+		Targets: map[string]RevisionTargets{
+			DefaultTarget: {oldTarget, betaTarget},
+			"beta":        {betaTagTarget},
+		},
+		Rules: []RuleTarget{{
+			RouteRule: beta,
+			Targets:   RevisionTargets{betaTagTarget},
+		}, {
+			RouteRule: canary,
+			Targets: RevisionTargets{{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: goodConfig.Name,
+					RevisionName:      goodNewRev.Name,
+					Percent:           ptr.Int64(100),
+					LatestRevision:    ptr.Bool(false),
+				},
+				Protocol: net.ProtocolH2C,
+			}},
+		}},
+		revisionTargets: []RevisionTarget{oldTarget, betaTarget},
+		Configurations: map[string]*v1.Configuration{
+			goodConfig.Name: goodConfig,
+			niceConfig.Name: niceConfig,
+		},
+		Revisions: map[string]*v1.Revision{
+			goodOldRev.Name: goodOldRev,
+			goodNewRev.Name: goodNewRev,
+			niceNewRev.Name: niceNewRev,
+		},
+	}
+	tc, err := BuildTrafficConfiguration(configLister, revLister, testRouteWithTrafficTargets(WithSpecTraffic(v1.TrafficTarget{
+		RevisionName: goodOldRev.Name,
+		Percent:      ptr.Int64(100),
+	}, v1.TrafficTarget{
+		Tag:          "beta",
+		RevisionName: niceNewRev.Name,
+	}), WithSpecRules(beta, canary)))
+	if err != nil {
+		t.Fatal("Unexpected error", err)
+	}
+	if got, want := tc, expected; !cmp.Equal(want, got, cmpOpts...) {
+		t.Fatalf("Unexpected traffic diff (-want +got):\n%s", cmp.Diff(want, got, cmpOpts...))
+	}
+}
+
 var errAPI = errors.New("failed to connect API")
 
 type revFakeErrorLister struct {
@@ -1413,6 +1492,59 @@ func TestRoundTripping(t *testing.T) {
 	}
 }
 
+func TestAddRevisionTag(t *testing.T) {
+	route := testRouteWithTrafficTargets(WithSpecTraffic(v1.TrafficTarget{
+		ConfigurationName: goodConfig.Name,
+		Percent:           ptr.Int64(100),
+	}))
+	tc, err := BuildTrafficConfiguration(configLister, revLister, route)
+	if err != nil {
+		t.Fatal("Unexpected error", err)
+	}
+	tc.Visibility = map[string]netv1alpha1.IngressVisibility{
+		DefaultTarget: netv1alpha1.IngressVisibilityExternalIP,
+	}
+	tc.AddRevisionTag("blue", goodOldRev)
+
+	wantTargets := RevisionTargets{{
+		TrafficTarget: v1.TrafficTarget{
+			Tag:            "blue",
+			RevisionName:   goodOldRev.Name,
+			Percent:        ptr.Int64(100),
+			LatestRevision: ptr.Bool(false),
+		},
+		Protocol: goodOldRev.GetProtocol(),
+	}}
+	if got := tc.Targets["blue"]; !cmp.Equal(got, wantTargets) {
+		t.Errorf("Targets mismatch, diff(-want,+got):\n%s", cmp.Diff(wantTargets, got))
+	}
+	if got, want := tc.Visibility["blue"], netv1alpha1.IngressVisibilityExternalIP; got != want {
+		t.Errorf("Visibility = %v, want: %v", got, want)
+	}
+	if got := tc.Revisions[goodOldRev.Name]; got != goodOldRev {
+		t.Errorf("Revisions[%q] = %v, want: %v", goodOldRev.Name, got, goodOldRev)
+	}
+
+	wantStatus := []v1.TrafficTarget{{
+		RevisionName:   goodNewRev.Name,
+		Percent:        ptr.Int64(100),
+		LatestRevision: ptr.Bool(true),
+	}, {
+		Tag:            "blue",
+		RevisionName:   goodOldRev.Name,
+		URL:            domains.URL(domains.HTTPScheme, "blue-test-route.test.example.com"),
+		Percent:        ptr.Int64(0),
+		LatestRevision: ptr.Bool(false),
+	}}
+	got, err := tc.GetRevisionTrafficTargets(getContext(), route, &Rollout{})
+	if err != nil {
+		t.Fatal("Unexpected error:", err)
+	}
+	if !cmp.Equal(got, wantStatus) {
+		t.Errorf("Traffic targets mismatch, diff(-want,+got):\n%s", cmp.Diff(wantStatus, got))
+	}
+}
+
 func TestRoundTrippingWithRollout(t *testing.T) {
 	expected := []v1.TrafficTarget{{
 		RevisionName:   "older-rev",
@@ -1609,6 +1741,67 @@ func getTestReadyConfig(name string) (*v1.Configuration, *v1.Revision, *v1.Revis
 	return config, rev1, rev2
 }
 
+func TestBuildRolloutSchedule(t *testing.T) {
+	cfg := goodConfig.DeepCopy()
+	cfg.Annotations = map[string]string{
+		serving.RolloutStepsKey: "5:10m,25:30m",
+	}
+	invalid := niceConfig.DeepCopy()
+	invalid.Annotations = map[string]string{
+		serving.RolloutStepsKey: "25:10m,5:30m",
+	}
+	tc := &Config{
+		Targets: map[string]RevisionTargets{
+			DefaultTarget: {{
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: cfg.Name,
+					RevisionName:      goodNewRev.Name,
+					Percent:           ptr.Int64(60),
+					LatestRevision:    ptr.Bool(true),
+				},
+			}, {
+				TrafficTarget: v1.TrafficTarget{
+					ConfigurationName: invalid.Name,
+					RevisionName:      niceNewRev.Name,
+					Percent:           ptr.Int64(40),
+					LatestRevision:    ptr.Bool(true),
+				},
+			}},
+		},
+		Configurations: map[string]*v1.Configuration{
+			cfg.Name:     cfg,
+			invalid.Name: invalid,
+		},
+	}
+	wantR := &Rollout{
+		Configurations: []*ConfigurationRollout{{
+			ConfigurationName: cfg.Name,
+			Percent:           60,
+			Revisions: []RevisionRollout{{
+				RevisionName: goodNewRev.Name,
+				Percent:      60,
+			}},
+			Schedule: []RolloutStep{{
+				Percent: 5,
+				Hold:    int64(10 * time.Minute),
+			}, {
+				Percent: 25,
+				Hold:    int64(30 * time.Minute),
+			}},
+		}, {
+			ConfigurationName: invalid.Name,
+			Percent:           40,
+			Revisions: []RevisionRollout{{
+				RevisionName: niceNewRev.Name,
+				Percent:      40,
+			}},
+		}},
+	}
+	if gotR := tc.BuildRollout(); !cmp.Equal(gotR, wantR) {
+		t.Errorf("Rollout mismatch, diff(-want,+got):\n%s", cmp.Diff(wantR, gotR))
+	}
+}
+
 func TestBuildTrafficConfigurationTag0Percent(t *testing.T) {
 	expected := &Config{
 		Targets: map[string]RevisionTargets{
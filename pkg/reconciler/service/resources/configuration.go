@@ -42,7 +42,16 @@ func MakeConfigurationFromExisting(service *v1.Service, existing *v1.Configurati
 		serving.ServiceUIDLabelKey: string(service.ObjectMeta.UID),
 	}
 
-	exclude := append([]string{corev1.LastAppliedConfigAnnotation}, serving.RolloutDurationAnnotation...)
+	exclude := append([]string{
+		corev1.LastAppliedConfigAnnotation,
+		serving.RolloutMinSuccessPercentageKey,
+		serving.RolloutMaxLatencyKey,
+		serving.RolloutAnalysisActionKey,
+		serving.RolloutControlKey,
+		serving.RolloutStrategyKey,
+		serving.BlueGreenRollbackWindowKey,
+		serving.RollbackKey,
+	}, serving.RolloutDurationAnnotation...)
 	anns := kmap.ExcludeKeyList(service.GetAnnotations(), exclude)
 
 	routeName := names.Route(service)
@@ -32,7 +32,12 @@ func TestConfigurationSpec(t *testing.T) {
 	s := createService()
 	s.Annotations = kmeta.UnionMaps(s.Annotations,
 		map[string]string{
-			serving.RolloutDurationKey: "2021s",
+			serving.RolloutDurationKey:             "2021s",
+			serving.RolloutMinSuccessPercentageKey: "99",
+			serving.RolloutControlKey:              serving.RolloutControlPause,
+			serving.RolloutStrategyKey:             serving.RolloutStrategyBlueGreen,
+			serving.BlueGreenRollbackWindowKey:     "10m",
+			serving.RollbackKey:                    "1",
 		},
 	)
 
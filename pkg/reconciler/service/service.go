@@ -18,7 +18,9 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"go.uber.org/zap"
@@ -33,6 +35,7 @@ import (
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/serving/pkg/apis/serving"
 	v1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -94,6 +97,7 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, service *v1.Service) pkg
 		// Update our Status based on the state of our underlying Configuration.
 		service.Status.PropagateConfigurationStatus(&config.Status)
 	}
+	c.recordRevision(ctx, service, config)
 
 	// When the Configuration names a Revision, check that the named Revision is owned
 	// by our Configuration and matches its generation before reprogramming the Route,
@@ -171,6 +175,75 @@ func (c *Reconciler) route(ctx context.Context, service *v1.Service) (*v1.Route,
 	return route, nil
 }
 
+// recordRevision appends the latest ready revision of the Configuration to the
+// revision history of the Service. A revision missing from the lister is
+// recorded on a later reconcile.
+func (c *Reconciler) recordRevision(ctx context.Context, service *v1.Service, config *v1.Configuration) {
+	name := config.Status.LatestReadyRevisionName
+	if name == "" {
+		return
+	}
+	if h := service.Status.RevisionHistory; len(h) > 0 && h[len(h)-1].RevisionName == name {
+		return
+	}
+	rev, err := c.revisionLister.Revisions(service.Namespace).Get(name)
+	if err != nil {
+		logging.FromContext(ctx).Debugw("Failed to get Revision "+name+" to record", zap.Error(err))
+		return
+	}
+	entry := v1.RevisionHistoryEntry{
+		RevisionName: name,
+		TemplateHash: templateHash(rev),
+		Timestamp:    rev.CreationTimestamp,
+		Creator:      rev.Annotations[serving.CreatorAnnotation],
+	}
+	if rc := rev.Status.GetCondition(v1.RevisionConditionReady); rc != nil && !rc.LastTransitionTime.Inner.IsZero() {
+		entry.Timestamp = rc.LastTransitionTime.Inner
+	}
+	service.Status.RecordRevision(entry)
+}
+
+// templateHash returns a hash of the spec of the revision.
+func templateHash(rev *v1.Revision) string {
+	b, err := json.Marshal(rev.Spec)
+	if err != nil {
+		return ""
+	}
+	h := fnv.New64a()
+	h.Write(b)
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// makeRoute returns the Route of the Service. When the Service is rolled back,
+// all the traffic of the Route goes to the revision of the history rolled back
+// to, whose name is returned as well. That revision is pinned in the status of
+// the Service until the rollback annotation changes.
+func (c *Reconciler) makeRoute(ctx context.Context, service *v1.Service) (*v1.Route, string) {
+	route := resources.MakeRoute(service)
+	n := service.Rollback()
+	if n == 0 {
+		service.Status.Rollback = nil
+		return route, ""
+	}
+	if rb := service.Status.Rollback; rb == nil || rb.Revisions != n {
+		name, ok := service.Status.PreviousRevision(n)
+		if !ok {
+			controller.GetEventRecorder(ctx).Eventf(service, corev1.EventTypeWarning, "RollbackFailed",
+				"Cannot roll back %d revisions, the revision history has %d entries", n, len(service.Status.RevisionHistory))
+			service.Status.Rollback = nil
+			return route, ""
+		}
+		service.Status.Rollback = &v1.RollbackStatus{Revisions: n, RevisionName: name}
+	}
+	name := service.Status.Rollback.RevisionName
+	route.Spec.Traffic = []v1.TrafficTarget{{
+		RevisionName:   name,
+		LatestRevision: ptr.Bool(false),
+		Percent:        ptr.Int64(100),
+	}}
+	return route, name
+}
+
 func (c *Reconciler) checkRoutesNotReady(config *v1.Configuration, logger *zap.SugaredLogger, route *v1.Route, service *v1.Service) {
 	// `manual` is not reconciled.
 	rc := service.Status.GetCondition(v1.ServiceConditionRoutesReady)
@@ -245,8 +318,8 @@ func (c *Reconciler) reconcileConfiguration(ctx context.Context, service *v1.Ser
 }
 
 func (c *Reconciler) createRoute(ctx context.Context, service *v1.Service) (*v1.Route, error) {
-	return c.client.ServingV1().Routes(service.Namespace).Create(
-		ctx, resources.MakeRoute(service), metav1.CreateOptions{})
+	route, _ := c.makeRoute(ctx, service)
+	return c.client.ServingV1().Routes(service.Namespace).Create(ctx, route, metav1.CreateOptions{})
 }
 
 func routeSemanticEquals(ctx context.Context, desiredRoute, route *v1.Route) (bool, error) {
@@ -270,7 +343,7 @@ func (c *Reconciler) reconcileRoute(ctx context.Context, service *v1.Service, ro
 	// We are setting the up-to-date default values here so an update won't be triggered if the only
 	// diff is the new default values.
 	existing.SetDefaults(ctx)
-	desiredRoute := resources.MakeRoute(service)
+	desiredRoute, rollbackRevision := c.makeRoute(ctx, service)
 	equals, err := routeSemanticEquals(ctx, desiredRoute, existing)
 	if err != nil {
 		return nil, err
@@ -278,12 +351,18 @@ func (c *Reconciler) reconcileRoute(ctx context.Context, service *v1.Service, ro
 	if equals {
 		return route, nil
 	}
+	rolledBack := rollbackRevision != "" && !equality.Semantic.DeepEqual(desiredRoute.Spec.Traffic, existing.Spec.Traffic)
 
 	// Preserve the rest of the object (e.g. ObjectMeta except for labels and annotations).
 	existing.Spec = desiredRoute.Spec
 	existing.Labels = desiredRoute.Labels
 	existing.Annotations = desiredRoute.Annotations
-	return c.client.ServingV1().Routes(service.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	route, err = c.client.ServingV1().Routes(service.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	if err == nil && rolledBack {
+		controller.GetEventRecorder(ctx).Eventf(service, corev1.EventTypeNormal, "RolledBack",
+			"Rolled back Route %q to Revision %q", route.Name, rollbackRevision)
+	}
+	return route, err
 }
 
 // CheckNameAvailability checks that if the named Revision specified by the Configuration
@@ -56,6 +56,24 @@ import (
 )
 
 func TestReconcile(t *testing.T) {
+	historyRev := rev("history", "foo", WithRunLatestRollout,
+		WithConfigGeneration(1), WithConfigAnn(serving.UpdaterAnnotation, "someone@example.com"))
+	historyRev.CreationTimestamp = metav1.NewTime(time.Unix(1600000000, 0))
+	rollback := func(n string) ServiceOption {
+		return func(svc *v1.Service) {
+			WithRunLatestRollout(svc)
+			WithServiceAnnotation(serving.RollbackKey, n)(svc)
+		}
+	}
+	rollbackHistory := WithRevisionHistory(v1.RevisionHistoryEntry{
+		RevisionName: "rollback-00001",
+	}, v1.RevisionHistoryEntry{
+		RevisionName: "rollback-00002",
+	})
+	rollForwardRev := rev("rollback", "foo", WithRunLatestRollout,
+		WithConfigGeneration(3), WithConfigAnn(serving.UpdaterAnnotation, "someone@example.com"))
+	rollForwardRev.CreationTimestamp = metav1.NewTime(time.Unix(1600000000, 0))
+
 	retryAttempted := false
 	table := TableTest{{
 		Name: "bad workqueue key",
@@ -537,6 +555,151 @@ func TestReconcile(t *testing.T) {
 					Percent:      ptr.Int64(100),
 				})),
 		}},
+	}, {
+		Name: "latest ready revision recorded in history",
+		Objects: []runtime.Object{
+			DefaultService("history", "foo", WithRunLatestRollout, WithInitSvcConditions, WithServiceGeneration(1)),
+			route("history", "foo", WithRunLatestRollout, withRouteReady,
+				WithURL, WithAddress, WithInitRouteConditions,
+				WithStatusTraffic(v1.TrafficTarget{
+					RevisionName: "history-00001",
+					Percent:      ptr.Int64(100),
+				}), MarkTrafficAssigned, MarkIngressReady),
+			config("history", "foo", WithRunLatestRollout,
+				WithConfigGeneration(1), WithConfigObservedGen,
+				WithLatestCreated("history-00001"), WithLatestReady("history-00001")),
+			historyRev,
+		},
+		Key: "foo/history",
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: DefaultService("history", "foo", WithRunLatestRollout,
+				WithReadyConfig("history-00001"),
+				WithReadyRoute, WithSvcStatusDomain, WithSvcStatusAddress,
+				WithSvcStatusTraffic(v1.TrafficTarget{
+					RevisionName: "history-00001",
+					Percent:      ptr.Int64(100),
+				}),
+				WithRevisionHistory(v1.RevisionHistoryEntry{
+					RevisionName: "history-00001",
+					TemplateHash: templateHash(historyRev),
+					Timestamp:    historyRev.CreationTimestamp,
+					Creator:      "someone@example.com",
+				})),
+		}},
+	}, {
+		Name: "rollback to the previous revision",
+		Objects: []runtime.Object{
+			DefaultService("rollback", "foo", rollback("1"), WithInitSvcConditions,
+				WithServiceGeneration(1), rollbackHistory),
+			route("rollback", "foo", WithRunLatestRollout, withRouteReady,
+				WithURL, WithAddress, WithInitRouteConditions,
+				WithStatusTraffic(v1.TrafficTarget{
+					RevisionName: "rollback-00002",
+					Percent:      ptr.Int64(100),
+				}), MarkTrafficAssigned, MarkIngressReady),
+			config("rollback", "foo", WithRunLatestRollout,
+				WithConfigGeneration(2), WithConfigObservedGen,
+				WithLatestCreated("rollback-00002"), WithLatestReady("rollback-00002")),
+		},
+		Key: "foo/rollback",
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: route("rollback", "foo", rollback("1"), withRouteReady,
+				WithURL, WithAddress, WithInitRouteConditions,
+				WithSpecTraffic(v1.TrafficTarget{
+					RevisionName:   "rollback-00001",
+					LatestRevision: ptr.Bool(false),
+					Percent:        ptr.Int64(100),
+				}),
+				WithStatusTraffic(v1.TrafficTarget{
+					RevisionName: "rollback-00002",
+					Percent:      ptr.Int64(100),
+				}), MarkTrafficAssigned, MarkIngressReady),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: DefaultService("rollback", "foo", rollback("1"),
+				WithReadyConfig("rollback-00002"), rollbackHistory,
+				WithRollbackStatus(1, "rollback-00001"),
+				WithServiceStatusRouteNotReady, WithSvcStatusDomain, WithSvcStatusAddress,
+				WithSvcStatusTraffic(v1.TrafficTarget{
+					RevisionName: "rollback-00002",
+					Percent:      ptr.Int64(100),
+				})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "RolledBack", "Rolled back Route %q to Revision %q", "rollback", "rollback-00001"),
+		},
+	}, {
+		// The revision rolled back to stays put as the history grows.
+		Name: "roll forward while rolled back",
+		Objects: []runtime.Object{
+			DefaultService("rollback", "foo", rollback("1"), WithInitSvcConditions,
+				WithServiceGeneration(1), rollbackHistory, WithRollbackStatus(1, "rollback-00001")),
+			route("rollback", "foo", rollback("1"), withRouteReady,
+				WithURL, WithAddress, WithInitRouteConditions,
+				WithSpecTraffic(v1.TrafficTarget{
+					RevisionName:   "rollback-00001",
+					LatestRevision: ptr.Bool(false),
+					Percent:        ptr.Int64(100),
+				}),
+				WithStatusTraffic(v1.TrafficTarget{
+					RevisionName: "rollback-00001",
+					Percent:      ptr.Int64(100),
+				}), MarkTrafficAssigned, MarkIngressReady),
+			config("rollback", "foo", WithRunLatestRollout,
+				WithConfigGeneration(3), WithConfigObservedGen,
+				WithLatestCreated("rollback-00003"), WithLatestReady("rollback-00003")),
+			rollForwardRev,
+		},
+		Key: "foo/rollback",
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: DefaultService("rollback", "foo", rollback("1"),
+				WithReadyConfig("rollback-00003"),
+				WithReadyRoute, WithSvcStatusDomain, WithSvcStatusAddress,
+				WithSvcStatusTraffic(v1.TrafficTarget{
+					RevisionName: "rollback-00001",
+					Percent:      ptr.Int64(100),
+				}),
+				WithRevisionHistory(v1.RevisionHistoryEntry{
+					RevisionName: "rollback-00001",
+				}, v1.RevisionHistoryEntry{
+					RevisionName: "rollback-00002",
+				}, v1.RevisionHistoryEntry{
+					RevisionName: "rollback-00003",
+					TemplateHash: templateHash(rollForwardRev),
+					Timestamp:    rollForwardRev.CreationTimestamp,
+					Creator:      "someone@example.com",
+				}),
+				WithRollbackStatus(1, "rollback-00001")),
+		}},
+	}, {
+		Name: "rollback past the revision history",
+		Objects: []runtime.Object{
+			DefaultService("rollback", "foo", rollback("2"), WithInitSvcConditions,
+				WithServiceGeneration(1), rollbackHistory),
+			route("rollback", "foo", rollback("2"), withRouteReady,
+				WithURL, WithAddress, WithInitRouteConditions,
+				WithStatusTraffic(v1.TrafficTarget{
+					RevisionName: "rollback-00002",
+					Percent:      ptr.Int64(100),
+				}), MarkTrafficAssigned, MarkIngressReady),
+			config("rollback", "foo", WithRunLatestRollout,
+				WithConfigGeneration(2), WithConfigObservedGen,
+				WithLatestCreated("rollback-00002"), WithLatestReady("rollback-00002")),
+		},
+		Key: "foo/rollback",
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: DefaultService("rollback", "foo", rollback("2"),
+				WithReadyConfig("rollback-00002"), rollbackHistory,
+				WithReadyRoute, WithSvcStatusDomain, WithSvcStatusAddress,
+				WithSvcStatusTraffic(v1.TrafficTarget{
+					RevisionName: "rollback-00002",
+					Percent:      ptr.Int64(100),
+				})),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "RollbackFailed",
+				"Cannot roll back %d revisions, the revision history has %d entries", 2, 2),
+		},
 	}, {
 		Name: "configuration lagging",
 		// When both route and config are ready, the service should become ready.
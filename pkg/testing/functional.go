@@ -205,6 +205,11 @@ func WithMetricAnnotation(metric string) PodAutoscalerOption {
 	return withAnnotationValue(autoscaling.MetricAnnotationKey, metric)
 }
 
+// WithAdditionalMetricsAnnotation adds an additional metrics annotation to the PA.
+func WithAdditionalMetricsAnnotation(metrics string) PodAutoscalerOption {
+	return withAnnotationValue(autoscaling.AdditionalMetricsAnnotationKey, metrics)
+}
+
 // WithObservedGeneration returns a PodAutoScalerOption which sets
 // the Status.ObservedGeneration field to the given generation.
 func WithObservedGeneration(gen int64) PodAutoscalerOption {
@@ -42,6 +42,13 @@ func WithSpecTraffic(traffic ...v1.TrafficTarget) RouteOption {
 	}
 }
 
+// WithSpecRules sets a Route's rules to the provided values.
+func WithSpecRules(rules ...v1.RouteRule) RouteOption {
+	return func(r *v1.Route) {
+		r.Spec.Rules = rules
+	}
+}
+
 // WithRouteUID sets the Route's UID
 func WithRouteUID(uid types.UID) RouteOption {
 	return func(r *v1.Route) {
@@ -104,6 +111,13 @@ func WithStatusTraffic(traffic ...v1.TrafficTarget) RouteOption {
 	}
 }
 
+// WithStatusRollouts sets the Route's status rollouts to the specified rollouts.
+func WithStatusRollouts(rollouts ...v1.RolloutStatus) RouteOption {
+	return func(r *v1.Route) {
+		r.Status.Rollouts = rollouts
+	}
+}
+
 // WithRouteOwnersRemoved clears the owner references of this Route.
 func WithRouteOwnersRemoved(r *v1.Route) {
 	r.OwnerReferences = nil
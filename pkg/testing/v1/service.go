@@ -421,6 +421,21 @@ func WithServiceLatestReadyRevision(lrr string) ServiceOption {
 	}
 }
 
+// WithRevisionHistory sets the revision history on the Service's status.
+func WithRevisionHistory(entries ...v1.RevisionHistoryEntry) ServiceOption {
+	return func(s *v1.Service) {
+		s.Status.RevisionHistory = entries
+	}
+}
+
+// WithRollbackStatus pins the revision the Service is rolled back to in its
+// status.
+func WithRollbackStatus(n int, name string) ServiceOption {
+	return func(s *v1.Service) {
+		s.Status.Rollback = &v1.RollbackStatus{Revisions: n, RevisionName: name}
+	}
+}
+
 // WithReadinessProbe sets the provided probe to be the readiness
 // probe on the service.
 func WithReadinessProbe(p *corev1.Probe) ServiceOption {